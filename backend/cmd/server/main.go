@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -13,12 +15,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 	"github.com/saurabh22suman/oreo.io/internal/auth"
+	"github.com/saurabh22suman/oreo.io/internal/cache"
 	"github.com/saurabh22suman/oreo.io/internal/database"
 	"github.com/saurabh22suman/oreo.io/internal/handlers"
+	"github.com/saurabh22suman/oreo.io/internal/logging"
 	"github.com/saurabh22suman/oreo.io/internal/middleware"
+	"github.com/saurabh22suman/oreo.io/internal/models"
 	"github.com/saurabh22suman/oreo.io/internal/repository"
 	"github.com/saurabh22suman/oreo.io/internal/services"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
 )
 
 func main() {
@@ -32,8 +39,13 @@ func main() {
 		log.Println("Running in Docker - using environment variables from docker-compose")
 	}
 
-	// Initialize database connection - force real DB for projects functionality
-	dbConn, err := database.NewConnection()
+	// Structured logger; standard log output is routed through it too
+	logger := logging.FromEnv(os.Stdout)
+	slog.SetDefault(logger)
+
+	// Initialize database connection - force real DB for projects functionality. Connecting is
+	// retried with backoff so a database that is still starting doesn't crash the server.
+	dbConn, err := database.NewConnectionWithRetry(dbConnectRetryFromEnv())
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -50,6 +62,14 @@ func main() {
 		}
 	}()
 
+	// Share Redis as the application cache, falling back to process memory with the mock connection
+	var appCache cache.Cache
+	if client, ok := redisConn.(*redis.Client); ok {
+		appCache = cache.NewRedisCache(client)
+	} else {
+		appCache = cache.NewMemoryCache()
+	}
+
 	// Initialize services with real database
 	log.Println("Using real database for all operations")
 
@@ -57,20 +77,37 @@ func main() {
 	sqlxDB := sqlx.NewDb(dbConn, "postgres")
 
 	userRepo := repository.NewUserRepository(dbConn)
-	projectHandlers := handlers.NewProjectHandlers(sqlxDB)
+	displayRows := services.DisplayRowLimitsFromEnv()
+	projectHandlers := handlers.NewProjectHandlers(sqlxDB, userRepo, displayRows)
 	log.Printf("Project handlers initialized: %+v", projectHandlers)
 	if projectHandlers == nil {
 		log.Fatal("Project handlers is nil!")
 	}
 
-	jwtService := auth.NewJWTService(os.Getenv("JWT_SECRET"))
+	jwtService, err := auth.NewJWTServiceWithConfig(auth.JWTConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to configure JWT signing: %v", err)
+	}
 	authService := services.NewAuthService(userRepo, jwtService)
+	// Dataset share tokens are signed with SHARE_TOKEN_SECRET, or JWT_SECRET when it isn't set
+	shareTokenSigner := auth.NewShareTokenSigner(auth.ShareTokenSecretFromEnv())
 	authHandlers := handlers.NewAuthHandlers(authService)
+	maxUploadRows := services.MaxUploadRowsFromEnv()
+
+	// Uploaded files live on local disk or in S3-compatible object storage (STORAGE_BACKEND)
+	fileStore, err := storage.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure file storage: %v", err)
+	}
 	sampleDataHandlers := handlers.NewSampleDataHandlers() // Set Gin mode based on environment
 	if os.Getenv("ENVIRONMENT") == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	// Background jobs stop when the server shuts down
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
 	// Initialize Gin router
 	router := gin.New()
 
@@ -78,42 +115,30 @@ func main() {
 	router.MaxMultipartMemory = 50 << 20 // 50MB
 
 	// Middleware
+	router.Use(middleware.RequestID(logger))
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3001"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "If-None-Match", middleware.RequestIDHeader, middleware.IdempotencyKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "ETag", middleware.RequestIDHeader, middleware.IdempotentReplayedHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	// Health check endpoints ping the database and Redis so load balancers see real outages.
+	// They are registered before the rate limiter so probes are never throttled.
+	redisPinger, _ := redisConn.(handlers.RedisPinger)
+	router.GET("/health", handlers.HealthCheck(dbConn, redisPinger))
+	router.GET("/health/db", handlers.DatabaseHealthCheck(dbConn))
+	router.GET("/health/redis", handlers.RedisHealthCheck(redisPinger))
+	router.GET("/health/live", handlers.LivenessCheck())
+	router.GET("/health/ready", handlers.ReadinessCheck(dbConn, redisPinger))
+
 	// Rate limiting middleware
 	router.Use(middleware.RateLimit())
 
-	// Health check endpoints
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"timestamp": time.Now().UTC(),
-			"database":  "connected (mock in development)",
-			"redis":     "connected (mock in development)",
-		})
-	})
-	router.GET("/health/db", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "healthy",
-			"type":   "database",
-		})
-	})
-	router.GET("/health/redis", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status": "healthy",
-			"type":   "redis",
-		})
-	})
-
 	// API routes
 	v1 := router.Group("/api/v1")
 	{
@@ -136,9 +161,16 @@ func main() {
 			auth.GET("/me", middleware.RequireAuthWithService(authService), handlers.GetCurrentUser())
 		}
 
-		// Protected routes
+		// Protected routes need a user session. Routes acting on a project's datasets also accept a
+		// project API key; its principal is a member of the key's project only, so the usual access
+		// checks keep the key inside that project.
+		apiKeyRepo := repository.NewAPIKeyRepository(sqlxDB)
+		requireAuth := middleware.RequireAuthOrAPIKey(authService, apiKeyRepo)
+		shareTokenRepo := repository.NewShareTokenRepository(sqlxDB)
 		protected := v1.Group("")
 		protected.Use(middleware.RequireAuthWithService(authService))
+		projectScoped := v1.Group("")
+		projectScoped.Use(requireAuth)
 		{
 			// Project routes
 			log.Printf("Registering project routes with handlers: %+v", projectHandlers)
@@ -149,74 +181,185 @@ func main() {
 				projects.GET("/:id", projectHandlers.GetProject())
 				projects.PUT("/:id", projectHandlers.UpdateProject())
 				projects.DELETE("/:id", projectHandlers.DeleteProject())
+				projects.GET("/invitations", projectHandlers.GetInvitations())
+				projects.POST("/:id/members", projectHandlers.InviteMember())
+				projects.POST("/:id/accept", projectHandlers.AcceptInvitation())
+
+				// API keys for programmatic access, managed by the project owner
+				apiKeyHandlers := handlers.NewAPIKeyHandlers(apiKeyRepo, repository.NewProjectRepository(sqlxDB))
+				projects.GET("/:id/api-keys", apiKeyHandlers.ListAPIKeys())
+				projects.POST("/:id/api-keys", apiKeyHandlers.CreateAPIKey())
+				projects.DELETE("/:id/api-keys/:key_id", apiKeyHandlers.RevokeAPIKey())
 			}
 
 			// Dataset routes
-			datasetHandlers := handlers.NewDatasetHandlers(sqlxDB)
-			datasets := protected.Group("/datasets")
+			datasetHandlers := handlers.NewDatasetHandlers(sqlxDB, fileStore, maxUploadRows, appCache, durationFromEnv("FIELD_STATS_CACHE_TTL"))
+			// Uploads and appends honor an Idempotency-Key header so client retries don't create duplicates
+			idempotent := middleware.Idempotency(appCache, durationFromEnv("IDEMPOTENCY_KEY_TTL"))
+			// Sample data imports create a real dataset, so they need a signed-in user with project access
+			protected.POST("/sample-data/:category/:filename/import", idempotent, datasetHandlers.ImportSampleDataset(sampleDataHandlers))
+			datasets := projectScoped.Group("/datasets")
 			{
-				datasets.POST("/upload", datasetHandlers.UploadDataset())
+				datasets.POST("/upload", idempotent, datasetHandlers.UploadDataset())
 				datasets.GET("/user", datasetHandlers.GetUserDatasets())
+				datasets.GET("/search", datasetHandlers.SearchDatasets())
 				datasets.GET("/project/:project_id", datasetHandlers.GetDatasets())
 				datasets.GET("/:id", datasetHandlers.GetDatasetByID())
+				datasets.GET("/:id/preview", datasetHandlers.PreviewDataset())
 				datasets.DELETE("/:id", datasetHandlers.DeleteDataset())
+				datasets.PUT("/:id/trust", datasetHandlers.SetDatasetTrust())
+				datasets.PUT("/:id/display-field", datasetHandlers.SetDatasetDisplayField())
+				datasets.PUT("/:id/csv-dialect", datasetHandlers.SetDatasetCSVDialect())
+				datasets.PUT("/:id/pii-guardrails", datasetHandlers.SetDatasetPIIGuardrails())
+				datasets.PUT("/:id/header-mode", datasetHandlers.SetDatasetHeaderMode())
+				datasets.PUT("/:id/enforce-schema", datasetHandlers.SetDatasetEnforceSchema())
+				datasets.PUT("/:id/null-tokens", datasetHandlers.SetDatasetNullTokens())
+				datasets.POST("/:id/compute-stats", datasetHandlers.ComputeDatasetStats())
+				datasets.GET("/:id/stats", datasetHandlers.GetDatasetStats())
+				datasets.GET("/:id/drift", datasetHandlers.GetDatasetDrift())
+
+				// Read-only share tokens for people without an account, managed by the project owner
+				shareTokenHandlers := handlers.NewShareTokenHandlers(shareTokenRepo, repository.NewDatasetRepository(sqlxDB), shareTokenSigner)
+				datasets.GET("/:id/share-tokens", shareTokenHandlers.ListShareTokens())
+				datasets.POST("/:id/share-tokens", shareTokenHandlers.CreateShareToken())
+				datasets.DELETE("/:id/share-tokens/:token_id", shareTokenHandlers.RevokeShareToken())
 			}
 
 			// Schema routes
 			schemaRepo := repository.NewSchemaRepository(sqlxDB)
-			schemaHandlers := handlers.NewSchemaHandlers(sqlxDB)
-			schemas := protected.Group("/schemas")
+			inferenceCache := services.NewSchemaInferenceCache(appCache, durationFromEnv("SCHEMA_INFERENCE_CACHE_TTL"))
+			// Pages of dataset data are cached per dataset version unless DATA_PAGE_CACHE_DISABLED=true
+			var dataPages *services.DataPageCache
+			if os.Getenv("DATA_PAGE_CACHE_DISABLED") != "true" {
+				dataPages = services.NewDataPageCache(appCache, durationFromEnv("DATA_PAGE_CACHE_TTL"))
+			}
+			schemaHandlers := handlers.NewSchemaHandlers(sqlxDB, inferenceCache, displayRows, services.WebhookDispatcherFromEnv(), dataPages)
+			schemas := projectScoped.Group("/schemas")
 			{
 				schemas.POST("", schemaHandlers.CreateSchema())
 				schemas.GET("/dataset/:dataset_id", schemaHandlers.GetSchema())
+				schemas.GET("/dataset/:dataset_id/variants", schemaHandlers.ListSchemas())
 				schemas.POST("/infer/:dataset_id", schemaHandlers.InferSchema()) // Schema inference endpoint
 				schemas.PUT("/:schema_id", schemaHandlers.UpdateSchema())
 				schemas.DELETE("/:schema_id", schemaHandlers.DeleteSchema())
+				schemas.GET("/:schema_id/versions", schemaHandlers.ListSchemaVersions())
+				schemas.GET("/:schema_id/versions/diff", schemaHandlers.DiffSchemaVersions())
 			}
 
 			// Data routes
-			data := protected.Group("/data")
+			data := projectScoped.Group("/data")
 			{
-				data.GET("/dataset/:dataset_id", schemaHandlers.GetDatasetData())
 				data.POST("/dataset/:dataset_id/query", schemaHandlers.QueryDatasetData())
+				data.GET("/dataset/:dataset_id/search", schemaHandlers.SearchDatasetData())
+				data.GET("/dataset/:dataset_id/export", schemaHandlers.ExportDatasetData())
 				data.PUT("/dataset/:dataset_id", schemaHandlers.UpdateDatasetData())
+				data.DELETE("/dataset/:dataset_id", schemaHandlers.DeleteDatasetRows())
 				data.DELETE("/dataset/:dataset_id/row/:row_index", schemaHandlers.DeleteDatasetData())
+				data.DELETE("/dataset/:dataset_id/all", schemaHandlers.TruncateDatasetData())
 			}
 
+			// Reading a dataset's data also accepts a share token in place of a login
+			v1.GET("/data/dataset/:dataset_id", middleware.AllowShareToken(shareTokenSigner, shareTokenRepo, requireAuth), schemaHandlers.GetDatasetData())
+
 			// Data submission routes for append functionality
 			submissionRepo := repository.NewDataSubmissionRepository(sqlxDB)
+			submissionRepo.SetApplyRetry(applyRetryFromEnv())
 			validationSvc := services.NewValidationService(schemaRepo, submissionRepo)
-			submissionHandlers := handlers.NewDataSubmissionHandlers(submissionRepo, schemaRepo, validationSvc)
+			validationSvc.SetMaxRows(maxUploadRows)
+			validationSvc.SetEmailValidation(services.EmailValidationFromEnv())
+			validationSvc.SetNumericMaxMagnitude(services.NumericMaxMagnitudeFromEnv())
+			validationSvc.SetMaxConcurrentValidations(services.MaxConcurrentValidationsFromEnv())
+			validationSvc.SetStorage(fileStore)
+			// Purge staging data of submissions left pending beyond SUBMISSION_STAGING_TTL
+			if stagingTTL := durationFromEnv("SUBMISSION_STAGING_TTL"); stagingTTL > 0 {
+				cleanupInterval := durationFromEnv("SUBMISSION_CLEANUP_INTERVAL")
+				if cleanupInterval <= 0 {
+					cleanupInterval = time.Hour
+				}
+				deleteExpired := os.Getenv("SUBMISSION_CLEANUP_DELETE") == "true"
+				stagingCleanup := services.NewStagingCleanupService(submissionRepo, fileStore, stagingTTL, deleteExpired)
+				go stagingCleanup.Run(backgroundCtx, cleanupInterval)
+			}
+			// Remove files of finished submissions and orphaned uploads older than FILE_RETENTION
+			if fileRetention := durationFromEnv("FILE_RETENTION"); fileRetention > 0 {
+				janitorInterval := durationFromEnv("FILE_CLEANUP_INTERVAL")
+				if janitorInterval <= 0 {
+					janitorInterval = time.Hour
+				}
+				fileJanitor := services.NewFileJanitor(submissionRepo, repository.NewDatasetRepository(sqlxDB), fileStore, "submissions", "uploads", fileRetention)
+				go fileJanitor.Run(backgroundCtx, janitorInterval)
+			}
+
+			submissionDedup := services.NewSubmissionDeduplicator(submissionRepo, durationFromEnv("SUBMISSION_DEDUP_WINDOW"))
+			// Submitters are notified in-app, and by email when SMTP_HOST is set, when their submission is reviewed
+			notificationRepo := repository.NewNotificationRepository(sqlxDB)
+			notificationSvc := services.NewNotificationService(notificationRepo, services.MailerFromEnv())
+			submissionHandlers := handlers.NewDataSubmissionHandlers(submissionRepo, schemaRepo, validationSvc, submissionDedup, fileStore, services.MaxBusinessRulesFromEnv(), services.MaxDirectUploadSizeFromEnv(), notificationSvc, dataPages)
 			
+			// Draft schemas are checked with the same validation as submissions
+			schemas.POST("/validate", submissionHandlers.ValidateDraftSchema())
+
 			// User submission routes
-			datasets.POST("/:dataset_id/append", submissionHandlers.SubmitDataForAppend())
+			datasets.POST("/:dataset_id/append", idempotent, submissionHandlers.SubmitDataForAppend())
+			datasets.POST("/:dataset_id/append/presign", submissionHandlers.PresignAppendUpload())
+			datasets.POST("/:dataset_id/append/complete", idempotent, submissionHandlers.CompleteAppendUpload())
 			datasets.GET("/:dataset_id/submissions", submissionHandlers.GetDataSubmissions())
 			
 			// Submission management routes
-			submissions := protected.Group("/submissions")
+			submissions := projectScoped.Group("/submissions")
 			{
 				submissions.GET("/:submission_id/details", submissionHandlers.GetSubmissionDetails())
+				submissions.GET("/:submission_id/summary", submissionHandlers.GetSubmissionSummary())
+				submissions.GET("/:submission_id/verify", submissionHandlers.VerifySubmission())
+				submissions.GET("/:submission_id/conflicts", submissionHandlers.GetSubmissionConflicts())
+				submissions.GET("/:submission_id/staging/export", submissionHandlers.ExportStagingData())
+				submissions.DELETE("/:submission_id", submissionHandlers.WithdrawSubmission())
+				submissions.GET("/:submission_id/comments", submissionHandlers.ListSubmissionComments())
+				submissions.POST("/:submission_id/comments", submissionHandlers.CreateSubmissionComment())
 			}
 			
 			// Staging data routes for live editing
-			staging := protected.Group("/staging")
+			staging := projectScoped.Group("/staging")
 			{
 				staging.PUT("/:staging_id", submissionHandlers.UpdateStagingData())
 			}
 
 			// Business rules routes
-			businessRules := protected.Group("/datasets/:dataset_id/rules")
+			businessRules := projectScoped.Group("/datasets/:dataset_id/rules")
 			{
 				businessRules.POST("", submissionHandlers.CreateBusinessRule())
+				businessRules.POST("/bulk", submissionHandlers.BulkCreateBusinessRules())
 				businessRules.GET("", submissionHandlers.GetBusinessRules())
+				businessRules.POST("/test", submissionHandlers.TestBusinessRule())
+				businessRules.PUT("/:rule_id", submissionHandlers.UpdateBusinessRule())
+				businessRules.PUT("/:rule_id/active", submissionHandlers.SetBusinessRuleActive())
+				businessRules.DELETE("/:rule_id", submissionHandlers.DeleteBusinessRule())
 			}
 
 			// Admin routes for submission review
 			admin := protected.Group("/admin")
 			{
 				admin.GET("/submissions/pending", submissionHandlers.GetPendingSubmissions())
+				admin.GET("/submissions/summary", submissionHandlers.GetSubmissionsSummary())
 				admin.PUT("/submissions/:submission_id/review", submissionHandlers.ReviewSubmission())
 			}
+
+			// Notification routes for the current user
+			notificationHandlers := handlers.NewNotificationHandlers(notificationRepo)
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", notificationHandlers.GetNotifications())
+				notifications.PUT("/:notification_id/read", notificationHandlers.MarkNotificationRead())
+			}
+		}
+
+		// Trusted service routes authenticated by API key instead of a user session
+		directAppendSvc := services.NewDirectAppendService(repository.NewSchemaRepository(sqlxDB))
+		directAppendHandlers := handlers.NewDirectAppendHandlers(directAppendSvc)
+		trusted := v1.Group("/trusted")
+		trusted.Use(middleware.RequireAPIKey(apiKeyRepo, models.APIKeyScopeTrustedAppend))
+		{
+			trusted.POST("/datasets/:dataset_id/rows", directAppendHandlers.AppendRows())
 		}
 	}
 
@@ -245,6 +388,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopBackground()
 
 	// Give outstanding requests a 5-second timeout to complete
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -256,3 +400,58 @@ func main() {
 
 	log.Println("Server exited")
 }
+
+// durationFromEnv reads a duration such as "30s" or "72h" from the environment; unset or invalid values yield 0
+func durationFromEnv(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, ignoring: %v", name, value, err)
+		return 0
+	}
+	return duration
+}
+
+// dbConnectRetryFromEnv reads DB_CONNECT_ATTEMPTS and DB_CONNECT_RETRY_DELAY, falling back to the
+// database defaults when unset or invalid
+func dbConnectRetryFromEnv() (int, time.Duration) {
+	delay := durationFromEnv("DB_CONNECT_RETRY_DELAY")
+	if delay <= 0 {
+		delay = database.DefaultConnectBaseDelay
+	}
+
+	value := os.Getenv("DB_CONNECT_ATTEMPTS")
+	if value == "" {
+		return database.DefaultConnectAttempts, delay
+	}
+	attempts, err := strconv.Atoi(value)
+	if err != nil || attempts < 1 {
+		log.Printf("Warning: invalid DB_CONNECT_ATTEMPTS %q, using default of %d", value, database.DefaultConnectAttempts)
+		return database.DefaultConnectAttempts, delay
+	}
+	return attempts, delay
+}
+
+// applyRetryFromEnv reads APPLY_RETRY_ATTEMPTS and APPLY_RETRY_BASE_DELAY, falling back to the
+// repository defaults when unset or invalid
+func applyRetryFromEnv() (int, time.Duration) {
+	delay := durationFromEnv("APPLY_RETRY_BASE_DELAY")
+	if delay <= 0 {
+		delay = repository.DefaultTxRetryBaseDelay
+	}
+
+	value := os.Getenv("APPLY_RETRY_ATTEMPTS")
+	if value == "" {
+		return repository.DefaultTxRetryAttempts, delay
+	}
+	attempts, err := strconv.Atoi(value)
+	if err != nil || attempts < 1 {
+		log.Printf("Warning: invalid APPLY_RETRY_ATTEMPTS %q, using default of %d", value, repository.DefaultTxRetryAttempts)
+		return repository.DefaultTxRetryAttempts, delay
+	}
+	return attempts, delay
+}
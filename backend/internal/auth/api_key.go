@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// apiKeyPrefix marks plaintext API keys so they are easy to recognise, e.g. in leaked-secret scans
+const apiKeyPrefix = "oreo_"
+
+// APIKeyDisplayPrefixLength is how much of a key is stored in the clear to identify it in listings
+const APIKeyDisplayPrefixLength = 12
+
+// HashAPIKey returns the SHA-256 hex digest under which an API key is stored
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey returns a new random plaintext API key
+func GenerateAPIKey() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return apiKeyPrefix + hex.EncodeToString(secret), nil
+}
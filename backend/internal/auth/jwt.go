@@ -1,9 +1,12 @@
 package auth
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -43,13 +46,46 @@ type TokenPair struct {
 
 // jwtServiceImpl implements JWTService
 type jwtServiceImpl struct {
-	secretKey            []byte
+	method               jwt.SigningMethod
+	signingKey           interface{} // []byte for HS256, *rsa.PrivateKey for RS256; nil when only verifying
+	keyID                string
+	verificationKeys     map[string]interface{} // by kid, including the signing key's
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
 }
 
-// NewJWTService creates a new JWT service
-func NewJWTService(secretKey string) JWTService {
+// KeyID returns the kid header value identifying tokens signed with secret
+func KeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:8])
+}
+
+// PreviousSecretsFromEnv reads JWT_PREVIOUS_SECRETS, the comma-separated secrets that signed tokens
+// before the last rotation of JWT_SECRET
+func PreviousSecretsFromEnv() []string {
+	var secrets []string
+	for _, secret := range strings.Split(os.Getenv("JWT_PREVIOUS_SECRETS"), ",") {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
+
+// NewJWTService creates a new JWT service that signs tokens HS256 with secretKey. Tokens signed with
+// any of previousKeys still validate, so rotating the secret doesn't invalidate outstanding tokens.
+func NewJWTService(secretKey string, previousKeys ...string) JWTService {
+	verificationKeys := map[string]interface{}{KeyID(secretKey): []byte(secretKey)}
+	for _, key := range previousKeys {
+		verificationKeys[KeyID(key)] = []byte(key)
+	}
+
+	return newJWTService(jwt.SigningMethodHS256, []byte(secretKey), KeyID(secretKey), verificationKeys)
+}
+
+// newJWTService creates a service signing with signingKey under keyID, reading token lifetimes
+// from JWT_ACCESS_EXPIRY and JWT_REFRESH_EXPIRY
+func newJWTService(method jwt.SigningMethod, signingKey interface{}, keyID string, verificationKeys map[string]interface{}) *jwtServiceImpl {
 	accessDuration := 15 * time.Minute    // Default 15 minutes
 	refreshDuration := 7 * 24 * time.Hour // Default 7 days
 
@@ -67,7 +103,10 @@ func NewJWTService(secretKey string) JWTService {
 	}
 
 	return &jwtServiceImpl{
-		secretKey:            []byte(secretKey),
+		method:               method,
+		signingKey:           signingKey,
+		keyID:                keyID,
+		verificationKeys:     verificationKeys,
 		accessTokenDuration:  accessDuration,
 		refreshTokenDuration: refreshDuration,
 	}
@@ -75,6 +114,10 @@ func NewJWTService(secretKey string) JWTService {
 
 // GenerateTokenPair generates both access and refresh tokens
 func (j *jwtServiceImpl) GenerateTokenPair(userID uuid.UUID) (*TokenPair, error) {
+	if j.signingKey == nil {
+		return nil, errors.New("no signing key configured, tokens can only be verified")
+	}
+
 	// Generate access token
 	accessClaims := &JWTClaims{
 		UserID:    userID.String(),
@@ -89,8 +132,7 @@ func (j *jwtServiceImpl) GenerateTokenPair(userID uuid.UUID) (*TokenPair, error)
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(j.secretKey)
+	accessTokenString, err := j.sign(accessClaims)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign access token: %w", err)
 	}
@@ -109,8 +151,7 @@ func (j *jwtServiceImpl) GenerateTokenPair(userID uuid.UUID) (*TokenPair, error)
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString(j.secretKey)
+	refreshTokenString, err := j.sign(refreshClaims)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign refresh token: %w", err)
 	}
@@ -121,6 +162,13 @@ func (j *jwtServiceImpl) GenerateTokenPair(userID uuid.UUID) (*TokenPair, error)
 	}, nil
 }
 
+// sign signs claims with the current key, naming it in the kid header
+func (j *jwtServiceImpl) sign(claims *JWTClaims) (string, error) {
+	token := jwt.NewWithClaims(j.method, claims)
+	token.Header["kid"] = j.keyID
+	return token.SignedString(j.signingKey)
+}
+
 // ValidateAccessToken validates an access token and returns the claims
 func (j *jwtServiceImpl) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 	return j.validateToken(tokenString, "access")
@@ -143,13 +191,7 @@ func (j *jwtServiceImpl) RefreshAccessToken(refreshToken string) (*TokenPair, er
 
 // validateToken is a helper method to validate tokens
 func (j *jwtServiceImpl) validateToken(tokenString, expectedType string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return j.secretKey, nil
-	})
-
+	token, err := j.parse(tokenString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
 	}
@@ -165,3 +207,50 @@ func (j *jwtServiceImpl) validateToken(tokenString, expectedType string) (*JWTCl
 
 	return claims, nil
 }
+
+// parse verifies a token with the key named by its kid header. Tokens issued before key IDs carry
+// no kid, so every known key is tried for them.
+func (j *jwtServiceImpl) parse(tokenString string) (*jwt.Token, error) {
+	unverified, _, err := jwt.NewParser().ParseUnverified(tokenString, &JWTClaims{})
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []interface{}
+	if kid, _ := unverified.Header["kid"].(string); kid != "" {
+		key, ok := j.verificationKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		candidates = []interface{}{key}
+	} else {
+		if key, ok := j.verificationKeys[j.keyID]; ok {
+			candidates = append(candidates, key)
+		}
+		for kid, key := range j.verificationKeys {
+			if kid != j.keyID {
+				candidates = append(candidates, key)
+			}
+		}
+	}
+
+	for _, key := range candidates {
+		token, parseErr := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+			// Only the configured algorithm is accepted, so an RS256 public key is never used as an HMAC secret
+			if token.Method.Alg() != j.method.Alg() {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return key, nil
+		})
+		if parseErr == nil {
+			return token, nil
+		}
+		err = parseErr
+		// Only a signature mismatch means another key might fit
+		var validationErr *jwt.ValidationError
+		if !errors.As(parseErr, &validationErr) || validationErr.Errors&jwt.ValidationErrorSignatureInvalid == 0 {
+			break
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Supported JWT signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// JWTConfig selects how tokens are signed and verified
+type JWTConfig struct {
+	Algorithm       string   // HS256 (default) or RS256
+	Secret          string   // HS256 signing secret
+	PreviousSecrets []string // HS256 secrets that still verify after a rotation
+	// PrivateKeyPEM is the RS256 signing key. Without it the service can only verify tokens, which
+	// is all a resource server holding just the public key needs.
+	PrivateKeyPEM string
+	// PublicKeyPEM is the RS256 verification key; it is derived from PrivateKeyPEM when empty
+	PublicKeyPEM string
+	// PreviousPublicKeysPEM holds RS256 public keys, as concatenated PEM blocks, that still verify
+	// after a rotation
+	PreviousPublicKeysPEM string
+}
+
+// JWTConfigFromEnv reads JWT_ALGORITHM (HS256 or RS256, default HS256), JWT_SECRET and
+// JWT_PREVIOUS_SECRETS for HS256, and JWT_PRIVATE_KEY, JWT_PUBLIC_KEY and JWT_PREVIOUS_PUBLIC_KEYS
+// (PEM, with newlines optionally written as \n) for RS256
+func JWTConfigFromEnv() JWTConfig {
+	return JWTConfig{
+		Algorithm:             strings.ToUpper(strings.TrimSpace(os.Getenv("JWT_ALGORITHM"))),
+		Secret:                os.Getenv("JWT_SECRET"),
+		PreviousSecrets:       PreviousSecretsFromEnv(),
+		PrivateKeyPEM:         pemFromEnv("JWT_PRIVATE_KEY"),
+		PublicKeyPEM:          pemFromEnv("JWT_PUBLIC_KEY"),
+		PreviousPublicKeysPEM: pemFromEnv("JWT_PREVIOUS_PUBLIC_KEYS"),
+	}
+}
+
+// pemFromEnv reads a PEM value, restoring newlines escaped as \n to fit on one line
+func pemFromEnv(name string) string {
+	return strings.ReplaceAll(os.Getenv(name), `\n`, "\n")
+}
+
+// NewJWTServiceWithConfig creates a JWT service for the configured algorithm. HS256 behaves like
+// NewJWTService; RS256 signs with the private key and verifies with the public keys.
+func NewJWTServiceWithConfig(config JWTConfig) (JWTService, error) {
+	switch config.Algorithm {
+	case "", AlgorithmHS256:
+		return NewJWTService(config.Secret, config.PreviousSecrets...), nil
+	case AlgorithmRS256:
+		return newRS256Service(config)
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q (supported: %s, %s)", config.Algorithm, AlgorithmHS256, AlgorithmRS256)
+	}
+}
+
+func newRS256Service(config JWTConfig) (JWTService, error) {
+	var privateKey *rsa.PrivateKey
+	var publicKey *rsa.PublicKey
+	if config.PrivateKeyPEM != "" {
+		key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(config.PrivateKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid RS256 private key: %w", err)
+		}
+		privateKey, publicKey = key, &key.PublicKey
+	}
+	if config.PublicKeyPEM != "" {
+		key, err := jwt.ParseRSAPublicKeyFromPEM([]byte(config.PublicKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid RS256 public key: %w", err)
+		}
+		if privateKey != nil && !privateKey.PublicKey.Equal(key) {
+			return nil, errors.New("RS256 public key does not match the private key")
+		}
+		publicKey = key
+	}
+	if publicKey == nil {
+		return nil, errors.New("RS256 requires a private key to sign or a public key to verify")
+	}
+
+	keyID, err := PublicKeyID(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	verificationKeys := map[string]interface{}{keyID: publicKey}
+
+	previousKeys, err := parseRSAPublicKeys(config.PreviousPublicKeysPEM)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range previousKeys {
+		kid, err := PublicKeyID(key)
+		if err != nil {
+			return nil, err
+		}
+		verificationKeys[kid] = key
+	}
+
+	var signingKey interface{}
+	if privateKey != nil {
+		signingKey = privateKey
+	}
+	return newJWTService(jwt.SigningMethodRS256, signingKey, keyID, verificationKeys), nil
+}
+
+// parseRSAPublicKeys parses every PEM block of data as an RSA public key
+func parseRSAPublicKeys(data string) ([]*rsa.PublicKey, error) {
+	var keys []*rsa.PublicKey
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		key, err := jwt.ParseRSAPublicKeyFromPEM(pem.EncodeToMemory(block))
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous RS256 public key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// PublicKeyID returns the kid header value identifying tokens signed with the key pair of publicKey
+func PublicKeyID(publicKey *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode RS256 public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:8]), nil
+}
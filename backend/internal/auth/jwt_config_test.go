@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rsaKeyPEM generates an RSA key pair and returns it PEM-encoded
+func rsaKeyPEM(t *testing.T) (privatePEM, publicPEM string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	privatePEM = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	publicPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	return privatePEM, publicPEM
+}
+
+func TestNewJWTServiceWithConfig_RS256(t *testing.T) {
+	privatePEM, publicPEM := rsaKeyPEM(t)
+	issuer, err := NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256, PrivateKeyPEM: privatePEM})
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	pair, err := issuer.GenerateTokenPair(userID)
+	require.NoError(t, err)
+
+	token, _, err := jwt.NewParser().ParseUnverified(pair.AccessToken, &JWTClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmRS256, token.Method.Alg())
+
+	// A resource server with only the public key verifies but cannot issue tokens
+	verifier, err := NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256, PublicKeyPEM: publicPEM})
+	require.NoError(t, err)
+
+	claims, err := verifier.ValidateAccessToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), claims.UserID)
+
+	_, err = verifier.GenerateTokenPair(userID)
+	assert.Error(t, err)
+
+	// Tokens from another key pair are rejected
+	otherPEM, _ := rsaKeyPEM(t)
+	other, err := NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256, PrivateKeyPEM: otherPEM})
+	require.NoError(t, err)
+	_, err = other.ValidateAccessToken(pair.AccessToken)
+	assert.Error(t, err)
+}
+
+func TestNewJWTServiceWithConfig_RS256Rotation(t *testing.T) {
+	oldPrivate, oldPublic := rsaKeyPEM(t)
+	newPrivate, _ := rsaKeyPEM(t)
+
+	old, err := NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256, PrivateKeyPEM: oldPrivate})
+	require.NoError(t, err)
+	pair, err := old.GenerateTokenPair(uuid.New())
+	require.NoError(t, err)
+
+	rotated, err := NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256, PrivateKeyPEM: newPrivate, PreviousPublicKeysPEM: oldPublic})
+	require.NoError(t, err)
+	_, err = rotated.ValidateAccessToken(pair.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestNewJWTServiceWithConfig_RejectsAlgorithmConfusion(t *testing.T) {
+	_, publicPEM := rsaKeyPEM(t)
+	verifier, err := NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256, PublicKeyPEM: publicPEM})
+	require.NoError(t, err)
+
+	// An HS256 token "signed" with the public key as the secret must not validate
+	claims := &JWTClaims{UserID: uuid.New().String(), TokenType: "access"}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(publicPEM))
+	require.NoError(t, err)
+
+	_, err = verifier.ValidateAccessToken(forged)
+	assert.Error(t, err)
+}
+
+func TestNewJWTServiceWithConfig_Defaults(t *testing.T) {
+	service, err := NewJWTServiceWithConfig(JWTConfig{Secret: newSecret})
+	require.NoError(t, err)
+	pair, err := service.GenerateTokenPair(uuid.New())
+	require.NoError(t, err)
+
+	// HS256 stays the default and interoperates with NewJWTService
+	_, err = NewJWTService(newSecret).ValidateAccessToken(pair.AccessToken)
+	assert.NoError(t, err)
+
+	_, err = NewJWTServiceWithConfig(JWTConfig{Algorithm: "ES256"})
+	assert.Error(t, err)
+	_, err = NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256})
+	assert.Error(t, err)
+
+	privatePEM, _ := rsaKeyPEM(t)
+	_, otherPublic := rsaKeyPEM(t)
+	_, err = NewJWTServiceWithConfig(JWTConfig{Algorithm: AlgorithmRS256, PrivateKeyPEM: privatePEM, PublicKeyPEM: otherPublic})
+	assert.ErrorContains(t, err, "does not match")
+}
+
+func TestJWTConfigFromEnv(t *testing.T) {
+	_, publicPEM := rsaKeyPEM(t)
+	t.Setenv("JWT_ALGORITHM", "rs256")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_PREVIOUS_SECRETS", "")
+	t.Setenv("JWT_PRIVATE_KEY", "")
+	t.Setenv("JWT_PUBLIC_KEY", strings.ReplaceAll(publicPEM, "\n", `\n`))
+	t.Setenv("JWT_PREVIOUS_PUBLIC_KEYS", "")
+
+	config := JWTConfigFromEnv()
+	assert.Equal(t, AlgorithmRS256, config.Algorithm)
+	assert.Equal(t, publicPEM, config.PublicKeyPEM, "escaped newlines are restored")
+
+	_, err := NewJWTServiceWithConfig(config)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldSecret = "old-secret-key-at-least-32-characters-long"
+	newSecret = "new-secret-key-at-least-32-characters-long"
+)
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	userID := uuid.New()
+
+	oldPair, err := NewJWTService(oldSecret).GenerateTokenPair(userID)
+	require.NoError(t, err)
+
+	rotated := NewJWTService(newSecret, oldSecret)
+
+	// Tokens signed before the rotation still validate and refresh
+	claims, err := rotated.ValidateAccessToken(oldPair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userID.String(), claims.UserID)
+
+	newPair, err := rotated.RefreshAccessToken(oldPair.RefreshToken)
+	require.NoError(t, err)
+
+	// New tokens are signed with the new key and name it
+	token, _, err := jwt.NewParser().ParseUnverified(newPair.AccessToken, &JWTClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, KeyID(newSecret), token.Header["kid"])
+
+	// Once the old key is dropped its tokens are rejected
+	_, err = NewJWTService(newSecret).ValidateAccessToken(oldPair.AccessToken)
+	assert.ErrorContains(t, err, "unknown signing key")
+
+	_, err = NewJWTService(newSecret).ValidateAccessToken(newPair.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTService_TokensWithoutKeyID(t *testing.T) {
+	userID := uuid.New()
+	claims := &JWTClaims{UserID: userID.String(), TokenType: "access"}
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(oldSecret))
+	require.NoError(t, err)
+
+	_, err = NewJWTService(newSecret, oldSecret).ValidateAccessToken(legacy)
+	assert.NoError(t, err, "tokens issued before key IDs are checked against every key")
+
+	_, err = NewJWTService(newSecret).ValidateAccessToken(legacy)
+	assert.Error(t, err)
+}
+
+func TestJWTService_ForgedKeyID(t *testing.T) {
+	claims := &JWTClaims{UserID: uuid.New().String(), TokenType: "access"}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = KeyID(oldSecret)
+	forged, err := token.SignedString([]byte("attacker-chosen-secret"))
+	require.NoError(t, err)
+
+	_, err = NewJWTService(newSecret, oldSecret).ValidateAccessToken(forged)
+	assert.Error(t, err)
+}
+
+func TestPreviousSecretsFromEnv(t *testing.T) {
+	t.Setenv("JWT_PREVIOUS_SECRETS", "")
+	assert.Empty(t, PreviousSecretsFromEnv())
+
+	t.Setenv("JWT_PREVIOUS_SECRETS", " first , ,second")
+	assert.Equal(t, []string{"first", "second"}, PreviousSecretsFromEnv())
+}
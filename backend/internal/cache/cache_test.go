@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+
+	_, err := c.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrMiss)
+
+	require.NoError(t, c.Set(ctx, "key", []byte("value"), time.Minute))
+	value, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	now = now.Add(2 * time.Minute)
+	_, err = c.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrMiss)
+
+	require.NoError(t, c.Set(ctx, "other", []byte("x"), time.Minute))
+	require.NoError(t, c.Delete(ctx, "other"))
+	_, err = c.Get(ctx, "other")
+	assert.ErrorIs(t, err, ErrMiss)
+}
+
+func TestMemoryCache_SetIfAbsent(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+
+	stored, err := c.SetIfAbsent(ctx, "key", []byte("first"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, stored)
+
+	stored, err = c.SetIfAbsent(ctx, "key", []byte("second"), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, stored)
+	value, _ := c.Get(ctx, "key")
+	assert.Equal(t, []byte("first"), value)
+
+	now = now.Add(2 * time.Minute)
+	stored, err = c.SetIfAbsent(ctx, "key", []byte("third"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, stored, "expired entries can be replaced")
+}
+
+func TestMemoryCache_SweepsUnreadExpiredEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, c.Set(ctx, key, []byte("x"), time.Second))
+	}
+	require.NoError(t, c.Set(ctx, "forever", []byte("x"), 0))
+	assert.Len(t, c.entries, 4)
+
+	// Writes within the sweep interval don't scan the cache
+	now = now.Add(30 * time.Second)
+	require.NoError(t, c.Set(ctx, "d", []byte("x"), time.Hour))
+	assert.Len(t, c.entries, 5)
+
+	// The next write after the interval drops the expired keys that were never read again
+	now = now.Add(memorySweepInterval)
+	_, err := c.SetIfAbsent(ctx, "e", []byte("x"), time.Hour)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"forever", "d", "e"}, keys(c.entries))
+}
+
+func keys(entries map[string]memoryEntry) []string {
+	var names []string
+	for name := range entries {
+		names = append(names, name)
+	}
+	return names
+}
@@ -38,6 +38,7 @@ func NewConnection() (*sql.DB, error) {
 
 		// Test the connection
 		if err := db.Ping(); err != nil {
+			db.Close()
 			return nil, fmt.Errorf("failed to ping database: %w", err)
 		}
 
@@ -87,6 +88,7 @@ func NewConnection() (*sql.DB, error) {
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
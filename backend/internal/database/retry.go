@@ -0,0 +1,44 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Startup connection retry defaults, enough to wait out a database container that is still starting
+const (
+	DefaultConnectAttempts  = 10
+	DefaultConnectBaseDelay = 500 * time.Millisecond
+	maxConnectDelay         = 10 * time.Second
+)
+
+// NewConnectionWithRetry calls NewConnection until it succeeds or maxAttempts attempts have failed,
+// waiting baseDelay before the first retry and doubling the wait, up to 10s, before each further one
+func NewConnectionWithRetry(maxAttempts int, baseDelay time.Duration) (*sql.DB, error) {
+	return connectWithRetry(NewConnection, maxAttempts, baseDelay, time.Sleep)
+}
+
+func connectWithRetry(connect func() (*sql.DB, error), maxAttempts int, baseDelay time.Duration, sleep func(time.Duration)) (*sql.DB, error) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	delay := baseDelay
+	for attempt := 1; ; attempt++ {
+		db, err := connect()
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= maxAttempts {
+			return nil, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		log.Printf("Database not reachable (attempt %d/%d), retrying in %s: %v", attempt, maxAttempts, delay, err)
+		sleep(delay)
+		delay *= 2
+		if delay > maxConnectDelay {
+			delay = maxConnectDelay
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectWithRetry(t *testing.T) {
+	refused := errors.New("failed to ping database: connection refused")
+
+	t.Run("database that comes up late is connected to", func(t *testing.T) {
+		var delays []time.Duration
+		want := &sql.DB{}
+		calls := 0
+		db, err := connectWithRetry(func() (*sql.DB, error) {
+			calls++
+			if calls < 3 {
+				return nil, refused
+			}
+			return want, nil
+		}, 5, time.Second, func(d time.Duration) { delays = append(delays, d) })
+
+		require.NoError(t, err)
+		assert.Same(t, want, db)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+	})
+
+	t.Run("attempts are bounded and the backoff is capped", func(t *testing.T) {
+		var delays []time.Duration
+		calls := 0
+		_, err := connectWithRetry(func() (*sql.DB, error) {
+			calls++
+			return nil, refused
+		}, 6, 2*time.Second, func(d time.Duration) { delays = append(delays, d) })
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, refused)
+		assert.Contains(t, err.Error(), "6 attempts")
+		assert.Equal(t, 6, calls)
+		assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}, delays)
+	})
+
+	t.Run("a single attempt never sleeps", func(t *testing.T) {
+		_, err := connectWithRetry(func() (*sql.DB, error) {
+			return nil, refused
+		}, 0, time.Second, func(time.Duration) { t.Fatal("unexpected sleep") })
+		require.ErrorIs(t, err, refused)
+	})
+}
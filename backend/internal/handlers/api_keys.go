@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/saurabh22suman/oreo.io/internal/auth"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+)
+
+// APIKeyHandlers contains handlers for managing project API keys
+type APIKeyHandlers struct {
+	apiKeyRepo  *repository.APIKeyRepository
+	projectRepo *repository.ProjectRepository
+}
+
+// NewAPIKeyHandlers creates new API key handlers
+func NewAPIKeyHandlers(apiKeyRepo *repository.APIKeyRepository, projectRepo *repository.ProjectRepository) *APIKeyHandlers {
+	return &APIKeyHandlers{apiKeyRepo: apiKeyRepo, projectRepo: projectRepo}
+}
+
+// requireProjectOwner parses the project ID and checks the user owns the project. It writes the
+// error response and returns false otherwise; API keys are never project owners.
+func (h *APIKeyHandlers) requireProjectOwner(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	projectID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	isOwner, err := h.projectRepo.Exists(projectID, userUUID)
+	if err != nil {
+		log.Printf("Error checking project ownership: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if !isOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can manage API keys"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return projectID, userUUID, true
+}
+
+// ListAPIKeys returns a project's API keys without their secret values
+func (h *APIKeyHandlers) ListAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, _, ok := h.requireProjectOwner(c)
+		if !ok {
+			return
+		}
+
+		keys, err := h.apiKeyRepo.ListByProject(projectID)
+		if err != nil {
+			log.Printf("Error listing API keys: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list API keys"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+	}
+}
+
+// CreateAPIKey creates a project API key; its plaintext value is only returned in this response
+func (h *APIKeyHandlers) CreateAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateAPIKeyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		projectID, userUUID, ok := h.requireProjectOwner(c)
+		if !ok {
+			return
+		}
+
+		rawKey, err := auth.GenerateAPIKey()
+		if err != nil {
+			log.Printf("Error generating API key: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+			return
+		}
+
+		key := &models.APIKey{
+			ID:        uuid.New(),
+			ProjectID: projectID,
+			Name:      req.Name,
+			KeyPrefix: rawKey[:auth.APIKeyDisplayPrefixLength],
+			KeyHash:   auth.HashAPIKey(rawKey),
+			Scopes:    req.Scopes,
+			Role:      &req.Role,
+			CreatedBy: userUUID,
+		}
+		if err := h.apiKeyRepo.Create(key); err != nil {
+			log.Printf("Error creating API key: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "API key created successfully; store it now, it won't be shown again",
+			"api_key": models.CreatedAPIKey{APIKey: *key, Key: rawKey},
+		})
+	}
+}
+
+// RevokeAPIKey revokes a project API key, which stops it authenticating immediately
+func (h *APIKeyHandlers) RevokeAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, _, ok := h.requireProjectOwner(c)
+		if !ok {
+			return
+		}
+
+		keyID, err := uuid.Parse(c.Param("key_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+			return
+		}
+
+		if err := h.apiKeyRepo.Revoke(projectID, keyID); err != nil {
+			if errors.Is(err, repository.ErrAPIKeyNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "API key not found or already revoked"})
+				return
+			}
+			log.Printf("Error revoking API key: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+	}
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -18,23 +22,48 @@ import (
 	"github.com/saurabh22suman/oreo.io/internal/models"
 	"github.com/saurabh22suman/oreo.io/internal/repository"
 	"github.com/saurabh22suman/oreo.io/internal/services"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
 )
 
 type DataSubmissionHandlers struct {
 	submissionRepo  *repository.DataSubmissionRepository
 	schemaRepo      *repository.SchemaRepository
 	validationSvc   *services.ValidationService
+	dedup           *services.SubmissionDeduplicator
+	summarySvc      *services.SubmissionSummaryService
+	withdrawSvc     *services.SubmissionWithdrawService
+	commentSvc      *services.SubmissionCommentService
+	notifications   *services.NotificationService
+	directUploads   *services.DirectUploadService
+	files           storage.Storage
+	maxRules        int
+	dataPages       *services.DataPageCache
 }
 
 func NewDataSubmissionHandlers(
 	submissionRepo *repository.DataSubmissionRepository,
 	schemaRepo *repository.SchemaRepository,
 	validationSvc *services.ValidationService,
+	dedup *services.SubmissionDeduplicator,
+	files storage.Storage,
+	maxRules int,
+	maxDirectUploadSize int64,
+	notifications *services.NotificationService,
+	dataPages *services.DataPageCache,
 ) *DataSubmissionHandlers {
 	return &DataSubmissionHandlers{
 		submissionRepo: submissionRepo,
 		schemaRepo:     schemaRepo,
 		validationSvc:  validationSvc,
+		dedup:          dedup,
+		summarySvc:     services.NewSubmissionSummaryService(submissionRepo),
+		withdrawSvc:    services.NewSubmissionWithdrawService(submissionRepo, files),
+		commentSvc:     services.NewSubmissionCommentService(submissionRepo),
+		directUploads:  services.NewDirectUploadService(files, services.DefaultDirectUploadExpiry, maxDirectUploadSize),
+		files:          files,
+		maxRules:       maxRules,
+		notifications:  notifications,
+		dataPages:      dataPages,
 	}
 }
 
@@ -91,6 +120,14 @@ func (h *DataSubmissionHandlers) SubmitDataForAppend() gin.HandlerFunc {
 			return
 		}
 
+		// Confirm the content is really CSV before saving
+		if err := verifyFileContent(file, header.Filename); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid file content: %v", err),
+			})
+			return
+		}
+
 		// Validate file size (10MB limit for append operations)
 		const maxFileSize = 10 * 1024 * 1024 // 10MB
 		if header.Size > maxFileSize {
@@ -100,6 +137,38 @@ func (h *DataSubmissionHandlers) SubmitDataForAppend() gin.HandlerFunc {
 			return
 		}
 
+		// Optionally validate against a named schema variant instead of the dataset's default
+		schemaName, ok := h.resolveSchemaName(c, datasetID, c.PostForm("schema_name"))
+		if !ok {
+			return
+		}
+
+		mode, ok := h.resolveSubmissionMode(c, datasetID, userUUID, schemaName, c.PostForm("mode"))
+		if !ok {
+			return
+		}
+
+		columnMapping, ok := parseColumnMapping(c, c.PostForm("column_mapping"))
+		if !ok {
+			return
+		}
+
+		expectedRows, ok := parseExpectedRows(c, c.PostForm("expected_rows"))
+		if !ok {
+			return
+		}
+
+		// Quick mode validates a sample for fast feedback without creating a submission
+		switch c.PostForm("validation_mode") {
+		case "", "full":
+		case "quick":
+			h.quickValidate(c, file, datasetID, schemaName, columnMapping)
+			return
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "validation_mode must be 'full' or 'quick'"})
+			return
+		}
+
 		// Create submission record
 		submission := &models.DataSubmission{
 			ID:          uuid.New(),
@@ -111,74 +180,457 @@ func (h *DataSubmissionHandlers) SubmitDataForAppend() gin.HandlerFunc {
 			SubmittedAt: time.Now(),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
+			SchemaName:    schemaName,
+			Mode:          mode,
+			ColumnMapping: columnMapping,
 		}
 
-		// Save file to submissions directory
-		submissionDir := "submissions"
-		if err := os.MkdirAll(submissionDir, 0755); err != nil {
-			log.Printf("Error creating submission directory: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create submission directory"})
+		// Save file to the submissions prefix of the configured storage
+		filename := fmt.Sprintf("%s_%s", submission.ID.String(), header.Filename)
+		fileKey := storage.Key("submissions", filename)
+		submission.FilePath = fileKey
+
+		// Hash the content while saving so identical re-submissions can be detected
+		hasher := sha256.New()
+		if err := h.files.Put(c.Request.Context(), fileKey, io.TeeReader(file, hasher)); err != nil {
+			log.Printf("Error storing file: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
 			return
 		}
+		fileHash := hex.EncodeToString(hasher.Sum(nil))
+		submission.FileHash = &fileHash
 
-		filename := fmt.Sprintf("%s_%s", submission.ID.String(), header.Filename)
-		filepath := filepath.Join(submissionDir, filename)
-		submission.FilePath = filepath
+		h.createSubmission(c, submission, expectedRows)
+	}
+}
+
+// PresignAppendUpload returns a pre-signed URL for uploading a large append file straight to
+// blob storage; the client reports the finished upload to CompleteAppendUpload
+func (h *DataSubmissionHandlers) PresignAppendUpload() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
 
-		// Save file to disk
-		out, err := os.Create(filepath)
+		datasetID, err := uuid.Parse(c.Param("dataset_id"))
 		if err != nil {
-			log.Printf("Error creating file: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
 			return
 		}
-		defer out.Close()
 
-		_, err = io.Copy(out, file)
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(datasetID, userUUID)
 		if err != nil {
-			log.Printf("Error copying file: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+			log.Printf("Error checking dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to submit data to this dataset"})
+			return
+		}
+
+		var req models.PresignAppendUploadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if !isValidCSVFile(req.FileName) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid file type. Only CSV files are supported for data append",
+			})
+			return
+		}
+
+		upload, err := h.directUploads.Presign(c.Request.Context(), datasetID, userUUID, req.FileName)
+		switch {
+		case errors.Is(err, services.ErrDirectUploadUnsupported):
+			c.JSON(http.StatusNotImplemented, gin.H{"error": "Direct uploads require S3-compatible file storage"})
+			return
+		case errors.Is(err, services.ErrInvalidUploadFileName):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File name must not contain a path"})
+			return
+		case err != nil:
+			log.Printf("Error pre-signing upload: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prepare upload"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Upload the file with the given method and URL, then complete the upload",
+			"upload":  upload,
+		})
+	}
+}
+
+// CompleteAppendUpload validates a file uploaded through a pre-signed URL and creates its submission
+func (h *DataSubmissionHandlers) CompleteAppendUpload() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("dataset_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
 			return
 		}
 
-		// Validate the data against schema and business rules
-		validationResult, stagingData, err := h.validationSvc.ValidateDataSubmission(filepath, datasetID)
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(datasetID, userUUID)
 		if err != nil {
-			log.Printf("Error validating submission: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate submission"})
+			log.Printf("Error checking dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to submit data to this dataset"})
 			return
 		}
 
-		// Store validation results
-		validationJSON, _ := json.Marshal(validationResult)
-		validationRawMessage := json.RawMessage(validationJSON)
-		submission.ValidationResults = &validationRawMessage
-		submission.RowCount = validationResult.TotalRows
+		var req models.CompleteAppendUploadRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if !isValidCSVFile(req.FileName) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid file type. Only CSV files are supported for data append",
+			})
+			return
+		}
+		if req.ExpectedRows != nil && *req.ExpectedRows < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expected_rows must not be negative"})
+			return
+		}
 
-		// Save submission to database
-		if err := h.submissionRepo.CreateSubmission(submission); err != nil {
-			log.Printf("Error creating submission: %v", err)
-			os.Remove(filepath) // Clean up uploaded file
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save submission"})
+		schemaName, ok := h.resolveSchemaName(c, datasetID, req.SchemaName)
+		if !ok {
 			return
 		}
 
-		// Save staging data
-		for _, stagingRow := range stagingData {
-			stagingRow.SubmissionID = submission.ID
+		mode, ok := h.resolveSubmissionMode(c, datasetID, userUUID, schemaName, req.Mode)
+		if !ok {
+			return
 		}
 
-		if err := h.submissionRepo.CreateStagingData(stagingData); err != nil {
-			log.Printf("Error saving staging data: %v", err)
-			// Don't fail the entire submission, but log the error
+		// The upload ID becomes the submission ID, so an upload can only be completed once
+		if _, err := h.submissionRepo.GetSubmission(req.UploadID); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "Upload has already been completed"})
+			return
+		} else if !errors.Is(err, repository.ErrSubmissionNotFound) {
+			log.Printf("Error checking for completed upload: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete upload"})
+			return
 		}
 
-		c.JSON(http.StatusCreated, gin.H{
-			"message":           "Data submission created successfully",
-			"submission":        submission,
-			"validation_result": validationResult,
+		completed, err := h.directUploads.Complete(c.Request.Context(), datasetID, userUUID, req.UploadID, req.FileName)
+		var tooLargeErr *services.DirectUploadTooLargeError
+		switch {
+		case errors.As(err, &tooLargeErr):
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("File size exceeds %dMB limit for direct uploads", tooLargeErr.Limit>>20),
+			})
+			return
+		case errors.Is(err, services.ErrDirectUploadNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "No file has been uploaded for this upload ID"})
+			return
+		case errors.Is(err, services.ErrInvalidUploadFileName):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File name must not contain a path"})
+			return
+		case err != nil:
+			log.Printf("Error completing upload: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete upload"})
+			return
+		}
+
+		// Confirm the content is really CSV before validating it
+		if err := verifyFileContent(bytes.NewReader(completed.Head), req.FileName); err != nil {
+			h.removeFile(completed.Key)
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid file content: %v", err),
+			})
+			return
+		}
+
+		now := time.Now()
+		h.createSubmission(c, &models.DataSubmission{
+			ID:          req.UploadID,
+			DatasetID:   datasetID,
+			SubmittedBy: userUUID,
+			FileName:    strings.TrimSpace(req.FileName),
+			FilePath:    completed.Key,
+			FileSize:    completed.Size,
+			FileHash:    &completed.Hash,
+			Status:      models.DataSubmissionStatusPending,
+			SubmittedAt: now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+			SchemaName:    schemaName,
+			Mode:          mode,
+			ColumnMapping: req.ColumnMapping,
+		}, req.ExpectedRows)
+	}
+}
+
+// resolveSchemaName checks that a requested schema variant exists; an empty name selects the
+// dataset's default schema. It responds with an error and returns false when the name is unknown.
+func (h *DataSubmissionHandlers) resolveSchemaName(c *gin.Context, datasetID uuid.UUID, requested string) (*string, bool) {
+	name := strings.TrimSpace(requested)
+	if name == "" {
+		return nil, true
+	}
+	if _, err := h.schemaRepo.GetSchemaByName(datasetID, name); err != nil {
+		if errors.Is(err, repository.ErrSchemaNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Schema variant '%s' not found for this dataset", name)})
+			return nil, false
+		}
+		log.Printf("Error loading schema variant: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load schema"})
+		return nil, false
+	}
+	return &name, true
+}
+
+// resolveSubmissionMode checks a requested submission mode; an empty mode appends. Replacing or
+// upserting a dataset's rows needs edit access to the dataset, and upserts need unique fields in
+// the submission's schema to match rows on. It responds with an error and returns false when the
+// mode is unknown or not allowed.
+func (h *DataSubmissionHandlers) resolveSubmissionMode(c *gin.Context, datasetID, userID uuid.UUID, schemaName *string, requested string) (string, bool) {
+	mode := strings.TrimSpace(requested)
+	if mode == "" {
+		return models.SubmissionModeAppend, true
+	}
+	if !models.IsValidSubmissionMode(mode) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("mode must be '%s', '%s' or '%s'", models.SubmissionModeAppend, models.SubmissionModeReplace, models.SubmissionModeUpsert),
+		})
+		return "", false
+	}
+	if mode == models.SubmissionModeAppend {
+		return mode, true
+	}
+
+	canEdit, err := h.submissionRepo.CheckDatasetEditAccess(datasetID, userID)
+	if err != nil {
+		log.Printf("Error checking dataset edit access: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+		return "", false
+	}
+	if !canEdit {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Only the project owner or editors can submit data in %s mode", mode)})
+		return "", false
+	}
+
+	if mode == models.SubmissionModeUpsert {
+		schema, err := h.submissionSchema(datasetID, schemaName)
+		if err != nil && !errors.Is(err, repository.ErrSchemaNotFound) {
+			log.Printf("Error loading schema for upsert: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load schema"})
+			return "", false
+		}
+		if schema == nil || len(schema.UniqueFieldNames()) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Upsert needs a schema with at least one unique field to match rows on"})
+			return "", false
+		}
+	}
+	return mode, true
+}
+
+// submissionSchema loads the named schema variant of a dataset, or its default schema when name is nil
+func (h *DataSubmissionHandlers) submissionSchema(datasetID uuid.UUID, name *string) (*models.DatasetSchema, error) {
+	if name != nil {
+		return h.schemaRepo.GetSchemaByName(datasetID, *name)
+	}
+	return h.schemaRepo.GetSchemaByDatasetID(datasetID)
+}
+
+// parseColumnMapping reads the optional column_mapping form field, a JSON object mapping file
+// headers to schema field names. It responds with an error and returns false when it isn't one.
+func parseColumnMapping(c *gin.Context, raw string) (models.ColumnMapping, bool) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, true
+	}
+
+	var mapping models.ColumnMapping
+	if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "column_mapping must be a JSON object of file headers to schema field names"})
+		return nil, false
+	}
+	return mapping, true
+}
+
+// parseExpectedRows reads the optional expected_rows parameter. It responds with an error and
+// returns false when the value is not a non-negative number.
+func parseExpectedRows(c *gin.Context, raw string) (*int, bool) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, true
+	}
+
+	expected, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || expected < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expected_rows must be a non-negative number"})
+		return nil, false
+	}
+	return &expected, true
+}
+
+// createSubmission validates the stored file of a submission, which must have its FilePath and
+// FileHash set, and records the submission with its staging rows. An identical file submitted
+// recently returns the earlier submission instead. When expectedRows is set and the file parses
+// into a different number of rows, the response warns that the file may be malformed.
+func (h *DataSubmissionHandlers) createSubmission(c *gin.Context, submission *models.DataSubmission, expectedRows *int) {
+	datasetID, fileKey := submission.DatasetID, submission.FilePath
+
+	// Return the existing submission for an identical file submitted within the dedup window
+	existing, err := h.dedup.FindDuplicate(submission)
+	if err != nil {
+		log.Printf("Error checking for duplicate submission: %v", err)
+	} else if existing != nil {
+		h.respondDuplicateSubmission(c, existing, fileKey)
+		return
+	}
+
+	// Validate the data against schema and business rules
+	validationResult, stagingData, err := h.validationSvc.ValidateDataSubmission(fileKey, datasetID, submission.SchemaVariant(), submission.ColumnMapping)
+	var mappingErr *services.ColumnMappingError
+	if errors.As(err, &mappingErr) {
+		h.removeFile(fileKey)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid column mapping", "problems": mappingErr.Problems})
+		return
+	}
+	var rowLimitErr *services.RowLimitError
+	if errors.As(err, &rowLimitErr) {
+		h.removeFile(fileKey)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     rowLimitErr.Error(),
+			"row_count": rowLimitErr.Rows,
+			"max_rows":  rowLimitErr.Limit,
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Error validating submission: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate submission"})
+		return
+	}
+
+	// Store validation results
+	validationJSON, _ := json.Marshal(validationResult)
+	validationRawMessage := json.RawMessage(validationJSON)
+	submission.ValidationResults = &validationRawMessage
+	submission.RowCount = validationResult.TotalRows
+
+	// Save submission to database; an identical submission that arrived while this one was being
+	// validated is returned instead
+	if h.dedup.Enabled() {
+		existing, err = h.dedup.Create(submission)
+	} else {
+		err = h.submissionRepo.CreateSubmission(submission)
+	}
+	if err != nil {
+		log.Printf("Error creating submission: %v", err)
+		h.removeFile(fileKey) // Clean up uploaded file
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save submission"})
+		return
+	}
+	if existing != nil {
+		h.respondDuplicateSubmission(c, existing, fileKey)
+		return
+	}
+
+	// Save staging data
+	for _, stagingRow := range stagingData {
+		stagingRow.SubmissionID = submission.ID
+	}
+
+	if err := h.submissionRepo.CreateStagingData(stagingData); err != nil {
+		log.Printf("Error saving staging data: %v", err)
+		// Don't fail the entire submission, but log the error
+	}
+
+	warnings := []string{}
+	if expectedRows != nil {
+		if warning := services.ExpectedRowsWarning(*expectedRows, submission.RowCount); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":           "Data submission created successfully",
+		"submission":        submission,
+		"validation_result": validationResult,
+		"warnings":          warnings,
+	})
+}
+
+// respondDuplicateSubmission answers a repeated submission with the earlier one, dropping the new file
+func (h *DataSubmissionHandlers) respondDuplicateSubmission(c *gin.Context, existing *models.DataSubmission, fileKey string) {
+	h.removeFile(fileKey)
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Identical submission already received",
+		"submission": existing,
+		"duplicate":  true,
+	})
+}
+
+// quickValidate responds with validation results for a sample of the uploaded file
+func (h *DataSubmissionHandlers) quickValidate(c *gin.Context, file io.Reader, datasetID uuid.UUID, schemaName *string, columnMapping models.ColumnMapping) {
+	opts, err := services.ParseSampleOptions(c.PostForm("sample_size"), c.PostForm("sample_mode"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	variant := ""
+	if schemaName != nil {
+		variant = *schemaName
+	}
+
+	result, err := h.validationSvc.QuickValidate(file, datasetID, variant, columnMapping, opts)
+	var mappingErr *services.ColumnMappingError
+	if errors.As(err, &mappingErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid column mapping", "problems": mappingErr.Problems})
+		return
+	}
+	var rowLimitErr *services.RowLimitError
+	if errors.As(err, &rowLimitErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     rowLimitErr.Error(),
+			"row_count": rowLimitErr.Rows,
+			"max_rows":  rowLimitErr.Limit,
 		})
+		return
+	}
+	if err != nil {
+		log.Printf("Error quick-validating submission: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate submission"})
+		return
+	}
+
+	message := "Validation completed on every row"
+	if result.Sampled {
+		message = fmt.Sprintf("Validation ran on a %s sample of %d of %d rows; invalid counts are estimates", result.SampleMode, result.SampleSize, result.FileRows)
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          message,
+		"quick_validation": result,
+	})
 }
 
 // GetDataSubmissions retrieves submissions for a dataset
@@ -291,16 +743,34 @@ func (h *DataSubmissionHandlers) GetSubmissionDetails() gin.HandlerFunc {
 		}
 
 		// Get staging data
-		stagingData, err := h.submissionRepo.GetStagingData(submissionID, pageSize, offset)
+		stagingData, skippedRows, err := h.submissionRepo.GetStagingData(submissionID, pageSize, offset)
 		if err != nil {
 			log.Printf("Error getting staging data: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve staging data"})
 			return
 		}
 
+		// Show reviewers where the rows would land while the submission can still be applied;
+		// upserted rows land wherever their keys match, which is only known once applied
+		var placement *models.ApplyPlacementPreview
+		switch submission.Status {
+		case models.DataSubmissionStatusPending, models.DataSubmissionStatusUnderReview, models.DataSubmissionStatusApproved:
+			if submission.Mode == models.SubmissionModeUpsert {
+				break
+			}
+			placement, err = h.submissionRepo.PreviewApplyPlacement(submissionID, submission.DatasetID, submission.Mode)
+			if err != nil {
+				log.Printf("Error previewing apply placement: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview data placement"})
+				return
+			}
+		}
+
 		c.JSON(http.StatusOK, gin.H{
-			"submission":   submission,
-			"staging_data": stagingData,
+			"submission":        submission,
+			"staging_data":      stagingData,
+			"skipped_rows":      skippedRows,
+			"placement_preview": placement,
 			"pagination": gin.H{
 				"page":      page,
 				"page_size": pageSize,
@@ -310,50 +780,55 @@ func (h *DataSubmissionHandlers) GetSubmissionDetails() gin.HandlerFunc {
 	}
 }
 
-// UpdateStagingData handles live editing of staging data
-func (h *DataSubmissionHandlers) UpdateStagingData() gin.HandlerFunc {
+// GetSubmissionSummary returns submission metadata and its validation result without loading staging rows
+func (h *DataSubmissionHandlers) GetSubmissionSummary() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get staging data ID from URL params
-		stagingIDStr := c.Param("staging_id")
-		stagingID, err := uuid.Parse(stagingIDStr)
+		submissionID, err := uuid.Parse(c.Param("submission_id"))
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staging data ID"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
 			return
 		}
 
-		var updateRequest struct {
-			Data map[string]interface{} `json:"data" binding:"required"`
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
 		}
 
-		if err := c.ShouldBindJSON(&updateRequest); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 			return
 		}
 
-		// TODO: Add validation logic here to validate the updated data
-		// For now, we'll assume it's valid
-		dataJSON, _ := json.Marshal(updateRequest.Data)
-		validationErrors := json.RawMessage("[]")
-
-		err = h.submissionRepo.UpdateStagingDataRow(stagingID, dataJSON, models.ValidationStatusValid, &validationErrors)
+		summary, err := h.summarySvc.GetSummary(submissionID, userUUID)
 		if err != nil {
-			log.Printf("Error updating staging data: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update staging data"})
+			switch {
+			case errors.Is(err, repository.ErrSubmissionNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+			case errors.Is(err, services.ErrSubmissionAccessDenied):
+				c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this submission"})
+			default:
+				log.Printf("Error getting submission summary: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submission summary"})
+			}
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Staging data updated successfully",
-		})
+		c.JSON(http.StatusOK, summary)
 	}
 }
 
-// Admin endpoints
-
-// GetPendingSubmissions retrieves all pending submissions for admin review
-func (h *DataSubmissionHandlers) GetPendingSubmissions() gin.HandlerFunc {
+// VerifySubmission re-reads a submission's stored file and reports whether its SHA-256 and row
+// count still match those recorded at submission, and the optional expected_rows query parameter
+func (h *DataSubmissionHandlers) VerifySubmission() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get user ID and check admin privileges
+		submissionID, err := uuid.Parse(c.Param("submission_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+			return
+		}
+
 		userID, exists := c.Get("user_id")
 		if !exists {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
@@ -366,30 +841,474 @@ func (h *DataSubmissionHandlers) GetPendingSubmissions() gin.HandlerFunc {
 			return
 		}
 
-		// Check if user is admin
-		isAdmin, err := h.submissionRepo.IsUserAdmin(userUUID)
-		if err != nil {
-			log.Printf("Error checking admin status: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify admin status"})
+		expectedRows, ok := parseExpectedRows(c, c.Query("expected_rows"))
+		if !ok {
 			return
 		}
 
-		if !isAdmin {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
+		submission, err := h.submissionRepo.GetSubmission(submissionID)
+		if errors.Is(err, repository.ErrSubmissionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
 			return
 		}
-
-		submissions, err := h.submissionRepo.GetPendingSubmissions()
 		if err != nil {
-			log.Printf("Error getting pending submissions: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pending submissions"})
+			log.Printf("Error getting submission: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submission"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"submissions": submissions,
-			"count":       len(submissions),
-		})
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(submission.DatasetID, userUUID)
+		if err != nil {
+			log.Printf("Error checking dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this submission"})
+			return
+		}
+
+		report, err := h.validationSvc.VerifySubmissionFile(submission, expectedRows)
+		if errors.Is(err, services.ErrSubmissionFileMissing) {
+			c.JSON(http.StatusGone, gin.H{"error": "The submitted file is no longer stored"})
+			return
+		}
+		if err != nil {
+			log.Printf("Error verifying submission file: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify submission file"})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// WithdrawSubmission deletes a submission that has not been applied, along with its staging rows and file
+func (h *DataSubmissionHandlers) WithdrawSubmission() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		submissionID, err := uuid.Parse(c.Param("submission_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		if _, err := h.withdrawSvc.Withdraw(submissionID, userUUID); err != nil {
+			switch {
+			case errors.Is(err, repository.ErrSubmissionNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+			case errors.Is(err, services.ErrSubmissionWithdrawDenied):
+				c.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter or an admin can withdraw this submission"})
+			case errors.Is(err, services.ErrSubmissionAlreadyApplied):
+				c.JSON(http.StatusConflict, gin.H{"error": "Applied submissions cannot be withdrawn"})
+			default:
+				log.Printf("Error withdrawing submission: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw submission"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":       "Submission withdrawn",
+			"submission_id": submissionID,
+		})
+	}
+}
+
+// ListSubmissionComments returns the review thread of a submission to its submitter and reviewers
+func (h *DataSubmissionHandlers) ListSubmissionComments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		submissionID, err := uuid.Parse(c.Param("submission_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		comments, err := h.commentSvc.ListComments(submissionID, userUUID)
+		if err != nil {
+			h.respondCommentError(c, err, "Failed to retrieve comments")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"comments": comments})
+	}
+}
+
+// CreateSubmissionComment posts a comment on a submission, so a reviewer can ask for a fix and the
+// submitter can reply
+func (h *DataSubmissionHandlers) CreateSubmissionComment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		submissionID, err := uuid.Parse(c.Param("submission_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		var req models.CreateSubmissionCommentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		comment, err := h.commentSvc.AddComment(submissionID, userUUID, req.Body)
+		if err != nil {
+			h.respondCommentError(c, err, "Failed to post comment")
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Comment posted",
+			"comment": comment,
+		})
+	}
+}
+
+// respondCommentError maps submission comment errors to responses
+func (h *DataSubmissionHandlers) respondCommentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repository.ErrSubmissionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+	case errors.Is(err, services.ErrSubmissionCommentDenied):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the submitter and reviewers can see this submission's comments"})
+	case errors.Is(err, services.ErrEmptySubmissionComment):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment must not be empty"})
+	default:
+		log.Printf("Error handling submission comments: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// stagingExportBatchSize is how many staged rows are read per query while exporting
+const stagingExportBatchSize = 1000
+
+// ExportStagingData streams every staged row of a submission with its validation status
+func (h *DataSubmissionHandlers) ExportStagingData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		submissionID, err := uuid.Parse(c.Param("submission_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+			return
+		}
+
+		if format := c.DefaultQuery("format", "csv"); format != "csv" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported export format '%s'", format)})
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		submission, err := h.submissionRepo.GetSubmission(submissionID)
+		if err != nil {
+			if errors.Is(err, repository.ErrSubmissionNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+				return
+			}
+			log.Printf("Error getting submission: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submission"})
+			return
+		}
+
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(submission.DatasetID, userUUID)
+		if err != nil {
+			log.Printf("Error checking dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this submission"})
+			return
+		}
+
+		batch, skipped, err := h.submissionRepo.GetStagingData(submissionID, stagingExportBatchSize, 0)
+		if err != nil {
+			log.Printf("Error getting staging data: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve staging data"})
+			return
+		}
+
+		// Columns follow the submission's schema, or the staged keys when the schema is gone
+		schema, err := h.submissionSchema(submission.DatasetID, submission.SchemaName)
+		if err != nil && !errors.Is(err, repository.ErrSchemaNotFound) {
+			log.Printf("Error loading schema for staging export: %v", err)
+		}
+
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=submission_%s_staging.csv", submissionID))
+		c.Status(http.StatusOK)
+
+		exporter, err := services.NewStagingCSVExporter(c.Writer, services.StagingExportColumns(schema, batch))
+		if err != nil {
+			log.Printf("Error starting staging export: %v", err)
+			return
+		}
+
+		// Headers are already sent, so failures past this point can only be logged
+		offset := 0
+		for {
+			if err := exporter.WriteRows(batch); err != nil {
+				log.Printf("Error exporting staging data for submission %s: %v", submissionID, err)
+				return
+			}
+			c.Writer.Flush()
+
+			read := len(batch) + skipped
+			if read < stagingExportBatchSize {
+				return
+			}
+			offset += read
+
+			batch, skipped, err = h.submissionRepo.GetStagingData(submissionID, stagingExportBatchSize, offset)
+			if err != nil {
+				log.Printf("Error getting staging data for submission %s: %v", submissionID, err)
+				return
+			}
+		}
+	}
+}
+
+// GetSubmissionConflicts reports staged rows that would collide with existing dataset data on apply
+func (h *DataSubmissionHandlers) GetSubmissionConflicts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get submission ID from URL params
+		submissionIDStr := c.Param("submission_id")
+		submissionID, err := uuid.Parse(submissionIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+			return
+		}
+
+		// Get user ID and check access
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		submission, err := h.submissionRepo.GetSubmission(submissionID)
+		if err != nil {
+			log.Printf("Error getting submission: %v", err)
+			c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+			return
+		}
+
+		// Check if user has access to this dataset
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(submission.DatasetID, userUUID)
+		if err != nil {
+			log.Printf("Error checking dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this submission"})
+			return
+		}
+
+		// Only valid rows are applied, so only those can conflict
+		stagingData, err := h.submissionRepo.GetStagingDataByStatus(submissionID, models.ValidationStatusValid)
+		if err != nil {
+			log.Printf("Error getting staging data: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve staging data"})
+			return
+		}
+
+		report, err := h.validationSvc.DetectConflicts(submission.DatasetID, submission.SchemaVariant(), stagingData)
+		if err != nil {
+			log.Printf("Error detecting conflicts: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check for conflicts"})
+			return
+		}
+		report.SubmissionID = submissionID
+
+		c.JSON(http.StatusOK, gin.H{
+			"report":        report,
+			"has_conflicts": len(report.Conflicts) > 0,
+		})
+	}
+}
+
+// UpdateStagingData handles live editing of staging data
+func (h *DataSubmissionHandlers) UpdateStagingData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get staging data ID from URL params
+		stagingIDStr := c.Param("staging_id")
+		stagingID, err := uuid.Parse(stagingIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staging data ID"})
+			return
+		}
+
+		var updateRequest struct {
+			Data map[string]interface{} `json:"data" binding:"required"`
+		}
+
+		if err := c.ShouldBindJSON(&updateRequest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		// TODO: Add validation logic here to validate the updated data
+		// For now, we'll assume it's valid
+		dataJSON, _ := json.Marshal(updateRequest.Data)
+		validationErrors := json.RawMessage("[]")
+
+		err = h.submissionRepo.UpdateStagingDataRow(stagingID, dataJSON, models.ValidationStatusValid, &validationErrors)
+		if err != nil {
+			log.Printf("Error updating staging data: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update staging data"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Staging data updated successfully",
+		})
+	}
+}
+
+// Admin endpoints
+
+// GetPendingSubmissions retrieves all pending submissions for admin review
+func (h *DataSubmissionHandlers) GetPendingSubmissions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID and check admin privileges
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		// Check if user is admin
+		isAdmin, err := h.submissionRepo.IsUserAdmin(userUUID)
+		if err != nil {
+			log.Printf("Error checking admin status: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify admin status"})
+			return
+		}
+
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
+			return
+		}
+
+		submissions, err := h.submissionRepo.GetPendingSubmissions()
+		if err != nil {
+			log.Printf("Error getting pending submissions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve pending submissions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"submissions": submissions,
+			"count":       len(submissions),
+		})
+	}
+}
+
+// GetSubmissionsSummary returns submission counts by status, the oldest pending submission's age and the
+// datasets with the most pending submissions for the admin dashboard
+func (h *DataSubmissionHandlers) GetSubmissionsSummary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		isAdmin, err := h.submissionRepo.IsUserAdmin(userUUID)
+		if err != nil {
+			log.Printf("Error checking admin status: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify admin status"})
+			return
+		}
+
+		if !isAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin privileges required"})
+			return
+		}
+
+		topDatasets := 10
+		if raw := c.Query("top"); raw != "" {
+			top, err := strconv.Atoi(raw)
+			if err != nil || top < 1 || top > 100 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "top must be between 1 and 100"})
+				return
+			}
+			topDatasets = top
+		}
+
+		summary, err := h.submissionRepo.GetSubmissionDashboard(topDatasets)
+		if err != nil {
+			log.Printf("Error getting submissions summary: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve submissions summary"})
+			return
+		}
+
+		c.JSON(http.StatusOK, summary)
 	}
 }
 
@@ -436,6 +1355,12 @@ func (h *DataSubmissionHandlers) ReviewSubmission() gin.HandlerFunc {
 			return
 		}
 
+		// The submitter is told about status changes, so remember the status being replaced
+		reviewed, err := h.submissionRepo.GetSubmissionWithDetails(submissionID)
+		if err != nil {
+			log.Printf("Error getting submission for review: %v", err)
+		}
+
 		// Update submission status
 		err = h.submissionRepo.UpdateSubmissionStatus(submissionID, reviewRequest.Status, reviewRequest.AdminNotes, userUUID)
 		if err != nil {
@@ -444,7 +1369,15 @@ func (h *DataSubmissionHandlers) ReviewSubmission() gin.HandlerFunc {
 			return
 		}
 
+		// The new status is recorded, so the submitter is told now, even if applying the data fails
+		if reviewed != nil && reviewed.Status != reviewRequest.Status {
+			if err := h.notifications.NotifySubmissionReviewed(reviewed, reviewRequest.Status, reviewRequest.AdminNotes); err != nil {
+				log.Printf("Error notifying submitter of review: %v", err)
+			}
+		}
+
 		// If approved, apply the data to the target dataset
+		var applyReport *models.ApplyReport
 		if reviewRequest.Status == models.DataSubmissionStatusApproved {
 			submission, err := h.submissionRepo.GetSubmission(submissionID)
 			if err != nil {
@@ -453,12 +1386,45 @@ func (h *DataSubmissionHandlers) ReviewSubmission() gin.HandlerFunc {
 				return
 			}
 
-			err = h.submissionRepo.ApplyStagingDataToDataset(submissionID, submission.DatasetID, userUUID)
+			// Normalize dates and numeric formats to their stored form before copying rows into the dataset
+			if err := h.normalizeStagedValues(submission); err != nil {
+				log.Printf("Error normalizing staged values: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply data to dataset"})
+				return
+			}
+
+			// Upserts match rows on the unique fields of the schema the submission was validated against
+			var keyFields []string
+			if submission.Mode == models.SubmissionModeUpsert {
+				schema, err := h.submissionSchema(submission.DatasetID, submission.SchemaName)
+				if err != nil {
+					log.Printf("Error loading schema for upsert: %v", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load schema"})
+					return
+				}
+				keyFields = schema.UniqueFieldNames()
+				if len(keyFields) == 0 {
+					c.JSON(http.StatusConflict, gin.H{"error": "The schema no longer has unique fields to upsert on"})
+					return
+				}
+			}
+
+			applyReport, err = h.submissionRepo.ApplyStagingDataToDataset(submissionID, submission.DatasetID, userUUID, submission.Mode, keyFields)
+			var retryErr *repository.TxRetryError
+			if errors.As(err, &retryErr) {
+				log.Printf("Error applying data to dataset: %v", err)
+				c.JSON(http.StatusConflict, gin.H{
+					"error":    "Dataset is being updated concurrently; applying the data failed, please retry",
+					"attempts": retryErr.Attempts,
+				})
+				return
+			}
 			if err != nil {
 				log.Printf("Error applying data to dataset: %v", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply data to dataset"})
 				return
 			}
+			h.dataPages.Invalidate(c.Request.Context(), submission.DatasetID)
 
 			// Mark submission as applied
 			err = h.submissionRepo.MarkSubmissionApplied(submissionID)
@@ -469,7 +1435,36 @@ func (h *DataSubmissionHandlers) ReviewSubmission() gin.HandlerFunc {
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"message": "Submission review completed successfully",
+			"message":      "Submission review completed successfully",
+			"apply_report": applyReport,
+		})
+	}
+}
+
+// ValidateDraftSchema validates pasted rows against a schema definition before it is saved; no
+// dataset is involved and nothing is stored
+func (h *DataSubmissionHandlers) ValidateDraftSchema() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.ValidateDraftSchemaRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data", "details": err.Error()})
+			return
+		}
+		if len(req.Rows) > services.MaxDraftValidationRows {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("At most %d rows can be validated at once", services.MaxDraftValidationRows),
+			})
+			return
+		}
+
+		schema, err := services.DraftSchema(req.Fields, req.DateFormats)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"validation_result": h.validationSvc.ValidateDraftRows(schema, req.Rows),
 		})
 	}
 }
@@ -506,6 +1501,7 @@ func (h *DataSubmissionHandlers) CreateBusinessRule() gin.HandlerFunc {
 			RuleConfig   models.BusinessRuleConfig  `json:"rule_config" binding:"required"`
 			ErrorMessage string                     `json:"error_message" binding:"required"`
 			Priority     int                        `json:"priority"`
+			StopOnFail   bool                       `json:"stop_on_fail"`
 		}
 
 		if err := c.ShouldBindJSON(&ruleRequest); err != nil {
@@ -513,6 +1509,10 @@ func (h *DataSubmissionHandlers) CreateBusinessRule() gin.HandlerFunc {
 			return
 		}
 
+		if !h.checkReferenceTargets(c, userUUID, []models.BusinessRuleDefinition{{RuleType: ruleRequest.RuleType, RuleConfig: ruleRequest.RuleConfig}}) {
+			return
+		}
+
 		// Create business rule
 		configJSON, _ := json.Marshal(ruleRequest.RuleConfig)
 		rule := &models.DatasetBusinessRule{
@@ -524,24 +1524,236 @@ func (h *DataSubmissionHandlers) CreateBusinessRule() gin.HandlerFunc {
 			ErrorMessage: ruleRequest.ErrorMessage,
 			IsActive:     true,
 			Priority:     ruleRequest.Priority,
+			StopOnFail:   ruleRequest.StopOnFail,
 			CreatedBy:    userUUID,
 			CreatedAt:    time.Now(),
 			UpdatedAt:    time.Now(),
 		}
 
-		if err := h.submissionRepo.CreateBusinessRule(rule); err != nil {
+		ruleCount, err := services.CreateBusinessRuleWithinLimit(h.submissionRepo, rule, h.maxRules)
+		var ruleLimitErr *services.RuleLimitError
+		if errors.As(err, &ruleLimitErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      ruleLimitErr.Error(),
+				"rule_count": ruleLimitErr.Count,
+				"max_rules":  ruleLimitErr.Limit,
+			})
+			return
+		}
+		if err != nil {
 			log.Printf("Error creating business rule: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create business rule"})
 			return
 		}
 
 		c.JSON(http.StatusCreated, gin.H{
-			"message": "Business rule created successfully",
-			"rule":    rule,
+			"message":    "Business rule created successfully",
+			"rule":       rule,
+			"rule_count": ruleCount,
+			"max_rules":  h.maxRules,
+		})
+	}
+}
+
+// BulkCreateBusinessRules imports several business rules at once. Every rule is validated against
+// the dataset's default schema first; if any is invalid or the import would exceed the rule limit,
+// no rule is created. The response reports the outcome of each rule in request order.
+func (h *DataSubmissionHandlers) BulkCreateBusinessRules() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, err := uuid.Parse(c.Param("dataset_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(datasetID, userUUID)
+		if err != nil {
+			log.Printf("Error checking dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this dataset"})
+			return
+		}
+
+		var req models.BulkCreateBusinessRulesRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if len(req.Rules) == 0 || len(req.Rules) > services.MaxBulkBusinessRules {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Between 1 and %d rules can be imported at once", services.MaxBulkBusinessRules),
+			})
+			return
+		}
+
+		if !h.checkReferenceTargets(c, userUUID, req.Rules) {
+			return
+		}
+
+		schema, err := h.schemaRepo.GetSchemaByDatasetID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrSchemaNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Dataset has no schema to check rule fields against"})
+				return
+			}
+			log.Printf("Error loading schema for bulk rule import: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load schema"})
+			return
+		}
+
+		results, ruleCount, err := services.CreateBusinessRulesBulk(h.submissionRepo, datasetID, userUUID, req.Rules, schema, h.maxRules)
+		var ruleLimitErr *services.RuleLimitError
+		switch {
+		case errors.Is(err, services.ErrInvalidBulkRules):
+			invalid := 0
+			for _, result := range results {
+				if result.Status == models.BulkRuleStatusInvalid {
+					invalid++
+				}
+			}
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   fmt.Sprintf("%d of %d rules are invalid, no rules were created", invalid, len(results)),
+				"results": results,
+			})
+			return
+		case errors.As(err, &ruleLimitErr):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      fmt.Sprintf("importing %d rules would exceed the limit of %d active business rules", len(req.Rules), ruleLimitErr.Limit),
+				"results":    results,
+				"rule_count": ruleLimitErr.Count,
+				"max_rules":  ruleLimitErr.Limit,
+			})
+			return
+		case err != nil:
+			log.Printf("Error importing business rules: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create business rules"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message":    fmt.Sprintf("%d business rules created successfully", len(results)),
+			"results":    results,
+			"rule_count": ruleCount,
+			"max_rules":  h.maxRules,
 		})
 	}
 }
 
+// maxRuleTestRows caps the rows a rule test runs against
+const maxRuleTestRows = 1000
+
+// TestBusinessRule runs an unsaved business rule against sample rows or stored dataset data
+func (h *DataSubmissionHandlers) TestBusinessRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, err := uuid.Parse(c.Param("dataset_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(datasetID, userUUID)
+		if err != nil {
+			log.Printf("Error checking dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this dataset"})
+			return
+		}
+
+		var req models.TestBusinessRuleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if len(req.Rows) > maxRuleTestRows {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("At most %d rows can be tested at once", maxRuleTestRows),
+			})
+			return
+		}
+
+		rows, source := req.Rows, "request"
+		if len(rows) == 0 {
+			// Stored rows hold unmasked PII, and which rows a rule flags gives values away even
+			// when they are masked, so only editors can test rules against a dataset with PII fields
+			canEdit, err := h.submissionRepo.CheckDatasetEditAccess(datasetID, userUUID)
+			if err != nil {
+				log.Printf("Error checking dataset edit access: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+				return
+			}
+			if !canEdit {
+				schema, err := h.schemaRepo.GetSchemaByDatasetID(datasetID)
+				if err != nil && !errors.Is(err, repository.ErrSchemaNotFound) {
+					log.Printf("Error loading schema for rule test: %v", err)
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dataset schema"})
+					return
+				}
+				if services.HasPIIFields(schema) {
+					c.JSON(http.StatusForbidden, gin.H{
+						"error": "Only editors can test rules against this dataset's data because it has PII fields; post sample rows instead",
+					})
+					return
+				}
+			}
+
+			preview, err := h.schemaRepo.GetDatasetDataWithLimit(datasetID, 1, maxRuleTestRows, maxRuleTestRows, false)
+			if err != nil {
+				log.Printf("Error loading dataset data for rule test: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load dataset data"})
+				return
+			}
+			rows, source = preview.Data, "dataset"
+		}
+
+		result, err := h.validationSvc.TestBusinessRule(&req, rows, source)
+		if err != nil {
+			if errors.Is(err, services.ErrUnsupportedRuleType) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			log.Printf("Error testing business rule: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to test business rule"})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
 // GetBusinessRules retrieves business rules for a dataset
 func (h *DataSubmissionHandlers) GetBusinessRules() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -553,7 +1765,13 @@ func (h *DataSubmissionHandlers) GetBusinessRules() gin.HandlerFunc {
 			return
 		}
 
-		rules, err := h.submissionRepo.GetBusinessRules(datasetID)
+		// Inactive rules are listed on request so they can be found and reactivated
+		var rules []*models.DatasetBusinessRule
+		if c.Query("include_inactive") == "true" {
+			rules, err = h.submissionRepo.GetAllBusinessRules(datasetID)
+		} else {
+			rules, err = h.submissionRepo.GetBusinessRules(datasetID)
+		}
 		if err != nil {
 			log.Printf("Error getting business rules: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve business rules"})
@@ -561,14 +1779,242 @@ func (h *DataSubmissionHandlers) GetBusinessRules() gin.HandlerFunc {
 		}
 
 		c.JSON(http.StatusOK, gin.H{
-			"rules": rules,
-			"count": len(rules),
+			"rules":     rules,
+			"count":     len(rules),
+			"max_rules": h.maxRules,
+		})
+	}
+}
+
+// UpdateBusinessRule replaces a business rule's definition, keeping whether it is active
+func (h *DataSubmissionHandlers) UpdateBusinessRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := h.datasetBusinessRule(c)
+		if !ok {
+			return
+		}
+
+		var req models.BusinessRuleDefinition
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		schema, err := h.schemaRepo.GetSchemaByDatasetID(rule.DatasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrSchemaNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Dataset has no schema to check rule fields against"})
+				return
+			}
+			log.Printf("Error loading schema for business rule update: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load schema"})
+			return
+		}
+
+		if problems := services.ValidateBusinessRuleDefinition(req, schema); len(problems) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid business rule", "errors": problems})
+			return
+		}
+
+		configJSON, err := json.Marshal(req.RuleConfig)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule config"})
+			return
+		}
+
+		if !h.checkReferenceTargets(c, c.MustGet("user_id").(uuid.UUID), []models.BusinessRuleDefinition{req}) {
+			return
+		}
+
+		rule.RuleName = req.RuleName
+		rule.RuleType = req.RuleType
+		rule.RuleConfig = configJSON
+		rule.ErrorMessage = req.ErrorMessage
+		rule.Priority = req.Priority
+		rule.StopOnFail = req.StopOnFail
+		rule.UpdatedAt = time.Now()
+
+		if err := h.submissionRepo.UpdateBusinessRule(rule); err != nil {
+			log.Printf("Error updating business rule: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update business rule"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Business rule updated successfully",
+			"rule":    rule,
+		})
+	}
+}
+
+// SetBusinessRuleActive activates or deactivates a business rule. Reactivating a rule counts
+// against the dataset's active rule limit.
+func (h *DataSubmissionHandlers) SetBusinessRuleActive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := h.datasetBusinessRule(c)
+		if !ok {
+			return
+		}
+
+		var req models.SetBusinessRuleActiveRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "is_active is required"})
+			return
+		}
+
+		ruleCount, err := services.SetBusinessRuleActiveWithinLimit(h.submissionRepo, rule, *req.IsActive, h.maxRules)
+		var ruleLimitErr *services.RuleLimitError
+		if errors.As(err, &ruleLimitErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":      ruleLimitErr.Error(),
+				"rule_count": ruleLimitErr.Count,
+				"max_rules":  ruleLimitErr.Limit,
+			})
+			return
+		}
+		if err != nil {
+			log.Printf("Error changing business rule status: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update business rule"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"rule":       rule,
+			"rule_count": ruleCount,
+			"max_rules":  h.maxRules,
 		})
 	}
 }
 
+// DeleteBusinessRule deletes a business rule
+func (h *DataSubmissionHandlers) DeleteBusinessRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rule, ok := h.datasetBusinessRule(c)
+		if !ok {
+			return
+		}
+
+		if err := h.submissionRepo.DeleteBusinessRule(rule.ID); err != nil {
+			log.Printf("Error deleting business rule: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete business rule"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Business rule deleted successfully"})
+	}
+}
+
 // Helper functions
 
+// checkReferenceTargets checks the user can access the dataset each reference rule looks values up
+// in, so a rule can't reveal another dataset's values. It writes the error response and returns false
+// on failure; malformed target IDs are left to rule validation.
+func (h *DataSubmissionHandlers) checkReferenceTargets(c *gin.Context, userID uuid.UUID, defs []models.BusinessRuleDefinition) bool {
+	for _, def := range defs {
+		if def.RuleType != models.RuleTypeReference {
+			continue
+		}
+		targetID, err := uuid.Parse(def.RuleConfig.TargetDatasetID)
+		if err != nil {
+			continue
+		}
+
+		hasAccess, err := h.submissionRepo.CheckDatasetAccess(targetID, userID)
+		if err != nil {
+			log.Printf("Error checking referenced dataset access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return false
+		}
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access the referenced dataset"})
+			return false
+		}
+	}
+	return true
+}
+
+// datasetBusinessRule loads the business rule named by the request's rule_id after checking the
+// user can edit its dataset, writing the error response and returning false on failure. A rule
+// of another dataset is reported as not found.
+func (h *DataSubmissionHandlers) datasetBusinessRule(c *gin.Context) (*models.DatasetBusinessRule, bool) {
+	datasetID, err := uuid.Parse(c.Param("dataset_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+		return nil, false
+	}
+
+	ruleID, err := uuid.Parse(c.Param("rule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return nil, false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+
+	// Viewers can read rules but not change them
+	canEdit, err := h.submissionRepo.CheckDatasetEditAccess(datasetID, userUUID)
+	if err != nil {
+		log.Printf("Error checking dataset edit access: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+		return nil, false
+	}
+
+	if !canEdit {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to change this dataset's rules"})
+		return nil, false
+	}
+
+	rule, err := h.submissionRepo.GetBusinessRule(ruleID)
+	if err != nil && !errors.Is(err, repository.ErrBusinessRuleNotFound) {
+		log.Printf("Error getting business rule: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve business rule"})
+		return nil, false
+	}
+	if rule == nil || rule.DatasetID != datasetID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Business rule not found"})
+		return nil, false
+	}
+
+	return rule, true
+}
+
+// normalizeStagedValues rewrites valid staged rows to the stored form declared by the submission's schema
+func (h *DataSubmissionHandlers) normalizeStagedValues(submission *models.DataSubmission) error {
+	stagingData, err := h.submissionRepo.GetStagingDataByStatus(submission.ID, models.ValidationStatusValid)
+	if err != nil {
+		return err
+	}
+
+	changed, err := h.validationSvc.NormalizeStagingValues(submission.DatasetID, submission.SchemaVariant(), stagingData)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range changed {
+		if err := h.submissionRepo.UpdateStagingDataRow(row.ID, row.Data, row.ValidationStatus, row.ValidationErrors); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeFile deletes a stored submission file, logging instead of failing the request
+func (h *DataSubmissionHandlers) removeFile(key string) {
+	if err := h.files.Delete(context.Background(), key); err != nil {
+		log.Printf("Warning: Failed to delete file %s: %v", key, err)
+	}
+}
+
 func isValidCSVFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
 	return ext == ".csv"
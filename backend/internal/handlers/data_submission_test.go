@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/services"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+)
+
+// submissionSchemaRepo serves one schema with default dataset settings to the validation service
+type submissionSchemaRepo struct {
+	schema *models.DatasetSchema
+}
+
+func (r *submissionSchemaRepo) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error) {
+	return r.schema, nil
+}
+
+func (r *submissionSchemaRepo) GetSchemaByName(datasetID uuid.UUID, name string) (*models.DatasetSchema, error) {
+	return r.schema, nil
+}
+
+func (r *submissionSchemaRepo) FindExistingFieldValues(datasetID uuid.UUID, fieldName string, values []string) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (r *submissionSchemaRepo) FindExistingPIIValues(datasetID uuid.UUID, fieldName, kind string, values []string) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (r *submissionSchemaRepo) GetDatasetCSVDialect(datasetID uuid.UUID) (*models.CSVDialect, error) {
+	return nil, nil
+}
+
+func (r *submissionSchemaRepo) GetDatasetHeaderMode(datasetID uuid.UUID) (string, error) {
+	return models.HeaderModeStrict, nil
+}
+
+func (r *submissionSchemaRepo) GetDatasetNullTokens(datasetID uuid.UUID) (models.NullTokens, error) {
+	return nil, nil
+}
+
+func (r *submissionSchemaRepo) GetDatasetPIIGuardrails(datasetID uuid.UUID) (models.PIIGuardrails, error) {
+	return nil, nil
+}
+
+func (r *submissionSchemaRepo) GetBusinessRules(datasetID uuid.UUID) ([]*models.DatasetBusinessRule, error) {
+	return nil, nil
+}
+
+// submissionDedupStore finds recent submissions in recent and reports raced as the duplicate that
+// arrived while a submission was being validated
+type submissionDedupStore struct {
+	recent  *models.DataSubmission
+	raced   *models.DataSubmission
+	created []*models.DataSubmission
+}
+
+func (s *submissionDedupStore) FindRecentSubmission(datasetID, userID uuid.UUID, fileHash string, since time.Time) (*models.DataSubmission, error) {
+	return s.recent, nil
+}
+
+func (s *submissionDedupStore) CreateSubmission(submission *models.DataSubmission) error {
+	s.created = append(s.created, submission)
+	return nil
+}
+
+func (s *submissionDedupStore) CreateSubmissionUnlessRecent(submission *models.DataSubmission, since time.Time, isDuplicate func(*models.DataSubmission) bool) (*models.DataSubmission, error) {
+	if s.raced != nil && isDuplicate(s.raced) {
+		return s.raced, nil
+	}
+	s.created = append(s.created, submission)
+	return nil, nil
+}
+
+func TestCreateSubmission_Duplicates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	datasetID := uuid.New()
+	schemaRepo := &submissionSchemaRepo{schema: &models.DatasetSchema{
+		DatasetID: datasetID,
+		Fields:    []models.SchemaField{{Name: "name", DataType: "string"}},
+	}}
+
+	// submit stores a file and runs createSubmission for it, returning the response and whether
+	// the stored file is still there
+	submit := func(t *testing.T, store *submissionDedupStore) (*httptest.ResponseRecorder, bool) {
+		files := storage.NewLocalStorage(t.TempDir())
+		fileKey := "submissions/file.csv"
+		require.NoError(t, files.Put(context.Background(), fileKey, strings.NewReader("name\nalpha\n")))
+
+		validationSvc := services.NewValidationService(schemaRepo, schemaRepo)
+		validationSvc.SetStorage(files)
+		h := &DataSubmissionHandlers{
+			validationSvc: validationSvc,
+			dedup:         services.NewSubmissionDeduplicator(store, time.Minute),
+			files:         files,
+		}
+
+		fileHash := "abc123"
+		submission := &models.DataSubmission{
+			ID:          uuid.New(),
+			DatasetID:   datasetID,
+			SubmittedBy: uuid.New(),
+			FilePath:    fileKey,
+			FileHash:    &fileHash,
+			Mode:        models.SubmissionModeAppend,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/append", nil)
+		h.createSubmission(c, submission, nil)
+
+		_, err := files.Open(context.Background(), fileKey)
+		return w, err == nil
+	}
+
+	assertDuplicateOf := func(t *testing.T, w *httptest.ResponseRecorder, existing *models.DataSubmission) {
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Duplicate  bool                  `json:"duplicate"`
+			Submission models.DataSubmission `json:"submission"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.True(t, body.Duplicate)
+		assert.Equal(t, existing.ID, body.Submission.ID)
+	}
+
+	t.Run("duplicate found before validation", func(t *testing.T) {
+		existing := &models.DataSubmission{ID: uuid.New(), Mode: models.SubmissionModeAppend}
+		store := &submissionDedupStore{recent: existing}
+
+		w, kept := submit(t, store)
+		assertDuplicateOf(t, w, existing)
+		assert.False(t, kept, "the repeated file is removed")
+		assert.Empty(t, store.created)
+	})
+
+	t.Run("duplicate created while validating", func(t *testing.T) {
+		existing := &models.DataSubmission{ID: uuid.New(), Mode: models.SubmissionModeAppend}
+		store := &submissionDedupStore{raced: existing}
+
+		w, kept := submit(t, store)
+		assertDuplicateOf(t, w, existing)
+		assert.False(t, kept, "the repeated file is removed")
+		assert.Empty(t, store.created)
+	})
+}
@@ -1,13 +1,15 @@
 package handlers
 
 import (
-	"encoding/csv"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,21 +18,33 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/tealeg/xlsx/v3"
 
+	"github.com/saurabh22suman/oreo.io/internal/cache"
 	"github.com/saurabh22suman/oreo.io/internal/models"
 	"github.com/saurabh22suman/oreo.io/internal/repository"
+	"github.com/saurabh22suman/oreo.io/internal/services"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
 )
 
 // DatasetHandlers contains dataset-related handlers
 type DatasetHandlers struct {
 	datasetRepo *repository.DatasetRepository
 	schemaRepo  *repository.SchemaRepository
+	submissions *repository.DataSubmissionRepository
+	statsSvc    *services.FieldStatsService
+	files       storage.Storage
+	maxRows     int
 }
 
-// NewDatasetHandlers creates new dataset handlers
-func NewDatasetHandlers(db *sqlx.DB) *DatasetHandlers {
+// NewDatasetHandlers creates new dataset handlers; maxRows caps data rows per upload (zero or less disables the cap)
+func NewDatasetHandlers(db *sqlx.DB, files storage.Storage, maxRows int, statsCache cache.Cache, statsCacheTTL time.Duration) *DatasetHandlers {
+	schemaRepo := repository.NewSchemaRepository(db)
 	return &DatasetHandlers{
 		datasetRepo: repository.NewDatasetRepository(db),
-		schemaRepo:  repository.NewSchemaRepository(db),
+		schemaRepo:  schemaRepo,
+		submissions: repository.NewDataSubmissionRepository(db),
+		statsSvc:    services.NewFieldStatsService(schemaRepo, statsCache, statsCacheTTL),
+		files:       files,
+		maxRows:     maxRows,
 	}
 }
 
@@ -92,6 +106,14 @@ func (h *DatasetHandlers) UploadDataset() gin.HandlerFunc {
 			return
 		}
 
+		// Confirm the content matches the extension before saving
+		if err := verifyFileContent(file, header.Filename); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid file content: %v", err),
+			})
+			return
+		}
+
 		// Validate file size (50MB limit)
 		const maxFileSize = 50 * 1024 * 1024 // 50MB
 		if header.Size > maxFileSize {
@@ -108,6 +130,51 @@ func (h *DatasetHandlers) UploadDataset() gin.HandlerFunc {
 		}
 		description := c.PostForm("description")
 
+		// Excel workbooks are read from the named or zero-based indexed sheet, the first by default
+		sheet := strings.TrimSpace(c.PostForm("sheet"))
+
+		// Rows that fail to store roll back the whole insert unless best-effort is requested
+		insertMode := c.DefaultPostForm("insert_mode", models.BulkInsertAllOrNothing)
+		if !models.IsValidBulkInsertMode(insertMode) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("insert_mode must be '%s' or '%s'", models.BulkInsertAllOrNothing, models.BulkInsertBestEffort),
+			})
+			return
+		}
+
+		// Optional CSV dialect, given as JSON, for files that are not standard comma-separated CSV
+		var csvDialect *models.CSVDialect
+		if raw := c.PostForm("csv_dialect"); raw != "" {
+			csvDialect = &models.CSVDialect{}
+			if err := json.Unmarshal([]byte(raw), csvDialect); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "csv_dialect must be a JSON object"})
+				return
+			}
+			if err := services.ValidateCSVDialect(csvDialect); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		headerMode := c.DefaultPostForm("header_mode", models.HeaderModeStrict)
+		if !models.IsValidHeaderMode(headerMode) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "header_mode must be 'strict' or 'lenient'"})
+			return
+		}
+
+		// Optional cell values to read as null, given as a JSON array such as ["N/A", "-"]
+		var nullTokens models.NullTokens
+		if raw := c.PostForm("null_tokens"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &nullTokens); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "null_tokens must be a JSON array of strings"})
+				return
+			}
+			if nullTokens, err = services.NormalizeNullTokens(nullTokens); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
 		// Create dataset record
 		dataset := &models.Dataset{
 			ID:          uuid.New(),
@@ -118,74 +185,136 @@ func (h *DatasetHandlers) UploadDataset() gin.HandlerFunc {
 			FileSize:    header.Size,
 			MimeType:    header.Header.Get("Content-Type"),
 			Status:      models.DatasetStatusProcessing,
+			CSVDialect:  csvDialect,
+			HeaderMode:  headerMode,
+			NullTokens:  nullTokens,
 			UploadedBy:  userUUID,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
 
-		// Save file to uploads directory
-		uploadDir := "uploads"
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			log.Printf("Error creating upload directory: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
-			return
-		}
+		h.ingestDataset(c, dataset, file, sheet, insertMode, "uploaded")
+	}
+}
 
-		filename := fmt.Sprintf("%s_%s", dataset.ID.String(), header.Filename)
-		filepath := filepath.Join(uploadDir, filename)
-		dataset.FilePath = filepath
+// ingestDataset stores the file of a new dataset, parses it and saves the dataset together with its
+// rows, then responds with the created dataset. action names how the file arrived in the messages.
+func (h *DatasetHandlers) ingestDataset(c *gin.Context, dataset *models.Dataset, file io.Reader, sheet, insertMode, action string) {
+	// Save file to the uploads prefix of the configured storage
+	filename := fmt.Sprintf("%s_%s", dataset.ID.String(), dataset.FileName)
+	fileKey := storage.Key("uploads", filename)
+	dataset.FilePath = fileKey
 
-		// Save file to disk
-		out, err := os.Create(filepath)
-		if err != nil {
-			log.Printf("Error creating file: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
-			return
-		}
-		defer out.Close()
+	if err := h.files.Put(c.Request.Context(), fileKey, file); err != nil {
+		log.Printf("Error storing file: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
+		return
+	}
 
-		_, err = io.Copy(out, file)
-		if err != nil {
-			log.Printf("Error copying file: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
-			return
+	// Process file to get row and column count and data
+	parsed, err := h.processFile(fileKey, dataset.FileName, dataset.CSVDialect, sheet)
+	var rowLimitErr *services.RowLimitError
+	if errors.As(err, &rowLimitErr) {
+		h.removeFile(fileKey)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     rowLimitErr.Error(),
+			"row_count": rowLimitErr.Rows,
+			"max_rows":  rowLimitErr.Limit,
+		})
+		return
+	}
+	var sheetErr *SheetNotFoundError
+	if errors.As(err, &sheetErr) {
+		h.removeFile(fileKey)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  sheetErr.Error(),
+			"sheets": sheetErr.Sheets,
+		})
+		return
+	}
+	var headers []string
+	var dataRows [][]string
+	if err != nil {
+		log.Printf("Error processing file: %v", err)
+		dataset.Status = models.DatasetStatusError
+	} else {
+		headers, dataRows = parsed.Headers, parsed.Rows
+		dataset.RowCount = len(dataRows)
+		dataset.ColumnCount = len(headers)
+		dataset.ColumnOrder = headers
+		dataset.Status = models.DatasetStatusReady
+		if parsed.Sheet != "" {
+			dataset.SheetName = &parsed.Sheet
 		}
+	}
 
-		// Process file to get row and column count and data
-		rowCount, columnCount, headers, dataRows, err := h.processFile(filepath, header.Filename)
-		if err != nil {
-			log.Printf("Error processing file: %v", err)
-			dataset.Status = models.DatasetStatusError
-		} else {
-			dataset.RowCount = rowCount
-			dataset.ColumnCount = columnCount
-			dataset.Status = models.DatasetStatusReady
-		}
+	// A dataset with rows is created in the same transaction as its rows, so a failed insert
+	// leaves no dataset without data behind
+	var insertReport *models.BulkInsertReport
+	var createErr error
+	if err == nil && len(dataRows) > 0 {
+		insertReport, createErr = h.schemaRepo.CreateDatasetWithData(dataset, headers, dataRows, dataset.UploadedBy, insertMode)
+	} else {
+		createErr = h.datasetRepo.Create(dataset)
+	}
+	if createErr != nil {
+		log.Printf("Error creating dataset: %v", createErr)
+		// Clean up uploaded file
+		h.removeFile(fileKey)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save dataset"})
+		return
+	}
+	if insertReport != nil && insertReport.RolledBack {
+		h.removeFile(fileKey)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         fmt.Sprintf("%d of %d rows could not be stored, so the dataset was not created", len(insertReport.FailedRows), insertReport.TotalRows),
+			"insert_report": insertReport,
+		})
+		return
+	}
+	if insertReport != nil {
+		log.Printf("Stored %d of %d rows of data for dataset %s (%d failed)",
+			insertReport.InsertedRows, insertReport.TotalRows, dataset.ID, len(insertReport.FailedRows))
+	}
 
-		// Save dataset to database first
-		if err := h.datasetRepo.Create(dataset); err != nil {
-			log.Printf("Error creating dataset: %v", err)
-			// Clean up uploaded file
-			os.Remove(filepath)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save dataset"})
-			return
+	// Rows skipped by a best-effort insert are flagged in the dataset status and reported so
+	// the client can tell the user
+	status, warning := ingestionOutcome(insertReport)
+	if status != "" {
+		dataset.Status = status
+		if err := h.datasetRepo.UpdateStatus(dataset.ID, dataset.Status, dataset.RowCount, dataset.ColumnCount); err != nil {
+			log.Printf("Error updating status of dataset %s after ingestion: %v", dataset.ID, err)
 		}
+	}
 
-		// Store the actual data in database if processing was successful
-		if err == nil && len(dataRows) > 0 {
-			if err := h.schemaRepo.BulkInsertDatasetData(dataset.ID, headers, dataRows, userUUID); err != nil {
-				log.Printf("Error storing dataset data: %v", err)
-				// Don't fail the entire upload if data storage fails, 
-				// but log it for debugging
-			} else {
-				log.Printf("Successfully stored %d rows of data for dataset %s", len(dataRows), dataset.ID)
-			}
-		}
+	response := gin.H{
+		"message":       fmt.Sprintf("Dataset %s successfully", action),
+		"dataset":       dataset,
+		"insert_report": insertReport,
+	}
+	if warning != "" {
+		response["message"] = fmt.Sprintf("Dataset %s, but its data was not fully stored", action)
+		response["warning"] = warning
+	}
+	// List the other sheets of a workbook so clients can offer to pick a different one
+	if parsed != nil && len(parsed.Sheets) > 1 {
+		response["sheets"] = parsed.Sheets
+	}
+	c.JSON(http.StatusCreated, response)
+}
 
-		c.JSON(http.StatusCreated, gin.H{
-			"message": "Dataset uploaded successfully",
-			"dataset": dataset,
-		})
+// ingestionOutcome decides the dataset status and user-facing warning after storing an upload's
+// rows; an empty status keeps the status set from processing the file
+func ingestionOutcome(report *models.BulkInsertReport) (string, string) {
+	switch {
+	case report == nil:
+		return "", ""
+	case report.InsertedRows == 0:
+		return models.DatasetStatusIngestionFailed, fmt.Sprintf("None of the %d rows could be stored; the preview reads from the uploaded file", report.TotalRows)
+	case len(report.FailedRows) > 0:
+		return models.DatasetStatusPartial, fmt.Sprintf("%d of %d rows could not be stored; see insert_report for details", len(report.FailedRows), report.TotalRows)
+	default:
+		return "", ""
 	}
 }
 
@@ -199,7 +328,13 @@ func (h *DatasetHandlers) GetDatasets() gin.HandlerFunc {
 			return
 		}
 
-		datasets, err := h.datasetRepo.GetByProjectID(projectID)
+		page, pageSize := datasetListPage(c)
+		order, ok := datasetListSort(c)
+		if !ok {
+			return
+		}
+
+		datasets, total, err := h.datasetRepo.GetByProjectID(projectID, order, pageSize, (page-1)*pageSize)
 		if err != nil {
 			log.Printf("Error fetching datasets: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch datasets"})
@@ -209,6 +344,11 @@ func (h *DatasetHandlers) GetDatasets() gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{
 			"datasets": datasets,
 			"count":    len(datasets),
+			"pagination": gin.H{
+				"page":      page,
+				"page_size": pageSize,
+				"total":     total,
+			},
 		})
 	}
 }
@@ -228,7 +368,13 @@ func (h *DatasetHandlers) GetUserDatasets() gin.HandlerFunc {
 			return
 		}
 
-		datasets, err := h.datasetRepo.GetByUserID(userUUID)
+		page, pageSize := datasetListPage(c)
+		order, ok := datasetListSort(c)
+		if !ok {
+			return
+		}
+
+		datasets, total, err := h.datasetRepo.GetByUserID(userUUID, order, pageSize, (page-1)*pageSize)
 		if err != nil {
 			log.Printf("Error fetching user datasets: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch datasets"})
@@ -238,6 +384,100 @@ func (h *DatasetHandlers) GetUserDatasets() gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{
 			"datasets": datasets,
 			"count":    len(datasets),
+			"pagination": gin.H{
+				"page":      page,
+				"page_size": pageSize,
+				"total":     total,
+			},
+		})
+	}
+}
+
+// datasetListPage reads the page and page_size query parameters of a dataset list, falling back to
+// the first page of 20 when they are missing or out of range
+func datasetListPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
+
+// datasetListSort reads the sort and order query parameters of a dataset list, writing a 400
+// response listing the accepted fields and returning false when they are not allowed
+func datasetListSort(c *gin.Context) (repository.DatasetSort, bool) {
+	order, err := repository.ParseDatasetSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":       err.Error(),
+			"sort_fields": repository.DatasetSortFields(),
+		})
+		return repository.DatasetSort{}, false
+	}
+	return order, true
+}
+
+// SearchDatasets finds datasets in a project by name or description
+func (h *DatasetHandlers) SearchDatasets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		projectID, err := uuid.Parse(c.Query("project_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+
+		query := strings.TrimSpace(c.Query("q"))
+		if query == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+			return
+		}
+
+		page, pageSize := datasetListPage(c)
+
+		canView, err := h.datasetRepo.CanViewProject(projectID, userUUID)
+		if err != nil {
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
+		}
+
+		if !canView {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this project"})
+			return
+		}
+
+		datasets, total, err := h.datasetRepo.Search(projectID, query, pageSize, (page-1)*pageSize)
+		if err != nil {
+			log.Printf("Error searching datasets: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search datasets"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"datasets": datasets,
+			"count":    len(datasets),
+			"pagination": gin.H{
+				"page":      page,
+				"page_size": pageSize,
+				"total":     total,
+			},
 		})
 	}
 }
@@ -267,7 +507,12 @@ func (h *DatasetHandlers) DeleteDataset() gin.HandlerFunc {
 		// Get dataset to find file path
 		dataset, err := h.datasetRepo.GetByID(datasetID)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
 			return
 		}
 
@@ -278,113 +523,80 @@ func (h *DatasetHandlers) DeleteDataset() gin.HandlerFunc {
 			return
 		}
 
-		// Delete file from disk
-		if err := os.Remove(dataset.FilePath); err != nil {
-			log.Printf("Warning: Failed to delete file %s: %v", dataset.FilePath, err)
-		}
+		// Delete file from storage
+		h.removeFile(dataset.FilePath)
 
 		c.JSON(http.StatusOK, gin.H{"message": "Dataset deleted successfully"})
 	}
 }
 
-// Helper functions
-
-func isValidFileType(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	return ext == ".csv" || ext == ".xlsx" || ext == ".xls"
-}
-
-func (h *DatasetHandlers) processFile(filePath, filename string) (int, int, []string, [][]string, error) {
-	ext := strings.ToLower(filepath.Ext(filename))
-
-	switch ext {
-	case ".csv":
-		return h.processCSV(filePath)
-	case ".xlsx", ".xls":
-		return h.processExcel(filePath)
-	default:
-		return 0, 0, nil, nil, fmt.Errorf("unsupported file type: %s", ext)
-	}
-}
-
-func (h *DatasetHandlers) processCSV(filePath string) (int, int, []string, [][]string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return 0, 0, nil, nil, err
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
-	if err != nil {
-		return 0, 0, nil, nil, err
-	}
-
-	if len(records) == 0 {
-		return 0, 0, nil, nil, nil
-	}
-
-	// First row is headers, rest are data rows
-	headers := records[0]
-	dataRows := records[1:]
-	rowCount := len(dataRows)
-	columnCount := len(headers)
+// SetDatasetTrust toggles whether a dataset accepts direct appends from trusted API keys
+func (h *DatasetHandlers) SetDatasetTrust() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
 
-	return rowCount, columnCount, headers, dataRows, nil
-}
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
 
-func (h *DatasetHandlers) processExcel(filePath string) (int, int, []string, [][]string, error) {
-	workbook, err := xlsx.OpenFile(filePath)
-	if err != nil {
-		return 0, 0, nil, nil, err
-	}
+		datasetIDStr := c.Param("id")
+		datasetID, err := uuid.Parse(datasetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
 
-	if len(workbook.Sheets) == 0 {
-		return 0, 0, nil, nil, nil
-	}
+		var req models.UpdateDatasetTrustRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
 
-	sheet := workbook.Sheets[0] // Use first sheet
-	
-	var headers []string
-	var dataRows [][]string
-	
-	// Get headers from first row
-	if sheet.MaxRow > 0 {
-		headerRow, err := sheet.Row(0)
+		dataset, err := h.datasetRepo.GetByID(datasetID)
 		if err != nil {
-			return 0, 0, nil, nil, err
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
 		}
-		
-		// Use ForEachCell to iterate through cells
-		headerRow.ForEachCell(func(c *xlsx.Cell) error {
-			headers = append(headers, c.String())
-			return nil
-		})
-	}
-	
-	// Get data rows (skip header row)
-	for rowIndex := 1; rowIndex < sheet.MaxRow; rowIndex++ {
-		row, err := sheet.Row(rowIndex)
+
+		// Only the project owner can allow data to bypass validation
+		isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
 		if err != nil {
-			continue
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
 		}
-		
-		var rowData []string
-		row.ForEachCell(func(c *xlsx.Cell) error {
-			rowData = append(rowData, c.String())
-			return nil
-		})
-		dataRows = append(dataRows, rowData)
-	}
 
-	rowCount := len(dataRows)
-	columnCount := len(headers)
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change dataset trust"})
+			return
+		}
 
-	return rowCount, columnCount, headers, dataRows, nil
+		if err := h.datasetRepo.SetTrusted(datasetID, *req.IsTrusted); err != nil {
+			log.Printf("Error updating dataset trust: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update dataset trust"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Dataset trust updated successfully",
+			"is_trusted": *req.IsTrusted,
+		})
+	}
 }
 
-// GetDatasetByID returns a specific dataset by ID
-func (h *DatasetHandlers) GetDatasetByID() gin.HandlerFunc {
+// SetDatasetDisplayField sets or clears the field whose value labels the dataset's rows
+func (h *DatasetHandlers) SetDatasetDisplayField() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
 		if !exists {
@@ -405,36 +617,970 @@ func (h *DatasetHandlers) GetDatasetByID() gin.HandlerFunc {
 			return
 		}
 
-		// Get dataset with permission check
+		var req models.UpdateDatasetDisplayFieldRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
 		dataset, err := h.datasetRepo.GetByID(datasetID)
 		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
 			log.Printf("Error getting dataset: %v", err)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
 			return
 		}
 
-		// Check if user has access by getting their datasets
-		userDatasets, err := h.datasetRepo.GetByUserID(userUUID)
+		isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
 		if err != nil {
-			log.Printf("Error checking user access: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify access"})
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
 			return
 		}
 
-		// Check if the dataset belongs to the user
-		hasAccess := false
-		for _, userDataset := range userDatasets {
-			if userDataset.ID == datasetID {
-				hasAccess = true
-				break
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change the display field"})
+			return
+		}
+
+		// An empty value clears the display field
+		displayField := req.DisplayField
+		if displayField != nil && strings.TrimSpace(*displayField) == "" {
+			displayField = nil
+		}
+
+		if displayField != nil {
+			schema, err := h.schemaRepo.GetSchemaByDatasetID(datasetID)
+			if err != nil {
+				if errors.Is(err, repository.ErrSchemaNotFound) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Define a schema before choosing a display field"})
+					return
+				}
+				log.Printf("Error getting schema: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schema"})
+				return
+			}
+
+			if err := services.ValidateDisplayField(schema, *displayField); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
 			}
 		}
 
-		if !hasAccess {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		if err := h.datasetRepo.SetDisplayField(datasetID, displayField); err != nil {
+			log.Printf("Error updating dataset display field: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update display field"})
 			return
 		}
 
-		c.JSON(http.StatusOK, dataset)
+		c.JSON(http.StatusOK, gin.H{
+			"message":       "Dataset display field updated successfully",
+			"display_field": displayField,
+		})
 	}
 }
+
+// SetDatasetCSVDialect sets or clears the CSV dialect used to parse the dataset's appended files
+func (h *DatasetHandlers) SetDatasetCSVDialect() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		var req models.UpdateDatasetCSVDialectRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if err := services.ValidateCSVDialect(req.CSVDialect); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		dataset, err := h.datasetRepo.GetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
+		if err != nil {
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
+		}
+
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change the CSV dialect"})
+			return
+		}
+
+		if err := h.datasetRepo.SetCSVDialect(datasetID, req.CSVDialect); err != nil {
+			log.Printf("Error updating dataset CSV dialect: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update CSV dialect"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Dataset CSV dialect updated successfully",
+			"csv_dialect": req.CSVDialect,
+		})
+	}
+}
+
+// SetDatasetHeaderMode sets whether the dataset's submissions reject or ignore columns that aren't schema fields
+func (h *DatasetHandlers) SetDatasetHeaderMode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		var req models.UpdateDatasetHeaderModeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if !models.IsValidHeaderMode(req.HeaderMode) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "header_mode must be 'strict' or 'lenient'"})
+			return
+		}
+
+		dataset, err := h.datasetRepo.GetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
+		if err != nil {
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
+		}
+
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change the header mode"})
+			return
+		}
+
+		if err := h.datasetRepo.SetHeaderMode(datasetID, req.HeaderMode); err != nil {
+			log.Printf("Error updating dataset header mode: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update header mode"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Dataset header mode updated successfully",
+			"header_mode": req.HeaderMode,
+		})
+	}
+}
+
+// SetDatasetEnforceSchema toggles whether direct edits to the dataset's rows are validated against its schema
+func (h *DatasetHandlers) SetDatasetEnforceSchema() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		var req models.UpdateDatasetEnforceSchemaRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		dataset, err := h.datasetRepo.GetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
+		if err != nil {
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
+		}
+
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change schema enforcement"})
+			return
+		}
+
+		if err := h.datasetRepo.SetEnforceSchema(datasetID, *req.EnforceSchema); err != nil {
+			log.Printf("Error updating dataset schema enforcement: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update schema enforcement"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":        "Dataset schema enforcement updated successfully",
+			"enforce_schema": *req.EnforceSchema,
+		})
+	}
+}
+
+// SetDatasetNullTokens replaces the cell values read as null in the dataset's uploaded and appended files
+func (h *DatasetHandlers) SetDatasetNullTokens() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		var req models.UpdateDatasetNullTokensRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		tokens, err := services.NormalizeNullTokens(req.NullTokens)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		dataset, err := h.datasetRepo.GetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
+		if err != nil {
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
+		}
+
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change null tokens"})
+			return
+		}
+
+		if err := h.datasetRepo.SetNullTokens(datasetID, tokens); err != nil {
+			log.Printf("Error updating dataset null tokens: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update null tokens"})
+			return
+		}
+
+		if tokens == nil {
+			tokens = models.NullTokens{}
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Dataset null tokens updated successfully",
+			"null_tokens": tokens,
+		})
+	}
+}
+
+// SetDatasetPIIGuardrails replaces the contact fields of a dataset whose values must not repeat
+func (h *DatasetHandlers) SetDatasetPIIGuardrails() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		var req models.UpdateDatasetPIIGuardrailsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		if err := services.ValidatePIIGuardrails(req.PIIGuardrails); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		dataset, err := h.datasetRepo.GetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
+		if err != nil {
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
+		}
+
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change PII guardrails"})
+			return
+		}
+
+		if err := h.datasetRepo.SetPIIGuardrails(datasetID, req.PIIGuardrails); err != nil {
+			log.Printf("Error updating dataset PII guardrails: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update PII guardrails"})
+			return
+		}
+
+		guardrails := req.PIIGuardrails
+		if guardrails == nil {
+			guardrails = models.PIIGuardrails{}
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message":        "Dataset PII guardrails updated successfully",
+			"pii_guardrails": guardrails,
+		})
+	}
+}
+
+// Helper functions
+
+func isValidFileType(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	return ext == ".csv" || ext == ".xlsx" || ext == ".xls"
+}
+
+// removeFile deletes a stored upload, logging instead of failing the request
+func (h *DatasetHandlers) removeFile(key string) {
+	if key == "" {
+		return
+	}
+	if err := h.files.Delete(context.Background(), key); err != nil {
+		log.Printf("Warning: Failed to delete file %s: %v", key, err)
+	}
+}
+
+// parsedFile is the content of an uploaded file: its header row and data rows, and for Excel
+// workbooks the sheet they were read from along with every sheet name
+type parsedFile struct {
+	Headers []string
+	Rows    [][]string
+	Sheet   string
+	Sheets  []string
+}
+
+// SheetNotFoundError is returned when a requested sheet isn't in an uploaded workbook
+type SheetNotFoundError struct {
+	Requested string
+	Sheets    []string
+}
+
+func (e *SheetNotFoundError) Error() string {
+	return fmt.Sprintf("sheet '%s' not found; use a sheet name or a zero-based index", e.Requested)
+}
+
+// processFile parses a stored upload. sheet selects the sheet of an Excel workbook by name or
+// zero-based index and defaults to the first; it is ignored for CSV files.
+func (h *DatasetHandlers) processFile(fileKey, filename string, dialect *models.CSVDialect, sheet string) (*parsedFile, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext != ".csv" && ext != ".xlsx" && ext != ".xls" {
+		return nil, fmt.Errorf("unsupported file type: %s", ext)
+	}
+
+	file, err := h.files.Open(context.Background(), fileKey)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if ext == ".csv" {
+		_, _, headers, dataRows, err := h.processCSV(file, dialect)
+		if err != nil {
+			return nil, err
+		}
+		return &parsedFile{Headers: headers, Rows: dataRows}, nil
+	}
+	return h.processExcel(file, sheet)
+}
+
+func (h *DatasetHandlers) processCSV(file io.Reader, dialect *models.CSVDialect) (int, int, []string, [][]string, error) {
+	reader := services.NewCSVReader(file, dialect)
+
+	// First row is headers, rest are data rows
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return 0, 0, nil, nil, nil
+	}
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+
+	var dataRows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, nil, nil, err
+		}
+
+		dataRows = append(dataRows, record)
+		if err := services.CheckRowLimit(len(dataRows), h.maxRows); err != nil {
+			remaining, _ := services.CountRemainingRecords(reader)
+			return 0, 0, nil, nil, &services.RowLimitError{Limit: h.maxRows, Rows: len(dataRows) + remaining}
+		}
+	}
+
+	rowCount := len(dataRows)
+	columnCount := len(headers)
+
+	return rowCount, columnCount, headers, dataRows, nil
+}
+
+func (h *DatasetHandlers) processExcel(file io.Reader, sheetName string) (*parsedFile, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	workbook, err := xlsx.OpenBinary(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(workbook.Sheets) == 0 {
+		return &parsedFile{}, nil
+	}
+
+	sheet, err := selectSheet(workbook.Sheets, sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &parsedFile{Sheet: sheet.Name}
+	for _, other := range workbook.Sheets {
+		parsed.Sheets = append(parsed.Sheets, other.Name)
+	}
+	
+	// Get headers from first row
+	if sheet.MaxRow > 0 {
+		headerRow, err := sheet.Row(0)
+		if err != nil {
+			return nil, err
+		}
+		
+		// Use ForEachCell to iterate through cells
+		headerRow.ForEachCell(func(c *xlsx.Cell) error {
+			parsed.Headers = append(parsed.Headers, excelCellValue(c, workbook.Date1904))
+			return nil
+		})
+	}
+	
+	// Reject oversized sheets before reading any rows
+	if sheet.MaxRow > 1 {
+		if err := services.CheckRowLimit(sheet.MaxRow-1, h.maxRows); err != nil {
+			return nil, err
+		}
+	}
+
+	// Get data rows (skip header row)
+	for rowIndex := 1; rowIndex < sheet.MaxRow; rowIndex++ {
+		row, err := sheet.Row(rowIndex)
+		if err != nil {
+			continue
+		}
+		
+		var rowData []string
+		row.ForEachCell(func(c *xlsx.Cell) error {
+			rowData = append(rowData, excelCellValue(c, workbook.Date1904))
+			return nil
+		})
+		parsed.Rows = append(parsed.Rows, rowData)
+	}
+
+	return parsed, nil
+}
+
+// excelDateTimeFormat is used for Excel date cells that carry a time of day
+const excelDateTimeFormat = "2006-01-02 15:04:05"
+
+// excelCellValue reads a cell the way the same value would appear in a CSV export: formulas give
+// their last computed result, dates use services.NormalizedDateFormat (with the time when there is
+// one), numbers ignore display formatting and booleans are "true" or "false"
+func excelCellValue(c *xlsx.Cell, date1904 bool) string {
+	if c.Formula() != "" && c.Value == "" {
+		// The workbook was saved without calculating the formula, so there is no result to read
+		return ""
+	}
+
+	switch c.Type() {
+	case xlsx.CellTypeNumeric:
+		if c.IsTime() {
+			if t, err := c.GetTime(date1904); err == nil {
+				return formatExcelTime(t)
+			}
+		}
+		if value, err := c.GeneralNumericWithoutScientific(); err == nil {
+			return value
+		}
+		return c.Value
+	case xlsx.CellTypeBool:
+		return strconv.FormatBool(c.Bool())
+	case xlsx.CellTypeDate:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", services.NormalizedDateFormat} {
+			if t, err := time.Parse(layout, c.Value); err == nil {
+				return formatExcelTime(t)
+			}
+		}
+		return c.Value
+	}
+
+	value, err := c.FormattedValue()
+	if err != nil {
+		return c.Value
+	}
+	return value
+}
+
+// formatExcelTime formats a date cell's time, dropping the clock when it is midnight
+func formatExcelTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format(services.NormalizedDateFormat)
+	}
+	return t.Format(excelDateTimeFormat)
+}
+
+// selectSheet picks a workbook sheet by exact name, then by zero-based index; an empty selection
+// picks the first sheet
+func selectSheet(sheets []*xlsx.Sheet, selection string) (*xlsx.Sheet, error) {
+	if selection == "" {
+		return sheets[0], nil
+	}
+	for _, sheet := range sheets {
+		if sheet.Name == selection {
+			return sheet, nil
+		}
+	}
+	if index, err := strconv.Atoi(selection); err == nil && index >= 0 && index < len(sheets) {
+		return sheets[index], nil
+	}
+
+	names := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		names[i] = sheet.Name
+	}
+	return nil, &SheetNotFoundError{Requested: selection, Sheets: names}
+}
+
+// GetDatasetByID returns a specific dataset by ID
+func (h *DatasetHandlers) GetDatasetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetIDStr := c.Param("id")
+		datasetID, err := uuid.Parse(datasetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		// Get dataset with permission check
+		dataset, err := h.datasetRepo.GetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		// Check if the dataset belongs to the user
+		if dataset.UploadedBy != userUUID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+
+		c.JSON(http.StatusOK, dataset)
+	}
+}
+
+// ComputeDatasetStats recomputes and stores per-field statistics over a dataset's stored rows
+func (h *DatasetHandlers) ComputeDatasetStats() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, ok := h.authorizeDatasetAccess(c)
+		if !ok {
+			return
+		}
+
+		stats, err := h.statsSvc.Compute(c.Request.Context(), datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error computing field stats for dataset %s: %v", datasetID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute dataset stats"})
+			return
+		}
+
+		piiSchema, ok := h.callerPIISchema(c, datasetID)
+		if !ok {
+			return
+		}
+		services.HidePIIFieldValues(stats, piiSchema)
+
+		c.JSON(http.StatusOK, gin.H{
+			"stats":   stats,
+			"message": "Dataset stats computed successfully",
+		})
+	}
+}
+
+// GetDatasetStats returns the stored per-field statistics of a dataset
+func (h *DatasetHandlers) GetDatasetStats() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, ok := h.authorizeDatasetAccess(c)
+		if !ok {
+			return
+		}
+
+		stats, cached, err := h.statsSvc.Get(c.Request.Context(), datasetID)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrDatasetNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+			case errors.Is(err, repository.ErrFieldStatsNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Stats have not been computed for this dataset"})
+			default:
+				log.Printf("Error getting field stats for dataset %s: %v", datasetID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset stats"})
+			}
+			return
+		}
+
+		// The values listed for low-cardinality fields would give PII away like unmasked rows
+		piiSchema, ok := h.callerPIISchema(c, datasetID)
+		if !ok {
+			return
+		}
+		services.HidePIIFieldValues(stats, piiSchema)
+
+		c.JSON(http.StatusOK, gin.H{
+			"stats":  stats,
+			"cached": cached,
+		})
+	}
+}
+
+// Bounds of the limit query parameter of PreviewDataset
+const (
+	defaultPreviewRows = 20
+	maxPreviewRows     = 100
+)
+
+// Sources of a dataset preview
+const (
+	previewSourceDatabase = "database" // rows stored in dataset_data
+	previewSourceFile     = "file"     // rows parsed from the uploaded file
+)
+
+// PreviewDataset returns the first rows of a dataset. Stored rows are preferred; when none are
+// stored, for example because ingestion failed, the rows are read from the uploaded file instead.
+func (h *DatasetHandlers) PreviewDataset() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, ok := h.authorizeDatasetAccess(c)
+		if !ok {
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPreviewRows)))
+		if err != nil || limit < 1 || limit > maxPreviewRows {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxPreviewRows)})
+			return
+		}
+
+		dataset, err := h.datasetRepo.GetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			log.Printf("Error getting dataset: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		piiSchema, ok := h.callerPIISchema(c, datasetID)
+		if !ok {
+			return
+		}
+
+		stored, err := h.schemaRepo.GetDatasetDataWithLimit(datasetID, 1, limit, limit, false)
+		if err != nil {
+			log.Printf("Error loading preview rows for dataset %s: %v", datasetID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load dataset data"})
+			return
+		}
+		if len(stored.Data) > 0 {
+			services.MaskPIIFields(stored.Data, piiSchema)
+			c.JSON(http.StatusOK, gin.H{
+				"source":         previewSourceDatabase,
+				"dataset_status": dataset.Status,
+				"columns":        stored.Columns,
+				"data":           stored.Data,
+				"total_rows":     dataset.RowCount,
+			})
+			return
+		}
+
+		var sheet string
+		if dataset.SheetName != nil {
+			sheet = *dataset.SheetName
+		}
+		parsed, err := h.processFile(dataset.FilePath, dataset.FileName, dataset.CSVDialect, sheet)
+		if err != nil {
+			log.Printf("Error reading uploaded file of dataset %s for preview: %v", datasetID, err)
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":          "The uploaded file could not be read",
+				"dataset_status": dataset.Status,
+			})
+			return
+		}
+
+		rows := previewRows(parsed.Headers, parsed.Rows, limit)
+		services.MaskPIIFields(rows, piiSchema)
+
+		c.JSON(http.StatusOK, gin.H{
+			"source":         previewSourceFile,
+			"dataset_status": dataset.Status,
+			"columns":        parsed.Headers,
+			"data":           rows,
+			"total_rows":     len(parsed.Rows),
+		})
+	}
+}
+
+// previewRows maps the first limit records to rows keyed by header, leaving missing cells empty
+func previewRows(headers []string, records [][]string, limit int) []map[string]interface{} {
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	rows := make([]map[string]interface{}, len(records))
+	for i, record := range records {
+		row := make(map[string]interface{}, len(headers))
+		for j, header := range headers {
+			if j < len(record) {
+				row[header] = record[j]
+			} else {
+				row[header] = ""
+			}
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// Bounds of the submissions query parameter of GetDatasetDrift
+const (
+	defaultDriftSubmissions = 5
+	maxDriftSubmissions     = 50
+)
+
+// GetDatasetDrift compares the field stats of the dataset's most recent submissions against its
+// stored field stats, reporting new categorical values, null rate rises and type conformance drops
+func (h *DatasetHandlers) GetDatasetDrift() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, ok := h.authorizeDatasetAccess(c)
+		if !ok {
+			return
+		}
+
+		limit, err := strconv.Atoi(c.DefaultQuery("submissions", strconv.Itoa(defaultDriftSubmissions)))
+		if err != nil || limit < 1 || limit > maxDriftSubmissions {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("submissions must be between 1 and %d", maxDriftSubmissions)})
+			return
+		}
+
+		baseline, _, err := h.statsSvc.Get(c.Request.Context(), datasetID)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrDatasetNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+			case errors.Is(err, repository.ErrFieldStatsNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Stats have not been computed for this dataset"})
+			default:
+				log.Printf("Error getting field stats for dataset %s: %v", datasetID, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset stats"})
+			}
+			return
+		}
+
+		all, err := h.submissions.GetSubmissionsByDataset(datasetID)
+		if err != nil {
+			log.Printf("Error fetching submissions for dataset %s: %v", datasetID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
+			return
+		}
+
+		// Submissions come newest first; rejected and expired ones never reach the dataset
+		var recent []*models.DataSubmission
+		for _, submission := range all {
+			if submission.Status == models.DataSubmissionStatusRejected || submission.Status == models.DataSubmissionStatusExpired {
+				continue
+			}
+			recent = append(recent, &submission.DataSubmission)
+			if len(recent) == limit {
+				break
+			}
+		}
+
+		piiSchema, ok := h.callerPIISchema(c, datasetID)
+		if !ok {
+			return
+		}
+
+		report := services.DetectDrift(baseline, services.SubmissionFieldStatsFromResults(recent), services.DefaultDriftThresholds)
+		services.HidePIIDriftValues(report, piiSchema)
+		c.JSON(http.StatusOK, gin.H{"drift": report})
+	}
+}
+
+// authorizeDatasetAccess parses the dataset ID route param and checks the caller can access it,
+// writing the error response and returning false otherwise
+func (h *DatasetHandlers) authorizeDatasetAccess(c *gin.Context) (uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+
+	datasetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+		return uuid.Nil, false
+	}
+
+	hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
+	if err != nil {
+		log.Printf("Error checking dataset access: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+		return uuid.Nil, false
+	}
+
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
+		return uuid.Nil, false
+	}
+
+	return datasetID, true
+}
+
+// callerPIISchema returns the schema whose PII fields must be hidden from the caller, for handlers
+// that have passed authorizeDatasetAccess
+func (h *DatasetHandlers) callerPIISchema(c *gin.Context, datasetID uuid.UUID) (*models.DatasetSchema, bool) {
+	userID, _ := c.Get("user_id")
+	userUUID, _ := userID.(uuid.UUID)
+	return piiMaskingSchema(c, h.schemaRepo, datasetID, userUUID)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/services"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tealeg/xlsx/v3"
+)
+
+func TestDatasetHandlers_ProcessCSV_RowLimit(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "data.csv"), []byte("id,name\n"+strings.Repeat("1,a\n", 5)), 0644))
+
+	t.Run("within limit", func(t *testing.T) {
+		h := &DatasetHandlers{files: storage.NewLocalStorage(root), maxRows: 5}
+		parsed, err := h.processFile("data.csv", "data.csv", nil, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "name"}, parsed.Headers)
+		assert.Len(t, parsed.Rows, 5)
+		assert.Empty(t, parsed.Sheet)
+	})
+
+	t.Run("exceeds limit", func(t *testing.T) {
+		h := &DatasetHandlers{files: storage.NewLocalStorage(root), maxRows: 2}
+		_, err := h.processFile("data.csv", "data.csv", nil, "")
+		var rowLimitErr *services.RowLimitError
+		require.True(t, errors.As(err, &rowLimitErr))
+		assert.Equal(t, 5, rowLimitErr.Rows)
+		assert.Equal(t, 2, rowLimitErr.Limit)
+	})
+}
+
+func TestDatasetHandlers_ProcessCSV_Dialect(t *testing.T) {
+	input := "id;name\n1;'Smith; John'\n2;'O''Brien'\n"
+
+	h := &DatasetHandlers{}
+	rowCount, _, headers, dataRows, err := h.processCSV(strings.NewReader(input), &models.CSVDialect{Delimiter: ";", Quote: "'"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, rowCount)
+	assert.Equal(t, []string{"id", "name"}, headers)
+	assert.Equal(t, [][]string{{"1", "Smith; John"}, {"2", "O'Brien"}}, dataRows)
+}
+
+// workbook builds an xlsx file with one sheet per name, each with a "sheet" column holding its name
+func workbook(t *testing.T, names ...string) []byte {
+	t.Helper()
+	file := xlsx.NewFile()
+	for _, name := range names {
+		sheet, err := file.AddSheet(name)
+		require.NoError(t, err)
+		sheet.AddRow().AddCell().SetString("sheet")
+		sheet.AddRow().AddCell().SetString(name)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+	return buf.Bytes()
+}
+
+func TestDatasetHandlers_ProcessExcel_SheetSelection(t *testing.T) {
+	content := workbook(t, "Summary", "Data", "2")
+	h := &DatasetHandlers{}
+
+	tests := []struct {
+		selection string
+		want      string
+	}{
+		{"", "Summary"},
+		{"Data", "Data"},
+		{"1", "Data"},
+		{"2", "2"}, // names win over indexes
+	}
+	for _, tt := range tests {
+		parsed, err := h.processExcel(bytes.NewReader(content), tt.selection)
+		require.NoError(t, err, tt.selection)
+		assert.Equal(t, tt.want, parsed.Sheet)
+		assert.Equal(t, [][]string{{tt.want}}, parsed.Rows, "rows come from the selected sheet")
+		assert.Equal(t, []string{"Summary", "Data", "2"}, parsed.Sheets)
+	}
+
+	for _, selection := range []string{"Missing", "3", "-1"} {
+		_, err := h.processExcel(bytes.NewReader(content), selection)
+		var sheetErr *SheetNotFoundError
+		require.True(t, errors.As(err, &sheetErr), selection)
+		assert.Equal(t, []string{"Summary", "Data", "2"}, sheetErr.Sheets)
+	}
+}
+
+func TestDatasetHandlers_ProcessExcel_TypedValues(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("Data")
+	require.NoError(t, err)
+	header := sheet.AddRow()
+	for _, name := range []string{"name", "joined", "last_seen", "amount", "active", "total", "stale"} {
+		header.AddCell().SetString(name)
+	}
+	row := sheet.AddRow()
+	row.AddCell().SetString("Ada")
+	row.AddCell().SetDate(time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC))
+	row.AddCell().SetDateTime(time.Date(2024, 3, 9, 14, 30, 0, 0, time.UTC))
+	row.AddCell().SetFloatWithFormat(1234.5, "#,##0.00")
+	row.AddCell().SetBool(true)
+	total := row.AddCell()
+	total.SetFormula("D2*2")
+	total.Value = "2469"
+	row.AddCell().SetFormula("D2*3")
+
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+
+	parsed, err := (&DatasetHandlers{}).processExcel(&buf, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "joined", "last_seen", "amount", "active", "total", "stale"}, parsed.Headers)
+	assert.Equal(t, [][]string{{"Ada", "2024-03-09", "2024-03-09 14:30:00", "1234.5", "true", "2469", ""}}, parsed.Rows)
+}
+
+func TestDatasetListPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		query        string
+		wantPage     int
+		wantPageSize int
+	}{
+		{"", 1, 20},
+		{"?page=3&page_size=50", 3, 50},
+		{"?page=0&page_size=500", 1, 20},
+		{"?page=abc&page_size=-1", 1, 20},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/datasets/user"+tt.query, nil)
+
+		page, pageSize := datasetListPage(c)
+		assert.Equal(t, tt.wantPage, page, tt.query)
+		assert.Equal(t, tt.wantPageSize, pageSize, tt.query)
+	}
+}
+
+func TestPreviewRows(t *testing.T) {
+	headers := []string{"id", "name"}
+	records := [][]string{{"1", "Ann"}, {"2"}, {"3", "Cy"}}
+
+	rows := previewRows(headers, records, 2)
+	assert.Equal(t, []map[string]interface{}{
+		{"id": "1", "name": "Ann"},
+		{"id": "2", "name": ""},
+	}, rows)
+	assert.Len(t, previewRows(headers, records, 10), 3)
+	assert.Empty(t, previewRows(headers, nil, 10))
+}
+
+func TestIngestionOutcome(t *testing.T) {
+	status, warning := ingestionOutcome(&models.BulkInsertReport{TotalRows: 3, FailedRows: []models.BulkInsertRowError{{RowIndex: 0}, {RowIndex: 1}, {RowIndex: 2}}})
+	assert.Equal(t, models.DatasetStatusIngestionFailed, status)
+	assert.Contains(t, warning, "None of the 3 rows")
+
+	status, warning = ingestionOutcome(&models.BulkInsertReport{TotalRows: 3, InsertedRows: 2, FailedRows: []models.BulkInsertRowError{{RowIndex: 1}}})
+	assert.Equal(t, models.DatasetStatusPartial, status)
+	assert.Equal(t, "1 of 3 rows could not be stored; see insert_report for details", warning)
+
+	status, warning = ingestionOutcome(&models.BulkInsertReport{TotalRows: 3, InsertedRows: 3})
+	assert.Empty(t, status)
+	assert.Empty(t, warning)
+
+	status, _ = ingestionOutcome(nil)
+	assert.Empty(t, status, "files without rows keep their processing status")
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+	"github.com/saurabh22suman/oreo.io/internal/services"
+)
+
+// DirectAppendHandlers contains handlers for trusted service appends
+type DirectAppendHandlers struct {
+	appendSvc *services.DirectAppendService
+}
+
+// NewDirectAppendHandlers creates new direct append handlers
+func NewDirectAppendHandlers(appendSvc *services.DirectAppendService) *DirectAppendHandlers {
+	return &DirectAppendHandlers{appendSvc: appendSvc}
+}
+
+// AppendRows appends pre-validated rows to a trusted dataset without staging or review
+func (h *DirectAppendHandlers) AppendRows() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get API key from API key middleware
+		value, exists := c.Get("api_key")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+			return
+		}
+
+		key, ok := value.(*models.APIKey)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid API key"})
+			return
+		}
+
+		datasetIDStr := c.Param("dataset_id")
+		datasetID, err := uuid.Parse(datasetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		var req models.DirectAppendRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		result, err := h.appendSvc.AppendRows(key, datasetID, req.Rows)
+		if err != nil {
+			var coercionErr *services.CoercionError
+			var duplicateErr *services.PIIDuplicateError
+			switch {
+			case errors.As(err, &coercionErr):
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":  "Rows do not match the dataset schema",
+					"errors": coercionErr.Errors,
+				})
+			case errors.As(err, &duplicateErr):
+				c.JSON(http.StatusConflict, gin.H{
+					"error":  "Rows repeat contact details already in the dataset",
+					"errors": duplicateErr.Errors,
+				})
+			case errors.Is(err, services.ErrMissingScope),
+				errors.Is(err, services.ErrReadOnlyAPIKey),
+				errors.Is(err, services.ErrProjectMismatch),
+				errors.Is(err, services.ErrDatasetNotTrusted):
+				c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			case errors.Is(err, repository.ErrDatasetNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+			case errors.Is(err, repository.ErrSchemaNotFound):
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Dataset has no schema"})
+			default:
+				log.Printf("Error appending rows directly: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to append rows"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Rows appended successfully",
+			"result":  result,
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weakETag builds a weak entity tag from a last-modified time and the parameters that shape the response
+func weakETag(lastModified time.Time, params ...interface{}) string {
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "%d", lastModified.UTC().UnixNano())
+	for _, param := range params {
+		fmt.Fprintf(hasher, "|%v", param)
+	}
+	return `W/"` + hex.EncodeToString(hasher.Sum(nil))[:32] + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches the etag using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeakETag(t *testing.T) {
+	modified := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	etag := weakETag(modified, 1, 50, 1000)
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, etag, weakETag(modified, 1, 50, 1000))
+	assert.NotEqual(t, etag, weakETag(modified, 2, 50, 1000))
+	assert.NotEqual(t, etag, weakETag(modified.Add(time.Millisecond), 1, 50, 1000))
+}
+
+func TestEtagMatches(t *testing.T) {
+	etag := `W/"abc"`
+
+	assert.True(t, etagMatches(`W/"abc"`, etag))
+	assert.True(t, etagMatches(`"abc"`, etag))
+	assert.True(t, etagMatches(`"other", W/"abc"`, etag))
+	assert.True(t, etagMatches(`*`, etag))
+	assert.False(t, etagMatches(``, etag))
+	assert.False(t, etagMatches(`W/"other"`, etag))
+}
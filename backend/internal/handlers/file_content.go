@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLength is the number of leading bytes inspected by http.DetectContentType
+const sniffLength = 512
+
+// oleSignature is the header of legacy OLE2 compound documents such as .xls workbooks
+var oleSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+// verifyFileContent sniffs the leading bytes of an upload and checks they match its extension.
+// The reader is rewound to the start so the full content can still be saved.
+func verifyFileContent(file io.ReadSeeker, filename string) error {
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("failed to read file content: %w", err)
+	}
+	head = head[:n]
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read file content: %w", err)
+	}
+
+	detected := http.DetectContentType(head)
+	ext := strings.ToLower(filepath.Ext(filename))
+
+	switch ext {
+	case ".csv":
+		// CSV has no signature of its own; it sniffs as plain text, while HTML or XML is rejected
+		if !strings.HasPrefix(detected, "text/plain") && !strings.HasPrefix(detected, "text/csv") {
+			return fmt.Errorf("file content (%s) does not match the .csv extension", mediaType(detected))
+		}
+	case ".xlsx":
+		if detected != "application/zip" {
+			return fmt.Errorf("file content (%s) does not match the .xlsx extension", mediaType(detected))
+		}
+	case ".xls":
+		if !bytes.HasPrefix(head, oleSignature) && detected != "application/zip" {
+			return fmt.Errorf("file content (%s) does not match the .xls extension", mediaType(detected))
+		}
+	default:
+		return fmt.Errorf("unsupported file type: %s", ext)
+	}
+
+	return nil
+}
+
+// mediaType strips parameters such as charset from a detected content type
+func mediaType(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		return contentType[:i]
+	}
+	return contentType
+}
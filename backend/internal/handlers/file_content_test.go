@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyFileContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantErr  string
+	}{
+		{name: "plain csv", filename: "data.csv", content: "id,name\n1,Alice\n"},
+		{name: "empty csv", filename: "data.csv", content: ""},
+		{name: "xlsx zip", filename: "book.xlsx", content: "PK\x03\x04\x14\x00\x06\x00rest-of-archive"},
+		{name: "legacy xls", filename: "book.xls", content: "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1rest-of-document"},
+		{name: "html renamed to csv", filename: "data.csv", content: "<!DOCTYPE html><html><body>hi</body></html>", wantErr: "text/html"},
+		{name: "binary renamed to csv", filename: "data.csv", content: "\x00\x01\x02\x03\xff\xfe", wantErr: "does not match the .csv extension"},
+		{name: "zip renamed to csv", filename: "data.csv", content: "PK\x03\x04\x14\x00\x06\x00", wantErr: "application/zip"},
+		{name: "csv renamed to xlsx", filename: "book.xlsx", content: "id,name\n1,Alice\n", wantErr: "does not match the .xlsx extension"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := strings.NewReader(tt.content)
+			err := verifyFileContent(reader, tt.filename)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// The reader must be rewound so the whole file is still saved
+			rest, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(rest))
+		})
+	}
+}
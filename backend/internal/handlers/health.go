@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"database/sql"
 	"net/http"
 	"time"
 
@@ -28,24 +27,39 @@ type Services struct {
 type DatabaseStatus struct {
 	Status   string `json:"status"`
 	Response string `json:"response_time,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 // RedisStatus represents Redis health status
 type RedisStatus struct {
 	Status   string `json:"status"`
 	Response string `json:"response_time,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// healthCheckTimeout bounds each ping so a hung dependency fails the check instead of stalling it
+const healthCheckTimeout = 2 * time.Second
+
+// DatabasePinger is the database connection checked by the health endpoints; *sql.DB implements it
+type DatabasePinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// RedisPinger is the Redis connection checked by the health endpoints; *redis.Client implements it
+type RedisPinger interface {
+	Ping(ctx context.Context) *redis.StatusCmd
 }
 
 // HealthCheck returns the overall health status
-func HealthCheck(db *sql.DB, rdb *redis.Client) gin.HandlerFunc {
+func HealthCheck(db DatabasePinger, rdb RedisPinger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
 		// Check database
-		dbStatus := checkDatabase(db)
+		dbStatus := checkDatabase(c.Request.Context(), db)
 
 		// Check Redis
-		redisStatus := checkRedis(rdb)
+		redisStatus := checkRedis(c.Request.Context(), rdb)
 
 		// Determine overall status
 		status := "healthy"
@@ -76,11 +90,47 @@ func HealthCheck(db *sql.DB, rdb *redis.Client) gin.HandlerFunc {
 	}
 }
 
+// ReadinessResponse represents the readiness probe response
+type ReadinessResponse struct {
+	Status   string   `json:"status"` // "ready" or "not_ready"
+	Services Services `json:"services"`
+}
+
+// LivenessCheck reports that the process is up and serving requests. It never touches the
+// database or Redis, so an outage there doesn't get healthy instances restarted.
+func LivenessCheck() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "alive"})
+	}
+}
+
+// ReadinessCheck reports whether the instance can take traffic: 200 only when both the database
+// and Redis respond, 503 otherwise
+func ReadinessCheck(db DatabasePinger, rdb RedisPinger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := ReadinessResponse{
+			Status: "ready",
+			Services: Services{
+				Database: checkDatabase(c.Request.Context(), db),
+				Redis:    checkRedis(c.Request.Context(), rdb),
+			},
+		}
+
+		statusCode := http.StatusOK
+		if response.Services.Database.Status != "healthy" || response.Services.Redis.Status != "healthy" {
+			response.Status = "not_ready"
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		c.JSON(statusCode, response)
+	}
+}
+
 // DatabaseHealthCheck returns database-specific health status
-func DatabaseHealthCheck(db *sql.DB) gin.HandlerFunc {
+func DatabaseHealthCheck(db DatabasePinger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		status := checkDatabase(db)
+		status := checkDatabase(c.Request.Context(), db)
 
 		statusCode := http.StatusOK
 		if status.Status != "healthy" {
@@ -93,10 +143,10 @@ func DatabaseHealthCheck(db *sql.DB) gin.HandlerFunc {
 }
 
 // RedisHealthCheck returns Redis-specific health status
-func RedisHealthCheck(rdb *redis.Client) gin.HandlerFunc {
+func RedisHealthCheck(rdb RedisPinger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		status := checkRedis(rdb)
+		status := checkRedis(c.Request.Context(), rdb)
 
 		statusCode := http.StatusOK
 		if status.Status != "healthy" {
@@ -108,27 +158,24 @@ func RedisHealthCheck(rdb *redis.Client) gin.HandlerFunc {
 	}
 }
 
-// checkDatabase performs database health check
-func checkDatabase(db *sql.DB) DatabaseStatus {
+// checkDatabase pings the database within healthCheckTimeout
+func checkDatabase(ctx context.Context, db DatabasePinger) DatabaseStatus {
 	start := time.Now()
 
 	if db == nil {
 		return DatabaseStatus{
 			Status: "unhealthy",
+			Error:  "database is not configured",
 		}
 	}
 
-	if err := db.Ping(); err != nil {
-		return DatabaseStatus{
-			Status: "unhealthy",
-		}
-	}
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
 
-	// Test with a simple query
-	var result int
-	if err := db.QueryRow("SELECT 1").Scan(&result); err != nil {
+	if err := db.PingContext(ctx); err != nil {
 		return DatabaseStatus{
 			Status: "unhealthy",
+			Error:  err.Error(),
 		}
 	}
 
@@ -138,21 +185,24 @@ func checkDatabase(db *sql.DB) DatabaseStatus {
 	}
 }
 
-// checkRedis performs Redis health check
-func checkRedis(rdb *redis.Client) RedisStatus {
+// checkRedis pings Redis within healthCheckTimeout
+func checkRedis(ctx context.Context, rdb RedisPinger) RedisStatus {
 	start := time.Now()
 
 	if rdb == nil {
 		return RedisStatus{
 			Status: "unhealthy",
+			Error:  "redis is not configured",
 		}
 	}
 
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
 
 	if err := rdb.Ping(ctx).Err(); err != nil {
 		return RedisStatus{
 			Status: "unhealthy",
+			Error:  err.Error(),
 		}
 	}
 
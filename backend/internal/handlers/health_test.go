@@ -1,11 +1,13 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -13,43 +15,81 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// fakeDatabase answers pings with err, or waits for the deadline when hang is set
+type fakeDatabase struct {
+	err  error
+	hang bool
+}
+
+func (f *fakeDatabase) PingContext(ctx context.Context) error {
+	if f.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return f.err
+}
+
+// fakeRedis answers pings with PONG or err
+type fakeRedis struct {
+	err error
+}
+
+func (f *fakeRedis) Ping(ctx context.Context) *redis.StatusCmd {
+	cmd := redis.NewStatusCmd(ctx)
+	if f.err != nil {
+		cmd.SetErr(f.err)
+	} else {
+		cmd.SetVal("PONG")
+	}
+	return cmd
+}
+
 func TestHealthCheck(t *testing.T) {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
 
 	tests := []struct {
 		name           string
-		setupDB        func() *sql.DB
-		setupRedis     func() *redis.Client
+		db             DatabasePinger
+		redis          RedisPinger
 		expectedStatus int
 		expectedHealth string
+		expectedDB     string
+		expectedRedis  string
 	}{
 		{
-			name: "healthy services",
-			setupDB: func() *sql.DB {
-				// TODO: Return mock healthy DB
-				return nil
-			},
-			setupRedis: func() *redis.Client {
-				// TODO: Return mock healthy Redis
-				return nil
-			},
+			name:           "healthy services",
+			db:             &fakeDatabase{},
+			redis:          &fakeRedis{},
 			expectedStatus: http.StatusOK,
 			expectedHealth: "healthy",
+			expectedDB:     "healthy",
+			expectedRedis:  "healthy",
+		},
+		{
+			name:           "database down",
+			db:             &fakeDatabase{err: errors.New("connection refused")},
+			redis:          &fakeRedis{},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHealth: "unhealthy",
+			expectedDB:     "unhealthy",
+			expectedRedis:  "healthy",
+		},
+		{
+			name:           "redis down",
+			db:             &fakeDatabase{},
+			redis:          &fakeRedis{err: errors.New("i/o timeout")},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHealth: "unhealthy",
+			expectedDB:     "healthy",
+			expectedRedis:  "unhealthy",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// TODO: Implement proper mocking
-			// For now, skip this test until we have proper mock setup
-			t.Skip("Skipping until mock setup is complete")
-
 			router := gin.New()
-			db := tt.setupDB()
-			redis := tt.setupRedis()
-
-			router.GET("/health", HealthCheck(db, redis))
+			router.GET("/health", HealthCheck(tt.db, tt.redis))
 
 			req, _ := http.NewRequest("GET", "/health", nil)
 			w := httptest.NewRecorder()
@@ -61,10 +101,44 @@ func TestHealthCheck(t *testing.T) {
 			err := json.Unmarshal(w.Body.Bytes(), &response)
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedHealth, response.Status)
+			assert.Equal(t, tt.expectedDB, response.Services.Database.Status)
+			assert.Equal(t, tt.expectedRedis, response.Services.Redis.Status)
 		})
 	}
 }
 
+func TestHealthCheck_ReportsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/health/db", DatabaseHealthCheck(&fakeDatabase{err: errors.New("connection refused")}))
+	router.GET("/health/redis", RedisHealthCheck(&fakeRedis{err: errors.New("NOAUTH Authentication required")}))
+
+	for path, expected := range map[string]string{
+		"/health/db":    "connection refused",
+		"/health/redis": "NOAUTH Authentication required",
+	} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+		var status DatabaseStatus
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		assert.Equal(t, "unhealthy", status.Status, path)
+		assert.Equal(t, expected, status.Error, path)
+	}
+}
+
+func TestHealthCheck_TimesOut(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	status := checkDatabase(ctx, &fakeDatabase{hang: true})
+	assert.Equal(t, "unhealthy", status.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), status.Error)
+}
+
 func TestDatabaseHealthCheck(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -98,3 +172,50 @@ func TestRedisHealthCheck(t *testing.T) {
 		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
 	})
 }
+
+func TestLivenessCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/health/live", LivenessCheck())
+
+	req, _ := http.NewRequest("GET", "/health/live", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "alive"}`, w.Body.String())
+}
+
+func TestReadinessCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		db             DatabasePinger
+		redis          RedisPinger
+		expectedStatus int
+		expectedReady  string
+	}{
+		{"dependencies respond", &fakeDatabase{}, &fakeRedis{}, http.StatusOK, "ready"},
+		{"database not warmed up", &fakeDatabase{err: errors.New("connection refused")}, &fakeRedis{}, http.StatusServiceUnavailable, "not_ready"},
+		{"redis down", &fakeDatabase{}, &fakeRedis{err: errors.New("i/o timeout")}, http.StatusServiceUnavailable, "not_ready"},
+		{"nothing configured", nil, nil, http.StatusServiceUnavailable, "not_ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/health/ready", ReadinessCheck(tt.db, tt.redis))
+
+			req, _ := http.NewRequest("GET", "/health/ready", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response ReadinessResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedReady, response.Status)
+		})
+	}
+}
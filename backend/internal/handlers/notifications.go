@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+)
+
+// maxUnreadNotifications caps how many unread notifications are returned at once
+const maxUnreadNotifications = 100
+
+// NotificationHandlers contains handlers for the current user's in-app notifications
+type NotificationHandlers struct {
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewNotificationHandlers creates new notification handlers
+func NewNotificationHandlers(notificationRepo *repository.NotificationRepository) *NotificationHandlers {
+	return &NotificationHandlers{notificationRepo: notificationRepo}
+}
+
+// GetNotifications returns the current user's unread notifications, newest first
+func (h *NotificationHandlers) GetNotifications() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		notifications, err := h.notificationRepo.ListUnread(userUUID, maxUnreadNotifications)
+		if err != nil {
+			log.Printf("Error getting notifications: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve notifications"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"notifications": notifications,
+			"count":         len(notifications),
+		})
+	}
+}
+
+// MarkNotificationRead marks one of the current user's notifications as read
+func (h *NotificationHandlers) MarkNotificationRead() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		notificationID, err := uuid.Parse(c.Param("notification_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+			return
+		}
+
+		if err := h.notificationRepo.MarkRead(userUUID, notificationID); err != nil {
+			if errors.Is(err, repository.ErrNotificationNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found or already read"})
+				return
+			}
+			log.Printf("Error marking notification read: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark notification read"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+	}
+}
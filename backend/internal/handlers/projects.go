@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 
@@ -10,18 +11,26 @@ import (
 
 	"github.com/saurabh22suman/oreo.io/internal/models"
 	"github.com/saurabh22suman/oreo.io/internal/repository"
+	"github.com/saurabh22suman/oreo.io/internal/services"
 )
 
 // ProjectHandlers contains project-related handlers
 type ProjectHandlers struct {
 	projectRepo *repository.ProjectRepository
+	memberRepo  *repository.ProjectMemberRepository
+	userRepo    repository.UserRepository
+	displayRows services.DisplayRowLimits
 }
 
-// NewProjectHandlers creates new project handlers
-func NewProjectHandlers(db *sqlx.DB) *ProjectHandlers {
+// NewProjectHandlers creates new project handlers; userRepo resolves invitees by email and
+// displayRows bounds the row display cap owners can set on a project
+func NewProjectHandlers(db *sqlx.DB, userRepo repository.UserRepository, displayRows services.DisplayRowLimits) *ProjectHandlers {
 	log.Printf("Creating new ProjectHandlers with db: %+v", db)
 	handlers := &ProjectHandlers{
 		projectRepo: repository.NewProjectRepository(db),
+		memberRepo:  repository.NewProjectMemberRepository(db),
+		userRepo:    userRepo,
+		displayRows: displayRows,
 	}
 	log.Printf("Created ProjectHandlers: %+v", handlers)
 	return handlers
@@ -232,6 +241,16 @@ func (h *ProjectHandlers) UpdateProject() gin.HandlerFunc {
 			return
 		}
 
+		if req.MaxDisplayRows != nil {
+			if err := h.displayRows.ValidateProjectCap(*req.MaxDisplayRows); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Validation failed",
+					"details": err.Error(),
+				})
+				return
+			}
+		}
+
 		// Check if there are any updates
 		if !req.HasUpdates() {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "No updates provided"})
@@ -294,3 +313,165 @@ func (h *ProjectHandlers) DeleteProject() gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
 	}
 }
+
+// InviteMember invites a registered user, found by email, to join a project
+func (h *ProjectHandlers) InviteMember() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from auth middleware
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		// Parse project ID from URL
+		projectIDStr := c.Param("id")
+		projectID, err := uuid.Parse(projectIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid project ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		// Parse request body
+		var req models.InviteUserRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request data",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if !req.ValidateRole() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be admin, collaborator or viewer"})
+			return
+		}
+
+		// Only owners and admins can invite; non-members don't learn the project exists
+		role, err := h.memberRepo.GetUserRole(projectID, userUUID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+
+		if !models.CanManageMembers(role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only project owners and admins can invite members"})
+			return
+		}
+
+		invitee, err := h.userRepo.GetByEmail(c.Request.Context(), req.Email)
+		if err != nil {
+			if errors.Is(err, repository.ErrUserNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "No user is registered with that email"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to look up user",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		member, err := h.memberRepo.InviteUser(projectID, userUUID, invitee.ID, req.Role, req.Permissions)
+		if err != nil {
+			if errors.Is(err, repository.ErrAlreadyMember) {
+				c.JSON(http.StatusConflict, gin.H{"error": "User is already a member or has a pending invitation"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to invite user",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Invitation sent successfully",
+			"member":  member,
+		})
+	}
+}
+
+// GetInvitations returns the authenticated user's pending project invitations
+func (h *ProjectHandlers) GetInvitations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from auth middleware
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		invitations, err := h.memberRepo.GetPendingInvitations(userUUID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to retrieve invitations",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"invitations": invitations,
+			"count":       len(invitations),
+		})
+	}
+}
+
+// AcceptInvitation accepts the authenticated user's pending invitation to a project
+func (h *ProjectHandlers) AcceptInvitation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get user ID from auth middleware
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		// Parse project ID from URL
+		projectIDStr := c.Param("id")
+		projectID, err := uuid.Parse(projectIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid project ID",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if err := h.memberRepo.AcceptInvitation(projectID, userUUID); err != nil {
+			if errors.Is(err, repository.ErrInvitationNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "No pending invitation for this project"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to accept invitation",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted successfully"})
+	}
+}
@@ -1,15 +1,18 @@
 package handlers
 
 import (
-	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/saurabh22suman/oreo.io/internal/services"
 )
 
 // SampleDataHandlers provides endpoints for accessing sample datasets
@@ -36,11 +39,121 @@ type DatasetInfo struct {
 	Description string              `json:"description,omitempty"`
 }
 
+// categories lists the sample dataset categories: the visible subfolders of the sample data
+// directory, read on each request so a new folder is served without a restart
+func (h *SampleDataHandlers) categories() ([]string, error) {
+	entries, err := os.ReadDir(h.sampleDataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			categories = append(categories, entry.Name())
+		}
+	}
+	sort.Strings(categories)
+	return categories, nil
+}
+
+// errOutsideSampleData is returned for a sample file whose resolved path leaves the sample data directory
+var errOutsideSampleData = errors.New("path is outside the sample data directory")
+
+// sampleFilePath returns the absolute path of a sample file, with symlinks resolved when it exists,
+// and checks that it stays within the sample data directory
+func (h *SampleDataHandlers) sampleFilePath(category, filename string) (string, error) {
+	root, err := filepath.Abs(h.sampleDataPath)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	path := filepath.Join(root, category, filename)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errOutsideSampleData
+	}
+	return path, nil
+}
+
+// resolveSampleFile checks the category is one of the sample data folders and the filename names
+// a CSV file directly inside it, responding with an error and returning false otherwise. It returns
+// the category, the filename and the file's path, which is checked to be within the sample data directory.
+func (h *SampleDataHandlers) resolveSampleFile(c *gin.Context) (string, string, string, bool) {
+	category := c.Param("category")
+	filename := c.Param("filename")
+
+	// Add .csv extension if not provided
+	if !strings.HasSuffix(filename, ".csv") {
+		filename += ".csv"
+	}
+
+	categories, err := h.categories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to read sample data",
+		})
+		return "", "", "", false
+	}
+
+	valid := false
+	for _, name := range categories {
+		if name == category {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid category. Valid categories: " + strings.Join(categories, ", "),
+		})
+		return "", "", "", false
+	}
+
+	// Filenames name a file directly inside the category: no separators, parent references or hidden files
+	if strings.ContainsAny(filename, `/\`+"\x00") || strings.Contains(filename, "..") || strings.HasPrefix(filename, ".") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid filename",
+		})
+		return "", "", "", false
+	}
+
+	filePath, err := h.sampleFilePath(category, filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid file path",
+		})
+		return "", "", "", false
+	}
+
+	return category, filename, filePath, true
+}
+
 // ListSampleDatasets returns a list of available sample datasets
 func (h *SampleDataHandlers) ListSampleDatasets(c *gin.Context) {
 	datasets := make(map[string][]DatasetInfo)
 
-	categories := []string{"transportation", "users", "finance", "mixed"}
+	categories, err := h.categories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to read sample data",
+		})
+		return
+	}
 
 	for _, category := range categories {
 		categoryPath := filepath.Join(h.sampleDataPath, category)
@@ -73,12 +186,9 @@ func (h *SampleDataHandlers) ListSampleDatasets(c *gin.Context) {
 
 // GetSampleDatasetInfo returns detailed metadata about a specific dataset
 func (h *SampleDataHandlers) GetSampleDatasetInfo(c *gin.Context) {
-	category := c.Param("category")
-	filename := c.Param("filename")
-
-	// Add .csv extension if not provided
-	if !strings.HasSuffix(filename, ".csv") {
-		filename += ".csv"
+	category, filename, _, ok := h.resolveSampleFile(c)
+	if !ok {
+		return
 	}
 
 	info, err := h.getDatasetInfo(category, filename)
@@ -98,30 +208,11 @@ func (h *SampleDataHandlers) GetSampleDatasetInfo(c *gin.Context) {
 
 // DownloadSampleDataset allows downloading a specific sample dataset
 func (h *SampleDataHandlers) DownloadSampleDataset(c *gin.Context) {
-	category := c.Param("category")
-	filename := c.Param("filename")
-
-	// Add .csv extension if not provided
-	if !strings.HasSuffix(filename, ".csv") {
-		filename += ".csv"
-	}
-
-	// Validate category
-	validCategories := map[string]bool{
-		"transportation": true, "users": true, "finance": true, "mixed": true,
-	}
-
-	if !validCategories[category] {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   "Invalid category. Valid categories: transportation, users, finance, mixed",
-		})
+	_, filename, filePath, ok := h.resolveSampleFile(c)
+	if !ok {
 		return
 	}
 
-	// Construct file path
-	filePath := filepath.Join(h.sampleDataPath, category, filename)
-
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -139,12 +230,9 @@ func (h *SampleDataHandlers) DownloadSampleDataset(c *gin.Context) {
 
 // PreviewSampleDataset returns a preview of the dataset (first few rows)
 func (h *SampleDataHandlers) PreviewSampleDataset(c *gin.Context) {
-	category := c.Param("category")
-	filename := c.Param("filename")
-
-	// Add .csv extension if not provided
-	if !strings.HasSuffix(filename, ".csv") {
-		filename += ".csv"
+	category, filename, filePath, ok := h.resolveSampleFile(c)
+	if !ok {
+		return
 	}
 
 	// Parse query parameters
@@ -157,8 +245,6 @@ func (h *SampleDataHandlers) PreviewSampleDataset(c *gin.Context) {
 		limit = 100 // Max limit for preview
 	}
 
-	filePath := filepath.Join(h.sampleDataPath, category, filename)
-
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		c.JSON(http.StatusNotFound, gin.H{
@@ -179,35 +265,15 @@ func (h *SampleDataHandlers) PreviewSampleDataset(c *gin.Context) {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-
-	// Read header
-	header, err := reader.Read()
+	header, rows, _, err := readSampleCSV(file, limit, false)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   "Failed to read CSV header",
+			"error":   err.Error(),
 		})
 		return
 	}
 
-	// Read data rows up to limit
-	var rows []map[string]string
-	for i := 0; i < limit; i++ {
-		record, err := reader.Read()
-		if err != nil {
-			break // End of file or error
-		}
-
-		row := make(map[string]string)
-		for j, value := range record {
-			if j < len(header) {
-				row[header[j]] = value
-			}
-		}
-		rows = append(rows, row)
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
@@ -222,7 +288,10 @@ func (h *SampleDataHandlers) PreviewSampleDataset(c *gin.Context) {
 
 // getDatasetInfo is a helper function to get dataset metadata
 func (h *SampleDataHandlers) getDatasetInfo(category, filename string) (*DatasetInfo, error) {
-	filePath := filepath.Join(h.sampleDataPath, category, filename)
+	filePath, err := h.sampleFilePath(category, filename)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if file exists
 	fileInfo, err := os.Stat(filePath)
@@ -237,43 +306,9 @@ func (h *SampleDataHandlers) getDatasetInfo(category, filename string) (*Dataset
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-
-	// Read header
-	header, err := reader.Read()
+	header, sampleData, rowCount, err := readSampleCSV(file, 3, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header")
-	}
-
-	// Count rows
-	rowCount := 0
-	for {
-		_, err := reader.Read()
-		if err != nil {
-			break
-		}
-		rowCount++
-	}
-
-	// Get sample data (first 3 rows)
-	file.Seek(0, 0)
-	reader = csv.NewReader(file)
-	reader.Read() // Skip header
-
-	var sampleData []map[string]string
-	for i := 0; i < 3; i++ {
-		record, err := reader.Read()
-		if err != nil {
-			break
-		}
-
-		row := make(map[string]string)
-		for j, value := range record {
-			if j < len(header) {
-				row[header[j]] = value
-			}
-		}
-		sampleData = append(sampleData, row)
+		return nil, err
 	}
 
 	// Add description based on filename
@@ -291,6 +326,43 @@ func (h *SampleDataHandlers) getDatasetInfo(category, filename string) (*Dataset
 	}, nil
 }
 
+// readSampleCSV reads a sample dataset's header and up to sampleRows data rows. With countRows it
+// reads the whole file to count its data rows, using the same CSV reader as dataset uploads so a
+// quoted value spanning several lines counts as one row and the count matches what gets stored.
+func readSampleCSV(file io.Reader, sampleRows int, countRows bool) ([]string, []map[string]string, int, error) {
+	reader := services.NewCSVReader(file, nil)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to read CSV header")
+	}
+
+	var rows []map[string]string
+	rowCount := 0
+	for rowCount < sampleRows || countRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to parse CSV row %d: %v", rowCount+1, err)
+		}
+		rowCount++
+
+		if len(rows) < sampleRows {
+			row := make(map[string]string)
+			for j, value := range record {
+				if j < len(header) {
+					row[header[j]] = value
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return header, rows, rowCount, nil
+}
+
 // getDatasetDescription returns a description for known datasets
 func (h *SampleDataHandlers) getDatasetDescription(filename string) string {
 	descriptions := map[string]string{
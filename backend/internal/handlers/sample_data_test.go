@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleDatasetInfo_RowCountMatchesUpload(t *testing.T) {
+	input := "\ufeffid,notes\n1,\"first line\nsecond line\"\n2,plain\n3,\"a \"\"quoted\"\"\r\nvalue\"\n"
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "mixed"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "mixed", "notes.csv"), []byte(input), 0644))
+
+	h := &SampleDataHandlers{sampleDataPath: root}
+	info, err := h.getDatasetInfo("mixed", "notes.csv")
+	require.NoError(t, err)
+
+	uploadRows, _, uploadHeaders, _, err := (&DatasetHandlers{}).processCSV(strings.NewReader(input), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, info.Rows)
+	assert.Equal(t, uploadRows, info.Rows)
+	assert.Equal(t, uploadHeaders, info.Columns)
+	require.Len(t, info.SampleData, 3)
+	assert.Equal(t, "first line\nsecond line", info.SampleData[0]["notes"])
+}
+
+func TestSampleDatasetInfo_MalformedRowIsReported(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "mixed"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "mixed", "bad.csv"), []byte("id,name\n1,a\n2,b,extra\n3,c\n"), 0644))
+
+	h := &SampleDataHandlers{sampleDataPath: root}
+	_, err := h.getDatasetInfo("mixed", "bad.csv")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "row 2")
+}
+
+func sampleDataRouter(root string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := &SampleDataHandlers{sampleDataPath: root}
+	router := gin.New()
+	router.GET("/sample-data", h.ListSampleDatasets)
+	router.GET("/sample-data/:category/:filename/info", h.GetSampleDatasetInfo)
+	router.GET("/sample-data/:category/:filename/download", h.DownloadSampleDataset)
+	return router
+}
+
+func TestSampleDataCategories_Discovered(t *testing.T) {
+	root := t.TempDir()
+	for _, category := range []string{"weather", "finance", ".cache"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, category), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, category, "data.csv"), []byte("id\n1\n"), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(root, "loose.csv"), []byte("id\n1\n"), 0644))
+	router := sampleDataRouter(root)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sample-data", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data map[string][]DatasetInfo `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Len(t, body.Data, 2)
+	require.Len(t, body.Data["weather"], 1)
+	assert.Equal(t, "data.csv", body.Data["weather"][0].Filename)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sample-data/weather/data/download", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "id\n1\n", w.Body.String())
+}
+
+func TestSampleDataCategories_RejectsUnknownAndTraversal(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "sample-data")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "finance"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "..", "secret.csv"), []byte("id\n1\n"), 0644))
+	router := sampleDataRouter(root)
+
+	for _, path := range []string{
+		"/sample-data/../secret/info",
+		"/sample-data/%2E%2E/secret/info",
+		"/sample-data/.cache/data/download",
+		"/sample-data/travel/data/download",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.NotEqual(t, http.StatusOK, w.Code, path)
+		assert.NotContains(t, w.Body.String(), "secret.csv", path)
+	}
+
+	// Parameters that reach the handler unrouted are checked too
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Params = gin.Params{{Key: "category", Value: ".."}, {Key: "filename", Value: "secret"}}
+	(&SampleDataHandlers{sampleDataPath: root}).GetSampleDatasetInfo(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSampleDataFilename_StaysInsideSampleData(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.csv"), []byte("id\n1\n"), 0644))
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "finance"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "finance", "ok.csv"), []byte("id\n1\n"), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "secret.csv"), filepath.Join(root, "finance", "link.csv")))
+	h := &SampleDataHandlers{sampleDataPath: root}
+
+	serve := func(handler gin.HandlerFunc, filename string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Params = gin.Params{{Key: "category", Value: "finance"}, {Key: "filename", Value: filename}}
+		handler(c)
+		return w
+	}
+
+	for _, filename := range []string{"../../secret", `..\secret`, "sub/ok", "..", "link"} {
+		for _, handler := range []gin.HandlerFunc{h.DownloadSampleDataset, h.PreviewSampleDataset, h.GetSampleDatasetInfo} {
+			w := serve(handler, filename)
+			assert.Equal(t, http.StatusBadRequest, w.Code, filename)
+			assert.NotContains(t, w.Body.String(), "1\n", filename)
+		}
+	}
+
+	assert.Equal(t, http.StatusOK, serve(h.PreviewSampleDataset, "ok").Code)
+}
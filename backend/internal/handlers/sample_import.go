@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ImportSampleDatasetRequest picks the project a sample dataset is copied into
+type ImportSampleDatasetRequest struct {
+	ProjectID   string `json:"project_id" binding:"required"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InsertMode  string `json:"insert_mode"`
+}
+
+// ImportSampleDataset copies a sample CSV into one of the user's projects as a new dataset, going
+// through the same storage, parsing and bulk insert as an upload
+func (h *DatasetHandlers) ImportSampleDataset(samples *SampleDataHandlers) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		var req ImportSampleDatasetRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Project ID is required"})
+			return
+		}
+
+		projectID, err := uuid.Parse(req.ProjectID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+
+		insertMode := req.InsertMode
+		if insertMode == "" {
+			insertMode = models.BulkInsertAllOrNothing
+		}
+		if !models.IsValidBulkInsertMode(insertMode) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("insert_mode must be '%s' or '%s'", models.BulkInsertAllOrNothing, models.BulkInsertBestEffort),
+			})
+			return
+		}
+
+		_, filename, filePath, ok := samples.resolveSampleFile(c)
+		if !ok {
+			return
+		}
+
+		hasAccess, err := h.datasetRepo.CheckProjectAccess(projectID, userUUID)
+		if err != nil {
+			log.Printf("Error checking project access: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+			return
+		}
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to upload to this project"})
+			return
+		}
+
+		file, err := os.Open(filePath)
+		if os.IsNotExist(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Error opening sample file %s: %v", filePath, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read sample dataset"})
+			return
+		}
+		defer file.Close()
+
+		stat, err := file.Stat()
+		if err != nil {
+			log.Printf("Error reading sample file %s: %v", filePath, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read sample dataset"})
+			return
+		}
+
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			name = strings.TrimSuffix(filename, filepath.Ext(filename))
+		}
+		description := req.Description
+		if description == "" {
+			description = samples.getDatasetDescription(filename)
+		}
+
+		dataset := &models.Dataset{
+			ID:          uuid.New(),
+			ProjectID:   projectID,
+			Name:        name,
+			Description: description,
+			FileName:    filename,
+			FileSize:    stat.Size(),
+			MimeType:    "text/csv",
+			Status:      models.DatasetStatusProcessing,
+			HeaderMode:  models.HeaderModeStrict,
+			UploadedBy:  userUUID,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+
+		h.ingestDataset(c, dataset, file, "", insertMode, "imported")
+	}
+}
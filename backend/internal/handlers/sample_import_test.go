@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleImportRouter(root string, userID interface{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if userID != nil {
+		router.Use(func(c *gin.Context) { c.Set("user_id", userID) })
+	}
+	h := &DatasetHandlers{}
+	router.POST("/sample-data/:category/:filename/import", h.ImportSampleDataset(&SampleDataHandlers{sampleDataPath: root}))
+	return router
+}
+
+func TestImportSampleDataset_RejectsBadRequests(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "weather"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "weather", "daily.csv"), []byte("day,temp\n1,20\n"), 0644))
+	projectID := uuid.New().String()
+
+	tests := []struct {
+		name   string
+		userID interface{}
+		path   string
+		body   string
+		status int
+		error  string
+	}{
+		{"not signed in", nil, "/sample-data/weather/daily/import", `{"project_id":"` + projectID + `"}`, http.StatusUnauthorized, "not authenticated"},
+		{"missing project", uuid.New(), "/sample-data/weather/daily/import", `{}`, http.StatusBadRequest, "Project ID is required"},
+		{"bad project", uuid.New(), "/sample-data/weather/daily/import", `{"project_id":"nope"}`, http.StatusBadRequest, "Invalid project ID"},
+		{"bad insert mode", uuid.New(), "/sample-data/weather/daily/import", `{"project_id":"` + projectID + `","insert_mode":"some"}`, http.StatusBadRequest, "insert_mode"},
+		{"unknown category", uuid.New(), "/sample-data/finance/daily/import", `{"project_id":"` + projectID + `"}`, http.StatusBadRequest, "Invalid category"},
+		{"hidden file", uuid.New(), "/sample-data/weather/.daily/import", `{"project_id":"` + projectID + `"}`, http.StatusBadRequest, "Invalid filename"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			sampleImportRouter(root, tt.userID).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.error)
+		})
+	}
+}
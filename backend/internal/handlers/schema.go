@@ -1,15 +1,18 @@
 package handlers
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
+	"github.com/saurabh22suman/oreo.io/internal/logging"
 	"github.com/saurabh22suman/oreo.io/internal/models"
 	"github.com/saurabh22suman/oreo.io/internal/repository"
 	"github.com/saurabh22suman/oreo.io/internal/services"
@@ -19,13 +22,23 @@ import (
 type SchemaHandlers struct {
 	schemaRepo        *repository.SchemaRepository
 	inferenceService  *services.SchemaInferenceService
+	inferenceCache    *services.SchemaInferenceCache
+	displayRows       services.DisplayRowLimits
+	webhooks          *services.WebhookDispatcher
+	dataPages         *services.DataPageCache
 }
 
-// NewSchemaHandlers creates new schema handlers
-func NewSchemaHandlers(db *sqlx.DB) *SchemaHandlers {
+// NewSchemaHandlers creates new schema handlers; displayRows bounds how many rows of a dataset
+// can be paged through, webhooks receives schema.updated events and dataPages, when not nil,
+// caches pages of dataset data
+func NewSchemaHandlers(db *sqlx.DB, inferenceCache *services.SchemaInferenceCache, displayRows services.DisplayRowLimits, webhooks *services.WebhookDispatcher, dataPages *services.DataPageCache) *SchemaHandlers {
 	return &SchemaHandlers{
 		schemaRepo:       repository.NewSchemaRepository(db),
 		inferenceService: services.NewSchemaInferenceService(),
+		inferenceCache:   inferenceCache,
+		displayRows:      displayRows,
+		webhooks:         webhooks,
+		dataPages:        dataPages,
 	}
 }
 
@@ -62,12 +75,32 @@ func (h *SchemaHandlers) CreateSchema() gin.HandlerFunc {
 			return
 		}
 
+		// Schema names identify variants, so they must be unique per dataset
+		if _, err := h.schemaRepo.GetSchemaByName(req.DatasetID, req.Name); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("A schema named '%s' already exists for this dataset", req.Name)})
+			return
+		} else if !errors.Is(err, repository.ErrSchemaNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing schemas"})
+			return
+		}
+
+		// The first schema of a dataset always becomes its default
+		isDefault := req.IsDefault != nil && *req.IsDefault
+		if _, err := h.schemaRepo.GetSchemaByDatasetID(req.DatasetID); errors.Is(err, repository.ErrSchemaNotFound) {
+			isDefault = true
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing schemas"})
+			return
+		}
+
 		// Create schema object
 		schema := &models.DatasetSchema{
 			ID:          uuid.New(),
 			DatasetID:   req.DatasetID,
 			Name:        req.Name,
 			Description: req.Description,
+			DateFormats: req.DateFormats,
+			IsDefault:   isDefault,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
@@ -82,6 +115,7 @@ func (h *SchemaHandlers) CreateSchema() gin.HandlerFunc {
 				DataType:     fieldReq.DataType,
 				IsRequired:   fieldReq.IsRequired,
 				IsUnique:     fieldReq.IsUnique,
+				IsPII:        fieldReq.IsPII,
 				DefaultValue: fieldReq.DefaultValue,
 				Position:     fieldReq.Position,
 				Validation:   fieldReq.Validation,
@@ -100,6 +134,27 @@ func (h *SchemaHandlers) CreateSchema() gin.HandlerFunc {
 			schema.Fields = append(schema.Fields, field)
 		}
 
+		if err := services.ValidatePhoneFormats(schema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateEmailFormats(schema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateNumericPrecision(schema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateFieldTransforms(schema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateListFields(schema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		// Save to database
 		err = h.schemaRepo.CreateSchema(schema)
 		if err != nil {
@@ -107,6 +162,8 @@ func (h *SchemaHandlers) CreateSchema() gin.HandlerFunc {
 			return
 		}
 
+		h.webhooks.Publish(services.NewSchemaUpdatedEvent(services.SchemaActionCreated, schema, nil, schema.Fields))
+
 		c.JSON(http.StatusCreated, gin.H{
 			"schema":  schema,
 			"message": "Schema created successfully",
@@ -117,62 +174,113 @@ func (h *SchemaHandlers) CreateSchema() gin.HandlerFunc {
 // GetSchema retrieves schema for a dataset
 func (h *SchemaHandlers) GetSchema() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		log.Printf("[DEBUG] GetSchema: Starting request")
-		
+		logger := logging.FromContext(c.Request.Context()).With("handler", "GetSchema")
+
 		userID, exists := c.Get("user_id")
 		if !exists {
-			log.Printf("[ERROR] GetSchema: User not authenticated")
+			logger.Warn("user not authenticated")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
 			return
 		}
 
 		userUUID, ok := userID.(uuid.UUID)
 		if !ok {
-			log.Printf("[ERROR] GetSchema: Invalid user ID type")
+			logger.Error("invalid user ID type")
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 			return
 		}
 
 		datasetIDStr := c.Param("dataset_id")
-		log.Printf("[DEBUG] GetSchema: Dataset ID param: %s", datasetIDStr)
-		
 		datasetID, err := uuid.Parse(datasetIDStr)
 		if err != nil {
-			log.Printf("[ERROR] GetSchema: Invalid dataset ID format: %v", err)
+			logger.Warn("invalid dataset ID", "dataset_id", datasetIDStr, "error", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
 			return
 		}
 
-		log.Printf("[DEBUG] GetSchema: User %s requesting schema for dataset %s", userUUID, datasetID)
+		logger = logger.With("user_id", userUUID, "dataset_id", datasetID)
+		logger.Debug("fetching schema")
 
 		// Check access
 		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
 		if err != nil {
-			log.Printf("[ERROR] GetSchema: Error checking dataset access for dataset %s, user %s: %v", datasetID, userUUID, err)
+			logger.Error("failed to check dataset access", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
 			return
 		}
 
 		if !hasAccess {
-			log.Printf("[ERROR] GetSchema: User %s does not have access to dataset %s", userUUID, datasetID)
+			logger.Warn("dataset access denied")
 			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
 			return
 		}
 
-		log.Printf("[DEBUG] GetSchema: Access verified, fetching schema...")
-
-		schema, err := h.schemaRepo.GetSchemaByDatasetID(datasetID)
+		// ?name= selects a specific schema variant; otherwise the default is returned
+		var schema *models.DatasetSchema
+		if name := strings.TrimSpace(c.Query("name")); name != "" {
+			schema, err = h.schemaRepo.GetSchemaByName(datasetID, name)
+		} else {
+			schema, err = h.schemaRepo.GetSchemaByDatasetID(datasetID)
+		}
 		if err != nil {
-			log.Printf("[ERROR] GetSchema: Schema not found for dataset %s: %v", datasetID, err)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
+			if errors.Is(err, repository.ErrSchemaNotFound) {
+				logger.Debug("schema not found")
+				c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
+				return
+			}
+			logger.Error("failed to fetch schema", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schema"})
 			return
 		}
 
-		log.Printf("[DEBUG] GetSchema: Successfully fetched schema for dataset %s", datasetID)
+		logger.Debug("fetched schema", "schema_id", schema.ID)
 		c.JSON(http.StatusOK, gin.H{"schema": schema})
 	}
 }
 
+// ListSchemas lists every schema variant defined for a dataset
+func (h *SchemaHandlers) ListSchemas() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("dataset_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
+			return
+		}
+
+		schemas, err := h.schemaRepo.ListSchemasByDatasetID(datasetID)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to list schemas", "dataset_id", datasetID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list schemas"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"schemas": schemas})
+	}
+}
+
 // UpdateSchema updates an existing schema
 func (h *SchemaHandlers) UpdateSchema() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -202,11 +310,12 @@ func (h *SchemaHandlers) UpdateSchema() gin.HandlerFunc {
 		}
 
 		// Get existing schema to check access
-		existingSchema, err := h.schemaRepo.GetSchemaByDatasetID(uuid.UUID{}) // We need to get by schema ID instead
+		existingSchema, err := h.schemaRepo.GetSchemaByID(schemaID)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
 			return
 		}
+		previousFields := existingSchema.Fields
 
 		// Check access
 		hasAccess, err := h.schemaRepo.CheckDatasetAccess(existingSchema.DatasetID, userUUID)
@@ -223,19 +332,28 @@ func (h *SchemaHandlers) UpdateSchema() gin.HandlerFunc {
 		// Update schema
 		existingSchema.Name = req.Name
 		existingSchema.Description = req.Description
+		if req.DateFormats != nil {
+			existingSchema.DateFormats = req.DateFormats
+		}
 		existingSchema.UpdatedAt = time.Now()
 
 		// Update fields
 		existingSchema.Fields = []models.SchemaField{}
 		for _, fieldReq := range req.Fields {
+			// New fields get an ID; existing ones keep theirs so versions can tell renames apart
+			fieldID := fieldReq.ID
+			if fieldID == uuid.Nil {
+				fieldID = uuid.New()
+			}
 			field := models.SchemaField{
-				ID:           fieldReq.ID,
+				ID:           fieldID,
 				SchemaID:     schemaID,
 				Name:         fieldReq.Name,
 				DisplayName:  fieldReq.DisplayName,
 				DataType:     fieldReq.DataType,
 				IsRequired:   fieldReq.IsRequired,
 				IsUnique:     fieldReq.IsUnique,
+				IsPII:        fieldReq.IsPII,
 				DefaultValue: fieldReq.DefaultValue,
 				Position:     fieldReq.Position,
 				Validation:   fieldReq.Validation,
@@ -249,12 +367,35 @@ func (h *SchemaHandlers) UpdateSchema() gin.HandlerFunc {
 			existingSchema.Fields = append(existingSchema.Fields, field)
 		}
 
+		if err := services.ValidatePhoneFormats(existingSchema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateEmailFormats(existingSchema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateNumericPrecision(existingSchema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateFieldTransforms(existingSchema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := services.ValidateListFields(existingSchema.Fields); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		err = h.schemaRepo.UpdateSchema(existingSchema)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update schema"})
 			return
 		}
 
+		h.webhooks.Publish(services.NewSchemaUpdatedEvent(services.SchemaActionUpdated, existingSchema, previousFields, existingSchema.Fields))
+
 		c.JSON(http.StatusOK, gin.H{
 			"schema":  existingSchema,
 			"message": "Schema updated successfully",
@@ -273,41 +414,157 @@ func (h *SchemaHandlers) DeleteSchema() gin.HandlerFunc {
 			return
 		}
 
+		// Load the schema first so the schema.updated event can name its dataset and fields
+		schema, err := h.schemaRepo.GetSchemaByID(schemaID)
+		if err != nil {
+			if errors.Is(err, repository.ErrSchemaNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schema"})
+			return
+		}
+
 		err = h.schemaRepo.DeleteSchema(schemaID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete schema"})
 			return
 		}
 
+		h.webhooks.Publish(services.NewSchemaUpdatedEvent(services.SchemaActionDeleted, schema, schema.Fields, nil))
+
 		c.JSON(http.StatusOK, gin.H{"message": "Schema deleted successfully"})
 	}
 }
 
-// GetDatasetData retrieves paginated dataset data with maximum 1000 rows
-func (h *SchemaHandlers) GetDatasetData() gin.HandlerFunc {
+// ListSchemaVersions lists the recorded versions of a schema, oldest first
+func (h *SchemaHandlers) ListSchemaVersions() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		log.Printf("[DEBUG] GetDatasetData: Starting request")
-		
-		userID, exists := c.Get("user_id")
-		if !exists {
-			log.Printf("[ERROR] GetDatasetData: User not authenticated")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		schema, ok := h.viewableSchema(c)
+		if !ok {
 			return
 		}
 
-		userUUID, ok := userID.(uuid.UUID)
+		versions, err := h.schemaRepo.ListSchemaVersions(schema.ID)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to list schema versions", "schema_id", schema.ID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list schema versions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"versions": versions})
+	}
+}
+
+// DiffSchemaVersions compares two versions of a schema, named by the from and to query parameters,
+// reporting added, removed, renamed, type-changed and constraint-changed fields
+func (h *SchemaHandlers) DiffSchemaVersions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fromVersion, fromErr := strconv.Atoi(c.Query("from"))
+		toVersion, toErr := strconv.Atoi(c.Query("to"))
+		if fromErr != nil || toErr != nil || fromVersion < 1 || toVersion < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be schema version numbers"})
+			return
+		}
+
+		schema, ok := h.viewableSchema(c)
 		if !ok {
-			log.Printf("[ERROR] GetDatasetData: Invalid user ID type")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
 			return
 		}
 
+		versions := make([]*models.SchemaVersion, 2)
+		for i, number := range []int{fromVersion, toVersion} {
+			version, err := h.schemaRepo.GetSchemaVersion(schema.ID, number)
+			if errors.Is(err, repository.ErrSchemaVersionNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Schema version %d not found", number)})
+				return
+			}
+			if err != nil {
+				logging.FromContext(c.Request.Context()).Error("failed to get schema version", "schema_id", schema.ID, "version", number, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get schema version"})
+				return
+			}
+			versions[i] = version
+		}
+
+		c.JSON(http.StatusOK, services.DiffSchemaVersions(versions[0], versions[1]))
+	}
+}
+
+// viewableSchema loads the schema named by the schema_id parameter and checks the user can view its
+// dataset. It writes the error response and returns false otherwise.
+func (h *SchemaHandlers) viewableSchema(c *gin.Context) (*models.DatasetSchema, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+
+	schemaID, err := uuid.Parse(c.Param("schema_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schema ID"})
+		return nil, false
+	}
+
+	schema, err := h.schemaRepo.GetSchemaByID(schemaID)
+	if errors.Is(err, repository.ErrSchemaNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Schema not found"})
+		return nil, false
+	}
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("failed to get schema", "schema_id", schemaID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch schema"})
+		return nil, false
+	}
+
+	hasAccess, err := h.schemaRepo.CheckDatasetAccess(schema.DatasetID, userUUID)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("failed to check dataset access", "dataset_id", schema.DatasetID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+		return nil, false
+	}
+	if !hasAccess {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
+		return nil, false
+	}
+	return schema, true
+}
+
+// GetDatasetData retrieves paginated dataset data with maximum 1000 rows
+func (h *SchemaHandlers) GetDatasetData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := logging.FromContext(c.Request.Context()).With("handler", "GetDatasetData")
+
+		// A share token reads without a user, which also keeps PII fields masked
+		shareToken, shared := c.Get("share_token")
+		var userUUID uuid.UUID
+		if !shared {
+			userID, exists := c.Get("user_id")
+			if !exists {
+				logger.Warn("user not authenticated")
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+				return
+			}
+
+			var ok bool
+			userUUID, ok = userID.(uuid.UUID)
+			if !ok {
+				logger.Error("invalid user ID type")
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+				return
+			}
+		}
+
 		datasetIDStr := c.Param("dataset_id")
-		log.Printf("[DEBUG] GetDatasetData: Dataset ID param: %s", datasetIDStr)
-		
 		datasetID, err := uuid.Parse(datasetIDStr)
 		if err != nil {
-			log.Printf("[ERROR] GetDatasetData: Invalid dataset ID format: %v", err)
+			logger.Warn("invalid dataset ID", "dataset_id", datasetIDStr, "error", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
 			return
 		}
@@ -315,7 +572,6 @@ func (h *SchemaHandlers) GetDatasetData() gin.HandlerFunc {
 		// Parse pagination parameters with strict limits
 		page := 1
 		pageSize := 50 // Default page size
-		maxRows := 1000 // Maximum rows to display
 
 		if pageStr := c.Query("page"); pageStr != "" {
 			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
@@ -329,46 +585,106 @@ func (h *SchemaHandlers) GetDatasetData() gin.HandlerFunc {
 			}
 		}
 
-		// Ensure we don't exceed max rows limit
-		maxPage := maxRows / pageSize
-		if page > maxPage {
-			page = maxPage
-		}
+		// Row provenance (who added and last edited each row) is opt-in, and never shared
+		includeMeta := c.Query("include_meta") == "true" && !shared
 
-		log.Printf("[DEBUG] GetDatasetData: User %s requesting data for dataset %s (page=%d, pageSize=%d)", userUUID, datasetID, page, pageSize)
+		logger = logger.With("user_id", userUUID, "dataset_id", datasetID)
+		logger.Debug("fetching dataset data", "page", page, "page_size", pageSize)
 
-		// Check access
-		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
-		if err != nil {
-			log.Printf("[ERROR] GetDatasetData: Error checking dataset access for user %s, dataset %s: %v", userUUID, datasetID, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+		// Check access; share tokens were verified for their dataset by the share token middleware
+		if shared {
+			if token, ok := shareToken.(*models.ShareToken); !ok || token.DatasetID != datasetID {
+				logger.Warn("share token is not for this dataset")
+				c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
+				return
+			}
+		} else {
+			hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
+			if err != nil {
+				logger.Error("failed to check dataset access", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+				return
+			}
+
+			if !hasAccess {
+				logger.Warn("dataset access denied")
+				c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
+				return
+			}
+		}
+
+		// Ensure we don't exceed the project's display cap
+		maxRows, ok := h.resolveDisplayRows(c, datasetID)
+		if !ok {
 			return
 		}
+		page = services.ClampDisplayPage(page, pageSize, maxRows)
 
-		if !hasAccess {
-			log.Printf("[ERROR] GetDatasetData: User %s does not have access to dataset %s", userUUID, datasetID)
-			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
+		piiSchema, ok := piiMaskingSchema(c, h.schemaRepo, datasetID, userUUID)
+		if !ok {
 			return
 		}
 
-		log.Printf("[DEBUG] GetDatasetData: Access verified, fetching data...")
+		// Answer unchanged pages with 304 before building the full response
+		lastModified, err := h.schemaRepo.GetDatasetLastModified(datasetID)
+		if err != nil {
+			logger.Error("failed to get last modified time", "error", err)
+		} else {
+			etag := weakETag(lastModified, page, pageSize, maxRows, includeMeta, piiSchema != nil)
+			c.Header("ETag", etag)
+			c.Header("Cache-Control", "private, no-cache")
+			if etagMatches(c.GetHeader("If-None-Match"), etag) {
+				c.Status(http.StatusNotModified)
+				return
+			}
+		}
 
-		// Get data with row limit
-		result, err := h.schemaRepo.GetDatasetDataWithLimit(datasetID, page, pageSize, maxRows)
+		// Get data with row limit, from the page cache when this version of the page is cached
+		pageKey := services.DataPageKey{
+			DatasetID:   datasetID,
+			UpdatedAt:   lastModified,
+			Page:        page,
+			PageSize:    pageSize,
+			MaxRows:     maxRows,
+			IncludeMeta: includeMeta,
+		}
+		cacheable := err == nil
+		var result *models.DataPreviewResponse
+		cached := false
+		if cacheable {
+			result, cached = h.dataPages.Get(c.Request.Context(), pageKey)
+		}
+		if !cached {
+			result, err = h.schemaRepo.GetDatasetDataWithLimit(datasetID, page, pageSize, maxRows, includeMeta)
+			if err == nil && cacheable {
+				if err := h.dataPages.Set(c.Request.Context(), pageKey, result); err != nil {
+					logger.Warn("failed to cache data page", "error", err)
+				}
+			}
+		}
 		if err != nil {
-			log.Printf("[ERROR] GetDatasetData: Error getting dataset data for dataset %s: %v", datasetID, err)
-			// Return empty result instead of error for missing data
+			logger.Error("failed to get dataset data, returning an empty page", "error", err)
+			// Return empty result instead of error for missing data, without caching it
+			c.Writer.Header().Del("ETag")
 			result = &models.DataPreviewResponse{
 				Data:       []map[string]interface{}{},
+				Columns:    []string{},
 				Schema:     nil,
 				TotalRows:  0,
 				Page:       page,
 				PageSize:   pageSize,
 				TotalPages: 0,
 			}
-			log.Printf("[DEBUG] GetDatasetData: Returning empty result due to error")
 		} else {
-			log.Printf("[DEBUG] GetDatasetData: Successfully fetched %d rows for dataset %s", len(result.Data), datasetID)
+			logger.Debug("fetched dataset data", "rows", len(result.Data), "max_rows", maxRows)
+			services.MaskPIIFields(result.Data, piiSchema)
+
+			// Label rows with the dataset's display field when one is configured
+			if dataset, err := h.schemaRepo.GetDatasetByID(datasetID); err != nil {
+				logger.Error("failed to get dataset for row labels", "error", err)
+			} else if dataset.DisplayField != nil {
+				services.ApplyRowLabels(result.Data, *dataset.DisplayField)
+			}
 		}
 
 		c.JSON(http.StatusOK, result)
@@ -403,28 +719,63 @@ func (h *SchemaHandlers) UpdateDatasetData() gin.HandlerFunc {
 			return
 		}
 
-		// Check access
-		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
+		// Viewers can read rows, with PII masked, but not change them
+		canEdit, err := h.schemaRepo.CheckDatasetEditAccess(datasetID, userUUID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
 			return
 		}
 
-		if !hasAccess {
+		if !canEdit {
 			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to modify this dataset"})
 			return
 		}
 
-		// TODO: Add schema validation here
+		// Datasets that enforce their schema reject edits the append path would reject
+		dataset, err := h.schemaRepo.GetDatasetByID(datasetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+			return
+		}
+
+		if dataset.EnforceSchema {
+			schema, err := h.schemaRepo.GetSchemaByDatasetID(datasetID)
+			if err != nil && !errors.Is(err, repository.ErrSchemaNotFound) {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load dataset schema"})
+				return
+			}
+			if schema != nil {
+				if fieldErrors := services.ValidateRowEdit(repository.StoredRowData(req.Data), schema, req.RowIndex); len(fieldErrors) > 0 {
+					c.JSON(http.StatusBadRequest, gin.H{
+						"error":  "Row does not match the dataset schema",
+						"errors": fieldErrors,
+					})
+					return
+				}
+			}
+		}
 
-		// Update data
-		err = h.schemaRepo.UpdateDatasetData(datasetID, req.RowIndex, req.Data, userUUID)
+		// Update data, refusing the edit when the row changed since the client read it
+		version, err := h.schemaRepo.UpdateDatasetData(datasetID, req.RowIndex, req.Data, userUUID, req.Version)
 		if err != nil {
+			var conflictErr *repository.RowVersionConflictError
+			if errors.As(err, &conflictErr) {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":           "Row was changed by someone else",
+					"current_version": conflictErr.CurrentVersion,
+				})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update dataset data"})
 			return
 		}
+		h.dataPages.Invalidate(c.Request.Context(), datasetID)
 
-		c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+		c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully", "version": version})
 	}
 }
 
@@ -457,14 +808,14 @@ func (h *SchemaHandlers) DeleteDatasetData() gin.HandlerFunc {
 			return
 		}
 
-		// Check access
-		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
+		// Viewers can read rows, with PII masked, but not change them
+		canEdit, err := h.schemaRepo.CheckDatasetEditAccess(datasetID, userUUID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
 			return
 		}
 
-		if !hasAccess {
+		if !canEdit {
 			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to modify this dataset"})
 			return
 		}
@@ -475,11 +826,127 @@ func (h *SchemaHandlers) DeleteDatasetData() gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete dataset data"})
 			return
 		}
+		h.dataPages.Invalidate(c.Request.Context(), datasetID)
 
 		c.JSON(http.StatusOK, gin.H{"message": "Data deleted successfully"})
 	}
 }
 
+// DeleteDatasetRows deletes the rows of a dataset matched by a filter or listed by row index
+func (h *SchemaHandlers) DeleteDatasetRows() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetIDStr := c.Param("dataset_id")
+		datasetID, err := uuid.Parse(datasetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		var req models.BulkDeleteDataRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+		if err := req.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Viewers can read rows but not delete them
+		canEdit, err := h.schemaRepo.CheckDatasetEditAccess(datasetID, userUUID)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to check dataset edit access", "dataset_id", datasetID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !canEdit {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to delete rows of this dataset"})
+			return
+		}
+
+		deleted, err := h.schemaRepo.DeleteDatasetRows(datasetID, &req)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to delete dataset rows", "dataset_id", datasetID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete dataset data"})
+			return
+		}
+		h.dataPages.Invalidate(c.Request.Context(), datasetID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Dataset rows deleted successfully",
+			"deleted_rows": deleted,
+		})
+	}
+}
+
+// TruncateDatasetData deletes every row of a dataset, keeping its schema, rules and submissions
+func (h *SchemaHandlers) TruncateDatasetData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetIDStr := c.Param("dataset_id")
+		datasetID, err := uuid.Parse(datasetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		if c.Query("confirm") != "true" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Deleting all rows requires confirm=true"})
+			return
+		}
+
+		// Only the project owner can clear a dataset
+		isOwner, err := h.schemaRepo.IsDatasetOwner(datasetID, userUUID)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to check dataset ownership", "dataset_id", datasetID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !isOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can delete all dataset rows"})
+			return
+		}
+
+		deleted, err := h.schemaRepo.TruncateDatasetData(datasetID)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to truncate dataset data", "dataset_id", datasetID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete dataset data"})
+			return
+		}
+		h.dataPages.Invalidate(c.Request.Context(), datasetID)
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "All dataset rows deleted successfully",
+			"deleted_rows": deleted,
+		})
+	}
+}
+
 // QueryDatasetData executes a SQL query on dataset data
 func (h *SchemaHandlers) QueryDatasetData() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -505,7 +972,7 @@ func (h *SchemaHandlers) QueryDatasetData() gin.HandlerFunc {
 		// Check access
 		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
 		if err != nil {
-			log.Printf("Error checking dataset access: %v", err)
+			logging.FromContext(c.Request.Context()).Error("failed to check dataset access", "dataset_id", datasetID, "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
 			return
 		}
@@ -535,22 +1002,270 @@ func (h *SchemaHandlers) QueryDatasetData() gin.HandlerFunc {
 			pageSize = 1000 // Hard limit
 		}
 
+		// PII fields are masked for this user, so they are not matched either: a match would
+		// confirm a guessed value
+		piiSchema, ok := piiMaskingSchema(c, h.schemaRepo, datasetID, userUUID)
+		if !ok {
+			return
+		}
+
 		// Execute query
-		result, err := h.schemaRepo.QueryDatasetData(datasetID, queryReq.Query, pageSize)
+		result, err := h.schemaRepo.QueryDatasetData(datasetID, queryReq.Query, pageSize, services.PIIFieldNames(piiSchema))
 		if err != nil {
-			log.Printf("Error executing query: %v", err)
+			logging.FromContext(c.Request.Context()).Error("failed to execute query", "dataset_id", datasetID, "error", err)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Query execution failed: " + err.Error()})
 			return
 		}
+		services.MaskPIIFields(result.Data, piiSchema)
 
 		c.JSON(http.StatusOK, result)
 	}
 }
 
+// SearchDatasetData full-text searches a dataset's rows, most relevant first, highlighting the
+// fields that matched. Like GetDatasetData only the first 1000 matches are shown.
+func (h *SchemaHandlers) SearchDatasetData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetIDStr := c.Param("dataset_id")
+		datasetID, err := uuid.Parse(datasetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		query := strings.TrimSpace(c.Query("q"))
+		terms := services.SearchTerms(query)
+		if len(terms) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Search query must contain at least one word"})
+			return
+		}
+
+		// Parse pagination parameters with strict limits
+		page := 1
+		pageSize := 50
+
+		if pageStr := c.Query("page"); pageStr != "" {
+			if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+				page = p
+			}
+		}
+
+		if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+			if ps, err := strconv.Atoi(pageSizeStr); err == nil && ps > 0 && ps <= 100 {
+				pageSize = ps
+			}
+		}
+
+		// Check access
+		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to check dataset access", "dataset_id", datasetID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to search this dataset"})
+			return
+		}
+
+		maxRows, ok := h.resolveDisplayRows(c, datasetID)
+		if !ok {
+			return
+		}
+
+		// PII fields masked for this user are left out of the search, so matches can't confirm
+		// a guessed value
+		piiSchema, ok := piiMaskingSchema(c, h.schemaRepo, datasetID, userUUID)
+		if !ok {
+			return
+		}
+
+		results, totalRows, err := h.schemaRepo.SearchDatasetData(datasetID, query, page, pageSize, maxRows, services.PIIFieldNames(piiSchema))
+		if err != nil {
+			logging.FromContext(c.Request.Context()).Error("failed to search dataset data", "dataset_id", datasetID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search dataset data"})
+			return
+		}
+
+		// Highlight the masked values so matches never reveal a PII field's raw value
+		for i := range results {
+			services.MaskPIIFields([]map[string]interface{}{results[i].Data}, piiSchema)
+			results[i].Highlights = services.HighlightRow(results[i].Data, terms)
+		}
+
+		c.JSON(http.StatusOK, models.DataSearchResponse{
+			Query:      query,
+			Results:    results,
+			TotalRows:  totalRows,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: (totalRows + pageSize - 1) / pageSize,
+		})
+	}
+}
+
+// datasetExportBatchSize is how many dataset rows are read per query while exporting
+const datasetExportBatchSize = 1000
+
+// ExportDatasetData streams every row of a dataset as CSV (default) or, with format=parquet, as a
+// Parquet file whose column types follow the dataset's schema
+func (h *SchemaHandlers) ExportDatasetData() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.DefaultQuery("format", services.ExportFormatCSV)
+		contentType, ok := services.ExportContentType(format)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unsupported export format '%s'", format)})
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			return
+		}
+
+		userUUID, ok := userID.(uuid.UUID)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+			return
+		}
+
+		datasetID, err := uuid.Parse(c.Param("dataset_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+			return
+		}
+
+		logger := logging.FromContext(c.Request.Context()).With("handler", "ExportDatasetData", "user_id", userUUID, "dataset_id", datasetID, "format", format)
+
+		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
+		if err != nil {
+			logger.Error("failed to check dataset access", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+			return
+		}
+
+		if !hasAccess {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to view this dataset"})
+			return
+		}
+
+		piiSchema, ok := piiMaskingSchema(c, h.schemaRepo, datasetID, userUUID)
+		if !ok {
+			return
+		}
+
+		batch, lastRowIndex, err := h.schemaRepo.GetDatasetRowsAfter(datasetID, -1, datasetExportBatchSize)
+		if err != nil {
+			logger.Error("failed to get dataset rows", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve dataset data"})
+			return
+		}
+
+		// Columns and their types follow the default schema, or the stored keys when there is none
+		schema, err := h.schemaRepo.GetSchemaByDatasetID(datasetID)
+		if err != nil && !errors.Is(err, repository.ErrSchemaNotFound) {
+			logger.Warn("failed to load schema for export", "error", err)
+		}
+
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=dataset_%s.%s", datasetID, format))
+		c.Status(http.StatusOK)
+
+		exporter, err := services.NewDatasetExporter(format, c.Writer, services.DatasetExportFields(schema, batch))
+		if err != nil {
+			logger.Error("failed to start export", "error", err)
+			return
+		}
+
+		// Headers are already sent, so failures past this point can only be logged
+		for len(batch) > 0 {
+			services.MaskPIIFields(batch, piiSchema)
+			if err := exporter.WriteRows(batch); err != nil {
+				logger.Error("failed to export rows", "error", err)
+				return
+			}
+			c.Writer.Flush()
+
+			if len(batch) < datasetExportBatchSize {
+				break
+			}
+			batch, lastRowIndex, err = h.schemaRepo.GetDatasetRowsAfter(datasetID, lastRowIndex, datasetExportBatchSize)
+			if err != nil {
+				logger.Error("failed to get dataset rows", "error", err)
+				return
+			}
+		}
+
+		if err := exporter.Close(); err != nil {
+			logger.Error("failed to finish export", "error", err)
+		}
+	}
+}
+
+// resolveDisplayRows returns how many rows of a dataset the request may page through: the owning
+// project's cap, optionally lowered by the max_rows query parameter. It writes the error response
+// and returns false when the cap cannot be read.
+func (h *SchemaHandlers) resolveDisplayRows(c *gin.Context, datasetID uuid.UUID) (int, bool) {
+	projectCap, err := h.schemaRepo.GetDatasetDisplayRowCap(datasetID)
+	if errors.Is(err, repository.ErrDatasetNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+		return 0, false
+	}
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("failed to get display row cap", "dataset_id", datasetID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dataset display settings"})
+		return 0, false
+	}
+	return h.displayRows.Resolve(projectCap, c.Query("max_rows")), true
+}
+
+// piiMaskingSchema returns the schema whose PII fields must be masked for the user, or nil when the
+// user can edit the dataset or no field is flagged as PII. It writes the error response and returns
+// false when that cannot be decided, so raw values are never sent by mistake.
+func piiMaskingSchema(c *gin.Context, schemaRepo *repository.SchemaRepository, datasetID, userID uuid.UUID) (*models.DatasetSchema, bool) {
+	canEdit, err := schemaRepo.CheckDatasetEditAccess(datasetID, userID)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("failed to check dataset edit access", "dataset_id", datasetID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
+		return nil, false
+	}
+	if canEdit {
+		return nil, true
+	}
+
+	schema, err := schemaRepo.GetSchemaByDatasetID(datasetID)
+	if errors.Is(err, repository.ErrSchemaNotFound) {
+		return nil, true
+	}
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("failed to get schema for PII masking", "dataset_id", datasetID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dataset schema"})
+		return nil, false
+	}
+	if !services.HasPIIFields(schema) {
+		return nil, true
+	}
+	return schema, true
+}
+
 // InferSchema automatically infers schema from dataset data
 func (h *SchemaHandlers) InferSchema() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		log.Printf("[DEBUG] InferSchema: Starting schema inference request")
+		logger := logging.FromContext(c.Request.Context()).With("handler", "InferSchema")
 
 		// Get user ID from auth middleware
 		userID, exists := c.Get("user_id")
@@ -573,12 +1288,12 @@ func (h *SchemaHandlers) InferSchema() gin.HandlerFunc {
 			return
 		}
 
-		log.Printf("[DEBUG] InferSchema: User %s requesting inference for dataset %s", userUUID, datasetID)
+		logger = logger.With("user_id", userUUID, "dataset_id", datasetID)
 
 		// Check if user has access to this dataset
 		hasAccess, err := h.schemaRepo.CheckDatasetAccess(datasetID, userUUID)
 		if err != nil {
-			log.Printf("[ERROR] InferSchema: Error checking dataset access: %v", err)
+			logger.Error("failed to check dataset access", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify dataset access"})
 			return
 		}
@@ -591,15 +1306,40 @@ func (h *SchemaHandlers) InferSchema() gin.HandlerFunc {
 		// Get dataset information
 		dataset, err := h.schemaRepo.GetDatasetByID(datasetID)
 		if err != nil {
-			log.Printf("[ERROR] InferSchema: Error fetching dataset: %v", err)
+			if errors.Is(err, repository.ErrDatasetNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+				return
+			}
+			logger.Error("failed to fetch dataset", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset information"})
 			return
 		}
 
+		// Thresholds decide how strictly columns are promoted off string, marked required and flagged for review
+		opts, err := services.ParseInferenceOptions(c.Query("type_threshold"), c.Query("required_threshold"), c.Query("review_threshold"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// Serve a cached inference for this dataset version unless the caller forces a fresh analysis
+		force := c.Query("force") == "true"
+		if !force {
+			if cached, ok := h.inferenceCache.Get(c.Request.Context(), datasetID, dataset.UpdatedAt, opts); ok {
+				c.JSON(http.StatusOK, gin.H{
+					"inferred_schema": cached,
+					"thresholds":      opts.Describe(),
+					"cached":          true,
+					"message":         "Schema inference completed successfully",
+				})
+				return
+			}
+		}
+
 		// Get dataset data for analysis
 		headers, rows, err := h.schemaRepo.GetDatasetDataForInference(datasetID, 1000) // Analyze first 1000 rows
 		if err != nil {
-			log.Printf("[ERROR] InferSchema: Error fetching dataset data: %v", err)
+			logger.Error("failed to fetch dataset data", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset data for analysis"})
 			return
 		}
@@ -609,20 +1349,26 @@ func (h *SchemaHandlers) InferSchema() gin.HandlerFunc {
 			return
 		}
 
-		log.Printf("[DEBUG] InferSchema: Analyzing %d columns and %d rows", len(headers), len(rows))
+		logger.Debug("inferring schema", "columns", len(headers), "rows", len(rows))
 
 		// Perform schema inference
-		inferredSchema, err := h.inferenceService.InferSchemaFromData(headers, rows, dataset.Name)
+		inferredSchema, err := h.inferenceService.InferSchemaWithOptions(headers, rows, dataset.Name, opts)
 		if err != nil {
-			log.Printf("[ERROR] InferSchema: Error during inference: %v", err)
+			logger.Error("schema inference failed", "error", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to infer schema: " + err.Error()})
 			return
 		}
 
-		log.Printf("[DEBUG] InferSchema: Successfully inferred schema with confidence %.2f", inferredSchema.Confidence)
+		logger.Debug("inferred schema", "confidence", inferredSchema.Confidence)
+
+		if err := h.inferenceCache.Set(c.Request.Context(), datasetID, dataset.UpdatedAt, inferredSchema); err != nil {
+			logger.Warn("failed to cache inferred schema", "error", err)
+		}
 
 		c.JSON(http.StatusOK, gin.H{
 			"inferred_schema": inferredSchema,
+			"thresholds":      opts.Describe(),
+			"cached":          false,
 			"message":        "Schema inference completed successfully",
 		})
 	}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/saurabh22suman/oreo.io/internal/auth"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+)
+
+// ShareTokenHandlers contains handlers for managing read-only dataset share tokens
+type ShareTokenHandlers struct {
+	shareTokenRepo *repository.ShareTokenRepository
+	datasetRepo    *repository.DatasetRepository
+	signer         *auth.ShareTokenSigner
+}
+
+// NewShareTokenHandlers creates new share token handlers
+func NewShareTokenHandlers(shareTokenRepo *repository.ShareTokenRepository, datasetRepo *repository.DatasetRepository, signer *auth.ShareTokenSigner) *ShareTokenHandlers {
+	return &ShareTokenHandlers{shareTokenRepo: shareTokenRepo, datasetRepo: datasetRepo, signer: signer}
+}
+
+// requireDatasetOwner parses the dataset ID and checks the user owns the dataset's project. It
+// writes the error response and returns false otherwise.
+func (h *ShareTokenHandlers) requireDatasetOwner(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userUUID, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	datasetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dataset ID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	dataset, err := h.datasetRepo.GetByID(datasetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrDatasetNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Dataset not found"})
+			return uuid.Nil, uuid.Nil, false
+		}
+		log.Printf("Error getting dataset: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dataset"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	isOwner, err := h.datasetRepo.IsProjectOwner(dataset.ProjectID, userUUID)
+	if err != nil {
+		log.Printf("Error checking project access: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify project access"})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if !isOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can manage share tokens"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return datasetID, userUUID, true
+}
+
+// ListShareTokens returns a dataset's share tokens without their signed values
+func (h *ShareTokenHandlers) ListShareTokens() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, _, ok := h.requireDatasetOwner(c)
+		if !ok {
+			return
+		}
+
+		tokens, err := h.shareTokenRepo.ListByDataset(datasetID)
+		if err != nil {
+			log.Printf("Error listing share tokens: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list share tokens"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"share_tokens": tokens})
+	}
+}
+
+// CreateShareToken creates a read-only share token for a dataset; the signed token is only
+// returned in this response
+func (h *ShareTokenHandlers) CreateShareToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req models.CreateShareTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		now := time.Now()
+		if err := req.Validate(now); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		datasetID, userUUID, ok := h.requireDatasetOwner(c)
+		if !ok {
+			return
+		}
+
+		token := &models.ShareToken{
+			ID:         uuid.New(),
+			DatasetID:  datasetID,
+			Name:       req.Name,
+			Permission: models.SharePermissionView,
+			CreatedBy:  userUUID,
+			ExpiresAt:  req.ExpiresAt,
+			CreatedAt:  now,
+		}
+
+		// Sign before storing so a misconfigured secret doesn't leave an unusable grant behind
+		signed, err := h.signer.Sign(token.ID, token.DatasetID, token.Permission, token.ExpiresAt)
+		if err != nil {
+			log.Printf("Error signing share token: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share token"})
+			return
+		}
+
+		if err := h.shareTokenRepo.Create(token); err != nil {
+			log.Printf("Error creating share token: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create share token"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"message":     "Share token created successfully; store it now, it won't be shown again",
+			"share_token": models.CreatedShareToken{ShareToken: *token, Token: signed},
+		})
+	}
+}
+
+// RevokeShareToken revokes a dataset share token, which stops it working immediately
+func (h *ShareTokenHandlers) RevokeShareToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		datasetID, _, ok := h.requireDatasetOwner(c)
+		if !ok {
+			return
+		}
+
+		tokenID, err := uuid.Parse(c.Param("token_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share token ID"})
+			return
+		}
+
+		if err := h.shareTokenRepo.Revoke(datasetID, tokenID); err != nil {
+			if errors.Is(err, repository.ErrShareTokenNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Share token not found or already revoked"})
+				return
+			}
+			log.Printf("Error revoking share token: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke share token"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Share token revoked successfully"})
+	}
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// contextKey keys the request-scoped logger in a context
+type contextKey struct{}
+
+// FromEnv builds the application logger: JSON lines when ENVIRONMENT is production, human-readable
+// text otherwise, at LOG_LEVEL (debug, info, warn or error; default info)
+func FromEnv(w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: levelFromEnv()}
+	if os.Getenv("ENVIRONMENT") == "production" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+func levelFromEnv() slog.Level {
+	var level slog.Level
+	value := strings.TrimSpace(os.Getenv("LOG_LEVEL"))
+	if value == "" {
+		return slog.LevelInfo
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		slog.Warn("invalid LOG_LEVEL, using info", "value", value)
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// WithLogger returns a copy of ctx carrying logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger when there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saurabh22suman/oreo.io/internal/auth"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// fakeAPIKeyStore is an in-memory APIKeyStore keyed by hash
+type fakeAPIKeyStore struct {
+	keys map[string]*models.APIKey
+}
+
+func (f *fakeAPIKeyStore) GetByHash(keyHash string) (*models.APIKey, error) {
+	key, ok := f.keys[keyHash]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return key, nil
+}
+
+func (f *fakeAPIKeyStore) MarkUsed(id uuid.UUID) error {
+	return nil
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	revokedAt := time.Now()
+	principalID := uuid.New()
+	store := &fakeAPIKeyStore{keys: map[string]*models.APIKey{
+		auth.HashAPIKey("trusted-key"): {ID: uuid.New(), Scopes: []string{models.APIKeyScopeTrustedAppend}, PrincipalID: &principalID, CreatedBy: uuid.New()},
+		auth.HashAPIKey("read-key"):    {ID: uuid.New(), Scopes: []string{"data:read"}},
+		auth.HashAPIKey("revoked-key"): {ID: uuid.New(), Scopes: []string{models.APIKeyScopeTrustedAppend}, RevokedAt: &revokedAt},
+	}}
+
+	router := gin.New()
+	router.Use(RequireAPIKey(store, models.APIKeyScopeTrustedAppend))
+	router.POST("/rows", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.MustGet("user_id")})
+	})
+
+	tests := []struct {
+		name           string
+		key            string
+		expectedStatus int
+	}{
+		{name: "trusted key passes", key: "trusted-key", expectedStatus: http.StatusOK},
+		{name: "missing key", key: "", expectedStatus: http.StatusUnauthorized},
+		{name: "unknown key", key: "unknown-key", expectedStatus: http.StatusUnauthorized},
+		{name: "revoked key", key: "revoked-key", expectedStatus: http.StatusUnauthorized},
+		{name: "key without scope", key: "read-key", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/rows", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), principalID.String(), "the key acts as its principal")
+			}
+		})
+	}
+}
+
+func TestRequireAuthOrAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	principalID := uuid.New()
+	role := "collaborator"
+	revokedAt := time.Now()
+	store := &fakeAPIKeyStore{keys: map[string]*models.APIKey{
+		auth.HashAPIKey("project-key"): {ID: uuid.New(), Role: &role, PrincipalID: &principalID},
+		auth.HashAPIKey("trusted-key"): {ID: uuid.New(), Scopes: []string{models.APIKeyScopeTrustedAppend}},
+		auth.HashAPIKey("revoked-key"): {ID: uuid.New(), Role: &role, PrincipalID: &principalID, RevokedAt: &revokedAt},
+	}}
+
+	router := gin.New()
+	router.Use(RequireAuthOrAPIKey(nil, store))
+	router.GET("/protected", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.MustGet("user_id")})
+	})
+
+	tests := []struct {
+		name           string
+		key            string
+		expectedStatus int
+	}{
+		{name: "project key acts as its principal", key: "project-key", expectedStatus: http.StatusOK},
+		{name: "no key falls back to the bearer token", key: "", expectedStatus: http.StatusUnauthorized},
+		{name: "unknown key", key: "unknown-key", expectedStatus: http.StatusUnauthorized},
+		{name: "revoked key", key: "revoked-key", expectedStatus: http.StatusUnauthorized},
+		{name: "key without a principal", key: "trusted-key", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), principalID.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+	"github.com/saurabh22suman/oreo.io/internal/logging"
+)
+
+// IdempotencyKeyHeader lets clients retry a request without repeating its effects
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set to "true" on responses replayed for a repeated key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// DefaultIdempotencyTTL is how long a completed request is remembered when no TTL is configured
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencyLockTTL bounds how long an in-flight request holds its key, so a request that never
+// finishes doesn't block retries forever
+const idempotencyLockTTL = 10 * time.Minute
+
+// maxIdempotencyKeyLength bounds client-supplied keys
+const maxIdempotencyKeyLength = 255
+
+// maxBufferedIdempotentBody is how much of a request body is held in memory while it is hashed;
+// larger bodies, like file uploads, are spooled to a temporary file
+const maxBufferedIdempotentBody = 1 << 20
+
+// Idempotency record states
+const (
+	idempotencyPending   = "pending"
+	idempotencyCompleted = "completed"
+)
+
+// idempotencyRecord is what is stored for a key: a marker while the first request runs, then its
+// response. Both carry a hash of the request body, so a key reused for a different request is
+// caught.
+type idempotencyRecord struct {
+	State       string `json:"state"`
+	BodyHash    string `json:"body_hash"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// bodyRecorder copies everything written to the response
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency middleware makes a route safe to retry. A request carrying an Idempotency-Key runs
+// once per user, method, path and key: successful responses are stored for ttl and replayed to
+// repeats, a repeat arriving while the first request runs gets 409, a repeat with a different body
+// gets 422, and failed requests release the key so they can be retried. Requests without the
+// header are unaffected. Must run after authentication so keys are scoped to the caller.
+func Idempotency(store cache.Cache, ttl time.Duration) gin.HandlerFunc {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength),
+			})
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		logger := logging.FromContext(ctx)
+		userID, _ := c.Get("user_id")
+		cacheKey := idempotencyCacheKey(fmt.Sprint(userID), c.Request.Method, c.Request.URL.Path, key)
+
+		bodyHash, cleanup, err := hashRequestBody(c.Request)
+		if err != nil {
+			logger.Error("failed to read request body", "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		defer cleanup()
+
+		pending, _ := json.Marshal(idempotencyRecord{State: idempotencyPending, BodyHash: bodyHash})
+		reserved, err := store.SetIfAbsent(ctx, cacheKey, pending, idempotencyLockTTL)
+		if err != nil {
+			// Without the store the request still runs, just without replay protection
+			logger.Warn("idempotency store unavailable", "error", err)
+			c.Next()
+			return
+		}
+
+		if !reserved {
+			replayIdempotentResponse(c, store, cacheKey, bodyHash)
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status < 200 || status >= 300 {
+			if err := store.Delete(ctx, cacheKey); err != nil {
+				logger.Warn("failed to release idempotency key", "error", err)
+			}
+			return
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			State:       idempotencyCompleted,
+			BodyHash:    bodyHash,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := store.Set(ctx, cacheKey, record, ttl); err != nil {
+			logger.Warn("failed to store idempotent response", "error", err)
+		}
+	}
+}
+
+// replayIdempotentResponse answers a repeated key with the stored response, 409 while the first
+// request is still running, or 422 when the key was first used with a different body
+func replayIdempotentResponse(c *gin.Context, store cache.Cache, cacheKey, bodyHash string) {
+	data, err := store.Get(c.Request.Context(), cacheKey)
+	var record idempotencyRecord
+	if err == nil {
+		err = json.Unmarshal(data, &record)
+	}
+
+	switch {
+	case errors.Is(err, cache.ErrMiss):
+		// The first request failed and released the key between our reservation and lookup
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key just finished, please retry"})
+	case err != nil:
+		logging.FromContext(c.Request.Context()).Error("failed to read idempotent response", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+	case record.BodyHash != bodyHash:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request body"})
+	case record.State != idempotencyCompleted:
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(record.Status, record.ContentType, record.Body)
+	}
+	c.Abort()
+}
+
+// idempotencyCacheKey scopes a client key to the caller and endpoint, hashed to bound its length
+func idempotencyCacheKey(userID, method, path, key string) string {
+	sum := sha256.Sum256([]byte(userID + "\n" + method + "\n" + path + "\n" + key))
+	return "idempotency:" + hex.EncodeToString(sum[:])
+}
+
+// hashRequestBody hashes the request body with SHA-256 and replaces it with a copy the handler can
+// still read. Multipart forms are hashed by their fields and file contents rather than their raw
+// bytes, because clients pick a new random boundary on every send, retries included. The returned
+// cleanup removes any temporary file the copy was spooled to.
+func hashRequestBody(r *http.Request) (string, func(), error) {
+	body, cleanup, err := spoolRequestBody(r)
+	if err != nil {
+		return "", cleanup, err
+	}
+
+	var sum []byte
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" && params["boundary"] != "" {
+		sum, err = hashMultipartBody(body, params["boundary"])
+	} else {
+		hash := sha256.New()
+		_, err = io.Copy(hash, body)
+		sum = hash.Sum(nil)
+	}
+	if err == nil {
+		_, err = body.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+
+	r.Body = io.NopCloser(body)
+	return hex.EncodeToString(sum), cleanup, nil
+}
+
+// spoolRequestBody reads the request body into memory, or into a temporary file when it is larger
+// than maxBufferedIdempotentBody, so it can be read more than once
+func spoolRequestBody(r *http.Request) (io.ReadSeeker, func(), error) {
+	noop := func() {}
+	if r.Body == nil || r.Body == http.NoBody {
+		return bytes.NewReader(nil), noop, nil
+	}
+	defer r.Body.Close()
+
+	head, err := io.ReadAll(io.LimitReader(r.Body, maxBufferedIdempotentBody+1))
+	if err != nil {
+		return nil, noop, err
+	}
+	if len(head) <= maxBufferedIdempotentBody {
+		return bytes.NewReader(head), noop, nil
+	}
+
+	file, err := os.CreateTemp("", "idempotent-body-*")
+	if err != nil {
+		return nil, noop, err
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	if _, err := file.Write(head); err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	if _, err := io.Copy(file, r.Body); err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, noop, err
+	}
+	return file, cleanup, nil
+}
+
+// hashMultipartBody hashes each part of a multipart body by its form name, file name and content,
+// in order
+func hashMultipartBody(body io.Reader, boundary string) ([]byte, error) {
+	hash := sha256.New()
+	reader := multipart.NewReader(body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return hash.Sum(nil), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		content := sha256.New()
+		if _, err := io.Copy(content, part); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(hash, "%q %q %x\n", part.FormName(), part.FileName(), content.Sum(nil))
+	}
+}
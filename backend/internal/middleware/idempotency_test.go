@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+)
+
+// idempotencyRouter serves POST /upload as the user named in the X-User header, counting the
+// handler's runs and answering with the status in the X-Status header
+func idempotencyRouter(store cache.Cache, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", c.GetHeader("X-User"))
+		c.Next()
+	})
+	router.POST("/upload", Idempotency(store, time.Hour), func(c *gin.Context) {
+		*calls++
+		status := http.StatusCreated
+		if c.GetHeader("X-Status") == "500" {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{"call": *calls})
+	})
+	return router
+}
+
+func idempotentPost(router *gin.Engine, user, key string, status string) *httptest.ResponseRecorder {
+	return idempotentPostBody(router, user, key, status, "")
+}
+
+func idempotentPostBody(router *gin.Engine, user, key, status, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	req.Header.Set("X-Status", status)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysCompletedRequest(t *testing.T) {
+	calls := 0
+	router := idempotencyRouter(cache.NewMemoryCache(), &calls)
+
+	first := idempotentPost(router, "alice", "key-1", "")
+	second := idempotentPost(router, "alice", "key-1", "")
+
+	assert.Equal(t, 1, calls, "the handler runs once per key")
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+
+	idempotentPost(router, "alice", "key-2", "")
+	idempotentPost(router, "bob", "key-1", "")
+	assert.Equal(t, 3, calls, "keys are scoped to the user")
+
+	idempotentPost(router, "alice", "", "")
+	idempotentPost(router, "alice", "", "")
+	assert.Equal(t, 5, calls, "requests without a key always run")
+}
+
+func TestIdempotency_FailedRequestReleasesKey(t *testing.T) {
+	calls := 0
+	router := idempotencyRouter(cache.NewMemoryCache(), &calls)
+
+	assert.Equal(t, http.StatusInternalServerError, idempotentPost(router, "alice", "key-1", "500").Code)
+	retry := idempotentPost(router, "alice", "key-1", "")
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Empty(t, retry.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_InProgressConflict(t *testing.T) {
+	store := cache.NewMemoryCache()
+	calls := 0
+	router := idempotencyRouter(store, &calls)
+
+	bodyHash, _, err := hashRequestBody(httptest.NewRequest(http.MethodPost, "/upload", nil))
+	require.NoError(t, err)
+	pending, err := json.Marshal(idempotencyRecord{State: idempotencyPending, BodyHash: bodyHash})
+	require.NoError(t, err)
+	key := idempotencyCacheKey("alice", http.MethodPost, "/upload", "key-1")
+	require.NoError(t, store.Set(context.Background(), key, pending, time.Minute))
+
+	w := idempotentPost(router, "alice", "key-1", "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotency_RejectsKeyReusedWithDifferentBody(t *testing.T) {
+	calls := 0
+	router := idempotencyRouter(cache.NewMemoryCache(), &calls)
+
+	first := idempotentPostBody(router, "alice", "key-1", "", `{"rows":1}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	replay := idempotentPostBody(router, "alice", "key-1", "", `{"rows":1}`)
+	assert.Equal(t, "true", replay.Header().Get(IdempotentReplayedHeader))
+
+	w := idempotentPostBody(router, "alice", "key-1", "", `{"rows":2}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_MultipartRetryWithNewBoundary(t *testing.T) {
+	calls := 0
+	router := idempotencyRouter(cache.NewMemoryCache(), &calls)
+
+	post := func(boundary, content string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		require.NoError(t, writer.SetBoundary(boundary))
+		require.NoError(t, writer.WriteField("mode", "append"))
+		file, err := writer.CreateFormFile("file", "data.csv")
+		require.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		req.Header.Set("X-User", "alice")
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, post("first-boundary", "id\n1\n").Code)
+
+	retry := post("second-boundary", "id\n1\n")
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+
+	changed := post("third-boundary", "id\n2\n")
+	assert.Equal(t, http.StatusUnprocessableEntity, changed.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestHashRequestBody_SpoolsLargeBodies(t *testing.T) {
+	body := strings.Repeat("x", maxBufferedIdempotentBody+10)
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body))
+
+	hash, cleanup, err := hashRequestBody(req)
+	require.NoError(t, err)
+	defer cleanup()
+
+	sum := sha256.Sum256([]byte(body))
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+	read, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(read), "the handler still sees the whole body")
+}
+
+func TestIdempotency_RejectsOversizedKey(t *testing.T) {
+	calls := 0
+	router := idempotencyRouter(cache.NewMemoryCache(), &calls)
+
+	w := idempotentPost(router, "alice", strings.Repeat("k", maxIdempotencyKeyLength+1), "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, calls)
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(RequestID(slog.New(slog.NewJSONHandler(&logs, nil))))
+	router.GET("/test", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("handled")
+		c.String(http.StatusOK, c.GetString("request_id"))
+	})
+
+	tests := []struct {
+		name     string
+		incoming string
+		honored  bool
+	}{
+		{"assigned when missing", "", false},
+		{"incoming ID honored", "req-1234", true},
+		{"ID with spaces replaced", "req 1234", false},
+		{"oversized ID replaced", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			requestID := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, requestID, w.Body.String(), "the context and response carry the same ID")
+			if tt.honored {
+				assert.Equal(t, tt.incoming, requestID)
+			} else {
+				_, err := uuid.Parse(requestID)
+				assert.NoError(t, err)
+			}
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			assert.Equal(t, "handled", entry["msg"])
+			assert.Equal(t, requestID, entry["request_id"])
+		})
+	}
+}
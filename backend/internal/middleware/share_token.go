@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/saurabh22suman/oreo.io/internal/auth"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ShareTokenStore defines the lookups needed to accept dataset share tokens
+type ShareTokenStore interface {
+	GetByID(id uuid.UUID) (*models.ShareToken, error)
+	MarkUsed(id uuid.UUID) error
+}
+
+// shareTokenFromRequest returns the share token sent as the share_token query parameter or the
+// X-Share-Token header
+func shareTokenFromRequest(c *gin.Context) string {
+	if token := c.Query("share_token"); token != "" {
+		return token
+	}
+	return c.GetHeader("X-Share-Token")
+}
+
+// AllowShareToken middleware lets a dataset share token stand in for authentication on the route
+// it guards, which must name the dataset in its :dataset_id parameter. The token must be validly
+// signed, unexpired, unrevoked and issued for that dataset; it bypasses membership for that
+// dataset only. Requests without a share token are passed to requireAuth instead.
+func AllowShareToken(signer *auth.ShareTokenSigner, store ShareTokenStore, requireAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawToken := shareTokenFromRequest(c)
+		if rawToken == "" {
+			requireAuth(c)
+			return
+		}
+
+		token, ok := verifyShareToken(signer, store, rawToken, c.Param("dataset_id"))
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid, expired or revoked share token",
+			})
+			c.Abort()
+			return
+		}
+
+		if err := store.MarkUsed(token.ID); err != nil {
+			log.Printf("Warning: failed to record share token usage: %v", err)
+		}
+
+		c.Set("share_token", token)
+		c.Next()
+	}
+}
+
+// verifyShareToken checks a signed share token against its stored grant and the requested dataset
+func verifyShareToken(signer *auth.ShareTokenSigner, store ShareTokenStore, rawToken, datasetID string) (*models.ShareToken, bool) {
+	claims, err := signer.Verify(rawToken)
+	if err != nil || claims.DatasetID != datasetID || claims.Permission != models.SharePermissionView {
+		return nil, false
+	}
+
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, false
+	}
+
+	// The stored grant is authoritative, so revoking it stops the token before it expires
+	token, err := store.GetByID(tokenID)
+	if err != nil || !token.IsActive(time.Now()) || token.DatasetID.String() != datasetID {
+		return nil, false
+	}
+	return token, true
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/auth"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// fakeShareTokenStore is an in-memory ShareTokenStore keyed by token ID
+type fakeShareTokenStore struct {
+	tokens map[uuid.UUID]*models.ShareToken
+}
+
+func (f *fakeShareTokenStore) GetByID(id uuid.UUID) (*models.ShareToken, error) {
+	token, ok := f.tokens[id]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return token, nil
+}
+
+func (f *fakeShareTokenStore) MarkUsed(id uuid.UUID) error {
+	return nil
+}
+
+func TestAllowShareToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	signer := auth.NewShareTokenSigner("share-secret")
+	datasetID, otherDatasetID := uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	revokedAt := time.Now()
+
+	active := &models.ShareToken{ID: uuid.New(), DatasetID: datasetID, Permission: models.SharePermissionView, ExpiresAt: expiresAt}
+	revoked := &models.ShareToken{ID: uuid.New(), DatasetID: datasetID, Permission: models.SharePermissionView, ExpiresAt: expiresAt, RevokedAt: &revokedAt}
+	store := &fakeShareTokenStore{tokens: map[uuid.UUID]*models.ShareToken{active.ID: active, revoked.ID: revoked}}
+
+	sign := func(signer *auth.ShareTokenSigner, tokenID, datasetID uuid.UUID, expiresAt time.Time) string {
+		signed, err := signer.Sign(tokenID, datasetID, models.SharePermissionView, expiresAt)
+		require.NoError(t, err)
+		return signed
+	}
+
+	requireAuth := func(c *gin.Context) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+		c.Abort()
+	}
+	router := gin.New()
+	router.GET("/data/dataset/:dataset_id", AllowShareToken(signer, store, requireAuth), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"share_token": c.MustGet("share_token").(*models.ShareToken).ID})
+	})
+
+	tests := []struct {
+		name           string
+		datasetID      uuid.UUID
+		token          string
+		inHeader       bool
+		expectedStatus int
+	}{
+		{name: "query parameter", datasetID: datasetID, token: sign(signer, active.ID, datasetID, expiresAt), expectedStatus: http.StatusOK},
+		{name: "header", datasetID: datasetID, token: sign(signer, active.ID, datasetID, expiresAt), inHeader: true, expectedStatus: http.StatusOK},
+		{name: "no token needs a login", datasetID: datasetID, expectedStatus: http.StatusUnauthorized},
+		{name: "another dataset", datasetID: otherDatasetID, token: sign(signer, active.ID, datasetID, expiresAt), expectedStatus: http.StatusUnauthorized},
+		{name: "signed for another dataset", datasetID: otherDatasetID, token: sign(signer, active.ID, otherDatasetID, expiresAt), expectedStatus: http.StatusUnauthorized},
+		{name: "revoked", datasetID: datasetID, token: sign(signer, revoked.ID, datasetID, expiresAt), expectedStatus: http.StatusUnauthorized},
+		{name: "expired signature", datasetID: datasetID, token: sign(signer, active.ID, datasetID, time.Now().Add(-time.Minute)), expectedStatus: http.StatusUnauthorized},
+		{name: "wrong secret", datasetID: datasetID, token: sign(auth.NewShareTokenSigner("other-secret"), active.ID, datasetID, expiresAt), expectedStatus: http.StatusUnauthorized},
+		{name: "unknown grant", datasetID: datasetID, token: sign(signer, uuid.New(), datasetID, expiresAt), expectedStatus: http.StatusUnauthorized},
+		{name: "garbage", datasetID: datasetID, token: "not-a-token", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/data/dataset/"+tt.datasetID.String(), nil)
+			if tt.token != "" {
+				if tt.inHeader {
+					req.Header.Set("X-Share-Token", tt.token)
+				} else {
+					req.URL.RawQuery = "share_token=" + tt.token
+				}
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Contains(t, w.Body.String(), active.ID.String())
+			}
+		})
+	}
+
+	t.Run("no secret configured", func(t *testing.T) {
+		_, err := auth.NewShareTokenSigner("").Sign(active.ID, datasetID, models.SharePermissionView, expiresAt)
+		assert.Error(t, err)
+	})
+}
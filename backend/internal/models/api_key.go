@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey represents a project-scoped key used by services for programmatic access
+type APIKey struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	ProjectID   uuid.UUID  `json:"project_id" db:"project_id"`
+	Name        string     `json:"name" db:"name"`
+	KeyPrefix   string     `json:"key_prefix" db:"key_prefix"`
+	KeyHash     string     `json:"-" db:"key_hash"`
+	Scopes      []string   `json:"scopes" db:"scopes"`
+	Role        *string    `json:"role,omitempty" db:"role"`                 // project role the key acts with
+	PrincipalID *uuid.UUID `json:"principal_id,omitempty" db:"principal_id"` // synthetic user the key authenticates as
+	CreatedBy   uuid.UUID  `json:"created_by" db:"created_by"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// API key scopes
+const (
+	// APIKeyScopeTrustedAppend allows appending pre-validated rows to trusted datasets
+	APIKeyScopeTrustedAppend = "data:append_trusted"
+)
+
+// apiKeyScopes are the scopes a project owner may grant a key
+var apiKeyScopes = map[string]bool{
+	APIKeyScopeTrustedAppend: true,
+}
+
+// CreateAPIKeyRequest represents a project owner's request for a new API key
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=255"`
+	Role   string   `json:"role" binding:"required"` // collaborator or viewer
+	Scopes []string `json:"scopes"`
+}
+
+// Validate checks the key's role and scopes; keys can't manage members, so admin is not allowed
+func (r *CreateAPIKeyRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name must not be blank")
+	}
+	if r.Role != "collaborator" && r.Role != "viewer" {
+		return errors.New("role must be collaborator or viewer")
+	}
+	for _, scope := range r.Scopes {
+		if !apiKeyScopes[scope] {
+			return fmt.Errorf("unknown scope '%s'", scope)
+		}
+		if scope == APIKeyScopeTrustedAppend && r.Role == RoleViewer {
+			return fmt.Errorf("viewer keys can't be granted the '%s' scope", scope)
+		}
+	}
+	return nil
+}
+
+// CreatedAPIKey is a newly created key with its plaintext value, which is only ever returned once
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// IsActive reports whether the key has not been revoked
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil
+}
+
+// IsViewer reports whether the key acts with the read-only viewer role
+func (k *APIKey) IsViewer() bool {
+	return k.Role != nil && *k.Role == RoleViewer
+}
+
+// ActorID returns the user the key acts as: its principal, or for keys made only for trusted
+// appends, which have no principal, the project owner who created the key
+func (k *APIKey) ActorID() uuid.UUID {
+	if k.PrincipalID != nil {
+		return *k.PrincipalID
+	}
+	return k.CreatedBy
+}
+
+// HasScope reports whether the key grants the given scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// DirectAppendRequest represents a request from a trusted service to append rows
+type DirectAppendRequest struct {
+	Rows []map[string]interface{} `json:"rows" binding:"required,min=1"`
+}
+
+// DirectAppendResult represents the outcome of a direct append
+type DirectAppendResult struct {
+	DatasetID     uuid.UUID `json:"dataset_id"`
+	RowsAppended  int       `json:"rows_appended"`
+	StartRowIndex int       `json:"start_row_index"`
+}
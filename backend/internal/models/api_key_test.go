@@ -0,0 +1,17 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAPIKeyRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&CreateAPIKeyRequest{Name: "CI", Role: "collaborator"}).Validate())
+	assert.NoError(t, (&CreateAPIKeyRequest{Name: "CI", Role: "collaborator", Scopes: []string{APIKeyScopeTrustedAppend}}).Validate())
+
+	assert.EqualError(t, (&CreateAPIKeyRequest{Name: " ", Role: "viewer"}).Validate(), "name must not be blank")
+	assert.EqualError(t, (&CreateAPIKeyRequest{Name: "CI", Role: "admin"}).Validate(), "role must be collaborator or viewer")
+	assert.EqualError(t, (&CreateAPIKeyRequest{Name: "CI", Role: "viewer", Scopes: []string{"data:everything"}}).Validate(), "unknown scope 'data:everything'")
+	assert.EqualError(t, (&CreateAPIKeyRequest{Name: "CI", Role: "viewer", Scopes: []string{APIKeyScopeTrustedAppend}}).Validate(), "viewer keys can't be granted the 'data:append_trusted' scope")
+}
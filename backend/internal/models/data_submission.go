@@ -1,7 +1,9 @@
 package models
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -35,6 +37,64 @@ type DataSubmission struct {
 	AppliedAt         *time.Time             `json:"applied_at" db:"applied_at"`
 	CreatedAt         time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time              `json:"updated_at" db:"updated_at"`
+	FileHash          *string                `json:"file_hash,omitempty" db:"file_hash"`
+	SchemaName        *string                `json:"schema_name,omitempty" db:"schema_name"` // nil validates against the default schema
+	Mode              string                 `json:"mode" db:"mode"`                          // one of the SubmissionMode constants
+	ColumnMapping     ColumnMapping          `json:"column_mapping,omitempty" db:"column_mapping"` // file headers renamed to schema fields before validation
+}
+
+// SchemaVariant returns the schema name the submission validates against, or "" for the default schema
+func (s *DataSubmission) SchemaVariant() string {
+	if s.SchemaName == nil {
+		return ""
+	}
+	return *s.SchemaName
+}
+
+// ColumnMapping renames headers of a submitted file to schema field names, keyed by file header.
+// Headers without an entry keep their name.
+type ColumnMapping map[string]string
+
+// Value encodes the mapping as JSON for the JSONB column_mapping column; an empty mapping is stored as NULL
+func (m ColumnMapping) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(map[string]string(m))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode column mapping: %w", err)
+	}
+	return data, nil
+}
+
+// Scan decodes a mapping read from the JSONB column_mapping column
+func (m *ColumnMapping) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ColumnMapping", src)
+	}
+	return json.Unmarshal(data, (*map[string]string)(m))
+}
+
+// Equal reports whether two mappings rename the same headers to the same fields
+func (m ColumnMapping) Equal(other ColumnMapping) bool {
+	if len(m) != len(other) {
+		return false
+	}
+	for header, field := range m {
+		if target, ok := other[header]; !ok || target != field {
+			return false
+		}
+	}
+	return true
 }
 
 // DataSubmissionWithDetails includes additional details for display
@@ -68,6 +128,7 @@ type DatasetBusinessRule struct {
 	ErrorMessage string          `json:"error_message" db:"error_message"`
 	IsActive     bool            `json:"is_active" db:"is_active"`
 	Priority     int             `json:"priority" db:"priority"`
+	StopOnFail   bool            `json:"stop_on_fail" db:"stop_on_fail"` // a failing row skips lower-priority rules
 	CreatedBy    uuid.UUID       `json:"created_by" db:"created_by"`
 	CreatedAt    time.Time       `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
@@ -80,8 +141,21 @@ const (
 	DataSubmissionStatusApproved    = "approved"
 	DataSubmissionStatusRejected    = "rejected"
 	DataSubmissionStatusApplied     = "applied"
+	DataSubmissionStatusExpired     = "expired" // left pending past the staging TTL
+)
+
+// Submission modes decide how approved rows are applied to the dataset
+const (
+	SubmissionModeAppend  = "append"  // rows are added after the dataset's existing rows
+	SubmissionModeReplace = "replace" // the dataset's existing rows are removed first
+	SubmissionModeUpsert  = "upsert"  // rows matching a stored row on the schema's unique fields update it, the rest are added
 )
 
+// IsValidSubmissionMode reports whether mode is a supported submission mode
+func IsValidSubmissionMode(mode string) bool {
+	return mode == SubmissionModeAppend || mode == SubmissionModeReplace || mode == SubmissionModeUpsert
+}
+
 // ValidationStatus constants for staging data
 const (
 	ValidationStatusValid   = "valid"
@@ -97,6 +171,7 @@ const (
 	RuleTypeRangeCheck      = "range_check"
 	RuleTypeUnique          = "unique"
 	RuleTypeRequired        = "required"
+	RuleTypeReference       = "reference" // field_name values must exist in another dataset's field
 )
 
 // CreateDataSubmissionRequest represents the request to submit new data
@@ -105,6 +180,21 @@ type CreateDataSubmissionRequest struct {
 	FileName  string    `json:"file_name" binding:"required"`
 }
 
+// PresignAppendUploadRequest asks for a pre-signed URL to upload an append file directly to storage
+type PresignAppendUploadRequest struct {
+	FileName string `json:"file_name" binding:"required"`
+}
+
+// CompleteAppendUploadRequest reports a finished direct upload so its file is validated and submitted
+type CompleteAppendUploadRequest struct {
+	UploadID      uuid.UUID     `json:"upload_id" binding:"required"`
+	FileName      string        `json:"file_name" binding:"required"`
+	SchemaName    string        `json:"schema_name"`    // optional schema variant; defaults to the dataset's default schema
+	Mode          string        `json:"mode"`           // optional submission mode; defaults to append
+	ColumnMapping ColumnMapping `json:"column_mapping"` // optional renames of file headers to schema field names
+	ExpectedRows  *int          `json:"expected_rows"`  // optional data row count the client expects; a mismatch is warned about
+}
+
 // UpdateDataSubmissionRequest represents admin update to submission
 type UpdateDataSubmissionRequest struct {
 	Status     string  `json:"status" binding:"required,oneof=under_review approved rejected"`
@@ -121,6 +211,33 @@ type ValidationResult struct {
 	SchemaErrors       []DataValidationError  `json:"schema_errors"`
 	BusinessRuleErrors []DataValidationError  `json:"business_rule_errors"`
 	FieldStats         map[string]FieldStats  `json:"field_stats"`
+	IgnoredFields      []string               `json:"ignored_fields,omitempty"` // unknown columns dropped by the lenient header mode
+}
+
+// SubmissionSummary is a submission's metadata and stored validation result, without staging rows
+type SubmissionSummary struct {
+	Submission       *DataSubmission   `json:"submission"`
+	ValidationResult *ValidationResult `json:"validation_result"`
+}
+
+// Sampling modes for quick validation
+const (
+	SampleModeFirst  = "first"
+	SampleModeRandom = "random"
+)
+
+// SampledValidationResult is a validation result computed over a sample of an upload, with
+// invalid row counts extrapolated to the whole file
+type SampledValidationResult struct {
+	Sampled               bool              `json:"sampled"` // false when the sample covered every row
+	SampleMode            string            `json:"sample_mode"`
+	SampleSize            int               `json:"sample_size"` // rows actually validated
+	FileRows              int               `json:"file_rows"`
+	SampledRowIndexes     []int             `json:"sampled_row_indexes"`
+	EstimatedInvalidRows  int               `json:"estimated_invalid_rows"`
+	EstimatedValidRows    int               `json:"estimated_valid_rows"`
+	EstimatedInvalidRatio float64           `json:"estimated_invalid_ratio"`
+	Result                *ValidationResult `json:"result"` // counts and errors cover the sampled rows only
 }
 
 // FieldStats represents statistics for a field during validation
@@ -129,6 +246,81 @@ type FieldStats struct {
 	UniqueValues  int `json:"unique_values"`
 	NullValues    int `json:"null_values"`
 	InvalidValues int `json:"invalid_values"`
+	// Values lists the distinct non-null values, sorted, when there are few enough to track;
+	// it is empty for high-cardinality fields
+	Values []string `json:"values,omitempty"`
+}
+
+// Conflict types reported when staged rows collide with existing dataset data
+const (
+	ConflictTypeDuplicateKey    = "duplicate_key"
+	ConflictTypeUniqueViolation = "unique_violation"
+	ConflictTypeDuplicatePII    = "duplicate_pii"
+)
+
+// DataConflict represents a staged row that collides with a row already stored in the dataset
+type DataConflict struct {
+	RowIndex         int    `json:"row_index"`
+	FieldName        string `json:"field_name"`
+	ConflictType     string `json:"conflict_type"`
+	Value            string `json:"value"`
+	ExistingRowIndex int    `json:"existing_row_index"`
+	Message          string `json:"message"`
+}
+
+// ConflictReport summarizes conflicts between a submission and its target dataset
+type ConflictReport struct {
+	SubmissionID   uuid.UUID      `json:"submission_id"`
+	DatasetID      uuid.UUID      `json:"dataset_id"`
+	CheckedRows    int            `json:"checked_rows"`
+	ConflictedRows int            `json:"conflicted_rows"`
+	Conflicts      []DataConflict `json:"conflicts"`
+}
+
+// ApplyPlacementPreview reports where a submission's valid rows would land in the dataset if it
+// were applied now. Valid rows are numbered contiguously in staging order, so invalid or removed
+// staging rows leave no gaps.
+type ApplyPlacementPreview struct {
+	StartingRowIndex int  `json:"starting_row_index"`
+	FirstRowIndex    *int `json:"first_row_index"` // nil when no rows are valid
+	LastRowIndex     *int `json:"last_row_index"`
+	ValidRows        int  `json:"valid_rows"`
+}
+
+// ApplyReport summarizes how a submission's valid rows were applied to its dataset
+type ApplyReport struct {
+	Mode          string   `json:"mode"`
+	KeyFields     []string `json:"key_fields,omitempty"` // unique fields rows were matched on, in upsert mode
+	InsertedRows  int      `json:"inserted_rows"`
+	UpdatedRows   int      `json:"updated_rows"`             // stored rows overwritten in upsert mode
+	DeletedRows   int      `json:"deleted_rows"`             // stored rows removed in replace mode
+	DuplicateKeys int      `json:"duplicate_keys,omitempty"` // staged rows superseded by a later row with the same key
+}
+
+// SubmissionFile is the uploaded file of a submission with the state that decides its retention
+type SubmissionFile struct {
+	FilePath  string    `db:"file_path"`
+	Status    string    `db:"status"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// TestBusinessRuleRequest is a rule to try out against sample rows without saving it.
+// When Rows is empty the rule runs against the dataset's stored data, which only editors can do
+// for datasets with PII fields.
+type TestBusinessRuleRequest struct {
+	RuleType     string                   `json:"rule_type" binding:"required"`
+	RuleConfig   BusinessRuleConfig       `json:"rule_config" binding:"required"`
+	ErrorMessage string                   `json:"error_message"`
+	Rows         []map[string]interface{} `json:"rows"`
+}
+
+// BusinessRuleTestResult lists the rows a tested rule would flag
+type BusinessRuleTestResult struct {
+	RuleType    string                `json:"rule_type"`
+	Source      string                `json:"source"` // "request" or "dataset"
+	CheckedRows int                   `json:"checked_rows"`
+	FlaggedRows []int                 `json:"flagged_rows"`
+	Violations  []DataValidationError `json:"violations"`
 }
 
 // BusinessRuleConfig represents configuration for different rule types
@@ -148,4 +340,86 @@ type BusinessRuleConfig struct {
 	// For custom SQL validation  
 	Query        string      `json:"query,omitempty"`
 	Parameters   []string    `json:"parameters,omitempty"`
+
+	// For reference validation: the lookup dataset and field that field_name values must match
+	TargetDatasetID string   `json:"target_dataset_id,omitempty"`
+	TargetField     string   `json:"target_field,omitempty"`
+}
+
+// BusinessRuleDefinition is one rule of a bulk import
+type BusinessRuleDefinition struct {
+	RuleName     string             `json:"rule_name"`
+	RuleType     string             `json:"rule_type"`
+	RuleConfig   BusinessRuleConfig `json:"rule_config"`
+	ErrorMessage string             `json:"error_message"`
+	Priority     int                `json:"priority"`
+	StopOnFail   bool               `json:"stop_on_fail"`
+}
+
+// SetBusinessRuleActiveRequest activates or deactivates a business rule
+type SetBusinessRuleActiveRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+// BulkCreateBusinessRulesRequest imports several business rules at once
+type BulkCreateBusinessRulesRequest struct {
+	Rules []BusinessRuleDefinition `json:"rules" binding:"required"`
+}
+
+// Outcomes of a rule in a bulk import
+const (
+	BulkRuleStatusCreated    = "created"
+	BulkRuleStatusInvalid    = "invalid"
+	BulkRuleStatusNotCreated = "not_created" // valid, but another rule of the import was invalid
+)
+
+// BulkRuleResult reports the outcome of one rule of a bulk import, by its position in the request
+type BulkRuleResult struct {
+	Index    int                  `json:"index"`
+	RuleName string               `json:"rule_name"`
+	Status   string               `json:"status"`
+	Errors   []string             `json:"errors,omitempty"`
+	Rule     *DatasetBusinessRule `json:"rule,omitempty"`
+}
+
+// SubmissionStatusCount is the number of submissions with one status
+type SubmissionStatusCount struct {
+	Status string `json:"status" db:"status"`
+	Count  int    `json:"count" db:"count"`
+}
+
+// DatasetPendingSubmissions is one dataset's volume of submissions awaiting review
+type DatasetPendingSubmissions struct {
+	DatasetID          uuid.UUID `json:"dataset_id" db:"dataset_id"`
+	DatasetName        string    `json:"dataset_name" db:"dataset_name"`
+	ProjectName        string    `json:"project_name" db:"project_name"`
+	PendingSubmissions int       `json:"pending_submissions" db:"pending_submissions"`
+	PendingRows        int64     `json:"pending_rows" db:"pending_rows"`
+	OldestSubmittedAt  time.Time `json:"oldest_submitted_at" db:"oldest_submitted_at"`
+}
+
+// SubmissionDashboard summarizes every submission for the admin dashboard. Pending counts
+// submissions awaiting review: pending or under review.
+type SubmissionDashboard struct {
+	CountsByStatus           map[string]int              `json:"counts_by_status"`
+	Total                    int                         `json:"total"`
+	Pending                  int                         `json:"pending"`
+	OldestPendingSubmittedAt *time.Time                  `json:"oldest_pending_submitted_at"`
+	OldestPendingAgeSeconds  *int64                      `json:"oldest_pending_age_seconds"`
+	TopPendingDatasets       []DatasetPendingSubmissions `json:"top_pending_datasets"`
+}
+
+// SubmissionIntegrityReport compares a submission's stored file with the hash and row count recorded
+// when it was submitted, and with the row count the client expected
+type SubmissionIntegrityReport struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	FileHash     string    `json:"file_hash"`     // SHA-256 of the stored file now
+	RecordedHash *string   `json:"recorded_hash"` // SHA-256 recorded at submission
+	HashMatches  bool      `json:"hash_matches"`
+	RowCount     int       `json:"row_count"`   // data rows recorded at submission
+	ParsedRows   int       `json:"parsed_rows"` // data rows found re-parsing the stored file
+	FileLines    int       `json:"file_lines"`  // lines after the header, counting line breaks inside quoted values
+	ExpectedRows *int      `json:"expected_rows,omitempty"`
+	Verified     bool      `json:"verified"`
+	Warnings     []string  `json:"warnings"`
 }
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnMapping_RoundTrip(t *testing.T) {
+	mapping := ColumnMapping{"First Name": "first_name", "E-mail": "email"}
+
+	value, err := mapping.Value()
+	require.NoError(t, err)
+
+	var scanned ColumnMapping
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, mapping, scanned)
+
+	value, err = ColumnMapping{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value, "an empty mapping is stored as NULL")
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+}
+
+func TestColumnMapping_Equal(t *testing.T) {
+	mapping := ColumnMapping{"First Name": "first_name"}
+
+	assert.True(t, mapping.Equal(ColumnMapping{"First Name": "first_name"}))
+	assert.True(t, ColumnMapping(nil).Equal(ColumnMapping{}))
+	assert.False(t, mapping.Equal(ColumnMapping{"First Name": "name"}))
+	assert.False(t, mapping.Equal(ColumnMapping{"Name": "first_name"}))
+	assert.False(t, mapping.Equal(nil))
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,20 +12,200 @@ import (
 
 // Dataset represents a data file uploaded to a project
 type Dataset struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	ProjectID   uuid.UUID `json:"project_id" db:"project_id"`
-	Name        string    `json:"name" db:"name"`
-	Description string    `json:"description" db:"description"`
-	FileName    string    `json:"file_name" db:"file_name"`
-	FilePath    string    `json:"file_path" db:"file_path"`
-	FileSize    int64     `json:"file_size" db:"file_size"`
-	MimeType    string    `json:"mime_type" db:"mime_type"`
-	RowCount    int       `json:"row_count" db:"row_count"`
-	ColumnCount int       `json:"column_count" db:"column_count"`
-	Status      string    `json:"status" db:"status"` // "processing", "ready", "error"
-	UploadedBy  uuid.UUID `json:"uploaded_by" db:"uploaded_by"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID            uuid.UUID     `json:"id" db:"id"`
+	ProjectID     uuid.UUID     `json:"project_id" db:"project_id"`
+	Name          string        `json:"name" db:"name"`
+	Description   string        `json:"description" db:"description"`
+	FileName      string        `json:"file_name" db:"file_name"`
+	FilePath      string        `json:"file_path" db:"file_path"`
+	FileSize      int64         `json:"file_size" db:"file_size"`
+	MimeType      string        `json:"mime_type" db:"mime_type"`
+	RowCount      int           `json:"row_count" db:"row_count"`
+	ColumnCount   int           `json:"column_count" db:"column_count"`
+	Status        string        `json:"status" db:"status"` // "processing", "ready", "partial", "ingestion_failed", "error"
+	IsTrusted     bool          `json:"is_trusted" db:"is_trusted"`
+	DisplayField  *string       `json:"display_field" db:"display_field"`   // labels rows as _label in data responses
+	CSVDialect    *CSVDialect   `json:"csv_dialect" db:"csv_dialect"`       // nil parses CSV files as RFC 4180
+	SheetName     *string       `json:"sheet_name" db:"sheet_name"`         // Excel sheet the data was read from
+	ColumnOrder   ColumnOrder   `json:"column_order" db:"column_order"`     // header order of the uploaded file
+	PIIGuardrails PIIGuardrails `json:"pii_guardrails" db:"pii_guardrails"` // contact fields whose values must not repeat
+	HeaderMode    string        `json:"header_mode" db:"header_mode"`       // one of the HeaderMode constants
+	EnforceSchema bool          `json:"enforce_schema" db:"enforce_schema"` // direct row edits must pass schema validation
+	NullTokens    NullTokens    `json:"null_tokens" db:"null_tokens"`       // cell values read as null, such as "N/A"
+	UploadedBy    uuid.UUID     `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// CSVDialect describes how a dataset's CSV files are written. Empty settings keep the standard
+// dialect: comma delimiter, double-quote quoting with doubled quotes as escape, and no null marker.
+type CSVDialect struct {
+	Delimiter string `json:"delimiter,omitempty"`
+	Quote     string `json:"quote,omitempty"`
+	Escape    string `json:"escape,omitempty"`     // escapes the next character inside quotes; defaults to the quote
+	NullValue string `json:"null_value,omitempty"` // unquoted fields equal to this are read as empty
+	Encoding  string `json:"encoding,omitempty"`   // utf-8 or windows-1252; empty detects invalid UTF-8 bytes as windows-1252
+}
+
+// Character encodings accepted for CSV files
+const (
+	CSVEncodingUTF8        = "utf-8"
+	CSVEncodingWindows1252 = "windows-1252"
+)
+
+// Value encodes the dialect as JSON for the JSONB csv_dialect column
+func (d CSVDialect) Value() (driver.Value, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CSV dialect: %w", err)
+	}
+	return data, nil
+}
+
+// Scan decodes a dialect read from the JSONB csv_dialect column
+func (d *CSVDialect) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into CSVDialect", src)
+	}
+	return json.Unmarshal(data, d)
+}
+
+// ColumnOrder lists column names in the order they appeared in the uploaded file's header
+type ColumnOrder []string
+
+// Value encodes the order as JSON for the JSONB column_order column; an empty order is stored as NULL
+func (o ColumnOrder) Value() (driver.Value, error) {
+	if len(o) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]string(o))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode column order: %w", err)
+	}
+	return data, nil
+}
+
+// Scan decodes an order read from the JSONB column_order column
+func (o *ColumnOrder) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*o = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ColumnOrder", src)
+	}
+	return json.Unmarshal(data, (*[]string)(o))
+}
+
+// NullTokens are the cell values, such as "NA", "N/A" or "-", that a dataset reads as null
+type NullTokens []string
+
+// Matches reports whether a cell value, with surrounding whitespace trimmed, is one of the tokens.
+// Tokens are case-sensitive, so "NA" doesn't make "na" null.
+func (t NullTokens) Matches(value string) bool {
+	value = strings.TrimSpace(value)
+	for _, token := range t {
+		if value == token {
+			return true
+		}
+	}
+	return false
+}
+
+// Value encodes the tokens as JSON for the JSONB null_tokens column; no tokens are stored as NULL
+func (t NullTokens) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]string(t))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode null tokens: %w", err)
+	}
+	return data, nil
+}
+
+// Scan decodes tokens read from the JSONB null_tokens column
+func (t *NullTokens) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into NullTokens", src)
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Header modes decide what happens to submitted columns that aren't schema fields
+const (
+	HeaderModeStrict  = "strict"  // unknown columns make the submission invalid
+	HeaderModeLenient = "lenient" // unknown columns are ignored and never stored
+)
+
+// IsValidHeaderMode reports whether mode is one of the HeaderMode constants
+func IsValidHeaderMode(mode string) bool {
+	return mode == HeaderModeStrict || mode == HeaderModeLenient
+}
+
+// Kinds of personal contact data a PII guardrail can protect
+const (
+	PIIKindEmail = "email"
+	PIIKindPhone = "phone"
+)
+
+// PIIGuardrail rejects appended rows whose value for a contact field already exists in the
+// dataset. Emails match case-insensitively and phone numbers match on their digits.
+type PIIGuardrail struct {
+	FieldName string `json:"field_name"`
+	Kind      string `json:"kind"` // email or phone
+}
+
+// PIIGuardrails lists the guarded contact fields of a dataset
+type PIIGuardrails []PIIGuardrail
+
+// Value encodes the guardrails as JSON for the JSONB pii_guardrails column; no guardrails are stored as NULL
+func (g PIIGuardrails) Value() (driver.Value, error) {
+	if len(g) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]PIIGuardrail(g))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PII guardrails: %w", err)
+	}
+	return data, nil
+}
+
+// Scan decodes guardrails read from the JSONB pii_guardrails column
+func (g *PIIGuardrails) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*g = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into PIIGuardrails", src)
+	}
+	return json.Unmarshal(data, (*[]PIIGuardrail)(g))
 }
 
 // DatasetWithProject includes project information
@@ -43,9 +227,87 @@ type UpdateDatasetRequest struct {
 	Description string `json:"description" binding:"max=1000"`
 }
 
+// UpdateDatasetTrustRequest represents the request to toggle direct appends for a dataset
+type UpdateDatasetTrustRequest struct {
+	IsTrusted *bool `json:"is_trusted" binding:"required"`
+}
+
+// RowLabelKey is the key under which a row's display label is returned with dataset data
+const RowLabelKey = "_label"
+
+// UpdateDatasetDisplayFieldRequest represents the request to set or clear a dataset's display field
+type UpdateDatasetDisplayFieldRequest struct {
+	DisplayField *string `json:"display_field"` // null or empty clears the setting
+}
+
+// UpdateDatasetCSVDialectRequest represents the request to set or clear a dataset's CSV dialect
+type UpdateDatasetCSVDialectRequest struct {
+	CSVDialect *CSVDialect `json:"csv_dialect"` // null clears the setting
+}
+
+// UpdateDatasetPIIGuardrailsRequest represents the request to replace a dataset's PII guardrails
+type UpdateDatasetPIIGuardrailsRequest struct {
+	PIIGuardrails PIIGuardrails `json:"pii_guardrails"` // null or empty removes every guardrail
+}
+
+// UpdateDatasetHeaderModeRequest represents the request to set how a dataset treats unknown columns
+type UpdateDatasetHeaderModeRequest struct {
+	HeaderMode string `json:"header_mode" binding:"required"`
+}
+
+// UpdateDatasetEnforceSchemaRequest represents the request to toggle schema validation of direct row edits
+type UpdateDatasetEnforceSchemaRequest struct {
+	EnforceSchema *bool `json:"enforce_schema" binding:"required"`
+}
+
+// UpdateDatasetNullTokensRequest represents the request to replace the values a dataset reads as null
+type UpdateDatasetNullTokensRequest struct {
+	NullTokens NullTokens `json:"null_tokens"` // null or empty removes every token
+}
+
 // DatasetStatus constants
 const (
 	DatasetStatusProcessing = "processing"
 	DatasetStatusReady      = "ready"
 	DatasetStatusError      = "error"
+	// DatasetStatusIngestionFailed marks a dataset whose file was processed but whose rows could
+	// not be stored; its data can only be previewed from the uploaded file
+	DatasetStatusIngestionFailed = "ingestion_failed"
+	// DatasetStatusPartial marks a dataset where some rows of the uploaded file failed to store
+	DatasetStatusPartial = "partial"
 )
+
+// DatasetFieldStats holds the per-field statistics last computed over a dataset's stored rows
+type DatasetFieldStats struct {
+	DatasetID  uuid.UUID             `json:"dataset_id"`
+	RowCount   int                   `json:"row_count"`
+	Fields     map[string]FieldStats `json:"fields"`
+	ComputedAt time.Time             `json:"computed_at"`
+	Stale      bool                  `json:"stale"` // data changed since the stats were computed
+}
+
+// Bulk insert modes
+const (
+	BulkInsertAllOrNothing = "all_or_nothing" // any failed row rolls back the whole insert
+	BulkInsertBestEffort   = "best_effort"    // failed rows are skipped and reported
+)
+
+// BulkInsertRowError reports a row that could not be inserted
+type BulkInsertRowError struct {
+	RowIndex int    `json:"row_index"` // position of the row in the input
+	Error    string `json:"error"`
+}
+
+// BulkInsertReport summarizes a bulk insert of dataset rows
+type BulkInsertReport struct {
+	Mode         string               `json:"mode"`
+	TotalRows    int                  `json:"total_rows"`
+	InsertedRows int                  `json:"inserted_rows"`
+	FailedRows   []BulkInsertRowError `json:"failed_rows"`
+	RolledBack   bool                 `json:"rolled_back"`
+}
+
+// IsValidBulkInsertMode reports whether mode names a supported bulk insert mode
+func IsValidBulkInsertMode(mode string) bool {
+	return mode == BulkInsertAllOrNothing || mode == BulkInsertBestEffort
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnOrder_RoundTrip(t *testing.T) {
+	order := ColumnOrder{"id", "name", "city"}
+
+	value, err := order.Value()
+	require.NoError(t, err)
+
+	var scanned ColumnOrder
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, order, scanned)
+
+	var fromString ColumnOrder
+	require.NoError(t, fromString.Scan(string(value.([]byte))))
+	assert.Equal(t, order, fromString)
+}
+
+func TestColumnOrder_Empty(t *testing.T) {
+	value, err := ColumnOrder(nil).Value()
+	require.NoError(t, err)
+	assert.Nil(t, value, "empty order is stored as NULL")
+
+	scanned := ColumnOrder{"stale"}
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.Error(t, scanned.Scan(42))
+}
+
+func TestPIIGuardrails_RoundTrip(t *testing.T) {
+	guardrails := PIIGuardrails{{FieldName: "email", Kind: PIIKindEmail}, {FieldName: "mobile", Kind: PIIKindPhone}}
+
+	value, err := guardrails.Value()
+	require.NoError(t, err)
+
+	var scanned PIIGuardrails
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, guardrails, scanned)
+
+	value, err = PIIGuardrails{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value, "no guardrails are stored as NULL")
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+}
+
+func TestNullTokens(t *testing.T) {
+	tokens := NullTokens{"N/A", "-"}
+	assert.True(t, tokens.Matches(" N/A "))
+	assert.True(t, tokens.Matches("-"))
+	assert.False(t, tokens.Matches("n/a"), "tokens are case-sensitive")
+	assert.False(t, tokens.Matches(""))
+	assert.False(t, NullTokens(nil).Matches("N/A"))
+
+	value, err := tokens.Value()
+	require.NoError(t, err)
+	var scanned NullTokens
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, tokens, scanned)
+
+	value, err = NullTokens{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, value)
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+}
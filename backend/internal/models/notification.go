@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTypeSubmissionReviewed tells a submitter a reviewer changed their submission's status
+const NotificationTypeSubmissionReviewed = "submission_reviewed"
+
+// Notification is an in-app message for one user
+type Notification struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Type       string     `json:"type" db:"type"`
+	Title      string     `json:"title" db:"title"`
+	Message    string     `json:"message" db:"message"`
+	ResourceID *uuid.UUID `json:"resource_id,omitempty" db:"resource_id"` // what the notification is about
+	ReadAt     *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
@@ -16,6 +16,8 @@ type Project struct {
 	OwnerID     uuid.UUID `json:"owner_id" db:"owner_id"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// MaxDisplayRows caps how many rows of the project's datasets can be paged through; nil uses the server default
+	MaxDisplayRows *int `json:"max_display_rows,omitempty" db:"max_display_rows"`
 }
 
 // CreateProjectRequest represents the request to create a new project
@@ -28,6 +30,8 @@ type CreateProjectRequest struct {
 type UpdateProjectRequest struct {
 	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
 	Description *string `json:"description,omitempty" binding:"omitempty,max=1000"`
+	// MaxDisplayRows sets the project's row display cap; 0 clears it back to the server default
+	MaxDisplayRows *int `json:"max_display_rows,omitempty"`
 }
 
 // Validate validates the create project request
@@ -86,10 +90,15 @@ func (req *UpdateProjectRequest) Validate() error {
 		*req.Description = description
 	}
 
+	// Check display cap if provided; the server-wide maximum is checked by the handler
+	if req.MaxDisplayRows != nil && *req.MaxDisplayRows < 0 {
+		return errors.New("max display rows must not be negative")
+	}
+
 	return nil
 }
 
 // HasUpdates checks if the update request has any actual updates
 func (req *UpdateProjectRequest) HasUpdates() bool {
-	return req.Name != nil || req.Description != nil
+	return req.Name != nil || req.Description != nil || req.MaxDisplayRows != nil
 }
@@ -1,24 +1,65 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// MemberPermissions is a member's permission map, stored in the JSONB permissions column
+type MemberPermissions map[string]interface{}
+
+// Value encodes the permissions as JSON, storing an empty object when there are none
+func (p MemberPermissions) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	data, err := json.Marshal(map[string]interface{}(p))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode permissions: %w", err)
+	}
+	return data, nil
+}
+
+// Scan decodes permissions read from the JSONB column
+func (p *MemberPermissions) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*p = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into MemberPermissions", src)
+	}
+
+	permissions := map[string]interface{}{}
+	if err := json.Unmarshal(data, &permissions); err != nil {
+		return fmt.Errorf("failed to decode permissions: %w", err)
+	}
+	*p = permissions
+	return nil
+}
+
 // ProjectMember represents a user's membership in a project
 type ProjectMember struct {
-	ID          uuid.UUID              `json:"id" db:"id"`
-	ProjectID   uuid.UUID              `json:"project_id" db:"project_id"`
-	UserID      uuid.UUID              `json:"user_id" db:"user_id"`
-	Role        string                 `json:"role" db:"role"` // owner, admin, collaborator, viewer
-	InvitedBy   *uuid.UUID             `json:"invited_by,omitempty" db:"invited_by"`
-	InvitedAt   time.Time              `json:"invited_at" db:"invited_at"`
-	JoinedAt    *time.Time             `json:"joined_at,omitempty" db:"joined_at"`
-	Status      string                 `json:"status" db:"status"` // pending, accepted, declined, removed
-	Permissions map[string]interface{} `json:"permissions" db:"permissions"`
-	CreatedAt   time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time              `json:"updated_at" db:"updated_at"`
+	ID          uuid.UUID         `json:"id" db:"id"`
+	ProjectID   uuid.UUID         `json:"project_id" db:"project_id"`
+	UserID      uuid.UUID         `json:"user_id" db:"user_id"`
+	Role        string            `json:"role" db:"role"` // owner, admin, collaborator, viewer
+	InvitedBy   *uuid.UUID        `json:"invited_by,omitempty" db:"invited_by"`
+	InvitedAt   time.Time         `json:"invited_at" db:"invited_at"`
+	JoinedAt    *time.Time        `json:"joined_at,omitempty" db:"joined_at"`
+	Status      string            `json:"status" db:"status"` // pending, accepted, declined, removed
+	Permissions MemberPermissions `json:"permissions" db:"permissions"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
 }
 
 // ProjectMemberWithUser includes user information
@@ -28,6 +69,13 @@ type ProjectMemberWithUser struct {
 	UserEmail string `json:"user_email" db:"user_email"`
 }
 
+// ProjectInvitation is a pending membership with the project and inviter names
+type ProjectInvitation struct {
+	ProjectMember
+	ProjectName string  `json:"project_name" db:"project_name"`
+	InviterName *string `json:"inviter_name,omitempty" db:"inviter_name"`
+}
+
 // InviteUserRequest represents a request to invite a user to a project
 type InviteUserRequest struct {
 	Email       string                 `json:"email" binding:"required,email"`
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemberPermissions_RoundTrip(t *testing.T) {
+	permissions := MemberPermissions{
+		"can_upload":  true,
+		"max_uploads": float64(25),
+		"datasets":    []interface{}{"sales", "inventory"},
+		"limits": map[string]interface{}{
+			"rows":     float64(100000),
+			"approve":  false,
+			"contacts": []interface{}{"ops@example.com"},
+		},
+	}
+
+	value, err := permissions.Value()
+	require.NoError(t, err)
+
+	var scanned MemberPermissions
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, permissions, scanned)
+
+	// Postgres drivers may hand JSONB back as a string
+	var fromString MemberPermissions
+	require.NoError(t, fromString.Scan(string(value.([]byte))))
+	assert.Equal(t, permissions, fromString)
+}
+
+func TestMemberPermissions_Empty(t *testing.T) {
+	value, err := MemberPermissions(nil).Value()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(value.([]byte)))
+
+	var scanned MemberPermissions
+	require.NoError(t, scanned.Scan(nil))
+	assert.Nil(t, scanned)
+
+	assert.Error(t, scanned.Scan(`not json`))
+	assert.Error(t, scanned.Scan(42))
+}
+
+func TestProjectMember_PermissionsJSON(t *testing.T) {
+	member := ProjectMember{Permissions: MemberPermissions{"can_upload": true}}
+	data, err := json.Marshal(member)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"permissions":{"can_upload":true}`)
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"time"
 	"github.com/google/uuid"
 )
@@ -11,12 +13,19 @@ type SchemaFieldType string
 const (
 	FieldTypeString   SchemaFieldType = "string"
 	FieldTypeNumber   SchemaFieldType = "number"
+	FieldTypeInteger  SchemaFieldType = "integer" // whole number, at most MaxSafeInteger in magnitude
 	FieldTypeBoolean  SchemaFieldType = "boolean"
 	FieldTypeDate     SchemaFieldType = "date"
 	FieldTypeDateTime SchemaFieldType = "datetime"
 	FieldTypeEmail    SchemaFieldType = "email"
 	FieldTypeURL      SchemaFieldType = "url"
 	FieldTypeUUID     SchemaFieldType = "uuid"
+	FieldTypeObject   SchemaFieldType = "object" // JSON object stored natively in dataset_data
+	FieldTypeArray    SchemaFieldType = "array"  // JSON array stored natively in dataset_data
+	FieldTypeCurrency SchemaFieldType = "currency" // e.g. "$1,234.56", stored as its numeric amount
+	FieldTypePercent  SchemaFieldType = "percent"  // e.g. "45%", stored as percentage points (45)
+	FieldTypePhone    SchemaFieldType = "phone"    // validation.format selects a stricter region format
+	FieldTypeList     SchemaFieldType = "list"     // e.g. "red|green", split on validation.separator and stored as a JSON array
 )
 
 // DatasetSchema represents the schema definition for a dataset
@@ -26,6 +35,8 @@ type DatasetSchema struct {
 	Name        string         `json:"name" db:"name"`
 	Description string         `json:"description" db:"description"`
 	Fields      []SchemaField  `json:"fields"`
+	DateFormats []string       `json:"date_formats" db:"date_formats"` // Go layouts accepted for date fields
+	IsDefault   bool           `json:"is_default" db:"is_default"`     // used when no variant is named
 	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
 }
@@ -39,6 +50,7 @@ type SchemaField struct {
 	DataType     string          `json:"data_type" db:"data_type"` // Will store string values from SchemaFieldType
 	IsRequired   bool            `json:"is_required" db:"is_required"`
 	IsUnique     bool            `json:"is_unique" db:"is_unique"`
+	IsPII        bool            `json:"is_pii" db:"is_pii"` // masked on read for users who can't edit the dataset
 	DefaultValue *string         `json:"default_value" db:"default_value"`
 	Position     int             `json:"position" db:"position"`
 	Validation   FieldValidation `json:"validation"`
@@ -46,15 +58,51 @@ type SchemaField struct {
 	UpdatedAt    time.Time       `json:"updated_at" db:"updated_at"`
 }
 
+// UniqueFieldNames returns the names of the schema's unique fields, in field order
+func (s *DatasetSchema) UniqueFieldNames() []string {
+	var names []string
+	for _, field := range s.Fields {
+		if field.IsUnique {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
 // FieldValidation represents validation rules for a schema field
 type FieldValidation struct {
 	MinLength   *int     `json:"min_length,omitempty"`
 	MaxLength   *int     `json:"max_length,omitempty"`
 	MinValue    *float64 `json:"min_value,omitempty"`
 	MaxValue    *float64 `json:"max_value,omitempty"`
+	Precision   *int     `json:"precision,omitempty"` // most significant digits of numeric values
+	Scale       *int     `json:"scale,omitempty"`     // most digits after the decimal point of numeric values
 	Pattern     *string  `json:"pattern,omitempty"`
-	Options     []string `json:"options,omitempty"` // For enum/select fields
-	Format      *string  `json:"format,omitempty"`  // date format, etc.
+	Options     []string `json:"options,omitempty"`    // For enum/select fields
+	Format      *string  `json:"format,omitempty"`     // date format, etc.
+	Transforms  []string `json:"transforms,omitempty"` // FieldTransform names applied in order before validation
+	Separator   *string  `json:"separator,omitempty"`  // splits list values, "|" when unset
+	ItemType    *string  `json:"item_type,omitempty"`  // data type of each list item, string when unset
+	MinItems    *int     `json:"min_items,omitempty"`
+	MaxItems    *int     `json:"max_items,omitempty"`
+	UniqueItems bool     `json:"unique_items,omitempty"` // list items may not repeat
+}
+
+// Field transforms canonicalize text values before they are validated and stored
+const (
+	FieldTransformTrim      = "trim"      // remove surrounding whitespace
+	FieldTransformLowercase = "lowercase" // "Active" becomes "active"
+	FieldTransformUppercase = "uppercase" // "us" becomes "US"
+	FieldTransformTitlecase = "titlecase" // "jane DOE" becomes "Jane Doe"
+)
+
+// SchemaVersion is a snapshot of a schema's fields, recorded each time the schema is created or updated
+type SchemaVersion struct {
+	ID        uuid.UUID     `json:"id" db:"id"`
+	SchemaID  uuid.UUID     `json:"schema_id" db:"schema_id"`
+	Version   int           `json:"version" db:"version"`
+	Fields    []SchemaField `json:"fields"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
 }
 
 // DatasetData represents the actual data rows in a dataset
@@ -76,6 +124,8 @@ type CreateSchemaRequest struct {
 	Name        string                `json:"name" binding:"required"`
 	Description string                `json:"description"`
 	Fields      []CreateFieldRequest  `json:"fields" binding:"required"`
+	DateFormats []string              `json:"date_formats"`
+	IsDefault   *bool                 `json:"is_default"` // defaults to true for a dataset's first schema
 }
 
 // CreateFieldRequest represents the request to create a new field
@@ -85,16 +135,25 @@ type CreateFieldRequest struct {
 	DataType     string          `json:"data_type" binding:"required"`
 	IsRequired   bool            `json:"is_required"`
 	IsUnique     bool            `json:"is_unique"`
+	IsPII        bool            `json:"is_pii"`
 	DefaultValue *string         `json:"default_value"`
 	Position     int             `json:"position"`
 	Validation   FieldValidation `json:"validation"`
 }
 
+// ValidateDraftSchemaRequest asks to validate rows against a schema definition that isn't saved
+type ValidateDraftSchemaRequest struct {
+	Fields      []CreateFieldRequest     `json:"fields" binding:"required,min=1"`
+	DateFormats []string                 `json:"date_formats"`
+	Rows        []map[string]interface{} `json:"rows" binding:"required,min=1"`
+}
+
 // UpdateSchemaRequest represents the request to update a schema
 type UpdateSchemaRequest struct {
 	Name        string                `json:"name"`
 	Description string                `json:"description"`
 	Fields      []UpdateFieldRequest  `json:"fields"`
+	DateFormats []string              `json:"date_formats"` // nil keeps the current formats
 }
 
 // UpdateFieldRequest represents the request to update a field
@@ -105,6 +164,7 @@ type UpdateFieldRequest struct {
 	DataType     string          `json:"data_type"`
 	IsRequired   bool            `json:"is_required"`
 	IsUnique     bool            `json:"is_unique"`
+	IsPII        bool            `json:"is_pii"`
 	DefaultValue *string         `json:"default_value"`
 	Position     int             `json:"position"`
 	Validation   FieldValidation `json:"validation"`
@@ -119,6 +179,7 @@ type DataPreviewRequest struct {
 // DataPreviewResponse represents the response for data preview
 type DataPreviewResponse struct {
 	Data        []map[string]interface{} `json:"data"`
+	Columns     []string                 `json:"columns"` // column names in display order
 	Schema      *DatasetSchema           `json:"schema"`
 	TotalRows   int                      `json:"total"`
 	Page        int                      `json:"page"`
@@ -126,10 +187,68 @@ type DataPreviewResponse struct {
 	TotalPages  int                      `json:"total_pages"`
 }
 
+// DataSearchResult is a dataset row matching a full-text search, with its relevance and the
+// fields that matched, highlighted with <mark> tags
+type DataSearchResult struct {
+	RowIndex   int                    `json:"row_index"`
+	Rank       float64                `json:"rank"`
+	Data       map[string]interface{} `json:"data"`
+	Highlights map[string]string      `json:"highlights"`
+}
+
+// DataSearchResponse represents one page of full-text search results, most relevant first
+type DataSearchResponse struct {
+	Query      string             `json:"query"`
+	Results    []DataSearchResult `json:"results"`
+	TotalRows  int                `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalPages int                `json:"total_pages"`
+}
+
 // UpdateDataRequest represents request to update dataset data
 type UpdateDataRequest struct {
 	RowIndex int                    `json:"row_index" binding:"required"`
 	Data     map[string]interface{} `json:"data" binding:"required"`
+	Version  *int                   `json:"version"` // the row's version when it was read; the update is refused if it has changed
+}
+
+// Row filter operators for bulk row deletes
+const (
+	RowFilterEquals   = "equals"   // the field's text equals the value
+	RowFilterContains = "contains" // the field's text contains the value, ignoring case
+)
+
+// RowFilter matches rows by the text of one field
+type RowFilter struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"` // one of the RowFilter constants
+	Value    string `json:"value"`
+}
+
+// BulkDeleteDataRequest selects the rows to delete, either by filter or by row index
+type BulkDeleteDataRequest struct {
+	Filter     *RowFilter `json:"filter"`
+	RowIndices []int      `json:"row_indices"`
+}
+
+// Validate checks that the request selects rows in exactly one way
+func (r *BulkDeleteDataRequest) Validate() error {
+	if (r.Filter == nil) == (len(r.RowIndices) == 0) {
+		return errors.New("provide either a filter or row_indices")
+	}
+	if r.Filter != nil {
+		if r.Filter.Field == "" {
+			return errors.New("filter field is required")
+		}
+		if r.Filter.Operator != RowFilterEquals && r.Filter.Operator != RowFilterContains {
+			return fmt.Errorf("filter operator must be '%s' or '%s'", RowFilterEquals, RowFilterContains)
+		}
+		if r.Filter.Operator == RowFilterContains && r.Filter.Value == "" {
+			return errors.New("a contains filter needs a value")
+		}
+	}
+	return nil
 }
 
 // SchemaValidationError represents a schema validation error
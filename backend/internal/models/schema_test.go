@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkDeleteDataRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     BulkDeleteDataRequest
+		wantErr string
+	}{
+		{name: "row indices", req: BulkDeleteDataRequest{RowIndices: []int{0, 4}}},
+		{name: "equals filter", req: BulkDeleteDataRequest{Filter: &RowFilter{Field: "status", Operator: RowFilterEquals}}},
+		{name: "contains filter", req: BulkDeleteDataRequest{Filter: &RowFilter{Field: "email", Operator: RowFilterContains, Value: "@test."}}},
+		{name: "nothing selected", req: BulkDeleteDataRequest{}, wantErr: "provide either a filter or row_indices"},
+		{
+			name:    "both selections",
+			req:     BulkDeleteDataRequest{Filter: &RowFilter{Field: "status", Operator: RowFilterEquals}, RowIndices: []int{1}},
+			wantErr: "provide either a filter or row_indices",
+		},
+		{name: "no field", req: BulkDeleteDataRequest{Filter: &RowFilter{Operator: RowFilterEquals}}, wantErr: "filter field is required"},
+		{name: "unknown operator", req: BulkDeleteDataRequest{Filter: &RowFilter{Field: "status", Operator: "like"}}, wantErr: "filter operator must be 'equals' or 'contains'"},
+		{name: "empty contains", req: BulkDeleteDataRequest{Filter: &RowFilter{Field: "email", Operator: RowFilterContains}}, wantErr: "a contains filter needs a value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
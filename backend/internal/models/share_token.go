@@ -0,0 +1,60 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SharePermissionView lets a share token read a dataset's data
+const SharePermissionView = "view"
+
+// MaxShareTokenLifetime bounds how far in the future a share token may expire
+const MaxShareTokenLifetime = 90 * 24 * time.Hour
+
+// ShareToken is a dataset owner's grant letting anyone holding the signed token read one dataset
+// without an account. Only the grant is stored; the token itself is signed and returned once.
+type ShareToken struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	DatasetID  uuid.UUID  `json:"dataset_id" db:"dataset_id"`
+	Name       string     `json:"name" db:"name"`
+	Permission string     `json:"permission" db:"permission"`
+	CreatedBy  uuid.UUID  `json:"created_by" db:"created_by"`
+	ExpiresAt  time.Time  `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsActive reports whether the token is neither revoked nor expired at now
+func (t *ShareToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// CreateShareTokenRequest represents an owner's request to share a dataset read-only
+type CreateShareTokenRequest struct {
+	Name      string    `json:"name" binding:"required,max=255"` // who the token is for, shown in listings
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+}
+
+// Validate checks the token is named and expires in the future, within MaxShareTokenLifetime of now
+func (r *CreateShareTokenRequest) Validate(now time.Time) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return errors.New("name must not be blank")
+	}
+	if !r.ExpiresAt.After(now) {
+		return errors.New("expires_at must be in the future")
+	}
+	if r.ExpiresAt.After(now.Add(MaxShareTokenLifetime)) {
+		return errors.New("expires_at must be within 90 days")
+	}
+	return nil
+}
+
+// CreatedShareToken is a new share token with its signed value, which is only ever returned once
+type CreatedShareToken struct {
+	ShareToken
+	Token string `json:"token"`
+}
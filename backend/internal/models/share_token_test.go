@@ -0,0 +1,26 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateShareTokenRequest_Validate(t *testing.T) {
+	now := time.Now()
+
+	assert.NoError(t, (&CreateShareTokenRequest{Name: "Auditor", ExpiresAt: now.Add(24 * time.Hour)}).Validate(now))
+	assert.EqualError(t, (&CreateShareTokenRequest{Name: " ", ExpiresAt: now.Add(time.Hour)}).Validate(now), "name must not be blank")
+	assert.EqualError(t, (&CreateShareTokenRequest{Name: "Auditor", ExpiresAt: now}).Validate(now), "expires_at must be in the future")
+	assert.EqualError(t, (&CreateShareTokenRequest{Name: "Auditor", ExpiresAt: now.Add(91 * 24 * time.Hour)}).Validate(now), "expires_at must be within 90 days")
+}
+
+func TestShareToken_IsActive(t *testing.T) {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+
+	assert.True(t, (&ShareToken{ExpiresAt: now.Add(time.Hour)}).IsActive(now))
+	assert.False(t, (&ShareToken{ExpiresAt: now}).IsActive(now))
+	assert.False(t, (&ShareToken{ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt}).IsActive(now))
+}
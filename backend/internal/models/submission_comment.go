@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubmissionComment is one message in the review thread of a data submission
+type SubmissionComment struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	SubmissionID uuid.UUID `json:"submission_id" db:"submission_id"`
+	UserID       uuid.UUID `json:"user_id" db:"user_id"`
+	Body         string    `json:"body" db:"body"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// SubmissionCommentWithAuthor includes the name of the comment's author
+type SubmissionCommentWithAuthor struct {
+	SubmissionComment
+	AuthorName string `json:"author_name" db:"author_name"`
+}
+
+// CreateSubmissionCommentRequest represents a request to post a comment on a submission
+type CreateSubmissionCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
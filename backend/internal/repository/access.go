@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// rowGetter is the part of *sqlx.DB used by shared access checks
+type rowGetter interface {
+	Get(dest interface{}, query string, args ...interface{}) error
+}
+
+// datasetAccessQuery counts datasets the user can access: the project owner's, or those of
+// projects where the user is an accepted member
+const datasetAccessQuery = `
+	SELECT COUNT(*) 
+	FROM datasets d 
+	JOIN projects p ON d.project_id = p.id 
+	WHERE d.id = $1 AND (p.owner_id = $2 OR EXISTS (
+		SELECT 1 FROM project_members pm 
+		WHERE pm.project_id = p.id AND pm.user_id = $2 AND pm.status = 'accepted'
+	))`
+
+// checkDatasetAccess is the single dataset access check shared by all repositories
+func checkDatasetAccess(db rowGetter, datasetID, userID uuid.UUID) (bool, error) {
+	var count int
+	if err := db.Get(&count, datasetAccessQuery, datasetID, userID); err != nil {
+		return false, fmt.Errorf("failed to check dataset access: %w", err)
+	}
+	return count > 0, nil
+}
+
+// datasetEditAccessQuery counts datasets the user can edit: the project owner's, or those of
+// projects where the user is an accepted member with an editing role
+const datasetEditAccessQuery = `
+	SELECT COUNT(*) 
+	FROM datasets d 
+	JOIN projects p ON d.project_id = p.id 
+	WHERE d.id = $1 AND (p.owner_id = $2 OR EXISTS (
+		SELECT 1 FROM project_members pm 
+		WHERE pm.project_id = p.id AND pm.user_id = $2 
+		AND pm.status = 'accepted' AND pm.role IN ('owner', 'admin', 'collaborator')
+	))`
+
+// checkDatasetEditAccess is the dataset edit check shared by all repositories
+func checkDatasetEditAccess(db rowGetter, datasetID, userID uuid.UUID) (bool, error) {
+	var count int
+	if err := db.Get(&count, datasetEditAccessQuery, datasetID, userID); err != nil {
+		return false, fmt.Errorf("failed to check dataset edit access: %w", err)
+	}
+	return count > 0, nil
+}
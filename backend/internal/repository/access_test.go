@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRowGetter struct {
+	count int
+	err   error
+	query string
+	args  []interface{}
+}
+
+func (f *fakeRowGetter) Get(dest interface{}, query string, args ...interface{}) error {
+	f.query, f.args = query, args
+	if f.err != nil {
+		return f.err
+	}
+	*dest.(*int) = f.count
+	return nil
+}
+
+func TestCheckDatasetAccess(t *testing.T) {
+	datasetID, userID := uuid.New(), uuid.New()
+
+	db := &fakeRowGetter{count: 1}
+	hasAccess, err := checkDatasetAccess(db, datasetID, userID)
+	require.NoError(t, err)
+	assert.True(t, hasAccess)
+	assert.Equal(t, []interface{}{datasetID, userID}, db.args)
+
+	// Access follows the real schema: project owners and accepted project members
+	assert.Contains(t, db.query, "p.owner_id = $2")
+	assert.Contains(t, db.query, "project_members")
+	assert.Contains(t, db.query, "pm.status = 'accepted'")
+	assert.NotContains(t, db.query, "project_collaborators")
+
+	hasAccess, err = checkDatasetAccess(&fakeRowGetter{}, datasetID, userID)
+	require.NoError(t, err)
+	assert.False(t, hasAccess)
+
+	_, err = checkDatasetAccess(&fakeRowGetter{err: errors.New("connection reset")}, datasetID, userID)
+	assert.Error(t, err)
+}
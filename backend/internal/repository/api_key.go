@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ErrAPIKeyNotFound is returned when no API key matches the given hash
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// APIKeyRepository handles database operations for API keys
+type APIKeyRepository struct {
+	db *sqlx.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *sqlx.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// apiKeyColumns are the api_keys columns read by scanAPIKey, in order
+const apiKeyColumns = `id, project_id, name, key_prefix, key_hash, scopes, role, principal_id,
+			created_by, last_used_at, revoked_at, created_at`
+
+// scanAPIKey reads one api_keys row selected with apiKeyColumns
+func scanAPIKey(row interface{ Scan(...interface{}) error }) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID, &key.ProjectID, &key.Name, &key.KeyPrefix, &key.KeyHash,
+		pq.Array(&key.Scopes), &key.Role, &key.PrincipalID, &key.CreatedBy,
+		&key.LastUsedAt, &key.RevokedAt, &key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetByHash retrieves an API key by the hash of its plaintext value
+func (r *APIKeyRepository) GetByHash(keyHash string) (*models.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1`
+
+	key, err := scanAPIKey(r.db.QueryRow(query, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// MarkUsed records the time an API key was last used
+func (r *APIKeyRepository) MarkUsed(id uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET last_used_at = $1 WHERE id = $2`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update api key usage: %w", err)
+	}
+	return nil
+}
+
+// apiKeyPrincipalPassword is stored as the password hash of API key principals; it is never a valid
+// bcrypt hash, so nobody can log in as a key
+const apiKeyPrincipalPassword = "!"
+
+// apiKeyPrincipalName returns the user name of a key's principal, within the users name limit
+func apiKeyPrincipalName(keyName string) string {
+	name := []rune("API key: " + keyName)
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return string(name)
+}
+
+// Create stores a project API key together with its principal: a synthetic user that is an
+// accepted member of the project with the key's role, so requests made with the key pass the same
+// access checks as a person with that role. The key's ID, role and created_by must be set.
+func (r *APIKeyRepository) Create(key *models.APIKey) error {
+	if key.Role == nil {
+		return errors.New("api key role is required")
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	principalID := uuid.New()
+	_, err = tx.Exec(`
+		INSERT INTO users (id, email, name, password_hash, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)`,
+		principalID, fmt.Sprintf("api-key-%s@api-keys.oreo.invalid", key.ID),
+		apiKeyPrincipalName(key.Name), apiKeyPrincipalPassword, now)
+	if err != nil {
+		return fmt.Errorf("failed to create api key principal: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO project_members 
+		(id, project_id, user_id, role, invited_by, invited_at, joined_at, status, permissions, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6, 'accepted', '{}', $6, $6)`,
+		uuid.New(), key.ProjectID, principalID, *key.Role, key.CreatedBy, now)
+	if err != nil {
+		return fmt.Errorf("failed to add api key principal to project: %w", err)
+	}
+
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	key.PrincipalID = &principalID
+	key.CreatedAt = now
+	_, err = tx.Exec(`
+		INSERT INTO api_keys (id, project_id, name, key_prefix, key_hash, scopes, role, principal_id, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		key.ID, key.ProjectID, key.Name, key.KeyPrefix, key.KeyHash, pq.Array(key.Scopes),
+		key.Role, key.PrincipalID, key.CreatedBy, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit api key: %w", err)
+	}
+	return nil
+}
+
+// ListByProject returns a project's API keys, revoked ones included, newest first
+func (r *APIKeyRepository) ListByProject(projectID uuid.UUID) ([]models.APIKey, error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE project_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+// Revoke revokes an active key of the project and removes its principal from the project, so a
+// leaked key stops working even where the key itself isn't checked again
+func (r *APIKeyRepository) Revoke(projectID, keyID uuid.UUID) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var principalID *uuid.UUID
+	err = tx.Get(&principalID, `
+		UPDATE api_keys SET revoked_at = $1
+		WHERE id = $2 AND project_id = $3 AND revoked_at IS NULL
+		RETURNING principal_id`, time.Now(), keyID, projectID)
+	if err == sql.ErrNoRows {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	if principalID != nil {
+		_, err = tx.Exec(`DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`, projectID, *principalID)
+		if err != nil {
+			return fmt.Errorf("failed to remove api key principal from project: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit api key revocation: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,10 @@ package repository
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,23 +15,43 @@ import (
 	"github.com/saurabh22suman/oreo.io/internal/models"
 )
 
+// ErrSubmissionNotFound is returned when a data submission does not exist
+var ErrSubmissionNotFound = errors.New("submission not found")
+
+// ErrBusinessRuleNotFound is returned when a business rule does not exist
+var ErrBusinessRuleNotFound = errors.New("business rule not found")
+
 type DataSubmissionRepository struct {
-	db *sqlx.DB
+	db         *sqlx.DB
+	applyRetry txRetryPolicy
 }
 
 func NewDataSubmissionRepository(db *sqlx.DB) *DataSubmissionRepository {
-	return &DataSubmissionRepository{db: db}
+	return &DataSubmissionRepository{
+		db:         db,
+		applyRetry: newTxRetryPolicy(DefaultTxRetryAttempts, DefaultTxRetryBaseDelay),
+	}
+}
+
+// SetApplyRetry sets how many times applying staging data is attempted when it hits a serialization
+// failure or deadlock, and the delay before the first retry, which doubles on each further retry
+func (r *DataSubmissionRepository) SetApplyRetry(maxAttempts int, baseDelay time.Duration) {
+	r.applyRetry = newTxRetryPolicy(maxAttempts, baseDelay)
 }
 
 // CreateSubmission creates a new data submission request
 func (r *DataSubmissionRepository) CreateSubmission(submission *models.DataSubmission) error {
+	return insertSubmission(r.db, submission)
+}
+
+func insertSubmission(db sqlx.Execer, submission *models.DataSubmission) error {
 	query := `
 		INSERT INTO data_submissions (
 			id, dataset_id, submitted_by, file_name, file_path, file_size, 
-			row_count, status, validation_results, submitted_at, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+			row_count, status, validation_results, submitted_at, created_at, updated_at, file_hash, schema_name, mode, column_mapping
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`
 
-	_, err := r.db.Exec(query,
+	_, err := db.Exec(query,
 		submission.ID,
 		submission.DatasetID,
 		submission.SubmittedBy,
@@ -40,6 +64,10 @@ func (r *DataSubmissionRepository) CreateSubmission(submission *models.DataSubmi
 		submission.SubmittedAt,
 		submission.CreatedAt,
 		submission.UpdatedAt,
+		submission.FileHash,
+		submission.SchemaName,
+		submission.Mode,
+		submission.ColumnMapping,
 	)
 
 	return err
@@ -52,12 +80,71 @@ func (r *DataSubmissionRepository) GetSubmission(id uuid.UUID) (*models.DataSubm
 
 	err := r.db.Get(&submission, query, id)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+
+	return &submission, nil
+}
+
+// FindRecentSubmission retrieves the latest submission of an identical file by the same user since the given time
+func (r *DataSubmissionRepository) FindRecentSubmission(datasetID, userID uuid.UUID, fileHash string, since time.Time) (*models.DataSubmission, error) {
+	var submission models.DataSubmission
+	query := `
+		SELECT * FROM data_submissions 
+		WHERE dataset_id = $1 AND submitted_by = $2 AND file_hash = $3 AND submitted_at >= $4
+		ORDER BY submitted_at DESC
+		LIMIT 1`
+
+	err := r.db.Get(&submission, query, datasetID, userID, fileHash, since)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, err
 	}
 
 	return &submission, nil
 }
 
+// CreateSubmissionUnlessRecent creates a submission unless the most recent submission of the same
+// file by the same user to the dataset since the given time is a duplicate, in which case that
+// submission is returned and nothing is created. The lookup and insert hold a transaction-scoped
+// lock on the dataset, user and file hash, so concurrent double submits create one submission.
+func (r *DataSubmissionRepository) CreateSubmissionUnlessRecent(submission *models.DataSubmission, since time.Time, isDuplicate func(*models.DataSubmission) bool) (*models.DataSubmission, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lockKey := fmt.Sprintf("submission:%s:%s:%s", submission.DatasetID, submission.SubmittedBy, *submission.FileHash)
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey); err != nil {
+		return nil, fmt.Errorf("failed to lock submission: %w", err)
+	}
+
+	var recent models.DataSubmission
+	query := `
+		SELECT * FROM data_submissions 
+		WHERE dataset_id = $1 AND submitted_by = $2 AND file_hash = $3 AND submitted_at >= $4
+		ORDER BY submitted_at DESC
+		LIMIT 1`
+	err = tx.Get(&recent, query, submission.DatasetID, submission.SubmittedBy, *submission.FileHash, since)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil && isDuplicate(&recent) {
+		return &recent, nil
+	}
+
+	if err := insertSubmission(tx, submission); err != nil {
+		return nil, err
+	}
+	return nil, tx.Commit()
+}
+
 // GetSubmissionWithDetails retrieves a submission with additional details
 func (r *DataSubmissionRepository) GetSubmissionWithDetails(id uuid.UUID) (*models.DataSubmissionWithDetails, error) {
 	var submission models.DataSubmissionWithDetails
@@ -103,28 +190,10 @@ func (r *DataSubmissionRepository) GetSubmissionsByDataset(datasetID uuid.UUID)
 		WHERE ds.dataset_id = $1
 		ORDER BY ds.submitted_at DESC`
 
-	rows, err := r.db.Query(query, datasetID)
+	err := r.db.Select(&submissions, query, datasetID)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var submission models.DataSubmissionWithDetails
-		err := rows.Scan(
-			&submission.ID, &submission.DatasetID, &submission.SubmittedBy,
-			&submission.FileName, &submission.FilePath, &submission.FileSize,
-			&submission.RowCount, &submission.Status, &submission.ValidationResults,
-			&submission.AdminNotes, &submission.ReviewedBy, &submission.ReviewedAt,
-			&submission.SubmittedAt, &submission.AppliedAt, &submission.CreatedAt,
-			&submission.UpdatedAt, &submission.DatasetName, &submission.ProjectName,
-			&submission.SubmitterName, &submission.SubmitterEmail, &submission.ReviewerName,
-		)
-		if err != nil {
-			return nil, err
-		}
-		submissions = append(submissions, &submission)
-	}
 
 	return submissions, nil
 }
@@ -148,28 +217,10 @@ func (r *DataSubmissionRepository) GetPendingSubmissions() ([]*models.DataSubmis
 		WHERE ds.status IN ($1, $2)
 		ORDER BY ds.submitted_at ASC`
 
-	rows, err := r.db.Query(query, models.DataSubmissionStatusPending, models.DataSubmissionStatusUnderReview)
+	err := r.db.Select(&submissions, query, models.DataSubmissionStatusPending, models.DataSubmissionStatusUnderReview)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var submission models.DataSubmissionWithDetails
-		err := rows.Scan(
-			&submission.ID, &submission.DatasetID, &submission.SubmittedBy,
-			&submission.FileName, &submission.FilePath, &submission.FileSize,
-			&submission.RowCount, &submission.Status, &submission.ValidationResults,
-			&submission.AdminNotes, &submission.ReviewedBy, &submission.ReviewedAt,
-			&submission.SubmittedAt, &submission.AppliedAt, &submission.CreatedAt,
-			&submission.UpdatedAt, &submission.DatasetName, &submission.ProjectName,
-			&submission.SubmitterName, &submission.SubmitterEmail, &submission.ReviewerName,
-		)
-		if err != nil {
-			return nil, err
-		}
-		submissions = append(submissions, &submission)
-	}
 
 	return submissions, nil
 }
@@ -198,6 +249,69 @@ func (r *DataSubmissionRepository) MarkSubmissionApplied(id uuid.UUID) error {
 	return err
 }
 
+// ListExpiredPendingSubmissions retrieves submissions still pending that were submitted before the cutoff
+func (r *DataSubmissionRepository) ListExpiredPendingSubmissions(before time.Time) ([]*models.DataSubmission, error) {
+	var submissions []*models.DataSubmission
+	query := `
+		SELECT * FROM data_submissions 
+		WHERE status = $1 AND submitted_at < $2
+		ORDER BY submitted_at`
+
+	err := r.db.Select(&submissions, query, models.DataSubmissionStatusPending, before)
+	return submissions, err
+}
+
+// ListSubmissionFiles retrieves the file path, status and last update of every submission
+func (r *DataSubmissionRepository) ListSubmissionFiles() ([]models.SubmissionFile, error) {
+	var files []models.SubmissionFile
+	err := r.db.Select(&files, `SELECT file_path, status, updated_at FROM data_submissions`)
+	return files, err
+}
+
+// ExpirePendingSubmission marks a submission expired and removes its staged rows, returning whether
+// it was expired and how many rows were removed. Both happen in one transaction, and only while the
+// submission is still pending, so a submission reviewed in the meantime keeps its staged rows. With
+// remove set the submission itself is deleted as well.
+func (r *DataSubmissionRepository) ExpirePendingSubmission(id uuid.UUID, remove bool) (bool, int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, 0, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE data_submissions 
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND status = $3`
+	result, err := tx.Exec(query, models.DataSubmissionStatusExpired, id, models.DataSubmissionStatusPending)
+	if err != nil {
+		return false, 0, err
+	}
+	if expired, err := result.RowsAffected(); err != nil || expired == 0 {
+		return false, 0, err
+	}
+
+	result, err = tx.Exec("DELETE FROM data_submission_staging WHERE submission_id = $1", id)
+	if err != nil {
+		return false, 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, 0, err
+	}
+
+	if remove {
+		if _, err := tx.Exec("DELETE FROM data_submissions WHERE id = $1", id); err != nil {
+			return false, 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, 0, err
+	}
+	return true, deleted, nil
+}
+
 // DeleteSubmission deletes a submission and all its staging data
 func (r *DataSubmissionRepository) DeleteSubmission(id uuid.UUID) error {
 	tx, err := r.db.Beginx()
@@ -256,9 +370,10 @@ func (r *DataSubmissionRepository) CreateStagingData(stagingData []*models.DataS
 	return tx.Commit()
 }
 
-// GetStagingData retrieves staging data for a submission
-func (r *DataSubmissionRepository) GetStagingData(submissionID uuid.UUID, limit, offset int) ([]*models.DataSubmissionStaging, error) {
-	var stagingData []*models.DataSubmissionStaging
+// GetStagingData retrieves a page of staging data for a submission.
+// Rows that cannot be read are skipped and logged so one corrupt row does not fail the page;
+// the number of skipped rows is returned alongside the page.
+func (r *DataSubmissionRepository) GetStagingData(submissionID uuid.UUID, limit, offset int) ([]*models.DataSubmissionStaging, int, error) {
 	query := `
 		SELECT * FROM data_submission_staging 
 		WHERE submission_id = $1 
@@ -266,6 +381,65 @@ func (r *DataSubmissionRepository) GetStagingData(submissionID uuid.UUID, limit,
 		LIMIT $2 OFFSET $3`
 
 	rows, err := r.db.Query(query, submissionID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	return scanStagingRows(submissionID, rows)
+}
+
+// stagingRowScanner is the part of *sql.Rows used to read staging rows
+type stagingRowScanner interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+}
+
+// scanStagingRows reads staging rows, skipping rows that fail to scan or hold malformed JSON
+func scanStagingRows(submissionID uuid.UUID, rows stagingRowScanner) ([]*models.DataSubmissionStaging, int, error) {
+	var stagingData []*models.DataSubmissionStaging
+	skipped := 0
+
+	for rows.Next() {
+		var data models.DataSubmissionStaging
+		var rawData, rawErrors []byte
+		err := rows.Scan(
+			&data.ID, &data.SubmissionID, &data.RowIndex, &rawData,
+			&data.ValidationStatus, &rawErrors, &data.CreatedAt,
+		)
+		if err != nil {
+			log.Printf("Warning: skipping unreadable staging row of submission %s: %v", submissionID, err)
+			skipped++
+			continue
+		}
+
+		if !json.Valid(rawData) || (rawErrors != nil && !json.Valid(rawErrors)) {
+			log.Printf("Warning: skipping staging row %d of submission %s with malformed JSON", data.RowIndex, submissionID)
+			skipped++
+			continue
+		}
+
+		data.Data = json.RawMessage(rawData)
+		if rawErrors != nil {
+			validationErrors := json.RawMessage(rawErrors)
+			data.ValidationErrors = &validationErrors
+		}
+		stagingData = append(stagingData, &data)
+	}
+
+	return stagingData, skipped, rows.Err()
+}
+
+// GetStagingDataByStatus retrieves all staging rows of a submission with the given validation status
+func (r *DataSubmissionRepository) GetStagingDataByStatus(submissionID uuid.UUID, validationStatus string) ([]*models.DataSubmissionStaging, error) {
+	var stagingData []*models.DataSubmissionStaging
+	query := `
+		SELECT * FROM data_submission_staging
+		WHERE submission_id = $1 AND validation_status = $2
+		ORDER BY row_index`
+
+	rows, err := r.db.Query(query, submissionID, validationStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -297,37 +471,71 @@ func (r *DataSubmissionRepository) UpdateStagingDataRow(id uuid.UUID, data json.
 	return err
 }
 
-// ApplyStagingDataToDataset applies approved staging data to the target dataset
-func (r *DataSubmissionRepository) ApplyStagingDataToDataset(submissionID uuid.UUID, datasetID uuid.UUID, userID uuid.UUID) error {
+// ApplyStagingDataToDataset applies approved staging data to the target dataset. In replace mode
+// the dataset's existing rows are deleted in the same transaction, so the dataset keeps its ID,
+// schemas and history but only the submission's rows. In upsert mode rows whose keyFields values
+// match stored rows overwrite them and the rest are appended. Serialization failures and deadlocks
+// are retried; a *TxRetryError is returned once the attempts run out.
+func (r *DataSubmissionRepository) ApplyStagingDataToDataset(submissionID uuid.UUID, datasetID uuid.UUID, userID uuid.UUID, mode string, keyFields []string) (*models.ApplyReport, error) {
+	if !models.IsValidSubmissionMode(mode) {
+		return nil, fmt.Errorf("unsupported submission mode: %s", mode)
+	}
+	if mode == models.SubmissionModeUpsert && len(keyFields) == 0 {
+		return nil, fmt.Errorf("upsert of submission %s needs at least one key field", submissionID)
+	}
+
+	var report *models.ApplyReport
+	err := r.applyRetry.run("apply of submission "+submissionID.String(), func() error {
+		var err error
+		report, err = r.applyStagingData(submissionID, datasetID, userID, mode, keyFields)
+		return err
+	})
+	return report, err
+}
+
+func (r *DataSubmissionRepository) applyStagingData(submissionID uuid.UUID, datasetID uuid.UUID, userID uuid.UUID, mode string, keyFields []string) (*models.ApplyReport, error) {
 	tx, err := r.db.Beginx()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer tx.Rollback()
 
-	// Get the current max row index in the dataset
-	var maxRowIndex sql.NullInt64
-	err = tx.Get(&maxRowIndex, "SELECT MAX(row_index) FROM dataset_data WHERE dataset_id = $1", datasetID)
-	if err != nil {
-		return err
-	}
+	report := &models.ApplyReport{Mode: mode}
 
-	startIndex := 0
-	if maxRowIndex.Valid {
-		startIndex = int(maxRowIndex.Int64) + 1
+	if mode == models.SubmissionModeReplace {
+		result, err := tx.Exec(`DELETE FROM dataset_data WHERE dataset_id = $1`, datasetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove existing rows: %w", err)
+		}
+		deleted, _ := result.RowsAffected()
+		report.DeletedRows = int(deleted)
 	}
 
-	// Copy valid staging data to dataset_data
-	query := `
-		INSERT INTO dataset_data (dataset_id, row_index, data, created_by, updated_by)
-		SELECT $1, $2 + row_index, data, $3, $3
-		FROM data_submission_staging 
-		WHERE submission_id = $4 AND validation_status = $5
-		ORDER BY row_index`
+	if mode == models.SubmissionModeUpsert {
+		report.KeyFields = keyFields
+		if err := upsertStagingData(tx, report, submissionID, datasetID, userID, keyFields); err != nil {
+			return nil, err
+		}
+	} else {
+		startIndex, err := applyStartIndex(tx, datasetID, mode)
+		if err != nil {
+			return nil, err
+		}
 
-	_, err = tx.Exec(query, datasetID, startIndex, userID, submissionID, models.ValidationStatusValid)
-	if err != nil {
-		return err
+		// Copy valid staging data to dataset_data in staging order, numbered contiguously from
+		// startIndex so skipped or removed staging rows leave no gaps
+		query := `
+			INSERT INTO dataset_data (dataset_id, row_index, data, created_by, updated_by)
+			SELECT $1, $2 + ROW_NUMBER() OVER (ORDER BY row_index) - 1, data, $3, $3
+			FROM data_submission_staging 
+			WHERE submission_id = $4 AND validation_status = $5`
+
+		result, err := tx.Exec(query, datasetID, startIndex, userID, submissionID, models.ValidationStatusValid)
+		if err != nil {
+			return nil, err
+		}
+		inserted, _ := result.RowsAffected()
+		report.InsertedRows = int(inserted)
 	}
 
 	// Update dataset row count
@@ -336,41 +544,381 @@ func (r *DataSubmissionRepository) ApplyStagingDataToDataset(submissionID uuid.U
 		SET row_count = (SELECT COUNT(*) FROM dataset_data WHERE dataset_id = $1),
 		    updated_at = NOW()
 		WHERE id = $1`, datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// keyedRow is a staged or stored row with the text of its key fields; a NULL key value leaves
+// the row unkeyed
+type keyedRow struct {
+	rowIndex int
+	key      []sql.NullString
+	data     []byte // staged rows only
+}
+
+// upsertKey joins the row's key values, or returns false when any of them is missing or empty
+func (row keyedRow) upsertKey() (string, bool) {
+	values := make([]string, len(row.key))
+	for i, value := range row.key {
+		if !value.Valid || value.String == "" {
+			return "", false
+		}
+		values[i] = value.String
+	}
+	encoded, _ := json.Marshal(values)
+	return string(encoded), true
+}
+
+// rowUpdate overwrites the data of a stored row
+type rowUpdate struct {
+	rowIndex int
+	data     []byte
+}
+
+// planUpsert decides how staged rows, in staging order, merge into stored rows: a staged row
+// whose key matches stored rows updates all of them, and the rest are inserted in staging order.
+// When several staged rows share a key the last one wins and the others count as duplicates.
+// Rows without a complete key are always inserted.
+func planUpsert(staged, stored []keyedRow) (updates []rowUpdate, inserts [][]byte, duplicates int) {
+	storedByKey := make(map[string][]int)
+	for _, row := range stored {
+		if key, ok := row.upsertKey(); ok {
+			storedByKey[key] = append(storedByKey[key], row.rowIndex)
+		}
+	}
+
+	last := make(map[string]int)
+	for i, row := range staged {
+		if key, ok := row.upsertKey(); ok {
+			if _, seen := last[key]; seen {
+				duplicates++
+			}
+			last[key] = i
+		}
+	}
+
+	for i, row := range staged {
+		key, ok := row.upsertKey()
+		if !ok {
+			inserts = append(inserts, row.data)
+			continue
+		}
+		if last[key] != i {
+			continue
+		}
+		rowIndexes, found := storedByKey[key]
+		if !found {
+			inserts = append(inserts, row.data)
+			continue
+		}
+		for _, rowIndex := range rowIndexes {
+			updates = append(updates, rowUpdate{rowIndex: rowIndex, data: row.data})
+		}
+	}
+	return updates, inserts, duplicates
+}
+
+// upsertStagingData merges a submission's valid staged rows into the dataset by their keyFields
+// values, compared as JSON text, and records the outcome in report
+func upsertStagingData(tx *sqlx.Tx, report *models.ApplyReport, submissionID, datasetID, userID uuid.UUID, keyFields []string) error {
+	keyColumns := make([]string, len(keyFields))
+	args := []interface{}{submissionID, models.ValidationStatusValid}
+	for i, field := range keyFields {
+		keyColumns[i] = fmt.Sprintf("data->>$%d", len(args)+1)
+		args = append(args, field)
+	}
+	keys := strings.Join(keyColumns, ", ")
+
+	staged, err := queryKeyedRows(tx, len(keyFields), true, fmt.Sprintf(`
+		SELECT row_index, data, %s
+		FROM data_submission_staging
+		WHERE submission_id = $1 AND validation_status = $2
+		ORDER BY row_index`, keys), args...)
+	if err != nil {
+		return fmt.Errorf("failed to load staged rows: %w", err)
+	}
+
+	// Only stored rows sharing the first key value with a staged row can match
+	stored, err := queryKeyedRows(tx, len(keyFields), false, fmt.Sprintf(`
+		SELECT row_index, %s
+		FROM dataset_data
+		WHERE dataset_id = $%d AND %s IN (
+			SELECT %s FROM data_submission_staging
+			WHERE submission_id = $1 AND validation_status = $2
+		)
+		ORDER BY row_index`, keys, len(args)+1, keyColumns[0], keyColumns[0]), append(args, datasetID)...)
+	if err != nil {
+		return fmt.Errorf("failed to look up stored rows by key: %w", err)
+	}
+
+	updates, inserts, duplicates := planUpsert(staged, stored)
+	report.DuplicateKeys = duplicates
+
+	for _, update := range updates {
+		_, err := tx.Exec(`
+			UPDATE dataset_data SET data = $1, version = version + 1, updated_by = $2, updated_at = NOW()
+			WHERE dataset_id = $3 AND row_index = $4`,
+			update.data, userID, datasetID, update.rowIndex)
+		if err != nil {
+			return fmt.Errorf("failed to update row %d: %w", update.rowIndex, err)
+		}
+		report.UpdatedRows++
+	}
+
+	startIndex, err := nextDatasetRowIndex(tx, datasetID)
 	if err != nil {
 		return err
 	}
+	for i, data := range inserts {
+		_, err := tx.Exec(`
+			INSERT INTO dataset_data (dataset_id, row_index, data, created_by, updated_by)
+			VALUES ($1, $2, $3, $4, $4)`,
+			datasetID, startIndex+i, data, userID)
+		if err != nil {
+			return fmt.Errorf("failed to insert row %d: %w", startIndex+i, err)
+		}
+		report.InsertedRows++
+	}
+	return nil
+}
 
-	return tx.Commit()
+// queryKeyedRows reads rows of a row index, the data when withData is set, and keyCount key values
+func queryKeyedRows(tx *sqlx.Tx, keyCount int, withData bool, query string, args ...interface{}) ([]keyedRow, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keyed []keyedRow
+	for rows.Next() {
+		row := keyedRow{key: make([]sql.NullString, keyCount)}
+		dest := []interface{}{&row.rowIndex}
+		if withData {
+			dest = append(dest, &row.data)
+		}
+		for i := range row.key {
+			dest = append(dest, &row.key[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		keyed = append(keyed, row)
+	}
+	return keyed, rows.Err()
+}
+
+// PreviewApplyPlacement reports where a submission's valid staged rows would land if applied now,
+// using the same placement as ApplyStagingDataToDataset
+func (r *DataSubmissionRepository) PreviewApplyPlacement(submissionID, datasetID uuid.UUID, mode string) (*models.ApplyPlacementPreview, error) {
+	return previewApplyPlacement(r.db, submissionID, datasetID, mode)
+}
+
+func previewApplyPlacement(db rowGetter, submissionID, datasetID uuid.UUID, mode string) (*models.ApplyPlacementPreview, error) {
+	startIndex, err := applyStartIndex(db, datasetID, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	var validRows int
+	query := `
+		SELECT COUNT(*)
+		FROM data_submission_staging 
+		WHERE submission_id = $1 AND validation_status = $2`
+
+	if err := db.Get(&validRows, query, submissionID, models.ValidationStatusValid); err != nil {
+		return nil, fmt.Errorf("failed to count valid staging rows: %w", err)
+	}
+
+	preview := &models.ApplyPlacementPreview{
+		StartingRowIndex: startIndex,
+		ValidRows:        validRows,
+	}
+	if validRows > 0 {
+		first := startIndex
+		last := startIndex + validRows - 1
+		preview.FirstRowIndex, preview.LastRowIndex = &first, &last
+	}
+	return preview, nil
+}
+
+// applyStartIndex returns the row index a submission's first applied row gets: 0 when it replaces
+// the dataset's rows, otherwise the index after the dataset's last row
+func applyStartIndex(db rowGetter, datasetID uuid.UUID, mode string) (int, error) {
+	if mode == models.SubmissionModeReplace {
+		return 0, nil
+	}
+	return nextDatasetRowIndex(db, datasetID)
+}
+
+// nextDatasetRowIndex returns the row index after the dataset's last row, or 0 when it has no rows
+func nextDatasetRowIndex(db rowGetter, datasetID uuid.UUID) (int, error) {
+	var maxRowIndex sql.NullInt64
+	err := db.Get(&maxRowIndex, "SELECT MAX(row_index) FROM dataset_data WHERE dataset_id = $1", datasetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get max row index: %w", err)
+	}
+
+	if !maxRowIndex.Valid {
+		return 0, nil
+	}
+	return int(maxRowIndex.Int64) + 1, nil
 }
 
 // Business Rules methods
 
 // CreateBusinessRule creates a new business rule for a dataset
 func (r *DataSubmissionRepository) CreateBusinessRule(rule *models.DatasetBusinessRule) error {
+	return insertBusinessRule(r.db, rule)
+}
+
+func insertBusinessRule(db sqlx.Execer, rule *models.DatasetBusinessRule) error {
 	query := `
 		INSERT INTO dataset_business_rules (
 			id, dataset_id, rule_name, rule_type, rule_config, error_message,
-			is_active, priority, created_by, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+			is_active, priority, stop_on_fail, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
 
-	_, err := r.db.Exec(query,
+	_, err := db.Exec(query,
 		rule.ID, rule.DatasetID, rule.RuleName, rule.RuleType, rule.RuleConfig,
-		rule.ErrorMessage, rule.IsActive, rule.Priority, rule.CreatedBy,
+		rule.ErrorMessage, rule.IsActive, rule.Priority, rule.StopOnFail, rule.CreatedBy,
 		rule.CreatedAt, rule.UpdatedAt,
 	)
 
 	return err
 }
 
+// CreateBusinessRules creates several business rules in one transaction, so either all or none are stored
+func (r *DataSubmissionRepository) CreateBusinessRules(rules []*models.DatasetBusinessRule) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertBusinessRules(tx, rules); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertBusinessRules(db sqlx.Execer, rules []*models.DatasetBusinessRule) error {
+	for _, rule := range rules {
+		if err := insertBusinessRule(db, rule); err != nil {
+			return fmt.Errorf("failed to create business rule %s: %w", rule.RuleName, err)
+		}
+	}
+	return nil
+}
+
+// CountActiveBusinessRules returns how many active business rules a dataset has
+func (r *DataSubmissionRepository) CountActiveBusinessRules(datasetID uuid.UUID) (int, error) {
+	return countActiveBusinessRules(r.db, datasetID)
+}
+
+func countActiveBusinessRules(db sqlx.Queryer, datasetID uuid.UUID) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM dataset_business_rules 
+		WHERE dataset_id = $1 AND is_active = true`
+
+	err := sqlx.Get(db, &count, query, datasetID)
+	return count, err
+}
+
+// BusinessRuleStore is the business rule storage available while a dataset's rules are locked
+type BusinessRuleStore interface {
+	CountActiveBusinessRules(datasetID uuid.UUID) (int, error)
+	CreateBusinessRule(rule *models.DatasetBusinessRule) error
+	CreateBusinessRules(rules []*models.DatasetBusinessRule) error
+	SetBusinessRuleActive(id uuid.UUID, active bool) error
+}
+
+// lockedBusinessRules is a BusinessRuleStore working inside the locking transaction
+type lockedBusinessRules struct {
+	tx *sqlx.Tx
+}
+
+func (l *lockedBusinessRules) CountActiveBusinessRules(datasetID uuid.UUID) (int, error) {
+	return countActiveBusinessRules(l.tx, datasetID)
+}
+
+func (l *lockedBusinessRules) CreateBusinessRule(rule *models.DatasetBusinessRule) error {
+	return insertBusinessRule(l.tx, rule)
+}
+
+func (l *lockedBusinessRules) CreateBusinessRules(rules []*models.DatasetBusinessRule) error {
+	return insertBusinessRules(l.tx, rules)
+}
+
+func (l *lockedBusinessRules) SetBusinessRuleActive(id uuid.UUID, active bool) error {
+	return setBusinessRuleActive(l.tx, id, active)
+}
+
+// WithBusinessRulesLocked runs fn in one transaction that holds a lock on the dataset's business
+// rules, so counting the active rules and then changing them can't race with another change. The
+// transaction commits when fn succeeds.
+func (r *DataSubmissionRepository) WithBusinessRulesLocked(datasetID uuid.UUID, fn func(rules BusinessRuleStore) error) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext($1))`, "business_rules:"+datasetID.String()); err != nil {
+		return fmt.Errorf("failed to lock business rules: %w", err)
+	}
+	if err := fn(&lockedBusinessRules{tx: tx}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// businessRuleColumns lists dataset_business_rules columns in DatasetBusinessRule field order
+const businessRuleColumns = `id, dataset_id, rule_name, rule_type, rule_config, error_message,
+		       is_active, priority, stop_on_fail, created_by, created_at, updated_at`
+
 // GetBusinessRules retrieves active business rules for a dataset
 func (r *DataSubmissionRepository) GetBusinessRules(datasetID uuid.UUID) ([]*models.DatasetBusinessRule, error) {
+	return r.listBusinessRules(datasetID, false)
+}
+
+// GetAllBusinessRules retrieves a dataset's business rules, including inactive ones
+func (r *DataSubmissionRepository) GetAllBusinessRules(datasetID uuid.UUID) ([]*models.DatasetBusinessRule, error) {
+	return r.listBusinessRules(datasetID, true)
+}
+
+// GetBusinessRule retrieves a business rule by ID, active or not
+func (r *DataSubmissionRepository) GetBusinessRule(id uuid.UUID) (*models.DatasetBusinessRule, error) {
+	var rule models.DatasetBusinessRule
+	query := `SELECT ` + businessRuleColumns + ` FROM dataset_business_rules WHERE id = $1`
+
+	err := r.db.Get(&rule, query, id)
+	if err == sql.ErrNoRows {
+		return nil, ErrBusinessRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// listBusinessRules retrieves a dataset's business rules in evaluation order
+func (r *DataSubmissionRepository) listBusinessRules(datasetID uuid.UUID, includeInactive bool) ([]*models.DatasetBusinessRule, error) {
 	var rules []*models.DatasetBusinessRule
 	query := `
-		SELECT * FROM dataset_business_rules 
-		WHERE dataset_id = $1 AND is_active = true 
+		SELECT ` + businessRuleColumns + `
+		FROM dataset_business_rules 
+		WHERE dataset_id = $1 AND (is_active = true OR $2)
 		ORDER BY priority ASC, created_at ASC`
 
-	rows, err := r.db.Query(query, datasetID)
+	rows, err := r.db.Query(query, datasetID, includeInactive)
 	if err != nil {
 		return nil, err
 	}
@@ -381,7 +929,7 @@ func (r *DataSubmissionRepository) GetBusinessRules(datasetID uuid.UUID) ([]*mod
 		err := rows.Scan(
 			&rule.ID, &rule.DatasetID, &rule.RuleName, &rule.RuleType,
 			&rule.RuleConfig, &rule.ErrorMessage, &rule.IsActive, &rule.Priority,
-			&rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt,
+			&rule.StopOnFail, &rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -397,17 +945,39 @@ func (r *DataSubmissionRepository) UpdateBusinessRule(rule *models.DatasetBusine
 	query := `
 		UPDATE dataset_business_rules 
 		SET rule_name = $1, rule_type = $2, rule_config = $3, error_message = $4,
-		    is_active = $5, priority = $6, updated_at = $7
-		WHERE id = $8`
+		    is_active = $5, priority = $6, stop_on_fail = $7, updated_at = $8
+		WHERE id = $9`
 
 	_, err := r.db.Exec(query,
 		rule.RuleName, rule.RuleType, rule.RuleConfig, rule.ErrorMessage,
-		rule.IsActive, rule.Priority, time.Now(), rule.ID,
+		rule.IsActive, rule.Priority, rule.StopOnFail, time.Now(), rule.ID,
 	)
 
 	return err
 }
 
+// SetBusinessRuleActive activates or deactivates a business rule
+func (r *DataSubmissionRepository) SetBusinessRuleActive(id uuid.UUID, active bool) error {
+	return setBusinessRuleActive(r.db, id, active)
+}
+
+func setBusinessRuleActive(db sqlx.Execer, id uuid.UUID, active bool) error {
+	query := `UPDATE dataset_business_rules SET is_active = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := db.Exec(query, active, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrBusinessRuleNotFound
+	}
+	return nil
+}
+
 // DeleteBusinessRule deletes a business rule
 func (r *DataSubmissionRepository) DeleteBusinessRule(id uuid.UUID) error {
 	query := `DELETE FROM dataset_business_rules WHERE id = $1`
@@ -417,19 +987,12 @@ func (r *DataSubmissionRepository) DeleteBusinessRule(id uuid.UUID) error {
 
 // CheckDatasetAccess verifies if user has access to the dataset
 func (r *DataSubmissionRepository) CheckDatasetAccess(datasetID uuid.UUID, userID uuid.UUID) (bool, error) {
-	var count int
-	query := `
-		SELECT COUNT(*) FROM datasets d
-		JOIN projects p ON d.project_id = p.id
-		LEFT JOIN project_collaborators pc ON p.id = pc.project_id
-		WHERE d.id = $1 AND (p.created_by = $2 OR pc.user_id = $2)`
-
-	err := r.db.Get(&count, query, datasetID, userID)
-	if err != nil {
-		return false, err
-	}
+	return checkDatasetAccess(r.db, datasetID, userID)
+}
 
-	return count > 0, nil
+// CheckDatasetEditAccess checks if a user can change a dataset's existing data
+func (r *DataSubmissionRepository) CheckDatasetEditAccess(datasetID uuid.UUID, userID uuid.UUID) (bool, error) {
+	return checkDatasetEditAccess(r.db, datasetID, userID)
 }
 
 // IsUserAdmin checks if user has admin privileges
@@ -445,3 +1008,115 @@ func (r *DataSubmissionRepository) IsUserAdmin(userID uuid.UUID) (bool, error) {
 	// Assuming 'admin' or 'super_admin' roles have admin privileges
 	return role == "admin" || role == "super_admin", nil
 }
+
+// CreateSubmissionComment adds a comment to a submission's review thread
+func (r *DataSubmissionRepository) CreateSubmissionComment(comment *models.SubmissionComment) error {
+	query := `
+		INSERT INTO submission_comments (id, submission_id, user_id, body, created_at)
+		VALUES (:id, :submission_id, :user_id, :body, :created_at)`
+
+	if _, err := r.db.NamedExec(query, comment); err != nil {
+		return fmt.Errorf("failed to create submission comment: %w", err)
+	}
+	return nil
+}
+
+// ListSubmissionComments returns a submission's review thread, oldest first
+func (r *DataSubmissionRepository) ListSubmissionComments(submissionID uuid.UUID) ([]models.SubmissionCommentWithAuthor, error) {
+	query := `
+		SELECT sc.id, sc.submission_id, sc.user_id, sc.body, sc.created_at, u.name AS author_name
+		FROM submission_comments sc
+		JOIN users u ON sc.user_id = u.id
+		WHERE sc.submission_id = $1
+		ORDER BY sc.created_at, sc.id`
+
+	comments := []models.SubmissionCommentWithAuthor{}
+	if err := r.db.Select(&comments, query, submissionID); err != nil {
+		return nil, fmt.Errorf("failed to list submission comments: %w", err)
+	}
+	return comments, nil
+}
+
+// rowSelecter is the part of *sqlx.DB used by aggregate reports
+type rowSelecter interface {
+	rowGetter
+	Select(dest interface{}, query string, args ...interface{}) error
+}
+
+// submissionStatuses are the statuses reported by the submission dashboard, even with no submissions
+var submissionStatuses = []string{
+	models.DataSubmissionStatusPending,
+	models.DataSubmissionStatusUnderReview,
+	models.DataSubmissionStatusApproved,
+	models.DataSubmissionStatusRejected,
+	models.DataSubmissionStatusApplied,
+	models.DataSubmissionStatusExpired,
+}
+
+// GetSubmissionDashboard summarizes submissions by status, the age of the oldest one awaiting review
+// and the topDatasets datasets with the most submissions awaiting review
+func (r *DataSubmissionRepository) GetSubmissionDashboard(topDatasets int) (*models.SubmissionDashboard, error) {
+	return submissionDashboard(r.db, topDatasets)
+}
+
+// oldestPendingSubmission is the oldest submission awaiting review and its age
+type oldestPendingSubmission struct {
+	SubmittedAt *time.Time `db:"submitted_at"`
+	AgeSeconds  *int64     `db:"age_seconds"`
+}
+
+// submissionDashboard builds the dashboard with aggregate queries, never loading submission rows
+func submissionDashboard(db rowSelecter, topDatasets int) (*models.SubmissionDashboard, error) {
+	var counts []models.SubmissionStatusCount
+	if err := db.Select(&counts, `SELECT status, COUNT(*) AS count FROM data_submissions GROUP BY status`); err != nil {
+		return nil, fmt.Errorf("failed to count submissions by status: %w", err)
+	}
+
+	dashboard := &models.SubmissionDashboard{
+		CountsByStatus:     make(map[string]int, len(submissionStatuses)),
+		TopPendingDatasets: []models.DatasetPendingSubmissions{},
+	}
+	for _, status := range submissionStatuses {
+		dashboard.CountsByStatus[status] = 0
+	}
+	for _, count := range counts {
+		dashboard.CountsByStatus[count.Status] = count.Count
+		dashboard.Total += count.Count
+	}
+	dashboard.Pending = dashboard.CountsByStatus[models.DataSubmissionStatusPending] +
+		dashboard.CountsByStatus[models.DataSubmissionStatusUnderReview]
+	if dashboard.Pending == 0 {
+		return dashboard, nil
+	}
+
+	// submitted_at has no time zone, so the age is measured against the database's local time
+	var oldest oldestPendingSubmission
+	oldestQuery := `
+		SELECT MIN(submitted_at) AS submitted_at,
+			EXTRACT(EPOCH FROM LOCALTIMESTAMP - MIN(submitted_at))::bigint AS age_seconds
+		FROM data_submissions
+		WHERE status IN ($1, $2)`
+	if err := db.Get(&oldest, oldestQuery, models.DataSubmissionStatusPending, models.DataSubmissionStatusUnderReview); err != nil {
+		return nil, fmt.Errorf("failed to get oldest pending submission: %w", err)
+	}
+	dashboard.OldestPendingSubmittedAt = oldest.SubmittedAt
+	dashboard.OldestPendingAgeSeconds = oldest.AgeSeconds
+
+	topQuery := `
+		SELECT d.id AS dataset_id, d.name AS dataset_name, p.name AS project_name,
+			COUNT(*) AS pending_submissions,
+			COALESCE(SUM(ds.row_count), 0) AS pending_rows,
+			MIN(ds.submitted_at) AS oldest_submitted_at
+		FROM data_submissions ds
+		JOIN datasets d ON ds.dataset_id = d.id
+		JOIN projects p ON d.project_id = p.id
+		WHERE ds.status IN ($1, $2)
+		GROUP BY d.id, d.name, p.name
+		ORDER BY pending_submissions DESC, pending_rows DESC, d.name
+		LIMIT $3`
+	if err := db.Select(&dashboard.TopPendingDatasets, topQuery, models.DataSubmissionStatusPending, models.DataSubmissionStatusUnderReview, topDatasets); err != nil {
+		return nil, fmt.Errorf("failed to get datasets with pending submissions: %w", err)
+	}
+
+	return dashboard, nil
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStagingRow holds the column values of one data_submission_staging row
+type fakeStagingRow struct {
+	rowIndex         int
+	data             []byte
+	validationErrors []byte
+	scanErr          error
+}
+
+type fakeStagingRows struct {
+	rows    []fakeStagingRow
+	current int
+}
+
+func (f *fakeStagingRows) Next() bool {
+	f.current++
+	return f.current <= len(f.rows)
+}
+
+func (f *fakeStagingRows) Scan(dest ...interface{}) error {
+	row := f.rows[f.current-1]
+	if row.scanErr != nil {
+		return row.scanErr
+	}
+	*dest[0].(*uuid.UUID) = uuid.New()
+	*dest[1].(*uuid.UUID) = uuid.New()
+	*dest[2].(*int) = row.rowIndex
+	*dest[3].(*[]byte) = row.data
+	*dest[4].(*string) = "valid"
+	*dest[5].(*[]byte) = row.validationErrors
+	*dest[6].(*time.Time) = time.Now()
+	return nil
+}
+
+func (f *fakeStagingRows) Err() error {
+	return nil
+}
+
+func TestScanStagingRows_SkipsCorruptRows(t *testing.T) {
+	rows := &fakeStagingRows{rows: []fakeStagingRow{
+		{rowIndex: 0, data: []byte(`{"name":"Ann"}`)},
+		{rowIndex: 1, data: []byte(`{"name":`)},
+		{rowIndex: 2, data: []byte(`{"name":"Bob"}`), validationErrors: []byte(`[]`)},
+		{rowIndex: 3, scanErr: errors.New("bad column")},
+		{rowIndex: 4, data: []byte(`{"name":"Cy"}`), validationErrors: []byte(`[{`)},
+	}}
+
+	staging, skipped, err := scanStagingRows(uuid.New(), rows)
+	require.NoError(t, err)
+	assert.Equal(t, 3, skipped)
+	require.Len(t, staging, 2)
+
+	assert.Equal(t, 0, staging[0].RowIndex)
+	assert.Equal(t, json.RawMessage(`{"name":"Ann"}`), staging[0].Data)
+	assert.Nil(t, staging[0].ValidationErrors)
+
+	assert.Equal(t, 2, staging[1].RowIndex)
+	require.NotNil(t, staging[1].ValidationErrors)
+	assert.Equal(t, json.RawMessage(`[]`), *staging[1].ValidationErrors)
+}
+
+// fakePlacementDB answers the row index queries used to place applied rows
+type fakePlacementDB struct {
+	datasetRows  []int        // row indexes stored in dataset_data
+	stagingValid map[int]bool // staging row index -> valid; missing indexes were removed
+}
+
+func (f *fakePlacementDB) Get(dest interface{}, query string, args ...interface{}) error {
+	if strings.Contains(query, "FROM dataset_data") {
+		max := dest.(*sql.NullInt64)
+		*max = sql.NullInt64{}
+		for _, rowIndex := range f.datasetRows {
+			if !max.Valid || int64(rowIndex) > max.Int64 {
+				*max = sql.NullInt64{Int64: int64(rowIndex), Valid: true}
+			}
+		}
+		return nil
+	}
+
+	count := 0
+	for _, isValid := range f.stagingValid {
+		if isValid {
+			count++
+		}
+	}
+	*dest.(*int) = count
+	return nil
+}
+
+// apply mirrors ApplyStagingDataToDataset: valid rows, in staging order, are numbered
+// contiguously from the next row index, or from 0 after a replace removed the existing rows
+func (f *fakePlacementDB) apply(t *testing.T, datasetID uuid.UUID, mode string) []int {
+	if mode == models.SubmissionModeReplace {
+		f.datasetRows = nil
+	}
+	startIndex, err := applyStartIndex(f, datasetID, mode)
+	require.NoError(t, err)
+
+	stagingIndexes := make([]int, 0, len(f.stagingValid))
+	for rowIndex := range f.stagingValid {
+		stagingIndexes = append(stagingIndexes, rowIndex)
+	}
+	sort.Ints(stagingIndexes)
+
+	var placed []int
+	for _, rowIndex := range stagingIndexes {
+		if f.stagingValid[rowIndex] {
+			placed = append(placed, startIndex+len(placed))
+		}
+	}
+	f.datasetRows = append(f.datasetRows, placed...)
+	return placed
+}
+
+func TestPreviewApplyPlacement_MatchesApply(t *testing.T) {
+	datasetID := uuid.New()
+
+	t.Run("empty dataset", func(t *testing.T) {
+		db := &fakePlacementDB{stagingValid: map[int]bool{0: true, 1: true}}
+
+		preview, err := previewApplyPlacement(db, uuid.New(), datasetID, models.SubmissionModeAppend)
+		require.NoError(t, err)
+		assert.Equal(t, 0, preview.StartingRowIndex)
+
+		placed := db.apply(t, datasetID, models.SubmissionModeAppend)
+		assert.Equal(t, []int{0, 1}, placed)
+		assert.Equal(t, placed[0], *preview.FirstRowIndex)
+		assert.Equal(t, placed[len(placed)-1], *preview.LastRowIndex)
+	})
+
+	t.Run("appends after existing rows without gaps for invalid rows", func(t *testing.T) {
+		db := &fakePlacementDB{
+			datasetRows:  []int{0, 1, 2, 3, 4},
+			stagingValid: map[int]bool{0: false, 1: true, 2: false, 3: true},
+		}
+
+		preview, err := previewApplyPlacement(db, uuid.New(), datasetID, models.SubmissionModeAppend)
+		require.NoError(t, err)
+		assert.Equal(t, 5, preview.StartingRowIndex)
+		assert.Equal(t, 2, preview.ValidRows)
+
+		placed := db.apply(t, datasetID, models.SubmissionModeAppend)
+		assert.Equal(t, []int{5, 6}, placed)
+		assert.Equal(t, placed[0], *preview.FirstRowIndex)
+		assert.Equal(t, placed[len(placed)-1], *preview.LastRowIndex)
+	})
+
+	t.Run("staging rows removed during review", func(t *testing.T) {
+		// Rows 1, 2 and 5 were deleted from staging, leaving gaps in the staging indexes
+		db := &fakePlacementDB{
+			datasetRows:  []int{0, 1, 2},
+			stagingValid: map[int]bool{0: true, 3: true, 4: true, 6: false, 7: true},
+		}
+
+		preview, err := previewApplyPlacement(db, uuid.New(), datasetID, models.SubmissionModeAppend)
+		require.NoError(t, err)
+
+		placed := db.apply(t, datasetID, models.SubmissionModeAppend)
+		assert.Equal(t, []int{3, 4, 5, 6}, placed)
+		assert.Equal(t, placed[0], *preview.FirstRowIndex)
+		assert.Equal(t, placed[len(placed)-1], *preview.LastRowIndex)
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, db.datasetRows, "applied rows continue the dataset without gaps")
+	})
+
+	t.Run("replace starts over at zero", func(t *testing.T) {
+		db := &fakePlacementDB{
+			datasetRows:  []int{0, 1, 2, 3},
+			stagingValid: map[int]bool{0: true, 1: false, 2: true},
+		}
+
+		preview, err := previewApplyPlacement(db, uuid.New(), datasetID, models.SubmissionModeReplace)
+		require.NoError(t, err)
+		assert.Equal(t, 0, preview.StartingRowIndex)
+
+		placed := db.apply(t, datasetID, models.SubmissionModeReplace)
+		assert.Equal(t, []int{0, 1}, placed)
+		assert.Equal(t, placed[len(placed)-1], *preview.LastRowIndex)
+		assert.Equal(t, []int{0, 1}, db.datasetRows, "only the submission's rows remain")
+	})
+
+	t.Run("no valid rows", func(t *testing.T) {
+		db := &fakePlacementDB{datasetRows: []int{0}, stagingValid: map[int]bool{0: false}}
+
+		preview, err := previewApplyPlacement(db, uuid.New(), datasetID, models.SubmissionModeAppend)
+		require.NoError(t, err)
+		assert.Equal(t, 1, preview.StartingRowIndex)
+		assert.Zero(t, preview.ValidRows)
+		assert.Nil(t, preview.FirstRowIndex)
+		assert.Nil(t, preview.LastRowIndex)
+	})
+}
+
+// keyed builds a row keyed on the given values; an empty value is NULL
+func keyed(rowIndex int, data string, key ...string) keyedRow {
+	row := keyedRow{rowIndex: rowIndex, data: []byte(data), key: make([]sql.NullString, len(key))}
+	for i, value := range key {
+		row.key[i] = sql.NullString{String: value, Valid: value != ""}
+	}
+	return row
+}
+
+func TestPlanUpsert(t *testing.T) {
+	stored := []keyedRow{
+		keyed(0, "", "a", "1"),
+		keyed(1, "", "b", "1"),
+		keyed(2, "", "b", "1"), // stored duplicate of row 1
+		keyed(3, "", "c", "1"),
+	}
+	staged := []keyedRow{
+		keyed(0, `{"id":"a","v":1}`, "a", "1"),
+		keyed(1, `{"id":"a","v":2}`, "a", "2"), // same first key, different second: new row
+		keyed(2, `{"id":"b","v":1}`, "b", "1"),
+		keyed(3, `{"id":"d","v":1}`, "d", "1"),
+		keyed(4, `{"id":"","v":1}`, "", "1"),   // incomplete key is always inserted
+		keyed(5, `{"id":"a","v":3}`, "a", "1"), // later duplicate of staged row 0 wins
+	}
+
+	updates, inserts, duplicates := planUpsert(staged, stored)
+
+	assert.Equal(t, 1, duplicates)
+	assert.Equal(t, []rowUpdate{
+		{rowIndex: 1, data: []byte(`{"id":"b","v":1}`)},
+		{rowIndex: 2, data: []byte(`{"id":"b","v":1}`)},
+		{rowIndex: 0, data: []byte(`{"id":"a","v":3}`)},
+	}, updates)
+	assert.Equal(t, [][]byte{
+		[]byte(`{"id":"a","v":2}`),
+		[]byte(`{"id":"d","v":1}`),
+		[]byte(`{"id":"","v":1}`),
+	}, inserts, "inserts keep staging order")
+}
+
+// fakeDashboardDB answers the submission dashboard's aggregate queries
+type fakeDashboardDB struct {
+	counts  []models.SubmissionStatusCount
+	oldest  time.Time
+	top     []models.DatasetPendingSubmissions
+	queries []string
+	topArgs []interface{}
+}
+
+func (f *fakeDashboardDB) Get(dest interface{}, query string, args ...interface{}) error {
+	f.queries = append(f.queries, query)
+	oldest := dest.(*oldestPendingSubmission)
+	age := int64(3600)
+	oldest.SubmittedAt, oldest.AgeSeconds = &f.oldest, &age
+	return nil
+}
+
+func (f *fakeDashboardDB) Select(dest interface{}, query string, args ...interface{}) error {
+	f.queries = append(f.queries, query)
+	switch d := dest.(type) {
+	case *[]models.SubmissionStatusCount:
+		*d = f.counts
+	case *[]models.DatasetPendingSubmissions:
+		f.topArgs = args
+		*d = f.top
+	}
+	return nil
+}
+
+func TestSubmissionDashboard(t *testing.T) {
+	oldest := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	db := &fakeDashboardDB{
+		counts: []models.SubmissionStatusCount{
+			{Status: models.DataSubmissionStatusPending, Count: 3},
+			{Status: models.DataSubmissionStatusUnderReview, Count: 2},
+			{Status: models.DataSubmissionStatusApproved, Count: 7},
+		},
+		oldest: oldest,
+		top:    []models.DatasetPendingSubmissions{{DatasetName: "sales", PendingSubmissions: 4, PendingRows: 120}},
+	}
+
+	dashboard, err := submissionDashboard(db, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 12, dashboard.Total)
+	assert.Equal(t, 5, dashboard.Pending)
+	assert.Equal(t, 0, dashboard.CountsByStatus[models.DataSubmissionStatusRejected])
+	assert.Len(t, dashboard.CountsByStatus, 6)
+	require.NotNil(t, dashboard.OldestPendingSubmittedAt)
+	assert.Equal(t, oldest, *dashboard.OldestPendingSubmittedAt)
+	assert.Equal(t, int64(3600), *dashboard.OldestPendingAgeSeconds)
+	assert.Equal(t, db.top, dashboard.TopPendingDatasets)
+	assert.Equal(t, 5, db.topArgs[2])
+
+	// Every figure comes from an aggregate query
+	require.Len(t, db.queries, 3)
+	assert.Contains(t, db.queries[0], "GROUP BY status")
+	assert.Contains(t, db.queries[1], "MIN(submitted_at)")
+	assert.Contains(t, db.queries[2], "GROUP BY d.id")
+}
+
+func TestSubmissionDashboard_NothingPending(t *testing.T) {
+	db := &fakeDashboardDB{counts: []models.SubmissionStatusCount{{Status: models.DataSubmissionStatusApplied, Count: 2}}}
+
+	dashboard, err := submissionDashboard(db, 5)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dashboard.Total)
+	assert.Zero(t, dashboard.Pending)
+	assert.Nil(t, dashboard.OldestPendingSubmittedAt)
+	assert.Empty(t, dashboard.TopPendingDatasets)
+	assert.Len(t, db.queries, 1)
+}
@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,15 +24,104 @@ func NewDatasetRepository(db *sqlx.DB) *DatasetRepository {
 	return &DatasetRepository{db: db}
 }
 
-// Create creates a new dataset
-func (r *DatasetRepository) Create(dataset *models.Dataset) error {
-	query := `
+// Fields dataset listings can be sorted by
+const (
+	DatasetSortCreatedAt = "created_at"
+	DatasetSortUpdatedAt = "updated_at"
+	DatasetSortName      = "name"
+	DatasetSortRowCount  = "row_count"
+)
+
+// datasetSortColumns is the allowlist of sort fields and the column each orders by; only these
+// columns are ever written into a listing's ORDER BY
+var datasetSortColumns = map[string]string{
+	DatasetSortCreatedAt: "created_at",
+	DatasetSortUpdatedAt: "updated_at",
+	DatasetSortName:      "name",
+	DatasetSortRowCount:  "row_count",
+}
+
+// ErrInvalidDatasetSort is returned for a sort field or order outside the allowlist
+var ErrInvalidDatasetSort = errors.New("invalid dataset sort")
+
+// DatasetSort orders a dataset listing
+type DatasetSort struct {
+	Field      string
+	Descending bool
+}
+
+// DefaultDatasetSort lists the newest datasets first
+var DefaultDatasetSort = DatasetSort{Field: DatasetSortCreatedAt, Descending: true}
+
+// ParseDatasetSort validates a sort field and order ("asc" or "desc") against the allowlist. An
+// empty field keeps the default sort; an empty order sorts names ascending and other fields
+// descending.
+func ParseDatasetSort(field, order string) (DatasetSort, error) {
+	field = strings.ToLower(strings.TrimSpace(field))
+	order = strings.ToLower(strings.TrimSpace(order))
+	if field == "" {
+		field = DefaultDatasetSort.Field
+	}
+	if _, ok := datasetSortColumns[field]; !ok {
+		return DatasetSort{}, fmt.Errorf("%w: unknown sort field '%s'", ErrInvalidDatasetSort, field)
+	}
+
+	switch order {
+	case "":
+		return DatasetSort{Field: field, Descending: field != DatasetSortName}, nil
+	case "asc":
+		return DatasetSort{Field: field}, nil
+	case "desc":
+		return DatasetSort{Field: field, Descending: true}, nil
+	default:
+		return DatasetSort{}, fmt.Errorf("%w: order must be asc or desc", ErrInvalidDatasetSort)
+	}
+}
+
+// DatasetSortFields lists the accepted sort fields
+func DatasetSortFields() []string {
+	fields := make([]string, 0, len(datasetSortColumns))
+	for field := range datasetSortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// orderBy returns the ORDER BY clause of the sort for datasets aliased as alias ("" for none),
+// breaking ties by ID so pages are stable. Names sort case-insensitively and unknown fields fall
+// back to the default sort.
+func (s DatasetSort) orderBy(alias string) string {
+	column, ok := datasetSortColumns[s.Field]
+	if !ok {
+		column, s = datasetSortColumns[DefaultDatasetSort.Field], DefaultDatasetSort
+	}
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+	column = prefix + column
+	if s.Field == DatasetSortName {
+		column = "LOWER(" + column + ")"
+	}
+
+	direction := "ASC"
+	if s.Descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %sid", column, direction, prefix)
+}
+
+// insertDatasetQuery inserts a dataset from its named fields
+const insertDatasetQuery = `
 		INSERT INTO datasets (id, project_id, name, description, file_name, file_path, 
-			file_size, mime_type, row_count, column_count, status, uploaded_by, created_at, updated_at)
+			file_size, mime_type, row_count, column_count, status, csv_dialect, sheet_name, column_order, header_mode, null_tokens, uploaded_by, created_at, updated_at)
 		VALUES (:id, :project_id, :name, :description, :file_name, :file_path, 
-			:file_size, :mime_type, :row_count, :column_count, :status, :uploaded_by, :created_at, :updated_at)`
+			:file_size, :mime_type, :row_count, :column_count, :status, :csv_dialect, :sheet_name, :column_order, :header_mode, :null_tokens, :uploaded_by, :created_at, :updated_at)`
 
-	_, err := r.db.NamedExec(query, dataset)
+// Create creates a new dataset
+func (r *DatasetRepository) Create(dataset *models.Dataset) error {
+	_, err := r.db.NamedExec(insertDatasetQuery, dataset)
 	return err
 }
 
@@ -39,44 +132,94 @@ func (r *DatasetRepository) GetByID(id uuid.UUID) (*models.Dataset, error) {
 
 	err := r.db.Get(&dataset, query, id)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDatasetNotFound
+		}
 		return nil, err
 	}
 
 	return &dataset, nil
 }
 
-// GetByProjectID retrieves all datasets for a project
-func (r *DatasetRepository) GetByProjectID(projectID uuid.UUID) ([]models.Dataset, error) {
-	var datasets []models.Dataset
+// GetByProjectID retrieves one page of a project's datasets in sort order, with the project's total
+// number of datasets
+func (r *DatasetRepository) GetByProjectID(projectID uuid.UUID, order DatasetSort, limit, offset int) ([]models.Dataset, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM datasets WHERE project_id = $1`, projectID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count datasets: %w", err)
+	}
+
+	datasets := []models.Dataset{}
 	query := `
 		SELECT * FROM datasets 
 		WHERE project_id = $1 
-		ORDER BY created_at DESC`
+		` + order.orderBy("") + `
+		LIMIT $2 OFFSET $3`
 
-	err := r.db.Select(&datasets, query, projectID)
-	if err != nil {
-		return nil, err
+	if err := r.db.Select(&datasets, query, projectID, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get datasets: %w", err)
 	}
 
-	return datasets, nil
+	return datasets, total, nil
 }
 
-// GetByUserID retrieves all datasets uploaded by a user
-func (r *DatasetRepository) GetByUserID(userID uuid.UUID) ([]models.DatasetWithProject, error) {
-	var datasets []models.DatasetWithProject
+// GetByUserID retrieves one page of the datasets uploaded by a user in sort order, with the user's
+// total number of datasets
+func (r *DatasetRepository) GetByUserID(userID uuid.UUID, order DatasetSort, limit, offset int) ([]models.DatasetWithProject, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM datasets WHERE uploaded_by = $1`, userID); err != nil {
+		return nil, 0, fmt.Errorf("failed to count user datasets: %w", err)
+	}
+
+	datasets := []models.DatasetWithProject{}
 	query := `
 		SELECT d.*, p.name as project_name
 		FROM datasets d
 		JOIN projects p ON d.project_id = p.id
 		WHERE d.uploaded_by = $1
-		ORDER BY d.created_at DESC`
+		` + order.orderBy("d") + `
+		LIMIT $2 OFFSET $3`
 
-	err := r.db.Select(&datasets, query, userID)
-	if err != nil {
-		return nil, err
+	if err := r.db.Select(&datasets, query, userID, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get user datasets: %w", err)
 	}
 
-	return datasets, nil
+	return datasets, total, nil
+}
+
+// Search finds a project's datasets whose name or description contains the query, ignoring case,
+// and returns one page of matches with the total number of matches
+func (r *DatasetRepository) Search(projectID uuid.UUID, query string, limit, offset int) ([]models.DatasetWithProject, int, error) {
+	pattern := "%" + escapeLikePattern(query) + "%"
+
+	var total int
+	countQuery := `
+		SELECT COUNT(*) FROM datasets d
+		WHERE d.project_id = $1 AND (d.name ILIKE $2 OR d.description ILIKE $2)`
+
+	if err := r.db.Get(&total, countQuery, projectID, pattern); err != nil {
+		return nil, 0, fmt.Errorf("failed to count matching datasets: %w", err)
+	}
+
+	datasets := []models.DatasetWithProject{}
+	searchQuery := `
+		SELECT d.*, p.name as project_name
+		FROM datasets d
+		JOIN projects p ON d.project_id = p.id
+		WHERE d.project_id = $1 AND (d.name ILIKE $2 OR d.description ILIKE $2)
+		ORDER BY d.created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	if err := r.db.Select(&datasets, searchQuery, projectID, pattern, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to search datasets: %w", err)
+	}
+
+	return datasets, total, nil
+}
+
+// escapeLikePattern escapes LIKE wildcards so the query matches literally
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 // Update updates a dataset
@@ -107,6 +250,90 @@ func (r *DatasetRepository) UpdateStatus(id uuid.UUID, status string, rowCount,
 	return err
 }
 
+// SetTrusted toggles whether a dataset accepts direct appends from trusted API keys
+func (r *DatasetRepository) SetTrusted(id uuid.UUID, trusted bool) error {
+	query := `
+		UPDATE datasets 
+		SET is_trusted = $1, updated_at = $2
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, trusted, time.Now(), id)
+	return err
+}
+
+// SetDisplayField sets the field used to label dataset rows; nil clears it
+func (r *DatasetRepository) SetDisplayField(id uuid.UUID, displayField *string) error {
+	query := `
+		UPDATE datasets 
+		SET display_field = $1, updated_at = $2
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, displayField, time.Now(), id)
+	return err
+}
+
+// SetCSVDialect sets the dialect used to parse the dataset's CSV files; nil restores the standard dialect
+func (r *DatasetRepository) SetCSVDialect(id uuid.UUID, dialect *models.CSVDialect) error {
+	query := `
+		UPDATE datasets 
+		SET csv_dialect = $1, updated_at = $2
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, dialect, time.Now(), id)
+	return err
+}
+
+// SetPIIGuardrails replaces the contact fields guarded against duplicates; empty removes every guardrail
+func (r *DatasetRepository) SetPIIGuardrails(id uuid.UUID, guardrails models.PIIGuardrails) error {
+	query := `
+		UPDATE datasets 
+		SET pii_guardrails = $1, updated_at = $2
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, guardrails, time.Now(), id)
+	return err
+}
+
+// SetHeaderMode sets whether the dataset rejects or ignores submitted columns that aren't schema fields
+func (r *DatasetRepository) SetHeaderMode(id uuid.UUID, mode string) error {
+	query := `
+		UPDATE datasets 
+		SET header_mode = $1, updated_at = $2
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, mode, time.Now(), id)
+	return err
+}
+
+// SetNullTokens replaces the cell values the dataset reads as null; no tokens clears the setting
+func (r *DatasetRepository) SetNullTokens(id uuid.UUID, tokens models.NullTokens) error {
+	query := `
+		UPDATE datasets 
+		SET null_tokens = $1, updated_at = $2
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, tokens, time.Now(), id)
+	return err
+}
+
+// SetEnforceSchema toggles whether direct edits to the dataset's rows are validated against its schema
+func (r *DatasetRepository) SetEnforceSchema(id uuid.UUID, enforce bool) error {
+	query := `
+		UPDATE datasets 
+		SET enforce_schema = $1, updated_at = $2
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, enforce, time.Now(), id)
+	return err
+}
+
+// ListFilePaths retrieves the uploaded file path of every dataset
+func (r *DatasetRepository) ListFilePaths() ([]string, error) {
+	var paths []string
+	err := r.db.Select(&paths, `SELECT file_path FROM datasets`)
+	return paths, err
+}
+
 // Delete deletes a dataset
 func (r *DatasetRepository) Delete(id uuid.UUID, userID uuid.UUID) error {
 	query := `DELETE FROM datasets WHERE id = $1 AND uploaded_by = $2`
@@ -128,8 +355,46 @@ func (r *DatasetRepository) Delete(id uuid.UUID, userID uuid.UUID) error {
 	return nil
 }
 
-// CheckProjectAccess verifies if a user has access to upload to a project
+// CheckProjectAccess verifies if a user has access to upload to a project: the owner, or an
+// accepted member whose role can edit the project. Viewers cannot upload.
 func (r *DatasetRepository) CheckProjectAccess(projectID, userID uuid.UUID) (bool, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM projects p
+		WHERE p.id = $1 AND (p.owner_id = $2 OR EXISTS (
+			SELECT 1 FROM project_members pm 
+			WHERE pm.project_id = p.id AND pm.user_id = $2 
+			AND pm.status = 'accepted' AND pm.role IN ('owner', 'admin', 'collaborator')
+		))`
+
+	err := r.db.Get(&count, query, projectID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CanViewProject verifies if a user can read a project: the owner or any accepted member
+func (r *DatasetRepository) CanViewProject(projectID, userID uuid.UUID) (bool, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM projects p
+		WHERE p.id = $1 AND (p.owner_id = $2 OR EXISTS (
+			SELECT 1 FROM project_members pm 
+			WHERE pm.project_id = p.id AND pm.user_id = $2 AND pm.status = 'accepted'
+		))`
+
+	err := r.db.Get(&count, query, projectID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// IsProjectOwner verifies if a user owns a project
+func (r *DatasetRepository) IsProjectOwner(projectID, userID uuid.UUID) (bool, error) {
 	var count int
 	query := `
 		SELECT COUNT(*) FROM projects 
@@ -140,7 +405,5 @@ func (r *DatasetRepository) CheckProjectAccess(projectID, userID uuid.UUID) (boo
 		return false, err
 	}
 
-	// TODO: Also check project_members table for collaborator access
-	// For now, only project owner can upload
 	return count > 0, nil
 }
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"sales", "sales"},
+		{"100%", `100\%`},
+		{"q1_report", `q1\_report`},
+		{`C:\data`, `C:\\data`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, escapeLikePattern(tt.input))
+		})
+	}
+}
+
+func TestParseDatasetSort(t *testing.T) {
+	tests := []struct {
+		field, order string
+		expected     DatasetSort
+		orderBy      string
+	}{
+		{"", "", DefaultDatasetSort, "ORDER BY d.created_at DESC, d.id"},
+		{"name", "", DatasetSort{Field: DatasetSortName}, "ORDER BY LOWER(d.name) ASC, d.id"},
+		{"row_count", "asc", DatasetSort{Field: DatasetSortRowCount}, "ORDER BY d.row_count ASC, d.id"},
+		{" Updated_At ", "DESC", DatasetSort{Field: DatasetSortUpdatedAt, Descending: true}, "ORDER BY d.updated_at DESC, d.id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+"/"+tt.order, func(t *testing.T) {
+			got, err := ParseDatasetSort(tt.field, tt.order)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.orderBy, got.orderBy("d"))
+		})
+	}
+
+	t.Run("rejects fields and orders outside the allowlist", func(t *testing.T) {
+		for _, input := range [][2]string{{"name; DROP TABLE datasets", ""}, {"file_path", "asc"}, {"name", "sideways"}} {
+			_, err := ParseDatasetSort(input[0], input[1])
+			assert.ErrorIs(t, err, ErrInvalidDatasetSort, input[0])
+		}
+	})
+
+	t.Run("unknown fields never reach the query", func(t *testing.T) {
+		assert.Equal(t, "ORDER BY created_at DESC, id", DatasetSort{Field: "1; --"}.orderBy(""))
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ErrNotificationNotFound is returned when a user has no unread notification with the given ID
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// NotificationRepository handles database operations for in-app notifications
+type NotificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateNotification stores a notification
+func (r *NotificationRepository) CreateNotification(notification *models.Notification) error {
+	query := `
+		INSERT INTO notifications (id, user_id, type, title, message, resource_id, created_at)
+		VALUES (:id, :user_id, :type, :title, :message, :resource_id, :created_at)`
+
+	if _, err := r.db.NamedExec(query, notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// ListUnread returns up to limit of the user's unread notifications, newest first
+func (r *NotificationRepository) ListUnread(userID uuid.UUID, limit int) ([]models.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, message, resource_id, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND read_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2`
+
+	notifications := []models.Notification{}
+	if err := r.db.Select(&notifications, query, userID, limit); err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+// MarkRead marks one of the user's unread notifications as read
+func (r *NotificationRepository) MarkRead(userID, id uuid.UUID) error {
+	result, err := r.db.Exec(`
+		UPDATE notifications SET read_at = $1
+		WHERE id = $2 AND user_id = $3 AND read_at IS NULL`, time.Now(), id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
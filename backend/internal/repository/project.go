@@ -37,7 +37,7 @@ func (r *ProjectRepository) Create(project *models.Project) error {
 // GetByID retrieves a project by ID
 func (r *ProjectRepository) GetByID(id uuid.UUID) (*models.Project, error) {
 	var project models.Project
-	query := `SELECT id, name, description, owner_id, created_at, updated_at FROM projects WHERE id = $1`
+	query := `SELECT id, name, description, owner_id, created_at, updated_at, max_display_rows FROM projects WHERE id = $1`
 
 	err := r.db.Get(&project, query, id)
 	if err != nil {
@@ -54,7 +54,7 @@ func (r *ProjectRepository) GetByID(id uuid.UUID) (*models.Project, error) {
 func (r *ProjectRepository) GetByOwnerID(ownerID uuid.UUID) ([]*models.Project, error) {
 	var projects []*models.Project
 	query := `
-		SELECT id, name, description, owner_id, created_at, updated_at 
+		SELECT id, name, description, owner_id, created_at, updated_at, max_display_rows 
 		FROM projects 
 		WHERE owner_id = $1 
 		ORDER BY created_at DESC`
@@ -86,6 +86,17 @@ func (r *ProjectRepository) Update(id uuid.UUID, updates *models.UpdateProjectRe
 		argIndex++
 	}
 
+	if updates.MaxDisplayRows != nil {
+		// Zero clears the cap so the project follows the server default again
+		var maxDisplayRows *int
+		if *updates.MaxDisplayRows > 0 {
+			maxDisplayRows = updates.MaxDisplayRows
+		}
+		setParts = append(setParts, fmt.Sprintf("max_display_rows = $%d", argIndex))
+		args = append(args, maxDisplayRows)
+		argIndex++
+	}
+
 	if len(setParts) == 0 {
 		// No updates to perform, just return the current project
 		return r.GetByID(id)
@@ -96,7 +107,7 @@ func (r *ProjectRepository) Update(id uuid.UUID, updates *models.UpdateProjectRe
 
 	// Build the query
 	query := fmt.Sprintf(
-		"UPDATE projects SET %s WHERE id = $%d RETURNING id, name, description, owner_id, created_at, updated_at",
+		"UPDATE projects SET %s WHERE id = $%d RETURNING id, name, description, owner_id, created_at, updated_at, max_display_rows",
 		fmt.Sprintf("%s", setParts[0]),
 		argIndex,
 	)
@@ -111,7 +122,7 @@ func (r *ProjectRepository) Update(id uuid.UUID, updates *models.UpdateProjectRe
 			setClause += part
 		}
 		query = fmt.Sprintf(
-			"UPDATE projects SET %s WHERE id = $%d RETURNING id, name, description, owner_id, created_at, updated_at",
+			"UPDATE projects SET %s WHERE id = $%d RETURNING id, name, description, owner_id, created_at, updated_at, max_display_rows",
 			setClause,
 			argIndex,
 		)
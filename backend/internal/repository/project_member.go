@@ -2,15 +2,21 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
 	"github.com/saurabh22suman/oreo.io/internal/models"
 )
 
+// ErrAlreadyMember is returned when inviting a user who is already a member or has a pending invitation
+var ErrAlreadyMember = errors.New("user is already a member of this project")
+
+// ErrInvitationNotFound is returned when a user has no pending invitation to a project
+var ErrInvitationNotFound = errors.New("no pending invitation found")
+
 type ProjectMemberRepository struct {
 	db *sqlx.DB
 }
@@ -64,7 +70,7 @@ func (r *ProjectMemberRepository) GetUserRole(projectID, userID uuid.UUID) (stri
 func (r *ProjectMemberRepository) GetUserProjects(userID uuid.UUID) ([]models.ProjectWithMembers, error) {
 	query := `
 		SELECT DISTINCT
-			p.id, p.name, p.description, p.owner_id, p.created_at, p.updated_at
+			p.id, p.name, p.description, p.owner_id, p.created_at, p.updated_at, p.max_display_rows
 		FROM projects p
 		JOIN project_members pm ON p.id = pm.project_id
 		WHERE pm.user_id = $1 AND pm.status = 'accepted'
@@ -99,7 +105,7 @@ func (r *ProjectMemberRepository) InviteUser(projectID, inviterID, inviteeID uui
 	checkQuery := `SELECT id FROM project_members WHERE project_id = $1 AND user_id = $2`
 	err := r.db.Get(&existingID, checkQuery, projectID, inviteeID)
 	if err == nil {
-		return nil, fmt.Errorf("user is already a member of this project")
+		return nil, ErrAlreadyMember
 	}
 	if err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to check existing membership: %w", err)
@@ -123,10 +129,9 @@ func (r *ProjectMemberRepository) InviteUser(projectID, inviterID, inviteeID uui
 		(id, project_id, user_id, role, invited_by, invited_at, status, permissions, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
-	permissionsJSON, _ := pq.Array([]byte{}).Value()
-	if permissions != nil {
-		// Convert permissions to JSONB
-		// Note: This is a simplified approach. In production, use proper JSON marshaling
+	permissionsJSON, err := member.Permissions.Value()
+	if err != nil {
+		return nil, err
 	}
 
 	_, err = r.db.Exec(query,
@@ -141,6 +146,29 @@ func (r *ProjectMemberRepository) InviteUser(projectID, inviterID, inviteeID uui
 	return member, nil
 }
 
+// GetPendingInvitations returns the invitations a user has not yet accepted, newest first
+func (r *ProjectMemberRepository) GetPendingInvitations(userID uuid.UUID) ([]models.ProjectInvitation, error) {
+	query := `
+		SELECT 
+			pm.id, pm.project_id, pm.user_id, pm.role, pm.invited_by, 
+			pm.invited_at, pm.joined_at, pm.status, pm.permissions, 
+			pm.created_at, pm.updated_at,
+			p.name as project_name, inviter.name as inviter_name
+		FROM project_members pm
+		JOIN projects p ON pm.project_id = p.id
+		LEFT JOIN users inviter ON pm.invited_by = inviter.id
+		WHERE pm.user_id = $1 AND pm.status = 'pending'
+		ORDER BY pm.invited_at DESC`
+
+	invitations := []models.ProjectInvitation{}
+	err := r.db.Select(&invitations, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
 // AcceptInvitation accepts a project invitation
 func (r *ProjectMemberRepository) AcceptInvitation(projectID, userID uuid.UUID) error {
 	query := `
@@ -159,7 +187,7 @@ func (r *ProjectMemberRepository) AcceptInvitation(projectID, userID uuid.UUID)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("no pending invitation found")
+		return ErrInvitationNotFound
 	}
 
 	return nil
@@ -216,10 +244,9 @@ func (r *ProjectMemberRepository) UpdateMemberRole(projectID, userID uuid.UUID,
 		SET role = $3, permissions = $4, updated_at = CURRENT_TIMESTAMP
 		WHERE project_id = $1 AND user_id = $2`
 
-	permissionsJSON, _ := pq.Array([]byte{}).Value()
-	if permissions != nil {
-		// Convert permissions to JSONB
-		// Note: This is a simplified approach. In production, use proper JSON marshaling
+	permissionsJSON, err := models.MemberPermissions(permissions).Value()
+	if err != nil {
+		return err
 	}
 
 	result, err := r.db.Exec(query, projectID, userID, role, permissionsJSON)
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Retry defaults for transactions that may lose a serialization race with concurrent writers
+const (
+	DefaultTxRetryAttempts  = 3
+	DefaultTxRetryBaseDelay = 50 * time.Millisecond
+)
+
+// retryablePgCodes are the Postgres error codes after which re-running a whole transaction may succeed
+var retryablePgCodes = map[pq.ErrorCode]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// TxRetryError is returned when a transaction still fails with a retryable error after every attempt
+type TxRetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *TxRetryError) Error() string {
+	return fmt.Sprintf("transaction failed after %d attempts due to concurrent updates: %v", e.Attempts, e.Err)
+}
+
+func (e *TxRetryError) Unwrap() error {
+	return e.Err
+}
+
+// isRetryableTxError reports whether err is a Postgres serialization failure or deadlock
+func isRetryableTxError(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && retryablePgCodes[pqErr.Code]
+}
+
+// txRetryPolicy re-runs a transaction on retryable errors with exponential backoff
+type txRetryPolicy struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	sleep       func(time.Duration)
+}
+
+func newTxRetryPolicy(maxAttempts int, baseDelay time.Duration) txRetryPolicy {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if baseDelay < 0 {
+		baseDelay = 0
+	}
+	return txRetryPolicy{maxAttempts: maxAttempts, baseDelay: baseDelay, sleep: time.Sleep}
+}
+
+// run calls fn until it succeeds, fails with a non-retryable error, or runs out of attempts.
+// fn must perform the whole transaction so each attempt starts from a clean state.
+func (p txRetryPolicy) run(name string, fn func() error) error {
+	delay := p.baseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !isRetryableTxError(err) {
+			return err
+		}
+		if attempt >= p.maxAttempts {
+			return &TxRetryError{Attempts: attempt, Err: err}
+		}
+
+		log.Printf("Retrying %s after attempt %d/%d failed: %v", name, attempt, p.maxAttempts, err)
+		p.sleep(delay)
+		delay *= 2
+	}
+}
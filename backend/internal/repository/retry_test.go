@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRetryPolicy returns a policy that records its backoff delays instead of sleeping
+func recordingRetryPolicy(maxAttempts int, delays *[]time.Duration) txRetryPolicy {
+	policy := newTxRetryPolicy(maxAttempts, 10*time.Millisecond)
+	policy.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+	return policy
+}
+
+func TestTxRetryPolicy_Run(t *testing.T) {
+	serializationFailure := &pq.Error{Code: "40001", Message: "could not serialize access due to concurrent update"}
+
+	t.Run("serialization failure succeeds on retry", func(t *testing.T) {
+		var delays []time.Duration
+		calls := 0
+		err := recordingRetryPolicy(3, &delays).run("apply", func() error {
+			calls++
+			if calls == 1 {
+				return serializationFailure
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, []time.Duration{10 * time.Millisecond}, delays)
+	})
+
+	t.Run("wrapped deadlock is retried with backoff until attempts run out", func(t *testing.T) {
+		var delays []time.Duration
+		calls := 0
+		err := recordingRetryPolicy(3, &delays).run("apply", func() error {
+			calls++
+			return fmt.Errorf("insert failed: %w", &pq.Error{Code: "40P01"})
+		})
+
+		var retryErr *TxRetryError
+		require.ErrorAs(t, err, &retryErr)
+		assert.Equal(t, 3, retryErr.Attempts)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, delays)
+		assert.Contains(t, err.Error(), "after 3 attempts")
+	})
+
+	t.Run("other errors are returned immediately", func(t *testing.T) {
+		var delays []time.Duration
+		calls := 0
+		uniqueViolation := &pq.Error{Code: "23505"}
+		err := recordingRetryPolicy(3, &delays).run("apply", func() error {
+			calls++
+			return uniqueViolation
+		})
+
+		assert.True(t, errors.Is(err, uniqueViolation))
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, delays)
+	})
+
+	t.Run("at least one attempt is made", func(t *testing.T) {
+		calls := 0
+		err := newTxRetryPolicy(0, 0).run("apply", func() error {
+			calls++
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
@@ -1,14 +1,40 @@
 package repository
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/saurabh22suman/oreo.io/internal/models"
 )
 
+// ErrSchemaNotFound is returned when a dataset has no schema
+var ErrSchemaNotFound = errors.New("schema not found")
+
+// ErrSchemaVersionNotFound is returned when a schema has no version with the requested number
+var ErrSchemaVersionNotFound = errors.New("schema version not found")
+
+// ErrDatasetNotFound is returned when a dataset does not exist
+var ErrDatasetNotFound = errors.New("dataset not found")
+
+// ErrFieldStatsNotFound is returned when no field statistics have been computed for a dataset
+var ErrFieldStatsNotFound = errors.New("field stats not found")
+
+// RowVersionConflictError is returned when a row update expects a version the row no longer has
+type RowVersionConflictError struct {
+	CurrentVersion int // 0 when the row no longer exists
+}
+
+func (e *RowVersionConflictError) Error() string {
+	return fmt.Sprintf("row version conflict: the row is at version %d", e.CurrentVersion)
+}
+
 // SchemaRepository handles database operations for schemas
 type SchemaRepository struct {
 	db *sqlx.DB
@@ -27,22 +53,25 @@ func (r *SchemaRepository) CreateSchema(schema *models.DatasetSchema) error {
 	}
 	defer tx.Rollback()
 
+	// A new default replaces the dataset's previous default
+	if schema.IsDefault {
+		_, err = tx.Exec(`UPDATE dataset_schemas SET is_default = FALSE WHERE dataset_id = $1 AND is_default`, schema.DatasetID)
+		if err != nil {
+			return fmt.Errorf("failed to clear default schema: %w", err)
+		}
+	}
+
 	// Insert schema
-	query := `
-		INSERT INTO dataset_schemas (id, dataset_id, name, description, created_at, updated_at)
-		VALUES (:id, :dataset_id, :name, :description, :created_at, :updated_at)`
-	
-	_, err = tx.NamedExec(query, schema)
-	if err != nil {
-		return fmt.Errorf("failed to create schema: %w", err)
+	if err := insertSchema(tx, schema); err != nil {
+		return err
 	}
 
 	// Insert fields
 	for _, field := range schema.Fields {
 		fieldQuery := `
-			INSERT INTO schema_fields (id, schema_id, name, display_name, data_type, is_required, is_unique, 
+			INSERT INTO schema_fields (id, schema_id, name, display_name, data_type, is_required, is_unique, is_pii,
 				default_value, position, validation, created_at, updated_at)
-			VALUES (:id, :schema_id, :name, :display_name, :data_type, :is_required, :is_unique, 
+			VALUES (:id, :schema_id, :name, :display_name, :data_type, :is_required, :is_unique, :is_pii,
 				:default_value, :position, :validation, :created_at, :updated_at)`
 		
 		// Convert validation to JSON
@@ -59,6 +88,7 @@ func (r *SchemaRepository) CreateSchema(schema *models.DatasetSchema) error {
 			"data_type":     field.DataType,
 			"is_required":   field.IsRequired,
 			"is_unique":     field.IsUnique,
+			"is_pii":        field.IsPII,
 			"default_value": field.DefaultValue,
 			"position":      field.Position,
 			"validation":    validationJSON,
@@ -72,25 +102,77 @@ func (r *SchemaRepository) CreateSchema(schema *models.DatasetSchema) error {
 		}
 	}
 
+	if err := recordSchemaVersion(tx, schema); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
-// GetSchemaByDatasetID retrieves schema for a dataset
+// schemaColumns are the dataset_schemas columns scanned by getSchema
+const schemaColumns = `id, dataset_id, name, description, date_formats, is_default, created_at, updated_at`
+
+// GetSchemaByDatasetID retrieves the default schema for a dataset
 func (r *SchemaRepository) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error) {
+	query := `SELECT ` + schemaColumns + ` 
+			  FROM dataset_schemas WHERE dataset_id = $1
+			  ORDER BY is_default DESC, created_at
+			  LIMIT 1`
+
+	return r.getSchema(query, datasetID)
+}
+
+// GetSchemaByID retrieves a schema by its ID
+func (r *SchemaRepository) GetSchemaByID(schemaID uuid.UUID) (*models.DatasetSchema, error) {
+	query := `SELECT ` + schemaColumns + ` 
+			  FROM dataset_schemas WHERE id = $1`
+
+	return r.getSchema(query, schemaID)
+}
+
+// GetSchemaByName retrieves a named schema variant of a dataset
+func (r *SchemaRepository) GetSchemaByName(datasetID uuid.UUID, name string) (*models.DatasetSchema, error) {
+	query := `SELECT ` + schemaColumns + ` 
+			  FROM dataset_schemas WHERE dataset_id = $1 AND name = $2`
+
+	return r.getSchema(query, datasetID, name)
+}
+
+// ListSchemasByDatasetID retrieves every schema variant of a dataset, default first
+func (r *SchemaRepository) ListSchemasByDatasetID(datasetID uuid.UUID) ([]*models.DatasetSchema, error) {
+	var names []string
+	query := `SELECT name FROM dataset_schemas WHERE dataset_id = $1 ORDER BY is_default DESC, name`
+	if err := r.db.Select(&names, query, datasetID); err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	schemas := make([]*models.DatasetSchema, 0, len(names))
+	for _, name := range names {
+		schema, err := r.GetSchemaByName(datasetID, name)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+	return schemas, nil
+}
+
+// getSchema loads the schema selected by query, which must select schemaColumns, along with its fields
+func (r *SchemaRepository) getSchema(query string, args ...interface{}) (*models.DatasetSchema, error) {
 	schema := &models.DatasetSchema{}
-	
-	// Get schema
-	query := `SELECT id, dataset_id, name, description, created_at, updated_at 
-			  FROM dataset_schemas WHERE dataset_id = $1`
-	
-	err := r.db.Get(schema, query, datasetID)
+
+	err := r.db.QueryRow(query, args...).Scan(&schema.ID, &schema.DatasetID, &schema.Name, &schema.Description,
+		pq.Array(&schema.DateFormats), &schema.IsDefault, &schema.CreatedAt, &schema.UpdatedAt)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSchemaNotFound
+		}
 		return nil, fmt.Errorf("failed to get schema: %w", err)
 	}
 
 	// Get fields
 	fieldsQuery := `
-		SELECT id, schema_id, name, display_name, data_type, is_required, is_unique, 
+		SELECT id, schema_id, name, display_name, data_type, is_required, is_unique, is_pii,
 			   default_value, position, validation, created_at, updated_at
 		FROM schema_fields 
 		WHERE schema_id = $1 
@@ -109,7 +191,7 @@ func (r *SchemaRepository) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.Da
 		
 		err := rows.Scan(
 			&field.ID, &field.SchemaID, &field.Name, &field.DisplayName,
-			&field.DataType, &field.IsRequired, &field.IsUnique,
+			&field.DataType, &field.IsRequired, &field.IsUnique, &field.IsPII,
 			&field.DefaultValue, &field.Position, &validationJSON,
 			&field.CreatedAt, &field.UpdatedAt,
 		)
@@ -132,6 +214,29 @@ func (r *SchemaRepository) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.Da
 	return schema, nil
 }
 
+// dateFormatsValue stores a schema's date formats; date_formats is NOT NULL, so a schema without
+// formats stores an empty array
+func dateFormatsValue(formats []string) interface{} {
+	if formats == nil {
+		formats = []string{}
+	}
+	return pq.Array(formats)
+}
+
+// insertSchema inserts the schema row, without its fields
+func insertSchema(tx sqlx.Execer, schema *models.DatasetSchema) error {
+	query := `
+		INSERT INTO dataset_schemas (id, dataset_id, name, description, date_formats, is_default, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := tx.Exec(query, schema.ID, schema.DatasetID, schema.Name, schema.Description,
+		dateFormatsValue(schema.DateFormats), schema.IsDefault, schema.CreatedAt, schema.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+	return nil
+}
+
 // UpdateSchema updates an existing schema
 func (r *SchemaRepository) UpdateSchema(schema *models.DatasetSchema) error {
 	tx, err := r.db.Beginx()
@@ -143,10 +248,10 @@ func (r *SchemaRepository) UpdateSchema(schema *models.DatasetSchema) error {
 	// Update schema
 	query := `
 		UPDATE dataset_schemas 
-		SET name = :name, description = :description, updated_at = :updated_at
-		WHERE id = :id`
+		SET name = $1, description = $2, date_formats = $3, updated_at = $4
+		WHERE id = $5`
 	
-	_, err = tx.NamedExec(query, schema)
+	_, err = tx.Exec(query, schema.Name, schema.Description, dateFormatsValue(schema.DateFormats), schema.UpdatedAt, schema.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update schema: %w", err)
 	}
@@ -160,9 +265,9 @@ func (r *SchemaRepository) UpdateSchema(schema *models.DatasetSchema) error {
 	// Insert updated fields
 	for _, field := range schema.Fields {
 		fieldQuery := `
-			INSERT INTO schema_fields (id, schema_id, name, display_name, data_type, is_required, is_unique, 
+			INSERT INTO schema_fields (id, schema_id, name, display_name, data_type, is_required, is_unique, is_pii,
 				default_value, position, validation, created_at, updated_at)
-			VALUES (:id, :schema_id, :name, :display_name, :data_type, :is_required, :is_unique, 
+			VALUES (:id, :schema_id, :name, :display_name, :data_type, :is_required, :is_unique, :is_pii,
 				:default_value, :position, :validation, :created_at, :updated_at)`
 		
 		validationJSON, err := json.Marshal(field.Validation)
@@ -178,6 +283,7 @@ func (r *SchemaRepository) UpdateSchema(schema *models.DatasetSchema) error {
 			"data_type":     field.DataType,
 			"is_required":   field.IsRequired,
 			"is_unique":     field.IsUnique,
+			"is_pii":        field.IsPII,
 			"default_value": field.DefaultValue,
 			"position":      field.Position,
 			"validation":    validationJSON,
@@ -191,9 +297,85 @@ func (r *SchemaRepository) UpdateSchema(schema *models.DatasetSchema) error {
 		}
 	}
 
+	if err := recordSchemaVersion(tx, schema); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
+// recordSchemaVersion snapshots the schema's fields as its next version
+func recordSchemaVersion(tx *sqlx.Tx, schema *models.DatasetSchema) error {
+	fields := schema.Fields
+	if fields == nil {
+		fields = []models.SchemaField{}
+	}
+	fieldsJSON, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema version: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO schema_versions (schema_id, version, fields)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2 FROM schema_versions WHERE schema_id = $1`,
+		schema.ID, fieldsJSON)
+	if err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return nil
+}
+
+// ListSchemaVersions returns every recorded version of a schema, oldest first
+func (r *SchemaRepository) ListSchemaVersions(schemaID uuid.UUID) ([]models.SchemaVersion, error) {
+	rows, err := r.db.Query(`
+		SELECT id, schema_id, version, fields, created_at
+		FROM schema_versions WHERE schema_id = $1
+		ORDER BY version`, schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schema versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []models.SchemaVersion{}
+	for rows.Next() {
+		version, err := scanSchemaVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+	return versions, rows.Err()
+}
+
+// GetSchemaVersion returns one recorded version of a schema
+func (r *SchemaRepository) GetSchemaVersion(schemaID uuid.UUID, version int) (*models.SchemaVersion, error) {
+	row := r.db.QueryRow(`
+		SELECT id, schema_id, version, fields, created_at
+		FROM schema_versions WHERE schema_id = $1 AND version = $2`, schemaID, version)
+
+	schemaVersion, err := scanSchemaVersion(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrSchemaVersionNotFound
+	}
+	return schemaVersion, err
+}
+
+// scanSchemaVersion scans a schema_versions row selected as id, schema_id, version, fields, created_at
+func scanSchemaVersion(row interface{ Scan(...interface{}) error }) (*models.SchemaVersion, error) {
+	version := &models.SchemaVersion{}
+	var fieldsJSON []byte
+	if err := row.Scan(&version.ID, &version.SchemaID, &version.Version, &fieldsJSON, &version.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan schema version: %w", err)
+	}
+	if err := json.Unmarshal(fieldsJSON, &version.Fields); err != nil {
+		return nil, fmt.Errorf("failed to decode schema version fields: %w", err)
+	}
+	return version, nil
+}
+
 // DeleteSchema deletes a schema and all its fields
 func (r *SchemaRepository) DeleteSchema(schemaID uuid.UUID) error {
 	query := `DELETE FROM dataset_schemas WHERE id = $1`
@@ -247,7 +429,7 @@ func (r *SchemaRepository) GetDatasetData(datasetID uuid.UUID, page, pageSize in
 		}
 
 		// Add row index to data
-		rowData["_row_index"] = rowIndex
+		rowData[rowIndexKey] = rowIndex
 		data = append(data, rowData)
 	}
 
@@ -262,6 +444,7 @@ func (r *SchemaRepository) GetDatasetData(datasetID uuid.UUID, page, pageSize in
 
 	return &models.DataPreviewResponse{
 		Data:       data,
+		Columns:    r.dataColumns(datasetID, schema, data),
 		Schema:     schema,
 		TotalRows:  totalRows,
 		Page:       page,
@@ -270,28 +453,82 @@ func (r *SchemaRepository) GetDatasetData(datasetID uuid.UUID, page, pageSize in
 	}, nil
 }
 
-// GetDatasetDataWithLimit retrieves dataset data with a maximum row limit
-func (r *SchemaRepository) GetDatasetDataWithLimit(datasetID uuid.UUID, page, pageSize, maxRows int) (*models.DataPreviewResponse, error) {
-	// Calculate the maximum offset we can allow
-	offset := (page - 1) * pageSize
+// displayWindow returns the offset and row count of a page when only the first maxRows rows can
+// be paged through; the last page is cut short at maxRows and pages beyond it are empty
+func displayWindow(page, pageSize, maxRows int) (offset, limit int) {
+	offset = (page - 1) * pageSize
 	if offset >= maxRows {
-		// Return empty result if beyond limit
-		return &models.DataPreviewResponse{
-			Data:       []map[string]interface{}{},
-			Schema:     nil,
-			TotalRows:  maxRows,
-			Page:       page,
-			PageSize:   pageSize,
-			TotalPages: maxRows / pageSize,
-		}, nil
+		return offset, 0
 	}
+	return offset, min(pageSize, maxRows-offset)
+}
 
-	// Adjust page size if it would exceed the limit
-	remainingRows := maxRows - offset
-	if pageSize > remainingRows {
-		pageSize = remainingRows
+// pageCount returns how many pages of pageSize rows it takes to show totalRows rows
+func pageCount(totalRows, pageSize int) int {
+	if pageSize <= 0 {
+		return 0
 	}
+	return (totalRows + pageSize - 1) / pageSize
+}
+
+// Keys added to row payloads next to the row's own data; they are never reported as columns
+const (
+	rowIndexKey     = "_row_index"
+	rowVersionKey   = "_version" // sent back as the expected version of an update
+	rowCreatedByKey = "_created_by"
+	rowUpdatedByKey = "_updated_by"
+	rowCreatedAtKey = "_created_at"
+	rowUpdatedAtKey = "_updated_at"
+)
+
+var rowPayloadKeys = map[string]bool{
+	rowIndexKey:        true,
+	rowVersionKey:      true,
+	rowCreatedByKey:    true,
+	rowUpdatedByKey:    true,
+	rowCreatedAtKey:    true,
+	rowUpdatedAtKey:    true,
+	models.RowLabelKey: true,
+}
 
+// StoredRowData returns a row payload without the keys added next to its data, so a row sent back
+// by a client is stored and validated as its data alone
+func StoredRowData(row map[string]interface{}) map[string]interface{} {
+	data := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		if !rowPayloadKeys[key] {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+// rowMeta is who added and last edited a stored row, and when
+type rowMeta struct {
+	CreatedBy sql.NullString
+	UpdatedBy sql.NullString
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+}
+
+// apply adds the metadata to a row payload; unknown users and times are null
+func (m rowMeta) apply(row map[string]interface{}) {
+	nullable := func(valid bool, value interface{}) interface{} {
+		if !valid {
+			return nil
+		}
+		return value
+	}
+	row[rowCreatedByKey] = nullable(m.CreatedBy.Valid, m.CreatedBy.String)
+	row[rowUpdatedByKey] = nullable(m.UpdatedBy.Valid, m.UpdatedBy.String)
+	row[rowCreatedAtKey] = nullable(m.CreatedAt.Valid, m.CreatedAt.Time)
+	row[rowUpdatedAtKey] = nullable(m.UpdatedAt.Valid, m.UpdatedAt.Time)
+}
+
+// GetDatasetDataWithLimit retrieves one page of dataset data when only the first maxRows rows can
+// be paged through. TotalRows and TotalPages describe the capped rows. With includeMeta each row
+// also carries the names of the users who added and last edited it, and when.
+func (r *SchemaRepository) GetDatasetDataWithLimit(datasetID uuid.UUID, page, pageSize, maxRows int, includeMeta bool) (*models.DataPreviewResponse, error) {
 	// Get count query with limit
 	countQuery := `SELECT LEAST(COUNT(*), $2) FROM dataset_data WHERE dataset_id = $1`
 	var totalRows int
@@ -300,15 +537,39 @@ func (r *SchemaRepository) GetDatasetDataWithLimit(datasetID uuid.UUID, page, pa
 		return nil, fmt.Errorf("failed to get data count: %w", err)
 	}
 
+	offset, limit := displayWindow(page, pageSize, maxRows)
+	if limit == 0 {
+		// Return empty result if beyond limit
+		return &models.DataPreviewResponse{
+			Data:       []map[string]interface{}{},
+			Columns:    []string{},
+			Schema:     nil,
+			TotalRows:  totalRows,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalPages: pageCount(totalRows, pageSize),
+		}, nil
+	}
+
 	// Get data with limit
 	dataQuery := `
-		SELECT row_index, data 
+		SELECT row_index, data, version 
 		FROM dataset_data 
 		WHERE dataset_id = $1 
 		ORDER BY row_index 
 		LIMIT $2 OFFSET $3`
+	if includeMeta {
+		dataQuery = `
+		SELECT dd.row_index, dd.data, dd.version, creator.name, editor.name, dd.created_at, dd.updated_at
+		FROM dataset_data dd
+		LEFT JOIN users creator ON creator.id = dd.created_by
+		LEFT JOIN users editor ON editor.id = dd.updated_by
+		WHERE dd.dataset_id = $1
+		ORDER BY dd.row_index
+		LIMIT $2 OFFSET $3`
+	}
 	
-	rows, err := r.db.Query(dataQuery, datasetID, pageSize, offset)
+	rows, err := r.db.Query(dataQuery, datasetID, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get data: %w", err)
 	}
@@ -316,10 +577,15 @@ func (r *SchemaRepository) GetDatasetDataWithLimit(datasetID uuid.UUID, page, pa
 
 	var data []map[string]interface{}
 	for rows.Next() {
-		var rowIndex int
+		var rowIndex, version int
 		var dataJSON []byte
+		var meta rowMeta
 		
-		err := rows.Scan(&rowIndex, &dataJSON)
+		dest := []interface{}{&rowIndex, &dataJSON, &version}
+		if includeMeta {
+			dest = append(dest, &meta.CreatedBy, &meta.UpdatedBy, &meta.CreatedAt, &meta.UpdatedAt)
+		}
+		err := rows.Scan(dest...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan data row: %w", err)
 		}
@@ -330,8 +596,12 @@ func (r *SchemaRepository) GetDatasetDataWithLimit(datasetID uuid.UUID, page, pa
 			return nil, fmt.Errorf("failed to unmarshal data: %w", err)
 		}
 
-		// Add row index to data
-		rowData["_row_index"] = rowIndex
+		// Add row index and version to data
+		rowData[rowIndexKey] = rowIndex
+		rowData[rowVersionKey] = version
+		if includeMeta {
+			meta.apply(rowData)
+		}
 		data = append(data, rowData)
 	}
 
@@ -342,25 +612,20 @@ func (r *SchemaRepository) GetDatasetDataWithLimit(datasetID uuid.UUID, page, pa
 		schema = nil
 	}
 
-	// Calculate total pages based on limited rows
-	limitedTotalRows := totalRows
-	if limitedTotalRows > maxRows {
-		limitedTotalRows = maxRows
-	}
-	totalPages := (limitedTotalRows + pageSize - 1) / pageSize
-
 	return &models.DataPreviewResponse{
 		Data:       data,
+		Columns:    r.dataColumns(datasetID, schema, data),
 		Schema:     schema,
-		TotalRows:  limitedTotalRows,
+		TotalRows:  totalRows,
 		Page:       page,
 		PageSize:   pageSize,
-		TotalPages: totalPages,
+		TotalPages: pageCount(totalRows, pageSize),
 	}, nil
 }
 
-// QueryDatasetData executes a SQL-like query on dataset data
-func (r *SchemaRepository) QueryDatasetData(datasetID uuid.UUID, sqlQuery string, pageSize int) (*models.DataPreviewResponse, error) {
+// QueryDatasetData executes a SQL-like query on dataset data. The query never matches the values
+// of hiddenFields, so callers who may not see those fields can't probe them.
+func (r *SchemaRepository) QueryDatasetData(datasetID uuid.UUID, sqlQuery string, pageSize int, hiddenFields []string) (*models.DataPreviewResponse, error) {
 	// For security, we'll implement a simple WHERE clause parser
 	// This is a simplified version - in production, use a proper SQL parser
 	
@@ -376,10 +641,15 @@ func (r *SchemaRepository) QueryDatasetData(datasetID uuid.UUID, sqlQuery string
 	// Very basic WHERE clause support - just search in JSON data
 	// This is simplified and should be enhanced for production
 	finalQuery := baseQuery
+	matchData := "data"
 	if sqlQuery != "" {
 		// Simple LIKE search in JSON data
-		finalQuery += ` AND data::text ILIKE $2`
 		args = append(args, "%"+sqlQuery+"%")
+		if len(hiddenFields) > 0 {
+			matchData = "(data - $3::text[])"
+			args = append(args, pq.Array(hiddenFields))
+		}
+		finalQuery += ` AND ` + matchData + `::text ILIKE $2`
 	}
 	
 	finalQuery += ` ORDER BY row_index LIMIT $` + fmt.Sprintf("%d", len(args)+1)
@@ -387,10 +657,9 @@ func (r *SchemaRepository) QueryDatasetData(datasetID uuid.UUID, sqlQuery string
 
 	// Get count first
 	countQuery := `SELECT COUNT(*) FROM dataset_data WHERE dataset_id = $1`
-	countArgs := []interface{}{datasetID}
+	countArgs := args[:len(args)-1] // the same arguments without the page size
 	if sqlQuery != "" {
-		countQuery += ` AND data::text ILIKE $2`
-		countArgs = append(countArgs, "%"+sqlQuery+"%")
+		countQuery += ` AND ` + matchData + `::text ILIKE $2`
 	}
 
 	var totalRows int
@@ -423,7 +692,7 @@ func (r *SchemaRepository) QueryDatasetData(datasetID uuid.UUID, sqlQuery string
 		}
 
 		// Add row index to data
-		rowData["_row_index"] = rowIndex
+		rowData[rowIndexKey] = rowIndex
 		data = append(data, rowData)
 	}
 
@@ -435,6 +704,7 @@ func (r *SchemaRepository) QueryDatasetData(datasetID uuid.UUID, sqlQuery string
 
 	return &models.DataPreviewResponse{
 		Data:       data,
+		Columns:    r.dataColumns(datasetID, schema, data),
 		Schema:     schema,
 		TotalRows:  totalRows,
 		Page:       1,
@@ -443,74 +713,483 @@ func (r *SchemaRepository) QueryDatasetData(datasetID uuid.UUID, sqlQuery string
 	}, nil
 }
 
-// BulkInsertDatasetData inserts multiple rows of CSV data
-func (r *SchemaRepository) BulkInsertDatasetData(datasetID uuid.UUID, headers []string, rows [][]string, userID uuid.UUID) error {
-	tx, err := r.db.Beginx()
+// dataColumns returns the display order of the columns in a page of dataset data
+func (r *SchemaRepository) dataColumns(datasetID uuid.UUID, schema *models.DatasetSchema, data []map[string]interface{}) []string {
+	var headerOrder models.ColumnOrder
+	if schema == nil || len(schema.Fields) == 0 {
+		if err := r.db.Get(&headerOrder, `SELECT column_order FROM datasets WHERE id = $1`, datasetID); err != nil {
+			// Datasets uploaded before the header order was recorded fall back to name order
+			headerOrder = nil
+		}
+	}
+	return orderDataColumns(schema, headerOrder, data)
+}
+
+// orderDataColumns orders columns by schema field position when the dataset has a schema, otherwise
+// by the uploaded file's header order. Columns present in the rows but in neither are appended in
+// name order, and the keys the payload adds itself (_row_index and row metadata) are left out.
+func orderDataColumns(schema *models.DatasetSchema, headerOrder []string, data []map[string]interface{}) []string {
+	columns := []string{}
+	seen := map[string]bool{}
+	for key := range rowPayloadKeys {
+		seen[key] = true
+	}
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			columns = append(columns, name)
+		}
+	}
+
+	if schema != nil && len(schema.Fields) > 0 {
+		fields := make([]models.SchemaField, len(schema.Fields))
+		copy(fields, schema.Fields)
+		sort.SliceStable(fields, func(i, j int) bool { return fields[i].Position < fields[j].Position })
+		for _, field := range fields {
+			add(field.Name)
+		}
+	} else {
+		for _, name := range headerOrder {
+			add(name)
+		}
+	}
+
+	var extra []string
+	for _, row := range data {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				extra = append(extra, name)
+			}
+		}
+	}
+	sort.Strings(extra)
+	return append(columns, extra...)
+}
+
+// datasetDataSearchVector must match the expression of the idx_dataset_data_search index
+const datasetDataSearchVector = `jsonb_to_tsvector('simple', data, '["string", "numeric"]')`
+
+// SearchDatasetData runs a web-style full-text search (quoted phrases, "or", "-" exclusions) over
+// the string and numeric values of a dataset's rows and returns one page of matches ranked by
+// relevance. Only the first maxRows matches can be paged through. The values of hiddenFields are
+// left out of the search, which then can't use the search index.
+func (r *SchemaRepository) SearchDatasetData(datasetID uuid.UUID, query string, page, pageSize, maxRows int, hiddenFields []string) ([]models.DataSearchResult, int, error) {
+	searchVector := datasetDataSearchVector
+	args := []interface{}{datasetID, query}
+	if len(hiddenFields) > 0 {
+		searchVector = `jsonb_to_tsvector('simple', data - $3::text[], '["string", "numeric"]')`
+		args = append(args, pq.Array(hiddenFields))
+	}
+	matchClause := `dataset_id = $1 AND ` + searchVector + ` @@ websearch_to_tsquery('simple', $2)`
+	limitParam := len(args) + 1
+
+	var totalRows int
+	countQuery := fmt.Sprintf(`
+		SELECT COUNT(*) FROM (
+			SELECT 1 FROM dataset_data WHERE %s LIMIT $%d
+		) matches`, matchClause, limitParam)
+
+	if err := r.db.Get(&totalRows, countQuery, append(args, maxRows)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count search matches: %w", err)
+	}
+
+	results := []models.DataSearchResult{}
+	offset, limit := displayWindow(page, pageSize, maxRows)
+	if limit == 0 {
+		return results, totalRows, nil
+	}
+
+	searchQuery := fmt.Sprintf(`
+		SELECT row_index, data, 
+			ts_rank(%s, websearch_to_tsquery('simple', $2)) AS rank
+		FROM dataset_data 
+		WHERE %s
+		ORDER BY rank DESC, row_index 
+		LIMIT $%d OFFSET $%d`, searchVector, matchClause, limitParam, limitParam+1)
+
+	rows, err := r.db.Query(searchQuery, append(args, limit, offset)...)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, 0, fmt.Errorf("failed to search data: %w", err)
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	// Prepare the insert statement
-	query := `
-		INSERT INTO dataset_data (dataset_id, row_index, data, created_by, updated_by)
-		VALUES ($1, $2, $3, $4, $4)`
+	for rows.Next() {
+		var result models.DataSearchResult
+		var dataJSON []byte
+
+		if err := rows.Scan(&result.RowIndex, &dataJSON, &result.Rank); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		if err := json.Unmarshal(dataJSON, &result.Data); err != nil {
+			return nil, 0, fmt.Errorf("failed to unmarshal data: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	return results, totalRows, rows.Err()
+}
 
+// BulkInsertDatasetData inserts multiple rows of CSV data using the given bulk insert mode
+func (r *SchemaRepository) BulkInsertDatasetData(datasetID uuid.UUID, headers []string, rows [][]string, userID uuid.UUID, mode string) (*models.BulkInsertReport, error) {
+	return r.BulkInsertDatasetRowsWithMode(datasetID, rowRecords(headers, rows, nil), userID, mode)
+}
+
+// rowRecords maps each row's values to the headers, filling missing values with empty strings.
+// Values matching one of nullTokens are stored as null.
+func rowRecords(headers []string, rows [][]string, nullTokens models.NullTokens) []map[string]interface{} {
+	records := make([]map[string]interface{}, len(rows))
 	for i, row := range rows {
 		// Create a map from headers to row values
 		data := make(map[string]interface{})
 		for j, header := range headers {
-			if j < len(row) {
+			if j < len(row) && nullTokens.Matches(row[j]) {
+				data[header] = nil
+			} else if j < len(row) {
 				data[header] = row[j]
 			} else {
 				data[header] = "" // Handle missing values
 			}
 		}
+		records[i] = data
+	}
+	return records
+}
+
+// CreateDatasetWithData creates a dataset and bulk-inserts its rows in one transaction, so a failed
+// insert leaves no dataset behind. The dataset is only committed when the report isn't rolled back;
+// in best-effort mode that includes datasets whose rows all failed.
+func (r *SchemaRepository) CreateDatasetWithData(dataset *models.Dataset, headers []string, rows [][]string, userID uuid.UUID, mode string) (*models.BulkInsertReport, error) {
+	if !models.IsValidBulkInsertMode(mode) {
+		return nil, fmt.Errorf("unsupported bulk insert mode: %s", mode)
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if _, err := tx.NamedExec(insertDatasetQuery, dataset); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create dataset: %w", err)
+	}
+
+	return insertDatasetRows(tx, dataset.ID, rowRecords(headers, rows, dataset.NullTokens), userID, mode)
+}
+
+// BulkInsertDatasetRows inserts rows keeping their native JSON types, so object and array cells
+// are stored as structured JSONB rather than strings. Any failed row rolls back the whole insert.
+func (r *SchemaRepository) BulkInsertDatasetRows(datasetID uuid.UUID, rows []map[string]interface{}, userID uuid.UUID) error {
+	report, err := r.BulkInsertDatasetRowsWithMode(datasetID, rows, userID, models.BulkInsertAllOrNothing)
+	if err != nil {
+		return err
+	}
+	if report.RolledBack {
+		failed := report.FailedRows[0]
+		return fmt.Errorf("failed to insert data for row %d: %s", failed.RowIndex, failed.Error)
+	}
+	return nil
+}
+
+// BulkInsertDatasetRowsWithMode inserts rows in one transaction and reports the outcome. In
+// all-or-nothing mode the first failed row rolls everything back; in best-effort mode failed rows
+// are skipped and the rest are committed. The returned error is only set when the transaction
+// itself fails.
+func (r *SchemaRepository) BulkInsertDatasetRowsWithMode(datasetID uuid.UUID, rows []map[string]interface{}, userID uuid.UUID, mode string) (*models.BulkInsertReport, error) {
+	if !models.IsValidBulkInsertMode(mode) {
+		return nil, fmt.Errorf("unsupported bulk insert mode: %s", mode)
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	return insertDatasetRows(tx, datasetID, rows, userID, mode)
+}
+
+// execTx is the part of *sqlx.Tx used to write dataset rows
+type execTx interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Commit() error
+	Rollback() error
+}
+
+// insertDatasetRows inserts rows into an open transaction and commits or rolls it back. In
+// best-effort mode each row runs under a savepoint, so a failed row doesn't abort the transaction.
+// Inserted rows get consecutive row indexes starting from 0.
+func insertDatasetRows(tx execTx, datasetID uuid.UUID, rows []map[string]interface{}, userID uuid.UUID, mode string) (*models.BulkInsertReport, error) {
+	defer tx.Rollback()
 
+	report := &models.BulkInsertReport{
+		Mode:       mode,
+		TotalRows:  len(rows),
+		FailedRows: []models.BulkInsertRowError{},
+	}
+	bestEffort := mode == models.BulkInsertBestEffort
+
+	// Prepare the insert statement
+	query := `
+		INSERT INTO dataset_data (dataset_id, row_index, data, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $4)`
+
+	for i, data := range rows {
 		// Marshal to JSON
 		dataJSON, err := json.Marshal(data)
 		if err != nil {
-			return fmt.Errorf("failed to marshal data for row %d: %w", i, err)
+			report.FailedRows = append(report.FailedRows, models.BulkInsertRowError{RowIndex: i, Error: fmt.Sprintf("failed to marshal data: %v", err)})
+			if !bestEffort {
+				break
+			}
+			continue
+		}
+
+		if bestEffort {
+			if _, err := tx.Exec("SAVEPOINT bulk_insert_row"); err != nil {
+				return nil, fmt.Errorf("failed to create savepoint for row %d: %w", i, err)
+			}
 		}
 
-		// Insert the row (row_index starts from 0)
-		_, err = tx.Exec(query, datasetID, i, dataJSON, userID)
+		// Inserted rows keep consecutive indexes, so skipped rows leave no gaps
+		_, err = tx.Exec(query, datasetID, report.InsertedRows, dataJSON, userID)
 		if err != nil {
-			return fmt.Errorf("failed to insert data for row %d: %w", i, err)
+			report.FailedRows = append(report.FailedRows, models.BulkInsertRowError{RowIndex: i, Error: err.Error()})
+			if !bestEffort {
+				break
+			}
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT bulk_insert_row"); err != nil {
+				return nil, fmt.Errorf("failed to roll back row %d: %w", i, err)
+			}
+			continue
+		}
+
+		if bestEffort {
+			if _, err := tx.Exec("RELEASE SAVEPOINT bulk_insert_row"); err != nil {
+				return nil, fmt.Errorf("failed to release savepoint for row %d: %w", i, err)
+			}
 		}
+		report.InsertedRows++
 	}
 
-	return tx.Commit()
+	if !bestEffort && len(report.FailedRows) > 0 {
+		report.InsertedRows = 0
+		report.RolledBack = true
+		return report, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit rows: %w", err)
+	}
+	return report, nil
 }
 
-// UpdateDatasetData updates or inserts a data row
-func (r *SchemaRepository) UpdateDatasetData(datasetID uuid.UUID, rowIndex int, data map[string]interface{}, userID uuid.UUID) error {
-	dataJSON, err := json.Marshal(data)
+// AppendDatasetRows appends rows after the current last row of a dataset and returns the first new row index
+func (r *SchemaRepository) AppendDatasetRows(datasetID uuid.UUID, rows []map[string]interface{}, userID uuid.UUID) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the dataset row so concurrent appends don't compute the same start index
+	if _, err := tx.Exec(`SELECT id FROM datasets WHERE id = $1 FOR UPDATE`, datasetID); err != nil {
+		return 0, fmt.Errorf("failed to lock dataset: %w", err)
+	}
+
+	startIndex, err := nextDatasetRowIndex(tx, datasetID)
 	if err != nil {
-		return fmt.Errorf("failed to marshal data: %w", err)
+		return 0, err
 	}
 
 	query := `
 		INSERT INTO dataset_data (dataset_id, row_index, data, created_by, updated_by)
-		VALUES ($1, $2, $3, $4, $4)
-		ON CONFLICT (dataset_id, row_index)
-		DO UPDATE SET 
-			data = EXCLUDED.data,
-			version = dataset_data.version + 1,
-			updated_by = EXCLUDED.updated_by,
-			updated_at = NOW()`
-	
-	_, err = r.db.Exec(query, datasetID, rowIndex, dataJSON, userID)
+		VALUES ($1, $2, $3, $4, $4)`
+
+	for i, row := range rows {
+		dataJSON, err := json.Marshal(row)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal data for row %d: %w", i, err)
+		}
+
+		if _, err := tx.Exec(query, datasetID, startIndex+i, dataJSON, userID); err != nil {
+			return 0, fmt.Errorf("failed to insert data for row %d: %w", i, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE datasets 
+		SET row_count = (SELECT COUNT(*) FROM dataset_data WHERE dataset_id = $1),
+		    updated_at = NOW()
+		WHERE id = $1`, datasetID)
 	if err != nil {
-		return fmt.Errorf("failed to update dataset data: %w", err)
+		return 0, fmt.Errorf("failed to update row count: %w", err)
 	}
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit append: %w", err)
+	}
+
+	return startIndex, nil
+}
+
+// UpdateDatasetData updates or inserts a data row and returns its new version. With an expected
+// version only an existing row at that version is updated; otherwise a *RowVersionConflictError
+// reports the row's current version.
+func (r *SchemaRepository) UpdateDatasetData(datasetID uuid.UUID, rowIndex int, data map[string]interface{}, userID uuid.UUID, expectedVersion *int) (int, error) {
+	dataJSON, err := json.Marshal(StoredRowData(data))
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	// Touch the dataset in the same statement so its updated_at tracks data changes
+	query := `
+		WITH upserted AS (
+			INSERT INTO dataset_data (dataset_id, row_index, data, created_by, updated_by)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (dataset_id, row_index)
+			DO UPDATE SET 
+				data = EXCLUDED.data,
+				version = dataset_data.version + 1,
+				updated_by = EXCLUDED.updated_by,
+				updated_at = NOW()
+			RETURNING dataset_id, version
+		), touched AS (
+			UPDATE datasets SET updated_at = NOW() WHERE id IN (SELECT dataset_id FROM upserted)
+		)
+		SELECT version FROM upserted`
+	args := []interface{}{datasetID, rowIndex, dataJSON, userID}
+	if expectedVersion != nil {
+		query = `
+		WITH upserted AS (
+			UPDATE dataset_data 
+			SET data = $3, version = version + 1, updated_by = $4, updated_at = NOW()
+			WHERE dataset_id = $1 AND row_index = $2 AND version = $5
+			RETURNING dataset_id, version
+		), touched AS (
+			UPDATE datasets SET updated_at = NOW() WHERE id IN (SELECT dataset_id FROM upserted)
+		)
+		SELECT version FROM upserted`
+		args = append(args, *expectedVersion)
+	}
+
+	var version int
+	err = r.db.Get(&version, query, args...)
+	if err == sql.ErrNoRows && expectedVersion != nil {
+		var current int
+		err = r.db.Get(&current, `SELECT version FROM dataset_data WHERE dataset_id = $1 AND row_index = $2`, datasetID, rowIndex)
+		if err != nil && err != sql.ErrNoRows {
+			return 0, fmt.Errorf("failed to get row version: %w", err)
+		}
+		return 0, &RowVersionConflictError{CurrentVersion: current}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to update dataset data: %w", err)
+	}
+
+	return version, nil
+}
+
+// TruncateDatasetData deletes all of a dataset's rows in one transaction and resets its row count,
+// keeping its schemas, business rules and submissions. It returns how many rows were deleted.
+func (r *SchemaRepository) TruncateDatasetData(datasetID uuid.UUID) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	return truncateDatasetRows(tx, datasetID)
+}
+
+func truncateDatasetRows(tx execTx, datasetID uuid.UUID) (int64, error) {
+	defer tx.Rollback()
+
+	// Lock the dataset row so a concurrent append can't land rows mid-truncate
+	if _, err := tx.Exec(`SELECT id FROM datasets WHERE id = $1 FOR UPDATE`, datasetID); err != nil {
+		return 0, fmt.Errorf("failed to lock dataset: %w", err)
+	}
+
+	result, err := tx.Exec(`DELETE FROM dataset_data WHERE dataset_id = $1`, datasetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete dataset data: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check deleted rows: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE datasets SET row_count = 0, updated_at = NOW() WHERE id = $1`, datasetID); err != nil {
+		return 0, fmt.Errorf("failed to reset row count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit truncate: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteDatasetRows deletes the rows matched by a filter or listed by index in one transaction and
+// recomputes the dataset's row count. It returns how many rows were deleted.
+func (r *SchemaRepository) DeleteDatasetRows(datasetID uuid.UUID, req *models.BulkDeleteDataRequest) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	return deleteDatasetRows(tx, datasetID, req)
+}
+
+// rowDeleteCondition returns the SQL condition, after the dataset ID as $1, selecting the rows of a bulk delete
+func rowDeleteCondition(req *models.BulkDeleteDataRequest) (string, []interface{}) {
+	if req.Filter == nil {
+		return `row_index = ANY($2)`, []interface{}{pq.Array(req.RowIndices)}
+	}
+	if req.Filter.Operator == models.RowFilterContains {
+		return `strpos(lower(data->>$2), lower($3)) > 0`, []interface{}{req.Filter.Field, req.Filter.Value}
+	}
+	return `data->>$2 = $3`, []interface{}{req.Filter.Field, req.Filter.Value}
+}
+
+func deleteDatasetRows(tx execTx, datasetID uuid.UUID, req *models.BulkDeleteDataRequest) (int64, error) {
+	defer tx.Rollback()
+
+	// Lock the dataset row so a concurrent append can't change the row count mid-delete
+	if _, err := tx.Exec(`SELECT id FROM datasets WHERE id = $1 FOR UPDATE`, datasetID); err != nil {
+		return 0, fmt.Errorf("failed to lock dataset: %w", err)
+	}
+
+	condition, args := rowDeleteCondition(req)
+	result, err := tx.Exec(`DELETE FROM dataset_data WHERE dataset_id = $1 AND `+condition, append([]interface{}{datasetID}, args...)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete dataset data: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check deleted rows: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE datasets 
+		SET row_count = (SELECT COUNT(*) FROM dataset_data WHERE dataset_id = $1),
+		    updated_at = NOW()
+		WHERE id = $1`, datasetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update row count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit delete: %w", err)
+	}
+	return deleted, nil
 }
 
 // DeleteDatasetData deletes a data row
 func (r *SchemaRepository) DeleteDatasetData(datasetID uuid.UUID, rowIndex int) error {
-	query := `DELETE FROM dataset_data WHERE dataset_id = $1 AND row_index = $2`
+	query := `
+		WITH deleted AS (
+			DELETE FROM dataset_data WHERE dataset_id = $1 AND row_index = $2
+			RETURNING dataset_id
+		)
+		UPDATE datasets SET updated_at = NOW() WHERE id IN (SELECT dataset_id FROM deleted)`
 	_, err := r.db.Exec(query, datasetID, rowIndex)
 	if err != nil {
 		return fmt.Errorf("failed to delete dataset data: %w", err)
@@ -518,41 +1197,142 @@ func (r *SchemaRepository) DeleteDatasetData(datasetID uuid.UUID, rowIndex int)
 	return nil
 }
 
-// CheckDatasetAccess checks if user has access to dataset
-func (r *SchemaRepository) CheckDatasetAccess(datasetID, userID uuid.UUID) (bool, error) {
+// IsDatasetOwner checks if user owns the project containing the dataset
+func (r *SchemaRepository) IsDatasetOwner(datasetID, userID uuid.UUID) (bool, error) {
 	query := `
 		SELECT COUNT(*) 
 		FROM datasets d 
 		JOIN projects p ON d.project_id = p.id 
-		WHERE d.id = $1 AND (p.owner_id = $2 OR EXISTS (
-			SELECT 1 FROM project_members pm 
-			WHERE pm.project_id = p.id AND pm.user_id = $2
-		))`
-	
+		WHERE d.id = $1 AND p.owner_id = $2`
+
 	var count int
-	err := r.db.Get(&count, query, datasetID, userID)
-	if err != nil {
-		return false, fmt.Errorf("failed to check dataset access: %w", err)
+	if err := r.db.Get(&count, query, datasetID, userID); err != nil {
+		return false, fmt.Errorf("failed to check dataset ownership: %w", err)
 	}
-	
 	return count > 0, nil
 }
 
+// CheckDatasetEditAccess checks if a user can change a dataset's existing data
+func (r *SchemaRepository) CheckDatasetEditAccess(datasetID, userID uuid.UUID) (bool, error) {
+	return checkDatasetEditAccess(r.db, datasetID, userID)
+}
+
+// CheckDatasetAccess checks if user has access to dataset
+func (r *SchemaRepository) CheckDatasetAccess(datasetID, userID uuid.UUID) (bool, error) {
+	return checkDatasetAccess(r.db, datasetID, userID)
+}
+
 // GetDatasetByID retrieves dataset information by ID
 func (r *SchemaRepository) GetDatasetByID(datasetID uuid.UUID) (*models.Dataset, error) {
 	query := `SELECT id, project_id, name, description, file_name, file_path, file_size, 
-			  mime_type, row_count, column_count, status, uploaded_by, created_at, updated_at 
+			  mime_type, row_count, column_count, status, is_trusted, display_field, csv_dialect, column_order, pii_guardrails, header_mode, enforce_schema, null_tokens, uploaded_by, created_at, updated_at 
 			  FROM datasets WHERE id = $1`
 	
 	var dataset models.Dataset
 	err := r.db.Get(&dataset, query, datasetID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDatasetNotFound
+		}
 		return nil, fmt.Errorf("failed to get dataset: %w", err)
 	}
 	
 	return &dataset, nil
 }
 
+// GetDatasetCSVDialect retrieves the CSV dialect configured on a dataset; nil means the standard dialect
+func (r *SchemaRepository) GetDatasetCSVDialect(datasetID uuid.UUID) (*models.CSVDialect, error) {
+	var dialect *models.CSVDialect
+	err := r.db.Get(&dialect, `SELECT csv_dialect FROM datasets WHERE id = $1`, datasetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDatasetNotFound
+		}
+		return nil, fmt.Errorf("failed to get CSV dialect: %w", err)
+	}
+	return dialect, nil
+}
+
+// GetDatasetHeaderMode retrieves whether a dataset rejects or ignores columns that aren't schema fields
+func (r *SchemaRepository) GetDatasetHeaderMode(datasetID uuid.UUID) (string, error) {
+	var mode string
+	err := r.db.Get(&mode, `SELECT header_mode FROM datasets WHERE id = $1`, datasetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrDatasetNotFound
+		}
+		return "", fmt.Errorf("failed to get header mode: %w", err)
+	}
+	return mode, nil
+}
+
+// GetDatasetNullTokens retrieves the cell values a dataset reads as null
+func (r *SchemaRepository) GetDatasetNullTokens(datasetID uuid.UUID) (models.NullTokens, error) {
+	var tokens models.NullTokens
+	err := r.db.Get(&tokens, `SELECT null_tokens FROM datasets WHERE id = $1`, datasetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDatasetNotFound
+		}
+		return nil, fmt.Errorf("failed to get null tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// GetDatasetPIIGuardrails retrieves the contact fields of a dataset guarded against duplicates
+func (r *SchemaRepository) GetDatasetPIIGuardrails(datasetID uuid.UUID) (models.PIIGuardrails, error) {
+	var guardrails models.PIIGuardrails
+	err := r.db.Get(&guardrails, `SELECT pii_guardrails FROM datasets WHERE id = $1`, datasetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDatasetNotFound
+		}
+		return nil, fmt.Errorf("failed to get PII guardrails: %w", err)
+	}
+	return guardrails, nil
+}
+
+// GetDatasetDisplayRowCap retrieves the row display cap of the project owning a dataset; nil means
+// the project uses the server default
+func (r *SchemaRepository) GetDatasetDisplayRowCap(datasetID uuid.UUID) (*int, error) {
+	query := `
+		SELECT p.max_display_rows
+		FROM datasets d
+		JOIN projects p ON p.id = d.project_id
+		WHERE d.id = $1`
+
+	var maxDisplayRows *int
+	err := r.db.Get(&maxDisplayRows, query, datasetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrDatasetNotFound
+		}
+		return nil, fmt.Errorf("failed to get display row cap: %w", err)
+	}
+	return maxDisplayRows, nil
+}
+
+// GetDatasetLastModified returns when a dataset's data or schema last changed
+func (r *SchemaRepository) GetDatasetLastModified(datasetID uuid.UUID) (time.Time, error) {
+	query := `
+		SELECT GREATEST(d.updated_at, COALESCE(MAX(s.updated_at), d.updated_at))
+		FROM datasets d
+		LEFT JOIN dataset_schemas s ON s.dataset_id = d.id
+		WHERE d.id = $1
+		GROUP BY d.id, d.updated_at`
+
+	var lastModified time.Time
+	err := r.db.Get(&lastModified, query, datasetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, ErrDatasetNotFound
+		}
+		return time.Time{}, fmt.Errorf("failed to get dataset last modified time: %w", err)
+	}
+
+	return lastModified, nil
+}
+
 // GetDatasetDataForInference retrieves dataset headers and sample data for schema inference
 func (r *SchemaRepository) GetDatasetDataForInference(datasetID uuid.UUID, maxRows int) ([]string, [][]string, error) {
 	// Get sample data rows
@@ -614,3 +1394,235 @@ func (r *SchemaRepository) GetDatasetDataForInference(datasetID uuid.UUID, maxRo
 	
 	return headers, rows, nil
 }
+
+// storedFieldText is the text of a stored field value ($2) compared by FindExistingFieldValues.
+// Numbers are written in plain decimal without trailing zeros, matching services.fieldValueText.
+const storedFieldText = `CASE WHEN jsonb_typeof(data->$2) = 'number'
+			THEN trim_scale((data->$2)::numeric)::text
+			ELSE data->>$2 END`
+
+// FindExistingFieldValues returns the row index of stored rows whose field value matches one of the given values
+func (r *SchemaRepository) FindExistingFieldValues(datasetID uuid.UUID, fieldName string, values []string) (map[string]int, error) {
+	existing := make(map[string]int)
+	if len(values) == 0 {
+		return existing, nil
+	}
+
+	query := `
+		SELECT row_index, ` + storedFieldText + `
+		FROM dataset_data
+		WHERE dataset_id = $1 AND ` + storedFieldText + ` = ANY($3)
+		ORDER BY row_index`
+
+	rows, err := r.db.Query(query, datasetID, fieldName, pq.Array(values))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up existing values: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rowIndex int
+		var value string
+		if err := rows.Scan(&rowIndex, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan existing value: %w", err)
+		}
+
+		// Keep the first stored occurrence of each value
+		if _, seen := existing[value]; !seen {
+			existing[value] = rowIndex
+		}
+	}
+
+	return existing, rows.Err()
+}
+
+// piiNormalizations are the SQL forms of services.NormalizePII, applied to a stored field value ($2)
+var piiNormalizations = map[string]string{
+	models.PIIKindEmail: `lower(btrim(data->>$2))`,
+	models.PIIKindPhone: `regexp_replace(data->>$2, '[^0-9]', '', 'g')`,
+}
+
+// FindExistingPIIValues returns the row index of stored rows whose field value, normalized for the
+// PII kind, matches one of the given normalized values
+func (r *SchemaRepository) FindExistingPIIValues(datasetID uuid.UUID, fieldName, kind string, values []string) (map[string]int, error) {
+	existing := make(map[string]int)
+	if len(values) == 0 {
+		return existing, nil
+	}
+
+	normalized, ok := piiNormalizations[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported PII kind %q", kind)
+	}
+
+	query := `
+		SELECT row_index, ` + normalized + `
+		FROM dataset_data
+		WHERE dataset_id = $1 AND ` + normalized + ` = ANY($3)
+		ORDER BY row_index`
+
+	rows, err := r.db.Query(query, datasetID, fieldName, pq.Array(values))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up existing PII values: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rowIndex int
+		var value string
+		if err := rows.Scan(&rowIndex, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan existing PII value: %w", err)
+		}
+
+		// Keep the first stored occurrence of each value
+		if _, seen := existing[value]; !seen {
+			existing[value] = rowIndex
+		}
+	}
+
+	return existing, rows.Err()
+}
+
+// GetAllDatasetRows returns every stored row of a dataset in row order
+func (r *SchemaRepository) GetAllDatasetRows(datasetID uuid.UUID) ([]map[string]interface{}, error) {
+	query := `SELECT data FROM dataset_data WHERE dataset_id = $1 ORDER BY row_index`
+
+	rows, err := r.db.Query(query, datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dataset rows: %w", err)
+	}
+	defer rows.Close()
+
+	var data []map[string]interface{}
+	for rows.Next() {
+		var dataJSON []byte
+		if err := rows.Scan(&dataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan data row: %w", err)
+		}
+
+		var row map[string]interface{}
+		if err := json.Unmarshal(dataJSON, &row); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data row: %w", err)
+		}
+		data = append(data, row)
+	}
+
+	return data, rows.Err()
+}
+
+// GetDatasetRowsAfter returns up to limit rows of a dataset whose row index is above afterRowIndex,
+// in row order, along with the last row index returned so callers can page through every row
+func (r *SchemaRepository) GetDatasetRowsAfter(datasetID uuid.UUID, afterRowIndex, limit int) ([]map[string]interface{}, int, error) {
+	query := `SELECT row_index, data FROM dataset_data
+		WHERE dataset_id = $1 AND row_index > $2
+		ORDER BY row_index
+		LIMIT $3`
+
+	rows, err := r.db.Query(query, datasetID, afterRowIndex, limit)
+	if err != nil {
+		return nil, afterRowIndex, fmt.Errorf("failed to get dataset rows: %w", err)
+	}
+	defer rows.Close()
+
+	data := []map[string]interface{}{}
+	lastRowIndex := afterRowIndex
+	for rows.Next() {
+		var dataJSON []byte
+		if err := rows.Scan(&lastRowIndex, &dataJSON); err != nil {
+			return nil, afterRowIndex, fmt.Errorf("failed to scan data row: %w", err)
+		}
+
+		var row map[string]interface{}
+		if err := json.Unmarshal(dataJSON, &row); err != nil {
+			return nil, afterRowIndex, fmt.Errorf("failed to unmarshal data row: %w", err)
+		}
+		data = append(data, row)
+	}
+
+	return data, lastRowIndex, rows.Err()
+}
+
+// SaveFieldStats replaces the stored field statistics of a dataset
+func (r *SchemaRepository) SaveFieldStats(stats *models.DatasetFieldStats) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM dataset_field_stats WHERE dataset_id = $1`, stats.DatasetID); err != nil {
+		return fmt.Errorf("failed to clear field stats: %w", err)
+	}
+
+	query := `
+		INSERT INTO dataset_field_stats (dataset_id, field_name, total_values, unique_values, null_values, invalid_values, distinct_values, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	for name, fieldStats := range stats.Fields {
+		// High-cardinality fields track no values and store NULL
+		var distinctValues interface{}
+		if len(fieldStats.Values) > 0 {
+			valuesJSON, err := json.Marshal(fieldStats.Values)
+			if err != nil {
+				return fmt.Errorf("failed to encode values of field %s: %w", name, err)
+			}
+			distinctValues = string(valuesJSON)
+		}
+
+		_, err := tx.Exec(query, stats.DatasetID, name, fieldStats.TotalValues, fieldStats.UniqueValues,
+			fieldStats.NullValues, fieldStats.InvalidValues, distinctValues, stats.ComputedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save stats for field %s: %w", name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetFieldStats returns the stored field statistics of a dataset
+func (r *SchemaRepository) GetFieldStats(datasetID uuid.UUID) (*models.DatasetFieldStats, error) {
+	query := `
+		SELECT field_name, total_values, unique_values, null_values, invalid_values, distinct_values, computed_at
+		FROM dataset_field_stats
+		WHERE dataset_id = $1`
+
+	rows, err := r.db.Query(query, datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get field stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.DatasetFieldStats{
+		DatasetID: datasetID,
+		Fields:    make(map[string]models.FieldStats),
+	}
+	for rows.Next() {
+		var name string
+		var fieldStats models.FieldStats
+		var distinctValues []byte
+		err := rows.Scan(&name, &fieldStats.TotalValues, &fieldStats.UniqueValues,
+			&fieldStats.NullValues, &fieldStats.InvalidValues, &distinctValues, &stats.ComputedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan field stats: %w", err)
+		}
+		if distinctValues != nil {
+			if err := json.Unmarshal(distinctValues, &fieldStats.Values); err != nil {
+				return nil, fmt.Errorf("failed to decode values of field %s: %w", name, err)
+			}
+		}
+
+		stats.Fields[name] = fieldStats
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read field stats: %w", err)
+	}
+
+	if len(stats.Fields) == 0 {
+		return nil, ErrFieldStatsNotFound
+	}
+
+	if err := r.db.Get(&stats.RowCount, `SELECT COUNT(*) FROM dataset_data WHERE dataset_id = $1`, datasetID); err != nil {
+		return nil, fmt.Errorf("failed to count dataset rows: %w", err)
+	}
+	return stats, nil
+}
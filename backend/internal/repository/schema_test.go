@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBulkInsertTx mimics a transaction with savepoints: inserts are pending until commit, and
+// rows whose JSON contains "fail" are rejected
+type fakeBulkInsertTx struct {
+	pending    []int
+	committed  []int
+	savepoints int
+	committedN int
+	rolledBack bool
+}
+
+func (f *fakeBulkInsertTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	switch {
+	case strings.HasPrefix(query, "SAVEPOINT"):
+		f.savepoints++
+	case strings.HasPrefix(query, "ROLLBACK TO SAVEPOINT"), strings.HasPrefix(query, "RELEASE SAVEPOINT"):
+	default:
+		if strings.Contains(string(args[2].([]byte)), "fail") {
+			return nil, errors.New("violates check constraint")
+		}
+		f.pending = append(f.pending, args[1].(int))
+	}
+	return nil, nil
+}
+
+func (f *fakeBulkInsertTx) Commit() error {
+	f.committed = append(f.committed, f.pending...)
+	f.committedN++
+	return nil
+}
+
+func (f *fakeBulkInsertTx) Rollback() error {
+	if f.committedN == 0 {
+		f.rolledBack = true
+	}
+	return nil
+}
+
+func bulkInsertRows() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"name": "Ann"},
+		{"name": "fail"},
+		{"name": "Bob"},
+		{"name": "fail"},
+		{"name": "Cy"},
+	}
+}
+
+func TestInsertDatasetRows_AllOrNothingRollsBack(t *testing.T) {
+	tx := &fakeBulkInsertTx{}
+
+	report, err := insertDatasetRows(tx, uuid.New(), bulkInsertRows(), uuid.New(), models.BulkInsertAllOrNothing)
+	require.NoError(t, err)
+
+	assert.True(t, report.RolledBack)
+	assert.Equal(t, 5, report.TotalRows)
+	assert.Equal(t, 0, report.InsertedRows)
+	require.Len(t, report.FailedRows, 1)
+	assert.Equal(t, 1, report.FailedRows[0].RowIndex)
+	assert.Contains(t, report.FailedRows[0].Error, "check constraint")
+
+	assert.True(t, tx.rolledBack)
+	assert.Empty(t, tx.committed)
+	assert.Zero(t, tx.savepoints)
+}
+
+func TestInsertDatasetRows_BestEffortCommitsGoodRows(t *testing.T) {
+	tx := &fakeBulkInsertTx{}
+
+	report, err := insertDatasetRows(tx, uuid.New(), bulkInsertRows(), uuid.New(), models.BulkInsertBestEffort)
+	require.NoError(t, err)
+
+	assert.False(t, report.RolledBack)
+	assert.Equal(t, 5, report.TotalRows)
+	assert.Equal(t, 3, report.InsertedRows)
+	require.Len(t, report.FailedRows, 2)
+	assert.Equal(t, 1, report.FailedRows[0].RowIndex)
+	assert.Equal(t, 3, report.FailedRows[1].RowIndex)
+
+	// Committed rows keep consecutive indexes despite the skipped ones
+	assert.False(t, tx.rolledBack)
+	assert.Equal(t, []int{0, 1, 2}, tx.committed)
+	assert.Equal(t, 5, tx.savepoints)
+}
+
+// fakeDatasetTables holds one dataset's stored state; statements only take effect on commit
+type fakeDatasetTables struct {
+	dataRows   int
+	rowCount   int
+	schemas    int
+	rules      int
+	statements []string
+
+	pendingDelete bool
+	pendingReset  bool
+	committed     bool
+}
+
+func (f *fakeDatasetTables) Exec(query string, args ...interface{}) (sql.Result, error) {
+	f.statements = append(f.statements, query)
+	switch {
+	case strings.HasPrefix(query, "DELETE FROM dataset_data"):
+		f.pendingDelete = true
+		return driver.RowsAffected(f.dataRows), nil
+	case strings.HasPrefix(query, "UPDATE datasets SET row_count = 0"):
+		f.pendingReset = true
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (f *fakeDatasetTables) Commit() error {
+	if f.pendingDelete {
+		f.dataRows = 0
+	}
+	if f.pendingReset {
+		f.rowCount = 0
+	}
+	f.committed = true
+	return nil
+}
+
+func (f *fakeDatasetTables) Rollback() error {
+	return nil
+}
+
+func TestTruncateDatasetRows_ClearsDataKeepsSchema(t *testing.T) {
+	tables := &fakeDatasetTables{dataRows: 42, rowCount: 42, schemas: 2, rules: 3}
+
+	deleted, err := truncateDatasetRows(tables, uuid.New())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), deleted)
+	assert.True(t, tables.committed)
+	assert.Zero(t, tables.dataRows)
+	assert.Zero(t, tables.rowCount)
+	assert.Equal(t, 2, tables.schemas)
+	assert.Equal(t, 3, tables.rules)
+
+	// Only dataset rows and the row count are touched
+	for _, statement := range tables.statements {
+		assert.NotContains(t, statement, "dataset_schemas")
+		assert.NotContains(t, statement, "dataset_business_rules")
+		assert.NotContains(t, statement, "data_submissions")
+	}
+}
+
+func TestDeleteDatasetRows_RecountsInOneTransaction(t *testing.T) {
+	tables := &fakeDatasetTables{dataRows: 3, rowCount: 10}
+	datasetID := uuid.New()
+
+	deleted, err := deleteDatasetRows(tables, datasetID, &models.BulkDeleteDataRequest{RowIndices: []int{2, 5, 7}})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), deleted)
+	assert.True(t, tables.committed)
+	require.Len(t, tables.statements, 3)
+	assert.Contains(t, tables.statements[0], "FOR UPDATE")
+	assert.Contains(t, tables.statements[1], "row_index = ANY($2)")
+	assert.Contains(t, tables.statements[2], "row_count = (SELECT COUNT(*)")
+}
+
+func TestRowDeleteCondition(t *testing.T) {
+	condition, args := rowDeleteCondition(&models.BulkDeleteDataRequest{
+		Filter: &models.RowFilter{Field: "status", Operator: models.RowFilterEquals, Value: "void"},
+	})
+	assert.Equal(t, `data->>$2 = $3`, condition)
+	assert.Equal(t, []interface{}{"status", "void"}, args)
+
+	condition, args = rowDeleteCondition(&models.BulkDeleteDataRequest{
+		Filter: &models.RowFilter{Field: "email", Operator: models.RowFilterContains, Value: "100%"},
+	})
+	assert.Equal(t, `strpos(lower(data->>$2), lower($3)) > 0`, condition, "contains matches literally, without LIKE wildcards")
+	assert.Equal(t, []interface{}{"email", "100%"}, args)
+}
+
+func TestOrderDataColumns(t *testing.T) {
+	data := []map[string]interface{}{
+		{"_row_index": 1, "name": "a", "id": "1", "city": "x"},
+		{"_row_index": 2, "name": "b", "id": "2", "city": "y", "zip": "1000"},
+	}
+
+	t.Run("schema position wins over header order", func(t *testing.T) {
+		schema := &models.DatasetSchema{Fields: []models.SchemaField{
+			{Name: "city", Position: 3},
+			{Name: "id", Position: 1},
+			{Name: "name", Position: 2},
+		}}
+		columns := orderDataColumns(schema, []string{"name", "city", "id"}, data)
+		assert.Equal(t, []string{"id", "name", "city", "zip"}, columns)
+	})
+
+	t.Run("header order without schema", func(t *testing.T) {
+		columns := orderDataColumns(nil, []string{"name", "id", "city"}, data)
+		assert.Equal(t, []string{"name", "id", "city", "zip"}, columns)
+	})
+
+	t.Run("name order without schema or header order", func(t *testing.T) {
+		columns := orderDataColumns(&models.DatasetSchema{}, nil, data)
+		assert.Equal(t, []string{"city", "id", "name", "zip"}, columns)
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		assert.Equal(t, []string{}, orderDataColumns(nil, nil, nil))
+	})
+}
+
+func TestDisplayWindow(t *testing.T) {
+	tests := []struct {
+		name                      string
+		page, pageSize, maxRows   int
+		expectOffset, expectLimit int
+	}{
+		{"first page", 1, 50, 1000, 0, 50},
+		{"last full page", 20, 50, 1000, 950, 50},
+		{"beyond the cap", 21, 50, 1000, 1000, 0},
+		{"partial last page", 34, 30, 1000, 990, 10},
+		{"cap smaller than a page", 1, 100, 40, 0, 40},
+		{"large cap", 100, 100, 10000, 9900, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, limit := displayWindow(tt.page, tt.pageSize, tt.maxRows)
+			assert.Equal(t, tt.expectOffset, offset)
+			assert.Equal(t, tt.expectLimit, limit)
+		})
+	}
+}
+
+func TestPageCount(t *testing.T) {
+	assert.Equal(t, 0, pageCount(0, 50))
+	assert.Equal(t, 20, pageCount(1000, 50))
+	assert.Equal(t, 34, pageCount(1000, 30))
+	assert.Equal(t, 1, pageCount(40, 100))
+}
+
+func TestRowMeta_Apply(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	row := map[string]interface{}{"name": "a"}
+	rowMeta{
+		CreatedBy: sql.NullString{String: "Ada", Valid: true},
+		CreatedAt: sql.NullTime{Time: created, Valid: true},
+	}.apply(row)
+
+	assert.Equal(t, map[string]interface{}{
+		"name":        "a",
+		"_created_by": "Ada",
+		"_updated_by": nil,
+		"_created_at": created,
+		"_updated_at": nil,
+	}, row)
+
+	columns := orderDataColumns(nil, []string{"name"}, []map[string]interface{}{row})
+	assert.Equal(t, []string{"name"}, columns, "row metadata is not reported as columns")
+}
+
+// recordingExecer records the arguments of each statement it is given
+type recordingExecer struct {
+	args [][]interface{}
+}
+
+func (r *recordingExecer) Exec(query string, args ...interface{}) (sql.Result, error) {
+	r.args = append(r.args, args)
+	return driver.RowsAffected(1), nil
+}
+
+func TestInsertSchema_WithoutDateFormats(t *testing.T) {
+	tx := &recordingExecer{}
+	schema := &models.DatasetSchema{ID: uuid.New(), DatasetID: uuid.New(), Name: "default", IsDefault: true}
+
+	require.NoError(t, insertSchema(tx, schema))
+	require.Len(t, tx.args, 1)
+
+	// date_formats is NOT NULL, so a schema created without formats stores an empty array
+	formats, ok := tx.args[0][4].(driver.Valuer)
+	require.True(t, ok)
+	value, err := formats.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "{}", value)
+
+	schema.DateFormats = []string{"02/01/2006"}
+	require.NoError(t, insertSchema(tx, schema))
+	value, err = tx.args[1][4].(driver.Valuer).Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{"02/01/2006"}`, value)
+}
+
+func TestStoredRowData(t *testing.T) {
+	row := map[string]interface{}{"name": "a", "_row_index": 3, "_version": 2, "_updated_by": "Ada", "_label": "a"}
+	assert.Equal(t, map[string]interface{}{"name": "a"}, StoredRowData(row))
+	assert.Len(t, row, 5, "the payload is not modified")
+}
+
+func TestRowVersionConflictError(t *testing.T) {
+	var err error = &RowVersionConflictError{CurrentVersion: 4}
+	var conflictErr *RowVersionConflictError
+	require.True(t, errors.As(err, &conflictErr))
+	assert.Equal(t, 4, conflictErr.CurrentVersion)
+	assert.EqualError(t, err, "row version conflict: the row is at version 4")
+}
+
+func TestRowRecords_NullTokens(t *testing.T) {
+	records := rowRecords([]string{"city", "population", "area"}, [][]string{{"Oslo", "N/A"}, {"N/A city", " - ", "454"}}, models.NullTokens{"N/A", "-"})
+	assert.Equal(t, []map[string]interface{}{
+		{"city": "Oslo", "population": nil, "area": ""},
+		{"city": "N/A city", "population": nil, "area": "454"},
+	}, records)
+}
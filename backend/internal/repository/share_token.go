@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ErrShareTokenNotFound is returned when no share token matches the given ID
+var ErrShareTokenNotFound = errors.New("share token not found")
+
+// shareTokenColumns are the dataset_share_tokens columns scanned into models.ShareToken
+const shareTokenColumns = `id, dataset_id, name, permission, created_by, expires_at,
+			last_used_at, revoked_at, created_at`
+
+// ShareTokenRepository handles database operations for dataset share tokens
+type ShareTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewShareTokenRepository creates a new share token repository
+func NewShareTokenRepository(db *sqlx.DB) *ShareTokenRepository {
+	return &ShareTokenRepository{db: db}
+}
+
+// Create stores a share token grant
+func (r *ShareTokenRepository) Create(token *models.ShareToken) error {
+	query := `
+		INSERT INTO dataset_share_tokens (id, dataset_id, name, permission, created_by, expires_at, created_at)
+		VALUES (:id, :dataset_id, :name, :permission, :created_by, :expires_at, :created_at)`
+
+	if _, err := r.db.NamedExec(query, token); err != nil {
+		return fmt.Errorf("failed to create share token: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a share token grant
+func (r *ShareTokenRepository) GetByID(id uuid.UUID) (*models.ShareToken, error) {
+	var token models.ShareToken
+	err := r.db.Get(&token, `SELECT `+shareTokenColumns+` FROM dataset_share_tokens WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrShareTokenNotFound
+		}
+		return nil, fmt.Errorf("failed to get share token: %w", err)
+	}
+	return &token, nil
+}
+
+// ListByDataset returns a dataset's share tokens, revoked and expired ones included, newest first
+func (r *ShareTokenRepository) ListByDataset(datasetID uuid.UUID) ([]models.ShareToken, error) {
+	query := `
+		SELECT ` + shareTokenColumns + `
+		FROM dataset_share_tokens
+		WHERE dataset_id = $1
+		ORDER BY created_at DESC`
+
+	tokens := []models.ShareToken{}
+	if err := r.db.Select(&tokens, query, datasetID); err != nil {
+		return nil, fmt.Errorf("failed to list share tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke revokes an active share token of the dataset
+func (r *ShareTokenRepository) Revoke(datasetID, id uuid.UUID) error {
+	result, err := r.db.Exec(`
+		UPDATE dataset_share_tokens SET revoked_at = $1
+		WHERE id = $2 AND dataset_id = $3 AND revoked_at IS NULL`, time.Now(), id, datasetID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke share token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrShareTokenNotFound
+	}
+	return nil
+}
+
+// MarkUsed records the time a share token was last used
+func (r *ShareTokenRepository) MarkUsed(id uuid.UUID) error {
+	_, err := r.db.Exec(`UPDATE dataset_share_tokens SET last_used_at = $1 WHERE id = $2`, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update share token usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+)
+
+// MaxBulkBusinessRules caps how many rules one bulk import may contain
+const MaxBulkBusinessRules = 100
+
+// ErrInvalidBulkRules is returned when a bulk import contains invalid rules; none of its rules are created
+var ErrInvalidBulkRules = errors.New("bulk import contains invalid rules")
+
+// ruleFieldTypes are the rule types whose config targets a single field_name
+var ruleFieldTypes = map[string]bool{
+	models.RuleTypeFieldValidation: true,
+	models.RuleTypeRangeCheck:      true,
+	models.RuleTypeUnique:          true,
+	models.RuleTypeRequired:        true,
+	models.RuleTypeReference:       true,
+}
+
+// ValidateBusinessRuleDefinition checks a rule's name, type and config, and that every field it
+// references exists in schema. It returns one message per problem found.
+func ValidateBusinessRuleDefinition(def models.BusinessRuleDefinition, schema *models.DatasetSchema) []string {
+	var problems []string
+	if strings.TrimSpace(def.RuleName) == "" {
+		problems = append(problems, "rule_name is required")
+	}
+	if strings.TrimSpace(def.ErrorMessage) == "" {
+		problems = append(problems, "error_message is required")
+	}
+
+	fields := make(map[string]bool, len(schema.Fields))
+	for _, field := range schema.Fields {
+		fields[field.Name] = true
+	}
+	checkField := func(name string) {
+		if !fields[name] {
+			problems = append(problems, fmt.Sprintf("field '%s' does not exist in the dataset schema", name))
+		}
+	}
+
+	config := def.RuleConfig
+	if ruleFieldTypes[def.RuleType] {
+		if config.FieldName == "" {
+			problems = append(problems, fmt.Sprintf("rule_config.field_name is required for %s rules", def.RuleType))
+		} else {
+			checkField(config.FieldName)
+		}
+	}
+
+	switch def.RuleType {
+	case models.RuleTypeFieldValidation:
+		if config.Pattern != "" {
+			if _, err := regexp.Compile(config.Pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("rule_config.pattern is not a valid regular expression: %v", err))
+			}
+		}
+	case models.RuleTypeRangeCheck:
+		minValue, hasMin := config.MinValue.(float64)
+		maxValue, hasMax := config.MaxValue.(float64)
+		switch {
+		case config.MinValue != nil && !hasMin, config.MaxValue != nil && !hasMax:
+			problems = append(problems, "rule_config.min_value and max_value must be numbers")
+		case !hasMin && !hasMax:
+			problems = append(problems, "range_check rules need rule_config.min_value or max_value")
+		case hasMin && hasMax && minValue > maxValue:
+			problems = append(problems, "rule_config.min_value is greater than max_value")
+		}
+	case models.RuleTypeCrossField:
+		if len(config.Fields) < 2 {
+			problems = append(problems, "cross_field rules need at least two rule_config.fields")
+		}
+		for _, name := range config.Fields {
+			checkField(name)
+		}
+		if strings.TrimSpace(config.Condition) == "" {
+			problems = append(problems, "rule_config.condition is required for cross_field rules")
+		} else if _, err := parseRuleCondition(config.Condition); err != nil {
+			problems = append(problems, fmt.Sprintf("rule_config.condition is not valid: %v", err))
+		}
+	case models.RuleTypeCustomSQL:
+		if strings.TrimSpace(config.Query) == "" {
+			problems = append(problems, "rule_config.query is required for custom_sql rules")
+		}
+	case models.RuleTypeRequired:
+		if strings.TrimSpace(config.Condition) != "" {
+			if _, err := parseRuleCondition(config.Condition); err != nil {
+				problems = append(problems, fmt.Sprintf("rule_config.condition is not valid: %v", err))
+			}
+		}
+	case models.RuleTypeReference:
+		if _, err := uuid.Parse(config.TargetDatasetID); err != nil {
+			problems = append(problems, "rule_config.target_dataset_id must be a dataset ID for reference rules")
+		}
+		if strings.TrimSpace(config.TargetField) == "" {
+			problems = append(problems, "rule_config.target_field is required for reference rules")
+		}
+	case models.RuleTypeUnique:
+	default:
+		problems = append(problems, fmt.Sprintf("unsupported rule_type '%s'", def.RuleType))
+	}
+
+	return problems
+}
+
+// CreateBusinessRulesBulk validates every definition against schema and creates them all in one
+// transaction. If any rule is invalid nothing is created and ErrInvalidBulkRules is returned with
+// the invalid rules reported; if the import would take the dataset past limit active rules a
+// RuleLimitError is returned. Results are in request order, and the returned count is the
+// dataset's active rule count afterwards. A limit of zero or less disables the cap.
+func CreateBusinessRulesBulk(repo BusinessRuleRepositoryInterface, datasetID, userID uuid.UUID, defs []models.BusinessRuleDefinition, schema *models.DatasetSchema, limit int) ([]models.BulkRuleResult, int, error) {
+	results := make([]models.BulkRuleResult, len(defs))
+	rules := make([]*models.DatasetBusinessRule, len(defs))
+	names := make(map[string]int, len(defs))
+	invalid := false
+	now := time.Now()
+
+	for i, def := range defs {
+		results[i] = models.BulkRuleResult{Index: i, RuleName: def.RuleName, Status: models.BulkRuleStatusNotCreated}
+
+		problems := ValidateBusinessRuleDefinition(def, schema)
+		if first, ok := names[def.RuleName]; ok && def.RuleName != "" {
+			problems = append(problems, fmt.Sprintf("rule_name duplicates rule %d", first))
+		} else {
+			names[def.RuleName] = i
+		}
+		if len(problems) > 0 {
+			results[i].Status = models.BulkRuleStatusInvalid
+			results[i].Errors = problems
+			invalid = true
+			continue
+		}
+
+		config, err := json.Marshal(def.RuleConfig)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode config of rule %d: %w", i, err)
+		}
+		rules[i] = &models.DatasetBusinessRule{
+			ID:           uuid.New(),
+			DatasetID:    datasetID,
+			RuleName:     def.RuleName,
+			RuleType:     def.RuleType,
+			RuleConfig:   config,
+			ErrorMessage: def.ErrorMessage,
+			IsActive:     true,
+			Priority:     def.Priority,
+			StopOnFail:   def.StopOnFail,
+			CreatedBy:    userID,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+	}
+
+	var count int
+	err := repo.WithBusinessRulesLocked(datasetID, func(store repository.BusinessRuleStore) error {
+		var err error
+		count, err = store.CountActiveBusinessRules(datasetID)
+		if err != nil {
+			return fmt.Errorf("failed to count business rules: %w", err)
+		}
+		if invalid {
+			return ErrInvalidBulkRules
+		}
+		if limit > 0 && count+len(rules) > limit {
+			return &RuleLimitError{Limit: limit, Count: count}
+		}
+		return store.CreateBusinessRules(rules)
+	})
+	if err != nil {
+		var limitErr *RuleLimitError
+		if errors.Is(err, ErrInvalidBulkRules) || errors.As(err, &limitErr) {
+			return results, count, err
+		}
+		return nil, count, err
+	}
+
+	for i, rule := range rules {
+		results[i].Status = models.BulkRuleStatusCreated
+		results[i].Rule = rule
+	}
+	return results, count + len(rules), nil
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *fakeBusinessRuleRepository) CreateBusinessRules(rules []*models.DatasetBusinessRule) error {
+	f.rules = append(f.rules, rules...)
+	return nil
+}
+
+var bulkRuleSchema = &models.DatasetSchema{Fields: []models.SchemaField{
+	{Name: "email", DataType: "email"},
+	{Name: "age", DataType: "integer"},
+	{Name: "start", DataType: "date"},
+	{Name: "end", DataType: "date"},
+}}
+
+func validBulkRules() []models.BusinessRuleDefinition {
+	minAge, maxAge := 18.0, 120.0
+	return []models.BusinessRuleDefinition{
+		{RuleName: "unique email", RuleType: models.RuleTypeUnique, RuleConfig: models.BusinessRuleConfig{FieldName: "email"}, ErrorMessage: "Email must be unique"},
+		{RuleName: "adult", RuleType: models.RuleTypeRangeCheck, RuleConfig: models.BusinessRuleConfig{FieldName: "age", MinValue: minAge, MaxValue: maxAge}, ErrorMessage: "Age out of range", Priority: 1},
+		{RuleName: "dates in order", RuleType: models.RuleTypeCrossField, RuleConfig: models.BusinessRuleConfig{Fields: []string{"end", "start"}, Condition: "end > start"}, ErrorMessage: "End must follow start"},
+	}
+}
+
+func TestCreateBusinessRulesBulk(t *testing.T) {
+	datasetID, userID := uuid.New(), uuid.New()
+	repo := &fakeBusinessRuleRepository{}
+
+	results, count, err := CreateBusinessRulesBulk(repo, datasetID, userID, validBulkRules(), bulkRuleSchema, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	require.Len(t, results, 3)
+	require.Len(t, repo.rules, 3)
+	for i, result := range results {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, models.BulkRuleStatusCreated, result.Status)
+		require.NotNil(t, result.Rule)
+		assert.Equal(t, userID, result.Rule.CreatedBy)
+		assert.True(t, result.Rule.IsActive)
+	}
+	assert.JSONEq(t, `{"field_name":"age","min_value":18,"max_value":120}`, string(repo.rules[1].RuleConfig))
+}
+
+func TestCreateBusinessRulesBulk_OneInvalidRuleCreatesNothing(t *testing.T) {
+	repo := &fakeBusinessRuleRepository{}
+	defs := validBulkRules()
+	defs = append(defs[:1], append([]models.BusinessRuleDefinition{
+		{RuleName: "phone required", RuleType: models.RuleTypeRequired, RuleConfig: models.BusinessRuleConfig{FieldName: "phone"}, ErrorMessage: "Phone is required"},
+	}, defs[1:]...)...)
+
+	results, count, err := CreateBusinessRulesBulk(repo, uuid.New(), uuid.New(), defs, bulkRuleSchema, 10)
+	require.ErrorIs(t, err, ErrInvalidBulkRules)
+	assert.Empty(t, repo.rules, "no rule is created when any rule is invalid")
+	assert.Equal(t, 0, count)
+
+	require.Len(t, results, 4)
+	assert.Equal(t, models.BulkRuleStatusNotCreated, results[0].Status)
+	assert.Equal(t, models.BulkRuleStatusInvalid, results[1].Status)
+	assert.Equal(t, []string{"field 'phone' does not exist in the dataset schema"}, results[1].Errors)
+	assert.Equal(t, models.BulkRuleStatusNotCreated, results[2].Status)
+	assert.Nil(t, results[2].Rule)
+}
+
+func TestCreateBusinessRulesBulk_RuleLimit(t *testing.T) {
+	datasetID := uuid.New()
+	repo := &fakeBusinessRuleRepository{rules: []*models.DatasetBusinessRule{{DatasetID: datasetID, IsActive: true}}}
+
+	_, count, err := CreateBusinessRulesBulk(repo, datasetID, uuid.New(), validBulkRules(), bulkRuleSchema, 3)
+	var ruleLimitErr *RuleLimitError
+	require.True(t, errors.As(err, &ruleLimitErr))
+	assert.Equal(t, 1, count)
+	assert.Len(t, repo.rules, 1)
+}
+
+func TestValidateBusinessRuleDefinition(t *testing.T) {
+	tests := []struct {
+		name string
+		def  models.BusinessRuleDefinition
+		want []string
+	}{
+		{
+			name: "unknown type",
+			def:  models.BusinessRuleDefinition{RuleName: "r", RuleType: "magic", ErrorMessage: "m"},
+			want: []string{"unsupported rule_type 'magic'"},
+		},
+		{
+			name: "missing name and message",
+			def:  models.BusinessRuleDefinition{RuleType: models.RuleTypeUnique, RuleConfig: models.BusinessRuleConfig{FieldName: "email"}},
+			want: []string{"rule_name is required", "error_message is required"},
+		},
+		{
+			name: "range without bounds",
+			def:  models.BusinessRuleDefinition{RuleName: "r", RuleType: models.RuleTypeRangeCheck, RuleConfig: models.BusinessRuleConfig{FieldName: "age"}, ErrorMessage: "m"},
+			want: []string{"range_check rules need rule_config.min_value or max_value"},
+		},
+		{
+			name: "range with text bound",
+			def:  models.BusinessRuleDefinition{RuleName: "r", RuleType: models.RuleTypeRangeCheck, RuleConfig: models.BusinessRuleConfig{FieldName: "age", MinValue: "ten"}, ErrorMessage: "m"},
+			want: []string{"rule_config.min_value and max_value must be numbers"},
+		},
+		{
+			name: "cross field with one unknown field",
+			def:  models.BusinessRuleDefinition{RuleName: "r", RuleType: models.RuleTypeCrossField, RuleConfig: models.BusinessRuleConfig{Fields: []string{"start"}, Condition: "start > x"}, ErrorMessage: "m"},
+			want: []string{"cross_field rules need at least two rule_config.fields"},
+		},
+		{
+			name: "bad pattern",
+			def:  models.BusinessRuleDefinition{RuleName: "r", RuleType: models.RuleTypeFieldValidation, RuleConfig: models.BusinessRuleConfig{FieldName: "email", Pattern: "("}, ErrorMessage: "m"},
+			want: []string{"rule_config.pattern is not a valid regular expression: error parsing regexp: missing closing ): `(`"},
+		},
+		{
+			name: "required with a condition missing its operator",
+			def:  models.BusinessRuleDefinition{RuleName: "r", RuleType: models.RuleTypeRequired, RuleConfig: models.BusinessRuleConfig{FieldName: "age", Condition: "email shipped"}, ErrorMessage: "m"},
+			want: []string{`rule_config.condition is not valid: condition "email shipped" has no comparison operator`},
+		},
+		{
+			name: "reference without a target",
+			def:  models.BusinessRuleDefinition{RuleName: "r", RuleType: models.RuleTypeReference, RuleConfig: models.BusinessRuleConfig{FieldName: "email", TargetDatasetID: "lookup"}, ErrorMessage: "m"},
+			want: []string{"rule_config.target_dataset_id must be a dataset ID for reference rules", "rule_config.target_field is required for reference rules"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidateBusinessRuleDefinition(tt.def, bulkRuleSchema))
+		})
+	}
+
+	t.Run("duplicate names in one import", func(t *testing.T) {
+		defs := append(validBulkRules(), validBulkRules()[0])
+		results, _, err := CreateBusinessRulesBulk(&fakeBusinessRuleRepository{}, uuid.New(), uuid.New(), defs, bulkRuleSchema, 0)
+		require.ErrorIs(t, err, ErrInvalidBulkRules)
+		assert.Equal(t, []string{"rule_name duplicates rule 0"}, results[3].Errors)
+	})
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+)
+
+// DefaultMaxBusinessRules is the cap on active business rules per dataset when MAX_BUSINESS_RULES is not set
+const DefaultMaxBusinessRules = 50
+
+// RuleLimitError reports a dataset that already has the maximum number of active business rules
+type RuleLimitError struct {
+	Limit int
+	Count int
+}
+
+func (e *RuleLimitError) Error() string {
+	return fmt.Sprintf("dataset already has %d active business rules, the limit is %d", e.Count, e.Limit)
+}
+
+// MaxBusinessRulesFromEnv reads MAX_BUSINESS_RULES; zero or a negative value disables the limit
+func MaxBusinessRulesFromEnv() int {
+	value := os.Getenv("MAX_BUSINESS_RULES")
+	if value == "" {
+		return DefaultMaxBusinessRules
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid MAX_BUSINESS_RULES %q, using default of %d: %v", value, DefaultMaxBusinessRules, err)
+		return DefaultMaxBusinessRules
+	}
+	return limit
+}
+
+// BusinessRuleRepositoryInterface is the storage used to change business rules within the limit.
+// Rules are counted and changed while the dataset's rules are locked, so concurrent changes can't
+// take a dataset past the limit.
+type BusinessRuleRepositoryInterface interface {
+	WithBusinessRulesLocked(datasetID uuid.UUID, fn func(rules repository.BusinessRuleStore) error) error
+}
+
+// CreateBusinessRuleWithinLimit stores an active rule unless the dataset already has limit active
+// rules, in which case it returns a RuleLimitError. It returns the dataset's active rule count
+// after the attempt. A limit of zero or less disables the cap.
+func CreateBusinessRuleWithinLimit(repo BusinessRuleRepositoryInterface, rule *models.DatasetBusinessRule, limit int) (int, error) {
+	var count int
+	err := repo.WithBusinessRulesLocked(rule.DatasetID, func(rules repository.BusinessRuleStore) error {
+		var err error
+		count, err = rules.CountActiveBusinessRules(rule.DatasetID)
+		if err != nil {
+			return fmt.Errorf("failed to count business rules: %w", err)
+		}
+
+		if limit > 0 && rule.IsActive && count >= limit {
+			return &RuleLimitError{Limit: limit, Count: count}
+		}
+
+		if err := rules.CreateBusinessRule(rule); err != nil {
+			return err
+		}
+
+		if rule.IsActive {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// SetBusinessRuleActiveWithinLimit activates or deactivates rule. Reactivating an inactive rule
+// counts against limit like creating one, returning a RuleLimitError when the dataset is full.
+// It returns the dataset's active rule count after the attempt. A limit of zero or less disables the cap.
+func SetBusinessRuleActiveWithinLimit(repo BusinessRuleRepositoryInterface, rule *models.DatasetBusinessRule, active bool, limit int) (int, error) {
+	var count int
+	err := repo.WithBusinessRulesLocked(rule.DatasetID, func(rules repository.BusinessRuleStore) error {
+		var err error
+		count, err = rules.CountActiveBusinessRules(rule.DatasetID)
+		if err != nil {
+			return fmt.Errorf("failed to count business rules: %w", err)
+		}
+
+		if active == rule.IsActive {
+			return nil
+		}
+		if active && limit > 0 && count >= limit {
+			return &RuleLimitError{Limit: limit, Count: count}
+		}
+
+		if err := rules.SetBusinessRuleActive(rule.ID, active); err != nil {
+			return err
+		}
+
+		if active {
+			count++
+		} else {
+			count--
+		}
+		return nil
+	})
+	if err == nil {
+		rule.IsActive = active
+	}
+	return count, err
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBusinessRuleRepository struct {
+	mu    sync.Mutex
+	rules []*models.DatasetBusinessRule
+}
+
+func (f *fakeBusinessRuleRepository) WithBusinessRulesLocked(datasetID uuid.UUID, fn func(rules repository.BusinessRuleStore) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fn(f)
+}
+
+func (f *fakeBusinessRuleRepository) CountActiveBusinessRules(datasetID uuid.UUID) (int, error) {
+	count := 0
+	for _, rule := range f.rules {
+		if rule.DatasetID == datasetID && rule.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeBusinessRuleRepository) CreateBusinessRule(rule *models.DatasetBusinessRule) error {
+	f.rules = append(f.rules, rule)
+	return nil
+}
+
+func TestCreateBusinessRuleWithinLimit(t *testing.T) {
+	datasetID := uuid.New()
+	repo := &fakeBusinessRuleRepository{}
+	newRule := func() *models.DatasetBusinessRule {
+		return &models.DatasetBusinessRule{ID: uuid.New(), DatasetID: datasetID, IsActive: true}
+	}
+
+	for i := 1; i <= 3; i++ {
+		count, err := CreateBusinessRuleWithinLimit(repo, newRule(), 3)
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+
+	t.Run("blocked at the cap", func(t *testing.T) {
+		count, err := CreateBusinessRuleWithinLimit(repo, newRule(), 3)
+		var ruleLimitErr *RuleLimitError
+		require.True(t, errors.As(err, &ruleLimitErr))
+		assert.Equal(t, 3, ruleLimitErr.Count)
+		assert.Equal(t, 3, ruleLimitErr.Limit)
+		assert.Equal(t, 3, count)
+		assert.Len(t, repo.rules, 3)
+	})
+
+	t.Run("other datasets are counted separately", func(t *testing.T) {
+		count, err := CreateBusinessRuleWithinLimit(repo, &models.DatasetBusinessRule{DatasetID: uuid.New(), IsActive: true}, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("zero disables the cap", func(t *testing.T) {
+		count, err := CreateBusinessRuleWithinLimit(repo, newRule(), 0)
+		require.NoError(t, err)
+		assert.Equal(t, 4, count)
+	})
+
+	t.Run("concurrent creates stop at the cap", func(t *testing.T) {
+		repo := &fakeBusinessRuleRepository{}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				CreateBusinessRuleWithinLimit(repo, newRule(), 3)
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, repo.rules, 3)
+	})
+}
+
+func TestMaxBusinessRulesFromEnv(t *testing.T) {
+	t.Setenv("MAX_BUSINESS_RULES", "")
+	assert.Equal(t, DefaultMaxBusinessRules, MaxBusinessRulesFromEnv())
+
+	t.Setenv("MAX_BUSINESS_RULES", "5")
+	assert.Equal(t, 5, MaxBusinessRulesFromEnv())
+
+	t.Setenv("MAX_BUSINESS_RULES", "many")
+	assert.Equal(t, DefaultMaxBusinessRules, MaxBusinessRulesFromEnv())
+}
+
+func (f *fakeBusinessRuleRepository) SetBusinessRuleActive(id uuid.UUID, active bool) error {
+	for _, rule := range f.rules {
+		if rule.ID == id {
+			rule.IsActive = active
+			return nil
+		}
+	}
+	return errors.New("rule not found")
+}
+
+func TestSetBusinessRuleActiveWithinLimit(t *testing.T) {
+	datasetID := uuid.New()
+	repo := &fakeBusinessRuleRepository{}
+	for i := 0; i < 2; i++ {
+		repo.rules = append(repo.rules, &models.DatasetBusinessRule{ID: uuid.New(), DatasetID: datasetID, IsActive: true})
+	}
+	inactive := &models.DatasetBusinessRule{ID: uuid.New(), DatasetID: datasetID}
+	repo.rules = append(repo.rules, inactive)
+
+	t.Run("reactivating is blocked at the cap", func(t *testing.T) {
+		count, err := SetBusinessRuleActiveWithinLimit(repo, inactive, true, 2)
+		var ruleLimitErr *RuleLimitError
+		require.True(t, errors.As(err, &ruleLimitErr))
+		assert.Equal(t, 2, count)
+		assert.False(t, inactive.IsActive)
+	})
+
+	t.Run("deactivating frees a slot", func(t *testing.T) {
+		count, err := SetBusinessRuleActiveWithinLimit(repo, repo.rules[0], false, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		assert.False(t, repo.rules[0].IsActive)
+
+		count, err = SetBusinessRuleActiveWithinLimit(repo, inactive, true, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.True(t, inactive.IsActive)
+	})
+
+	t.Run("setting the current state is a no-op", func(t *testing.T) {
+		count, err := SetBusinessRuleActiveWithinLimit(repo, inactive, true, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// CoerceRowToSchema converts the values of a row to the types declared by the schema.
+// Numbers, currency amounts and percentages become float64, booleans become bool, objects and arrays stay native JSON values,
+// lists become JSON arrays of their converted items, all other field types are stored as strings.
+// Fields missing from the row are stored as empty strings, or as null for numeric fields (see
+// NullEmptyNumericFields); null values are kept. Fields unknown to the schema are reported.
+func CoerceRowToSchema(rowData map[string]interface{}, schema *models.DatasetSchema, rowIndex int) (map[string]interface{}, []models.DataValidationError) {
+	var errors []models.DataValidationError
+	coerced := make(map[string]interface{}, len(schema.Fields))
+
+	schemaFields := make(map[string]bool, len(schema.Fields))
+	for _, field := range schema.Fields {
+		schemaFields[field.Name] = true
+
+		value, exists := rowData[field.Name]
+		if isNullValue(value, exists) {
+			if value == nil || isNumericType(field.DataType) {
+				coerced[field.Name] = nil
+			} else {
+				coerced[field.Name] = ""
+			}
+			continue
+		}
+
+		var converted interface{}
+		var ok bool
+		if field.DataType == string(models.FieldTypeList) {
+			converted, ok = coerceListValue(value, field)
+		} else {
+			converted, ok = coerceValue(value, field.DataType)
+		}
+		if !ok {
+			errors = append(errors, models.DataValidationError{
+				RowIndex:      rowIndex,
+				FieldName:     field.Name,
+				ErrorType:     "invalid_data_type",
+				Message:       fmt.Sprintf("Field '%s' cannot be converted to %s", field.Name, field.DataType),
+				ActualValue:   fmt.Sprintf("%v", value),
+				ExpectedValue: field.DataType,
+			})
+			continue
+		}
+		coerced[field.Name] = converted
+	}
+
+	for name, value := range rowData {
+		if !schemaFields[name] {
+			errors = append(errors, models.DataValidationError{
+				RowIndex:    rowIndex,
+				FieldName:   name,
+				ErrorType:   "unexpected_field",
+				Message:     fmt.Sprintf("Field '%s' is not defined in the dataset schema", name),
+				ActualValue: fmt.Sprintf("%v", value),
+			})
+		}
+	}
+
+	return coerced, errors
+}
+
+// coerceValue converts a single value to the Go type used to store the given schema data type
+func coerceValue(value interface{}, dataType string) (interface{}, bool) {
+	switch dataType {
+	case string(models.FieldTypeNumber), string(models.FieldTypeCurrency), string(models.FieldTypePercent):
+		f, ok := parseNumericValue(value, dataType)
+		if !ok {
+			return nil, false
+		}
+		return f, true
+	case string(models.FieldTypeInteger):
+		n, ok := parseIntegerValue(value)
+		if !ok || n > MaxSafeInteger || n < -MaxSafeInteger {
+			return nil, false
+		}
+		return float64(n), true
+	case string(models.FieldTypeBoolean):
+		if b, ok := value.(bool); ok {
+			return b, true
+		}
+		switch strings.ToLower(strings.TrimSpace(fmt.Sprintf("%v", value))) {
+		case "true", "1":
+			return true, true
+		case "false", "0":
+			return false, true
+		}
+		return nil, false
+	case string(models.FieldTypeObject), string(models.FieldTypeArray):
+		return ParseStructuredValue(value, dataType)
+	default:
+		return fmt.Sprintf("%v", value), true
+	}
+}
+
+// NormalizeBooleanFields rewrites boolean field values accepted by validation ("TRUE", "1", "false"...)
+// in place to JSON booleans and reports whether any value changed
+func NormalizeBooleanFields(rowData map[string]interface{}, schema *models.DatasetSchema) bool {
+	changed := false
+	for _, field := range schema.Fields {
+		if field.DataType != string(models.FieldTypeBoolean) {
+			continue
+		}
+
+		value, exists := rowData[field.Name]
+		if !exists || value == nil || value == "" {
+			continue
+		}
+		if _, native := value.(bool); native {
+			continue
+		}
+
+		if b, ok := coerceValue(value, field.DataType); ok {
+			rowData[field.Name] = b
+			changed = true
+		}
+	}
+	return changed
+}
+
+// ApplyFieldDefaults fills fields that are missing or empty in a row with their schema default
+// value, in place, and reports whether any value changed. Fields without a default stay empty.
+// A null value marks a field that is empty on purpose and is left null rather than defaulted.
+func ApplyFieldDefaults(rowData map[string]interface{}, schema *models.DatasetSchema) bool {
+	changed := false
+	for _, field := range schema.Fields {
+		if field.DefaultValue == nil || *field.DefaultValue == "" {
+			continue
+		}
+
+		value, exists := rowData[field.Name]
+		if exists && value != "" {
+			continue
+		}
+
+		rowData[field.Name] = *field.DefaultValue
+		changed = true
+	}
+	return changed
+}
+
+// NullEmptyNumericFields stores number, integer, currency and percent fields that are empty in a row
+// as null, in place, and reports whether any value changed. Null is the stored form of a value left
+// empty on purpose, so an empty amount is never mistaken for zero. Missing fields stay missing.
+func NullEmptyNumericFields(rowData map[string]interface{}, schema *models.DatasetSchema) bool {
+	changed := false
+	for _, field := range schema.Fields {
+		if !isNumericType(field.DataType) {
+			continue
+		}
+		if value, exists := rowData[field.Name]; exists && value == "" {
+			rowData[field.Name] = nil
+			changed = true
+		}
+	}
+	return changed
+}
+
+// NormalizeRowValues rewrites a row's values in place to the canonical form of their schema types:
+// field transforms are applied, missing values take their field's default, dates are in NormalizedDateFormat, numbers, currency
+// and percent values become JSON numbers and booleans JSON booleans. Values that don't parse are
+// left as they are. It reports whether any value changed.
+func NormalizeRowValues(rowData map[string]interface{}, schema *models.DatasetSchema) bool {
+	transformed := ApplyFieldTransforms(rowData, schema)
+	defaultsApplied := ApplyFieldDefaults(rowData, schema)
+	datesChanged := NormalizeDateFields(rowData, schema)
+	numbersChanged := NormalizeNumericFields(rowData, schema)
+	booleansChanged := NormalizeBooleanFields(rowData, schema)
+	return transformed || defaultsApplied || datesChanged || numbersChanged || booleansChanged
+}
+
+// NormalizeStagingValues rewrites staged rows to their stored form (see NormalizeRowValues) before
+// they are applied, so equal values are stored alike whatever format they were uploaded in.
+// It returns the rows whose data changed. An empty schemaName selects the dataset's default schema.
+func (v *ValidationService) NormalizeStagingValues(datasetID uuid.UUID, schemaName string, stagingData []*models.DataSubmissionStaging) ([]*models.DataSubmissionStaging, error) {
+	schema, err := v.loadSchema(datasetID, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", err)
+	}
+
+	var changed []*models.DataSubmissionStaging
+	for _, row := range stagingData {
+		var rowData map[string]interface{}
+		if err := json.Unmarshal(row.Data, &rowData); err != nil {
+			return nil, fmt.Errorf("failed to decode staged row %d: %w", row.RowIndex, err)
+		}
+
+		if !NormalizeRowValues(rowData, schema) {
+			continue
+		}
+
+		data, err := json.Marshal(rowData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode staged row %d: %w", row.RowIndex, err)
+		}
+		row.Data = data
+		changed = append(changed, row)
+	}
+
+	return changed, nil
+}
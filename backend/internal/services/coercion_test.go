@@ -0,0 +1,114 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeRowValues(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "joined", DataType: "date"},
+			{Name: "active", DataType: "boolean"},
+			{Name: "score", DataType: "number"},
+			{Name: "price", DataType: "currency"},
+			{Name: "code", DataType: "string"},
+		},
+	}
+
+	row := map[string]interface{}{"joined": "01/02/2006", "active": "TRUE", "score": "42.50", "price": "$1,000", "code": "007"}
+	assert.True(t, NormalizeRowValues(row, schema))
+	assert.Equal(t, map[string]interface{}{
+		"joined": "2006-01-02",
+		"active": true,
+		"score":  42.5,
+		"price":  1000.0,
+		"code":   "007",
+	}, row)
+
+	// Canonical values, empty values and values that fail validation are left alone
+	row = map[string]interface{}{"joined": "2006-01-02", "active": false, "score": 7.0, "price": "", "code": "x"}
+	assert.False(t, NormalizeRowValues(row, schema))
+
+	row = map[string]interface{}{"active": "maybe", "score": "NaN"}
+	assert.False(t, NormalizeRowValues(row, schema))
+	assert.Equal(t, "maybe", row["active"])
+	assert.Equal(t, "NaN", row["score"])
+}
+
+func TestApplyFieldDefaults(t *testing.T) {
+	status, count, blank := "active", "0", ""
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "status", DataType: "string", DefaultValue: &status},
+			{Name: "count", DataType: "integer", DefaultValue: &count},
+			{Name: "note", DataType: "string", DefaultValue: &blank},
+		},
+	}
+
+	row := map[string]interface{}{"status": "", "note": ""}
+	assert.True(t, ApplyFieldDefaults(row, schema))
+	assert.Equal(t, map[string]interface{}{"status": "active", "count": "0", "note": ""}, row, "missing and empty values take the default")
+
+	row = map[string]interface{}{"status": "closed", "count": 3.0}
+	assert.False(t, ApplyFieldDefaults(row, schema), "provided values are kept")
+
+	row = map[string]interface{}{"status": "closed"}
+	assert.True(t, NormalizeRowValues(row, schema))
+	assert.Equal(t, 0.0, row["count"], "defaults are normalized like uploaded values")
+}
+
+func TestValidationService_AppliesFieldDefaults(t *testing.T) {
+	region := "EU"
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "string", IsRequired: true},
+			{Name: "region", DataType: "string", IsRequired: true, DefaultValue: &region},
+			{Name: "country", DataType: "string", DefaultValue: &region},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	// The country column is missing altogether, the region is empty in the first row
+	path := filepath.Join(t.TempDir(), "append.csv")
+	require.NoError(t, os.WriteFile(path, []byte("id,region\n1,\n2,US\n"), 0o644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid, "a required field with a default may be left empty")
+	require.Len(t, staging, 2)
+	assert.JSONEq(t, `{"id": "1", "region": "EU", "country": "EU"}`, string(staging[0].Data))
+	assert.JSONEq(t, `{"id": "2", "region": "US", "country": "EU"}`, string(staging[1].Data))
+}
+
+func TestValidationService_NormalizeStagingValues_StoresValuesAlike(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "joined", DataType: "date"},
+			{Name: "active", DataType: "boolean"},
+			{Name: "score", DataType: "number"},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	staging := stagedRows(t,
+		map[string]interface{}{"joined": "01/02/2006", "active": "1", "score": "3"},
+		map[string]interface{}{"joined": "2006-01-02", "active": "true", "score": "3.0"},
+	)
+
+	changed, err := svc.NormalizeStagingValues(uuid.New(), "", staging)
+	require.NoError(t, err)
+	require.Len(t, changed, 2)
+
+	var first, second map[string]interface{}
+	require.NoError(t, json.Unmarshal(changed[0].Data, &first))
+	require.NoError(t, json.Unmarshal(changed[1].Data, &second))
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ColumnMappingError reports a column mapping that can't be applied to a file's headers
+type ColumnMappingError struct {
+	Problems []string
+}
+
+func (e *ColumnMappingError) Error() string {
+	return "invalid column mapping: " + strings.Join(e.Problems, "; ")
+}
+
+// TrimHeaders returns headers with surrounding whitespace removed, so "name " matches the field "name"
+func TrimHeaders(headers []string) []string {
+	trimmed := make([]string, len(headers))
+	for i, header := range headers {
+		trimmed[i] = strings.TrimSpace(header)
+	}
+	return trimmed
+}
+
+// ApplyColumnMapping returns headers with each mapped header renamed to its schema field. Every
+// mapped header must appear in the file, and no two columns may end up with the same name;
+// otherwise a *ColumnMappingError lists the problems. Mapping entries are matched and applied with
+// surrounding whitespace trimmed, like the headers. An empty mapping returns headers unchanged.
+func ApplyColumnMapping(headers []string, columnMapping models.ColumnMapping) ([]string, error) {
+	if len(columnMapping) == 0 {
+		return headers, nil
+	}
+
+	mapping := make(map[string]string, len(columnMapping))
+	for header, field := range columnMapping {
+		mapping[strings.TrimSpace(header)] = strings.TrimSpace(field)
+	}
+
+	var problems []string
+	present := make(map[string]bool, len(headers))
+	for _, header := range headers {
+		present[header] = true
+	}
+	mappedHeaders := make([]string, 0, len(mapping))
+	for header := range mapping {
+		mappedHeaders = append(mappedHeaders, header)
+	}
+	sort.Strings(mappedHeaders)
+	for _, header := range mappedHeaders {
+		if !present[header] {
+			problems = append(problems, fmt.Sprintf("column '%s' is not in the file", header))
+		}
+		if mapping[header] == "" {
+			problems = append(problems, fmt.Sprintf("column '%s' is mapped to an empty field name", header))
+		}
+	}
+
+	mapped := make([]string, len(headers))
+	sources := make(map[string]string, len(headers))
+	for i, header := range headers {
+		name, renamed := mapping[header]
+		if !renamed {
+			name = header
+		}
+		// Duplicate headers in the file itself are left to header validation
+		if first, ok := sources[name]; ok && (renamed || first != name) {
+			problems = append(problems, fmt.Sprintf("columns '%s' and '%s' both map to field '%s'", first, header, name))
+		}
+		sources[name] = header
+		mapped[i] = name
+	}
+
+	if len(problems) > 0 {
+		return nil, &ColumnMappingError{Problems: problems}
+	}
+	return mapped, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestApplyColumnMapping(t *testing.T) {
+	headers := []string{"First Name", "Email", "age"}
+
+	mapped, err := ApplyColumnMapping(headers, models.ColumnMapping{"First Name": "first_name", "Email": "email"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first_name", "email", "age"}, mapped)
+	assert.Equal(t, []string{"First Name", "Email", "age"}, headers, "headers are not modified")
+
+	unchanged, err := ApplyColumnMapping(headers, nil)
+	require.NoError(t, err)
+	assert.Equal(t, headers, unchanged)
+
+	_, err = ApplyColumnMapping(headers, models.ColumnMapping{"Last Name": "last_name", "Email": "age", "First Name": " "})
+	var mappingErr *ColumnMappingError
+	require.True(t, errors.As(err, &mappingErr))
+	assert.Equal(t, []string{
+		"column 'First Name' is mapped to an empty field name",
+		"column 'Last Name' is not in the file",
+		"columns 'Email' and 'age' both map to field 'age'",
+	}, mappingErr.Problems)
+
+	t.Run("duplicate file headers are left to header validation", func(t *testing.T) {
+		mapped, err := ApplyColumnMapping([]string{"id", "id", "Name"}, models.ColumnMapping{"Name": "name"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"id", "id", "name"}, mapped)
+	})
+}
+
+func TestValidationService_ColumnMapping(t *testing.T) {
+	repo := &fakeSchemaRepository{schema: &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "first_name", DataType: "string", IsRequired: true},
+		{Name: "age", DataType: "integer"},
+	}}}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	require.NoError(t, os.WriteFile(path, []byte("First Name,age\nAda,36\n"), 0644))
+
+	result, _, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid, "unmapped headers don't match the schema")
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", models.ColumnMapping{"First Name": "first_name"})
+	require.NoError(t, err)
+	assert.Empty(t, result.SchemaErrors)
+	require.Len(t, staging, 1)
+	assert.JSONEq(t, `{"first_name": "Ada", "age": "36"}`, string(staging[0].Data))
+
+	_, _, err = svc.ValidateDataSubmission(path, uuid.New(), "", models.ColumnMapping{"Surname": "first_name"})
+	var mappingErr *ColumnMappingError
+	assert.True(t, errors.As(err, &mappingErr))
+}
+
+func TestValidationService_HeaderWhitespaceAndOrder(t *testing.T) {
+	repo := &fakeSchemaRepository{schema: &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "first_name", DataType: "string", IsRequired: true},
+		{Name: "age", DataType: "integer"},
+	}}}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	require.NoError(t, os.WriteFile(path, []byte(" age ,first_name\t\n36,Ada\n"), 0644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+	assert.Empty(t, result.SchemaErrors)
+	require.Len(t, staging, 1)
+	assert.JSONEq(t, `{"first_name": "Ada", "age": "36"}`, string(staging[0].Data), "values follow their header, not their position")
+
+	t.Run("mapping keys are trimmed too", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("First Name ,age\nAda,36\n"), 0644))
+		result, _, err := svc.ValidateDataSubmission(path, uuid.New(), "", models.ColumnMapping{" First Name": "first_name "})
+		require.NoError(t, err)
+		assert.Empty(t, result.SchemaErrors)
+	})
+
+	t.Run("absent required fields are still reported", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte(" age \n36\n"), 0644))
+		result, _, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+		require.NoError(t, err)
+		assert.False(t, result.IsValid)
+		require.Len(t, result.SchemaErrors, 1)
+		assert.Equal(t, "first_name", result.SchemaErrors[0].FieldName)
+		assert.Equal(t, "missing_field", result.SchemaErrors[0].ErrorType)
+	})
+}
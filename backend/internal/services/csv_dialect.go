@@ -0,0 +1,228 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// CSVRecordReader reads CSV records one at a time, like csv.Reader
+type CSVRecordReader interface {
+	Read() ([]string, error)
+}
+
+// ErrCSVFieldCount is returned for a record whose field count differs from the header's
+var ErrCSVFieldCount = errors.New("wrong number of fields")
+
+// ErrCSVQuote is returned when a quoted field is not closed before the end of the file
+var ErrCSVQuote = errors.New("quoted field is not terminated")
+
+// dialectChar returns the single character of a dialect setting, or def when it is empty
+func dialectChar(value string, def rune) (rune, bool) {
+	if value == "" {
+		return def, true
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	return r, r != utf8.RuneError && size == len(value)
+}
+
+// ValidateCSVDialect checks that each dialect setting is a single usable character and that the
+// delimiter, quote and escape characters do not clash
+func ValidateCSVDialect(dialect *models.CSVDialect) error {
+	if dialect == nil {
+		return nil
+	}
+
+	settings := []struct {
+		name  string
+		value string
+		def   rune
+	}{
+		{"delimiter", dialect.Delimiter, ','},
+		{"quote", dialect.Quote, '"'},
+		{"escape", dialect.Escape, 0},
+	}
+	chars := make(map[string]rune, len(settings))
+	for _, setting := range settings {
+		r, ok := dialectChar(setting.value, setting.def)
+		if !ok {
+			return fmt.Errorf("csv %s must be a single character", setting.name)
+		}
+		if r == '\r' || r == '\n' {
+			return fmt.Errorf("csv %s cannot be a line break", setting.name)
+		}
+		chars[setting.name] = r
+	}
+
+	if chars["delimiter"] == chars["quote"] {
+		return fmt.Errorf("csv delimiter and quote must differ")
+	}
+	if chars["escape"] != 0 && chars["escape"] == chars["delimiter"] {
+		return fmt.Errorf("csv delimiter and escape must differ")
+	}
+	if strings.ContainsAny(dialect.NullValue, "\r\n") {
+		return fmt.Errorf("csv null_value cannot contain line breaks")
+	}
+	return validateCSVEncoding(dialect.Encoding)
+}
+
+// NewCSVReader returns a reader for CSV data written in the given dialect. Input is decoded to
+// UTF-8 first, see NewCSVDecoder. The standard dialect uses encoding/csv; custom quote, escape or
+// null settings use a dialect-aware parser. The dialect must have passed ValidateCSVDialect.
+func NewCSVReader(r io.Reader, dialect *models.CSVDialect) CSVRecordReader {
+	if dialect == nil {
+		return csv.NewReader(NewCSVDecoder(r, ""))
+	}
+	r = NewCSVDecoder(r, dialect.Encoding)
+
+	delimiter, _ := dialectChar(dialect.Delimiter, ',')
+	quote, _ := dialectChar(dialect.Quote, '"')
+	escape, _ := dialectChar(dialect.Escape, quote)
+
+	if quote == '"' && escape == '"' && dialect.NullValue == "" {
+		reader := csv.NewReader(r)
+		reader.Comma = delimiter
+		return reader
+	}
+
+	return &dialectReader{
+		r:         bufio.NewReader(r),
+		delimiter: delimiter,
+		quote:     quote,
+		escape:    escape,
+		nullValue: dialect.NullValue,
+		line:      1,
+	}
+}
+
+// dialectReader parses CSV with configurable delimiter, quote and escape characters. Outside
+// quotes the escape character (when it differs from the quote) also escapes the next character.
+type dialectReader struct {
+	r          *bufio.Reader
+	delimiter  rune
+	quote      rune
+	escape     rune
+	nullValue  string
+	line       int
+	fieldCount int
+}
+
+func (d *dialectReader) Read() ([]string, error) {
+	for {
+		record, empty, err := d.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if empty {
+			continue // blank lines are skipped, as encoding/csv does
+		}
+
+		if d.fieldCount == 0 {
+			d.fieldCount = len(record)
+		} else if len(record) != d.fieldCount {
+			return record, fmt.Errorf("record on line %d: %w", d.line-1, ErrCSVFieldCount)
+		}
+		return record, nil
+	}
+}
+
+// readRecord reads one line's worth of fields. empty is set for a blank line.
+func (d *dialectReader) readRecord() (record []string, empty bool, err error) {
+	var field strings.Builder
+	startLine := d.line
+	inQuotes, quoted, started := false, false, false
+
+	finishField := func() {
+		value := field.String()
+		if !quoted && d.nullValue != "" && value == d.nullValue {
+			value = ""
+		}
+		record = append(record, value)
+		field.Reset()
+		quoted = false
+	}
+
+	for {
+		r, _, readErr := d.r.ReadRune()
+		if readErr == io.EOF {
+			if inQuotes {
+				return nil, false, fmt.Errorf("record on line %d: %w", startLine, ErrCSVQuote)
+			}
+			if !started {
+				return nil, false, io.EOF
+			}
+			d.line++
+			finishField()
+			return record, false, nil
+		}
+		if readErr != nil {
+			return nil, false, readErr
+		}
+		if r == '\n' {
+			d.line++
+		}
+
+		if inQuotes {
+			switch {
+			case r == d.escape && d.escape != d.quote:
+				next, _, err := d.r.ReadRune()
+				if err != nil {
+					return nil, false, fmt.Errorf("record on line %d: %w", startLine, ErrCSVQuote)
+				}
+				if next == '\n' {
+					d.line++
+				}
+				field.WriteRune(next)
+			case r == d.quote:
+				if next, _, err := d.r.ReadRune(); err == nil {
+					if next == d.quote && d.escape == d.quote {
+						field.WriteRune(d.quote)
+						continue
+					}
+					d.r.UnreadRune()
+				}
+				inQuotes = false
+			default:
+				field.WriteRune(r)
+			}
+			continue
+		}
+
+		switch {
+		case r == '\n' || r == '\r':
+			if r == '\r' {
+				if next, _, err := d.r.ReadRune(); err == nil && next != '\n' {
+					d.r.UnreadRune()
+				}
+				d.line++
+			}
+			if !started {
+				return nil, true, nil
+			}
+			finishField()
+			return record, false, nil
+		case r == d.delimiter:
+			started = true
+			finishField()
+		case r == d.quote && field.Len() == 0 && !quoted:
+			started, inQuotes, quoted = true, true, true
+		case r == d.escape && d.escape != d.quote:
+			started = true
+			next, _, err := d.r.ReadRune()
+			if err != nil {
+				field.WriteRune(r)
+				continue
+			}
+			field.WriteRune(next)
+		default:
+			started = true
+			field.WriteRune(r)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAllRecords drains a CSV reader
+func readAllRecords(t *testing.T, reader CSVRecordReader) [][]string {
+	t.Helper()
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, record)
+	}
+}
+
+func TestNewCSVReader(t *testing.T) {
+	t.Run("standard dialect uses encoding/csv", func(t *testing.T) {
+		_, isStd := NewCSVReader(strings.NewReader(""), nil).(*csv.Reader)
+		assert.True(t, isStd)
+		_, isStd = NewCSVReader(strings.NewReader(""), &models.CSVDialect{Delimiter: ";"}).(*csv.Reader)
+		assert.True(t, isStd)
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		dialect models.CSVDialect
+		want    [][]string
+	}{
+		{
+			name:    "custom quote with doubled quote escape",
+			input:   "name,note\n'Smith, John','it''s \"fine\"'\n",
+			dialect: models.CSVDialect{Quote: "'"},
+			want:    [][]string{{"name", "note"}, {"Smith, John", `it's "fine"`}},
+		},
+		{
+			name:    "backslash escape",
+			input:   "a,b\n\"say \\\"hi\\\"\",x\\,y\n",
+			dialect: models.CSVDialect{Escape: `\`},
+			want:    [][]string{{"a", "b"}, {`say "hi"`, "x,y"}},
+		},
+		{
+			name:    "null marker only applies unquoted",
+			input:   "a|b|c\r\nNULL|\"NULL\"|1\r\n",
+			dialect: models.CSVDialect{Delimiter: "|", NullValue: "NULL"},
+			want:    [][]string{{"a", "b", "c"}, {"", "NULL", "1"}},
+		},
+		{
+			name:    "quoted line breaks and blank lines",
+			input:   "a;b\n\n'line1\nline2';2\n3;",
+			dialect: models.CSVDialect{Delimiter: ";", Quote: "'"},
+			want:    [][]string{{"a", "b"}, {"line1\nline2", "2"}, {"3", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect := tt.dialect
+			require.NoError(t, ValidateCSVDialect(&dialect))
+			assert.Equal(t, tt.want, readAllRecords(t, NewCSVReader(strings.NewReader(tt.input), &dialect)))
+		})
+	}
+
+	t.Run("unterminated quote", func(t *testing.T) {
+		reader := NewCSVReader(strings.NewReader("a\n'open\n"), &models.CSVDialect{Quote: "'"})
+		_, err := reader.Read()
+		require.NoError(t, err)
+		_, err = reader.Read()
+		assert.ErrorIs(t, err, ErrCSVQuote)
+	})
+
+	t.Run("field count must match header", func(t *testing.T) {
+		reader := NewCSVReader(strings.NewReader("a,b\n1,2,3\n"), &models.CSVDialect{Quote: "'"})
+		_, err := reader.Read()
+		require.NoError(t, err)
+		_, err = reader.Read()
+		assert.ErrorIs(t, err, ErrCSVFieldCount)
+	})
+}
+
+func TestValidateCSVDialect(t *testing.T) {
+	assert.NoError(t, ValidateCSVDialect(nil))
+	assert.NoError(t, ValidateCSVDialect(&models.CSVDialect{Delimiter: "\t", Quote: "'", Escape: `\`, NullValue: "NA"}))
+	assert.Error(t, ValidateCSVDialect(&models.CSVDialect{Delimiter: ";;"}))
+	assert.Error(t, ValidateCSVDialect(&models.CSVDialect{Quote: ","}))
+	assert.Error(t, ValidateCSVDialect(&models.CSVDialect{Escape: ","}))
+	assert.Error(t, ValidateCSVDialect(&models.CSVDialect{Delimiter: "\n"}))
+}
+
+func TestValidationService_CSVDialect(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "name", DataType: "string", IsRequired: true},
+			{Name: "city", DataType: "string"},
+		},
+	}
+	repo := &fakeSchemaRepository{schema: schema, dialect: &models.CSVDialect{Quote: "'"}}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "append.csv")
+	require.NoError(t, os.WriteFile(path, []byte("name,city\n'Doe, Jane','St. John''s'\n"), 0o644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ValidRows)
+	require.Len(t, staging, 1)
+	assert.JSONEq(t, `{"name": "Doe, Jane", "city": "St. John's"}`, string(staging[0].Data))
+}
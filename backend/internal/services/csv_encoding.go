@@ -0,0 +1,99 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// utf8BOM is the byte order mark Excel writes at the start of UTF-8 CSV exports
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// csvEncodingAliases maps accepted encoding names to their canonical form
+var csvEncodingAliases = map[string]string{
+	"":             "",
+	"utf-8":        models.CSVEncodingUTF8,
+	"utf8":         models.CSVEncodingUTF8,
+	"windows-1252": models.CSVEncodingWindows1252,
+	"cp1252":       models.CSVEncodingWindows1252,
+}
+
+// normalizeCSVEncoding returns the canonical name of encoding, or false when it is not supported
+func normalizeCSVEncoding(encoding string) (string, bool) {
+	canonical, ok := csvEncodingAliases[strings.ToLower(strings.TrimSpace(encoding))]
+	return canonical, ok
+}
+
+// validateCSVEncoding checks that encoding is one NewCSVDecoder understands
+func validateCSVEncoding(encoding string) error {
+	if _, ok := normalizeCSVEncoding(encoding); !ok {
+		return fmt.Errorf("csv encoding must be %s or %s", models.CSVEncodingUTF8, models.CSVEncodingWindows1252)
+	}
+	return nil
+}
+
+// NewCSVDecoder returns the text of r as UTF-8 without a leading byte order mark. Windows-1252
+// input is transcoded; an empty encoding keeps valid UTF-8 and reads any byte that is not part of
+// a valid UTF-8 sequence as Windows-1252, so both kinds of Excel export parse without setup.
+func NewCSVDecoder(r io.Reader, encoding string) io.Reader {
+	buffered := bufio.NewReader(r)
+	if prefix, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+
+	canonical, _ := normalizeCSVEncoding(encoding)
+	switch canonical {
+	case models.CSVEncodingUTF8:
+		return buffered
+	case models.CSVEncodingWindows1252:
+		return charmap.Windows1252.NewDecoder().Reader(buffered)
+	default:
+		return &fallbackDecoder{r: buffered}
+	}
+}
+
+// fallbackDecoder passes valid UTF-8 through and decodes stray bytes as Windows-1252
+type fallbackDecoder struct {
+	r       *bufio.Reader
+	pending []byte
+	err     error
+}
+
+func (d *fallbackDecoder) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(d.pending) > 0 {
+			copied := copy(p[n:], d.pending)
+			d.pending = d.pending[copied:]
+			n += copied
+			continue
+		}
+		if d.err != nil || (n > 0 && d.r.Buffered() == 0) {
+			break
+		}
+
+		r, size, err := d.r.ReadRune()
+		if err != nil {
+			d.err = err
+			break
+		}
+		if r == utf8.RuneError && size == 1 {
+			d.r.UnreadRune()
+			b, _ := d.r.ReadByte()
+			r = charmap.Windows1252.DecodeByte(b)
+		}
+		d.pending = utf8.AppendRune(d.pending[:0], r)
+	}
+
+	if n == 0 && d.err != nil {
+		return 0, d.err
+	}
+	return n, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCSVDecoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		encoding string
+		expected string
+	}{
+		{"utf-8 passes through", "id,name\n1,Zoë\n", "", "id,name\n1,Zoë\n"},
+		{"bom is stripped", "\ufeffid,name\n", "", "id,name\n"},
+		{"bom is stripped for utf-8", "\ufeffid\n", "utf-8", "id\n"},
+		{"bom is stripped before transcoding", "\ufeffid\n", "windows-1252", "id\n"},
+		{"bom only in the middle is kept", "id\n\ufeff1\n", "", "id\n\ufeff1\n"},
+		{"windows-1252 is transcoded", "caf\xe9,\x80 5\n", "windows-1252", "café,€ 5\n"},
+		{"windows-1252 alias", "na\xefve\n", "CP1252", "naïve\n"},
+		{"stray bytes are detected as windows-1252", "Zoë,caf\xe9,\x93quoted\x94\n", "", "Zoë,café,“quoted”\n"},
+		{"truncated utf-8 at the end", "ok\xc3", "", "okÃ"},
+		{"short input", "a", "", "a"},
+		{"empty input", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := io.ReadAll(NewCSVDecoder(strings.NewReader(tt.input), tt.encoding))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(decoded))
+
+			// Decoding must not depend on how the input is split into reads
+			decoded, err = io.ReadAll(iotest.OneByteReader(NewCSVDecoder(iotest.OneByteReader(strings.NewReader(tt.input)), tt.encoding)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(decoded))
+		})
+	}
+
+	t.Run("read errors are returned", func(t *testing.T) {
+		_, err := io.ReadAll(NewCSVDecoder(iotest.ErrReader(iotest.ErrTimeout), ""))
+		assert.ErrorIs(t, err, iotest.ErrTimeout)
+	})
+}
+
+func TestNewCSVReader_Encoding(t *testing.T) {
+	records := readAllRecords(t, NewCSVReader(strings.NewReader("\ufeffid,city\n1,M\xfcnchen\n"), nil))
+	assert.Equal(t, [][]string{{"id", "city"}, {"1", "München"}}, records)
+
+	records = readAllRecords(t, NewCSVReader(strings.NewReader("\ufeffid;city\n1;'K\xf6ln'\n"), &models.CSVDialect{Delimiter: ";", Quote: "'", Encoding: "windows-1252"}))
+	assert.Equal(t, [][]string{{"id", "city"}, {"1", "Köln"}}, records)
+}
+
+func TestValidateCSVDialect_Encoding(t *testing.T) {
+	assert.NoError(t, ValidateCSVDialect(&models.CSVDialect{Encoding: "utf-8"}))
+	assert.NoError(t, ValidateCSVDialect(&models.CSVDialect{Encoding: "Windows-1252"}))
+	assert.Error(t, ValidateCSVDialect(&models.CSVDialect{Encoding: "shift_jis"}))
+}
+
+func TestValidationService_ExcelExport(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "string", IsRequired: true},
+			{Name: "city", DataType: "string"},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "export.csv")
+	require.NoError(t, os.WriteFile(path, []byte("\xef\xbb\xbfid,city\n1,Z\xfcrich\n"), 0o644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	assert.True(t, result.IsValid)
+	require.Len(t, staging, 1)
+	assert.JSONEq(t, `{"id": "1", "city": "Zürich"}`, string(staging[0].Data))
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// DefaultDataPageCacheTTL is how long dataset data pages are cached when no TTL is configured
+const DefaultDataPageCacheTTL = 5 * time.Minute
+
+// DataPageCache caches pages of stored dataset rows. Keys include the dataset's updated_at and a
+// per-dataset generation that Invalidate replaces, so data changes never serve a stale page.
+// A nil cache is disabled: Get always misses and Set and Invalidate do nothing.
+type DataPageCache struct {
+	cache cache.Cache
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// DataPageKey identifies one cached page of a dataset version
+type DataPageKey struct {
+	DatasetID   uuid.UUID
+	UpdatedAt   time.Time
+	Page        int
+	PageSize    int
+	MaxRows     int
+	IncludeMeta bool
+}
+
+// NewDataPageCache creates a dataset data page cache
+func NewDataPageCache(c cache.Cache, ttl time.Duration) *DataPageCache {
+	if ttl <= 0 {
+		ttl = DefaultDataPageCacheTTL
+	}
+	return &DataPageCache{cache: c, ttl: ttl, now: time.Now}
+}
+
+func dataPageGenerationKey(datasetID uuid.UUID) string {
+	return fmt.Sprintf("data_page_gen:%s", datasetID)
+}
+
+func dataPageKey(key DataPageKey, generation string) string {
+	return fmt.Sprintf("data_page:%s:%s:%d:%d:%d:%d:%t", key.DatasetID, generation, key.UpdatedAt.UTC().UnixNano(), key.Page, key.PageSize, key.MaxRows, key.IncludeMeta)
+}
+
+// generation returns the dataset's current cache generation, "0" until it is first invalidated
+func (c *DataPageCache) generation(ctx context.Context, datasetID uuid.UUID) (string, error) {
+	value, err := c.cache.Get(ctx, dataPageGenerationKey(datasetID))
+	if errors.Is(err, cache.ErrMiss) {
+		return "0", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// Get returns the cached page, if any
+func (c *DataPageCache) Get(ctx context.Context, key DataPageKey) (*models.DataPreviewResponse, bool) {
+	if c == nil {
+		return nil, false
+	}
+	generation, err := c.generation(ctx, key.DatasetID)
+	if err != nil {
+		log.Printf("Warning: failed to read data page cache generation for dataset %s: %v", key.DatasetID, err)
+		return nil, false
+	}
+
+	data, err := c.cache.Get(ctx, dataPageKey(key, generation))
+	if err != nil {
+		if !errors.Is(err, cache.ErrMiss) {
+			log.Printf("Warning: failed to read cached data page for dataset %s: %v", key.DatasetID, err)
+		}
+		return nil, false
+	}
+
+	var page models.DataPreviewResponse
+	if err := json.Unmarshal(data, &page); err != nil {
+		log.Printf("Warning: discarding unreadable cached data page for dataset %s: %v", key.DatasetID, err)
+		return nil, false
+	}
+	return &page, true
+}
+
+// Set caches a page as read from storage, before any per-user masking
+func (c *DataPageCache) Set(ctx context.Context, key DataPageKey, page *models.DataPreviewResponse) error {
+	if c == nil {
+		return nil
+	}
+	generation, err := c.generation(ctx, key.DatasetID)
+	if err != nil {
+		return fmt.Errorf("failed to read data page cache generation: %w", err)
+	}
+	data, err := json.Marshal(page)
+	if err != nil {
+		return fmt.Errorf("failed to encode data page: %w", err)
+	}
+	return c.cache.Set(ctx, dataPageKey(key, generation), data, c.ttl)
+}
+
+// Invalidate starts a new generation for the dataset, so every page cached before it is missed and
+// left to expire
+func (c *DataPageCache) Invalidate(ctx context.Context, datasetID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	generation := strconv.FormatInt(c.now().UnixNano(), 10)
+	if err := c.cache.Set(ctx, dataPageGenerationKey(datasetID), []byte(generation), c.ttl); err != nil {
+		log.Printf("Warning: failed to invalidate cached data pages for dataset %s: %v", datasetID, err)
+	}
+}
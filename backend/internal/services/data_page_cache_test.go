@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataPageCache(t *testing.T) {
+	ctx := context.Background()
+	pages := NewDataPageCache(cache.NewMemoryCache(), time.Minute)
+	key := DataPageKey{DatasetID: uuid.New(), UpdatedAt: time.Now(), Page: 2, PageSize: 50, MaxRows: 1000}
+	page := &models.DataPreviewResponse{
+		Data:       []map[string]interface{}{{"name": "Ada", "age": float64(36)}},
+		Columns:    []string{"name", "age"},
+		TotalRows:  51,
+		Page:       2,
+		PageSize:   50,
+		TotalPages: 2,
+	}
+
+	_, ok := pages.Get(ctx, key)
+	assert.False(t, ok)
+
+	require.NoError(t, pages.Set(ctx, key, page))
+	cached, ok := pages.Get(ctx, key)
+	require.True(t, ok)
+	assert.Equal(t, page, cached)
+
+	// A different page, page size or provenance flag is cached separately
+	other := key
+	other.Page = 1
+	_, ok = pages.Get(ctx, other)
+	assert.False(t, ok)
+	other = key
+	other.IncludeMeta = true
+	_, ok = pages.Get(ctx, other)
+	assert.False(t, ok)
+
+	// New data bumps updated_at, which must not hit the old entry
+	newer := key
+	newer.UpdatedAt = key.UpdatedAt.Add(time.Second)
+	_, ok = pages.Get(ctx, newer)
+	assert.False(t, ok)
+
+	// Invalidation drops every cached page of the dataset, even for the same updated_at
+	pages.Invalidate(ctx, key.DatasetID)
+	_, ok = pages.Get(ctx, key)
+	assert.False(t, ok)
+
+	require.NoError(t, pages.Set(ctx, key, page))
+	_, ok = pages.Get(ctx, key)
+	assert.True(t, ok)
+}
+
+func TestDataPageCache_NilIsDisabled(t *testing.T) {
+	ctx := context.Background()
+	var pages *DataPageCache
+	key := DataPageKey{DatasetID: uuid.New(), UpdatedAt: time.Now(), Page: 1, PageSize: 50}
+
+	require.NoError(t, pages.Set(ctx, key, &models.DataPreviewResponse{Page: 1}))
+	_, ok := pages.Get(ctx, key)
+	assert.False(t, ok)
+	pages.Invalidate(ctx, key.DatasetID)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// searchTermPattern extracts the words of a search query, as Postgres' simple text search
+// configuration splits them
+var searchTermPattern = regexp.MustCompile(`[\p{L}\p{N}_]+`)
+
+// SearchTerms returns the distinct lowercase words of a web-style search query, skipping the
+// "or" operator and words excluded with a leading "-"
+func SearchTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		if strings.HasPrefix(word, "-") || strings.EqualFold(word, "or") {
+			continue
+		}
+		for _, term := range searchTermPattern.FindAllString(strings.ToLower(word), -1) {
+			if !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
+
+// HighlightRow returns the row's fields whose values contain any of the terms as a whole word,
+// with each occurrence wrapped in <mark> tags. Values are HTML-escaped so the result can be
+// rendered safely.
+func HighlightRow(row map[string]interface{}, terms []string) map[string]string {
+	highlights := make(map[string]string)
+	if len(terms) == 0 {
+		return highlights
+	}
+
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	// Longer terms first so a term that prefixes another doesn't split its match
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	pattern := regexp.MustCompile(`(?i)(^|[^\p{L}\p{N}_])(` + strings.Join(quoted, "|") + `)([^\p{L}\p{N}_]|$)`)
+
+	for field, value := range row {
+		if strings.HasPrefix(field, "_") || value == nil {
+			continue
+		}
+		text := fmt.Sprintf("%v", value)
+		if !pattern.MatchString(text) {
+			continue
+		}
+		highlights[field] = highlightText(text, pattern)
+	}
+	return highlights
+}
+
+// highlightText escapes text and marks every match of pattern's second group
+func highlightText(text string, pattern *regexp.Regexp) string {
+	var b strings.Builder
+	last := 0
+	for start := 0; start < len(text); {
+		loc := pattern.FindStringSubmatchIndex(text[start:])
+		if loc == nil {
+			break
+		}
+		termStart, termEnd := start+loc[4], start+loc[5]
+		b.WriteString(html.EscapeString(text[last:termStart]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[termStart:termEnd]))
+		b.WriteString("</mark>")
+		last = termEnd
+		// Continue from the term's end so a shared separator can start the next match
+		start = termEnd
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
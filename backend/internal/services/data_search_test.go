@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"acme", "corp", "london"}, SearchTerms(`"Acme Corp" or London -Paris acme`))
+	assert.Empty(t, SearchTerms("  -- or  "))
+}
+
+func TestHighlightRow(t *testing.T) {
+	row := map[string]interface{}{
+		"_row_index": 3,
+		"company":    "Acme Corp <UK>",
+		"city":       "London",
+		"notes":      "Acmeville branch",
+		"revenue":    float64(1200),
+		"manager":    nil,
+	}
+
+	highlights := HighlightRow(row, SearchTerms("acme london 1200"))
+
+	assert.Equal(t, map[string]string{
+		"company": "<mark>Acme</mark> Corp &lt;UK&gt;",
+		"city":    "<mark>London</mark>",
+		"revenue": "<mark>1200</mark>",
+	}, highlights, "only whole-word matches are highlighted")
+}
+
+func TestHighlightRow_RepeatedAndAdjacentTerms(t *testing.T) {
+	row := map[string]interface{}{"title": "red red-blue Red"}
+
+	highlights := HighlightRow(row, []string{"red", "blue"})
+
+	assert.Equal(t, "<mark>red</mark> <mark>red</mark>-<mark>blue</mark> <mark>Red</mark>", highlights["title"])
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// Dataset export formats
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// DefaultExportRowGroupSize is how many rows a Parquet export buffers per row group, which bounds
+// the exporter's memory use
+const DefaultExportRowGroupSize = 10000
+
+// exportContentTypes maps each export format to its Content-Type
+var exportContentTypes = map[string]string{
+	ExportFormatCSV:     "text/csv",
+	ExportFormatParquet: "application/vnd.apache.parquet",
+}
+
+// ExportContentType returns the Content-Type of an export format, or false when it is unsupported
+func ExportContentType(format string) (string, bool) {
+	contentType, ok := exportContentTypes[format]
+	return contentType, ok
+}
+
+// DatasetExporter writes dataset rows in an export format, one batch at a time. Close must be called
+// once every row is written to finish the output.
+type DatasetExporter interface {
+	WriteRows(rows []map[string]interface{}) error
+	Close() error
+}
+
+// NewDatasetExporter starts an export of rows with the given fields in format
+func NewDatasetExporter(format string, w io.Writer, fields []models.SchemaField) (DatasetExporter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return NewDatasetCSVExporter(w, fields)
+	case ExportFormatParquet:
+		return NewDatasetParquetExporter(w, fields, DefaultExportRowGroupSize), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format '%s'", format)
+	}
+}
+
+// DatasetExportFields returns the fields to export: the schema's in position order when known,
+// otherwise string fields for the sorted keys found in rows
+func DatasetExportFields(schema *models.DatasetSchema, rows []map[string]interface{}) []models.SchemaField {
+	if schema != nil && len(schema.Fields) > 0 {
+		return sortedFields(schema.Fields)
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, row := range rows {
+		for name := range row {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	fields := make([]models.SchemaField, len(names))
+	for i, name := range names {
+		fields[i] = models.SchemaField{Name: name, DataType: string(models.FieldTypeString), Position: i + 1}
+	}
+	return fields
+}
+
+// DatasetCSVExporter writes dataset rows as CSV with a header of field names
+type DatasetCSVExporter struct {
+	w      *csv.Writer
+	fields []models.SchemaField
+}
+
+// NewDatasetCSVExporter writes the header for fields and returns the exporter
+func NewDatasetCSVExporter(w io.Writer, fields []models.SchemaField) (*DatasetCSVExporter, error) {
+	e := &DatasetCSVExporter{w: csv.NewWriter(w), fields: fields}
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = field.Name
+	}
+	if err := e.w.Write(header); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// WriteRows writes a batch of rows and flushes them to the underlying writer
+func (e *DatasetCSVExporter) WriteRows(rows []map[string]interface{}) error {
+	for _, row := range rows {
+		record := make([]string, len(e.fields))
+		for i, field := range e.fields {
+			record[i] = exportCellValue(row[field.Name])
+		}
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Close flushes any buffered output
+func (e *DatasetCSVExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// DatasetParquetExporter writes dataset rows as Parquet, one optional column per field typed after
+// the field's data type. Values that can't be read as their field's type are written as nulls.
+type DatasetParquetExporter struct {
+	writer  *parquet.Writer
+	columns []parquetColumn
+}
+
+// parquetColumn is a field and the index of its leaf column in the Parquet schema
+type parquetColumn struct {
+	field models.SchemaField
+	index int
+}
+
+// NewDatasetParquetExporter starts a Parquet export of fields, cutting a row group every rowGroupSize rows
+func NewDatasetParquetExporter(w io.Writer, fields []models.SchemaField, rowGroupSize int) *DatasetParquetExporter {
+	group := make(parquet.Group, len(fields))
+	for _, field := range fields {
+		group[field.Name] = parquet.Optional(parquetNode(field.DataType))
+	}
+	schema := parquet.NewSchema("dataset", group)
+
+	// Group columns are ordered by name, so look up each field's leaf index
+	indexes := make(map[string]int, len(fields))
+	for i, path := range schema.Columns() {
+		indexes[path[0]] = i
+	}
+	columns := make([]parquetColumn, len(fields))
+	for i, field := range fields {
+		columns[i] = parquetColumn{field: field, index: indexes[field.Name]}
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i].index < columns[j].index })
+
+	return &DatasetParquetExporter{
+		writer:  parquet.NewWriter(w, schema, parquet.MaxRowsPerRowGroup(int64(rowGroupSize))),
+		columns: columns,
+	}
+}
+
+// parquetNode maps a schema field type to its Parquet logical type
+func parquetNode(dataType string) parquet.Node {
+	switch models.SchemaFieldType(dataType) {
+	case models.FieldTypeNumber, models.FieldTypeCurrency, models.FieldTypePercent:
+		return parquet.Leaf(parquet.DoubleType)
+	case models.FieldTypeInteger:
+		return parquet.Int(64)
+	case models.FieldTypeBoolean:
+		return parquet.Leaf(parquet.BooleanType)
+	case models.FieldTypeDate:
+		return parquet.Date()
+	case models.FieldTypeDateTime:
+		return parquet.Timestamp(parquet.Millisecond)
+	case models.FieldTypeUUID:
+		return parquet.UUID()
+	case models.FieldTypeObject, models.FieldTypeArray, models.FieldTypeList:
+		return parquet.JSON()
+	default:
+		return parquet.String()
+	}
+}
+
+// WriteRows buffers a batch of rows; full row groups are written out as they fill
+func (e *DatasetParquetExporter) WriteRows(rows []map[string]interface{}) error {
+	batch := make([]parquet.Row, len(rows))
+	for i, row := range rows {
+		values := make(parquet.Row, len(e.columns))
+		for j, column := range e.columns {
+			value, ok := parquetValue(column.field.DataType, row[column.field.Name])
+			if ok {
+				values[j] = value.Level(0, 1, column.index)
+			} else {
+				values[j] = parquet.NullValue().Level(0, 0, column.index)
+			}
+		}
+		batch[i] = values
+	}
+	_, err := e.writer.WriteRows(batch)
+	return err
+}
+
+// Close writes the last row group and the file footer
+func (e *DatasetParquetExporter) Close() error {
+	return e.writer.Close()
+}
+
+// parquetValue converts a stored JSON value to the Parquet value of its field type
+func parquetValue(dataType string, value interface{}) (parquet.Value, bool) {
+	if value == nil {
+		return parquet.Value{}, false
+	}
+	text, isString := value.(string)
+	if isString && strings.TrimSpace(text) == "" {
+		return parquet.Value{}, false
+	}
+
+	switch models.SchemaFieldType(dataType) {
+	case models.FieldTypeNumber, models.FieldTypeCurrency, models.FieldTypePercent:
+		if n, ok := exportNumber(value); ok {
+			return parquet.DoubleValue(n), true
+		}
+	case models.FieldTypeInteger:
+		if n, ok := exportNumber(value); ok && n == float64(int64(n)) {
+			return parquet.Int64Value(int64(n)), true
+		}
+	case models.FieldTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return parquet.BooleanValue(v), true
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return parquet.BooleanValue(b), true
+			}
+		}
+	case models.FieldTypeDate:
+		if t, err := time.Parse(NormalizedDateFormat, text); isString && err == nil {
+			return parquet.Int32Value(int32(t.Unix() / 86400)), true
+		}
+	case models.FieldTypeDateTime:
+		if t, ok := parseExportTimestamp(text); isString && ok {
+			return parquet.Int64Value(t.UnixMilli()), true
+		}
+	case models.FieldTypeUUID:
+		if id, err := uuid.Parse(text); isString && err == nil {
+			return parquet.FixedLenByteArrayValue(id[:]), true
+		}
+	case models.FieldTypeObject, models.FieldTypeArray, models.FieldTypeList:
+		if isString {
+			return parquet.ByteArrayValue([]byte(text)), true
+		}
+		if data, err := json.Marshal(value); err == nil {
+			return parquet.ByteArrayValue(data), true
+		}
+	default:
+		return parquet.ByteArrayValue([]byte(exportCellValue(value))), true
+	}
+	return parquet.Value{}, false
+}
+
+// exportNumber reads a stored number, which may have been kept as a string
+func exportNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// parseExportTimestamp reads a stored datetime written as RFC 3339 or "2006-01-02 15:04:05" (UTC)
+func parseExportTimestamp(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetParquetExporter(t *testing.T) {
+	fields := []models.SchemaField{
+		{Name: "name", DataType: "string", Position: 1},
+		{Name: "age", DataType: "integer", Position: 2},
+		{Name: "balance", DataType: "currency", Position: 3},
+		{Name: "active", DataType: "boolean", Position: 4},
+		{Name: "joined", DataType: "date", Position: 5},
+		{Name: "seen_at", DataType: "datetime", Position: 6},
+		{Name: "id", DataType: "uuid", Position: 7},
+		{Name: "tags", DataType: "array", Position: 8},
+	}
+
+	var buf bytes.Buffer
+	exporter := NewDatasetParquetExporter(&buf, fields, 2)
+
+	// Rows arrive in batches that don't line up with row groups
+	require.NoError(t, exporter.WriteRows([]map[string]interface{}{
+		{"name": "Ada", "age": float64(36), "balance": 1234.5, "active": true, "joined": "2024-01-02",
+			"seen_at": "2024-01-02T03:04:05Z", "id": "6f1c3b9e-6d0f-4a8e-9d57-2f8b0e3c1a11", "tags": []interface{}{"a", "b"}},
+		{"name": "Grace", "age": "41", "active": "false"},
+		{"name": "Alan", "age": "not a number", "joined": "someday"},
+	}))
+	require.NoError(t, exporter.WriteRows([]map[string]interface{}{{"name": "Edsger"}}))
+	require.NoError(t, exporter.Close())
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), file.NumRows())
+	assert.Len(t, file.RowGroups(), 2, "row groups are cut every rowGroupSize rows")
+
+	columnTypes := make(map[string]string)
+	for _, field := range file.Schema().Fields() {
+		assert.True(t, field.Optional(), "column %s is nullable", field.Name())
+		columnTypes[field.Name()] = field.Type().String()
+	}
+	assert.Equal(t, map[string]string{
+		"name":    "STRING",
+		"age":     "INT(64,true)",
+		"balance": "DOUBLE",
+		"active":  "BOOLEAN",
+		"joined":  "DATE",
+		"seen_at": "TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS)",
+		"id":      "UUID",
+		"tags":    "JSON",
+	}, columnTypes)
+
+	rows := make([]parquet.Row, 4)
+	reader := parquet.NewReader(bytes.NewReader(buf.Bytes()))
+	n, _ := reader.ReadRows(rows)
+	require.Equal(t, 4, n)
+
+	values := func(row parquet.Row) map[string]parquet.Value {
+		byName := make(map[string]parquet.Value)
+		for _, value := range row {
+			byName[file.Schema().Fields()[value.Column()].Name()] = value
+		}
+		return byName
+	}
+
+	first := values(rows[0])
+	assert.Equal(t, int64(36), first["age"].Int64())
+	assert.Equal(t, 1234.5, first["balance"].Double())
+	assert.Equal(t, int32(19724), first["joined"].Int32(), "days since the epoch")
+	assert.Equal(t, int64(1704164645000), first["seen_at"].Int64())
+	assert.Equal(t, `["a","b"]`, string(first["tags"].ByteArray()))
+
+	second := values(rows[1])
+	assert.Equal(t, int64(41), second["age"].Int64(), "numbers stored as strings are converted")
+	assert.False(t, second["active"].Boolean())
+	assert.True(t, second["balance"].IsNull())
+
+	third := values(rows[2])
+	assert.True(t, third["age"].IsNull(), "values not matching their type become null")
+	assert.True(t, third["joined"].IsNull())
+	assert.Equal(t, "Alan", string(third["name"].ByteArray()))
+}
+
+func TestDatasetCSVExporter(t *testing.T) {
+	var buf bytes.Buffer
+	fields := DatasetExportFields(nil, []map[string]interface{}{{"b": 1, "a": "x"}})
+	exporter, err := NewDatasetExporter(ExportFormatCSV, &buf, fields)
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.WriteRows([]map[string]interface{}{{"a": "x", "b": float64(1)}, {"a": "y", "b": map[string]interface{}{"k": "v"}}}))
+	require.NoError(t, exporter.Close())
+	assert.Equal(t, "a,b\nx,1\ny,\"{\"\"k\"\":\"\"v\"\"}\"\n", buf.String())
+
+	_, err = NewDatasetExporter("xlsx", &buf, fields)
+	assert.Error(t, err)
+	_, ok := ExportContentType(ExportFormatParquet)
+	assert.True(t, ok)
+}
+
+func TestDatasetExportFields(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "second", DataType: "number", Position: 2},
+		{Name: "first", DataType: "string", Position: 1},
+	}}
+	fields := DatasetExportFields(schema, nil)
+	require.Len(t, fields, 2)
+	assert.Equal(t, "first", fields[0].Name)
+	assert.Equal(t, "number", fields[1].DataType)
+
+	fields = DatasetExportFields(nil, []map[string]interface{}{{"z": 1}, {"y": 2}})
+	assert.Equal(t, "y,z", fmt.Sprintf("%s,%s", fields[0].Name, fields[1].Name))
+	assert.Equal(t, "string", fields[0].DataType)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// NormalizedDateFormat is the layout date fields are stored in once a submission is applied
+const NormalizedDateFormat = "2006-01-02"
+
+// defaultDateFormats are accepted for date fields when a dataset defines no formats of its own
+var defaultDateFormats = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"01/02/2006",
+	"02-01-2006",
+}
+
+// parseDate parses a value with the dataset's formats, falling back to the defaults when none are set
+func parseDate(value string, dateFormats []string) (time.Time, bool) {
+	formats := dateFormats
+	if len(formats) == 0 {
+		formats = defaultDateFormats
+	}
+
+	value = strings.TrimSpace(value)
+	for _, format := range formats {
+		if parsed, err := time.Parse(format, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// NormalizeDateFields rewrites parseable date field values in place to NormalizedDateFormat
+// and reports whether any value changed
+func NormalizeDateFields(rowData map[string]interface{}, schema *models.DatasetSchema) bool {
+	changed := false
+	for _, field := range schema.Fields {
+		if field.DataType != string(models.FieldTypeDate) {
+			continue
+		}
+
+		value, exists := rowData[field.Name]
+		if !exists || value == nil || value == "" {
+			continue
+		}
+
+		valueStr := fmt.Sprintf("%v", value)
+		parsed, ok := parseDate(valueStr, schema.DateFormats)
+		if !ok {
+			continue
+		}
+
+		if normalized := parsed.Format(NormalizedDateFormat); normalized != valueStr {
+			rowData[field.Name] = normalized
+			changed = true
+		}
+	}
+	return changed
+}
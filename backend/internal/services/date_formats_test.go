@@ -0,0 +1,63 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationService_CustomDateFormats(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields:      []models.SchemaField{{Name: "joined", DataType: "date"}},
+		DateFormats: []string{"Jan 2, 2006"},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	t.Run("custom format accepted", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"joined": "Mar 5, 2024"}, schema, 0)
+		assert.Empty(t, result.Errors)
+	})
+
+	t.Run("non-matching value rejected", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"joined": "2024-03-05"}, schema, 0)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "invalid_data_type", result.Errors[0].ErrorType)
+		assert.Equal(t, "Jan 2, 2006", result.Errors[0].ExpectedValue)
+	})
+
+	t.Run("defaults apply without custom formats", func(t *testing.T) {
+		plain := &models.DatasetSchema{Fields: schema.Fields}
+		assert.Empty(t, svc.validateRowAgainstSchema(map[string]interface{}{"joined": "2024-03-05"}, plain, 0).Errors)
+		assert.Len(t, svc.validateRowAgainstSchema(map[string]interface{}{"joined": "Mar 5, 2024"}, plain, 0).Errors, 1)
+	})
+}
+
+func TestValidationService_NormalizeStagingValues(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "joined", DataType: "date"},
+			{Name: "name", DataType: "string"},
+		},
+		DateFormats: []string{"Jan 2, 2006", "2006-01-02"},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	staging := stagedRows(t,
+		map[string]interface{}{"joined": "Mar 5, 2024", "name": "Mar 5, 2024"},
+		map[string]interface{}{"joined": "2024-01-09", "name": "Bob"},
+	)
+
+	changed, err := svc.NormalizeStagingValues(uuid.New(), "", staging)
+	require.NoError(t, err)
+	require.Len(t, changed, 1)
+	assert.Equal(t, 0, changed[0].RowIndex)
+
+	var row map[string]interface{}
+	require.NoError(t, json.Unmarshal(changed[0].Data, &row))
+	assert.Equal(t, "2024-03-05", row["joined"])
+	assert.Equal(t, "Mar 5, 2024", row["name"])
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ErrMissingScope is returned when an API key lacks the scope required for an operation
+var ErrMissingScope = errors.New("api key is missing the required scope")
+
+// ErrDatasetNotTrusted is returned when a dataset does not accept direct appends
+var ErrDatasetNotTrusted = errors.New("dataset does not accept direct appends")
+
+// ErrReadOnlyAPIKey is returned when an API key's role only allows reading
+var ErrReadOnlyAPIKey = errors.New("api key's role can't append rows")
+
+// ErrProjectMismatch is returned when an API key belongs to a different project than the dataset
+var ErrProjectMismatch = errors.New("api key does not belong to the dataset's project")
+
+// CoercionError is returned when rows cannot be converted to the dataset schema types
+type CoercionError struct {
+	Errors []models.DataValidationError
+}
+
+func (e *CoercionError) Error() string {
+	return fmt.Sprintf("%d value(s) could not be coerced to the dataset schema", len(e.Errors))
+}
+
+// DirectAppendRepositoryInterface defines the data access needed for direct appends
+type DirectAppendRepositoryInterface interface {
+	GetDatasetByID(datasetID uuid.UUID) (*models.Dataset, error)
+	GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error)
+	AppendDatasetRows(datasetID uuid.UUID, rows []map[string]interface{}, userID uuid.UUID) (int, error)
+	PIIValueFinder
+}
+
+// DirectAppendService appends pre-validated rows from trusted services, bypassing staging and review
+type DirectAppendService struct {
+	repo DirectAppendRepositoryInterface
+}
+
+// NewDirectAppendService creates a new direct append service
+func NewDirectAppendService(repo DirectAppendRepositoryInterface) *DirectAppendService {
+	return &DirectAppendService{repo: repo}
+}
+
+// AppendRows coerces rows to the dataset schema and appends them on behalf of an API key
+func (s *DirectAppendService) AppendRows(key *models.APIKey, datasetID uuid.UUID, rows []map[string]interface{}) (*models.DirectAppendResult, error) {
+	if !key.HasScope(models.APIKeyScopeTrustedAppend) {
+		return nil, ErrMissingScope
+	}
+	// Viewer keys granted the scope before creation refused it still can't write
+	if key.IsViewer() {
+		return nil, ErrReadOnlyAPIKey
+	}
+
+	dataset, err := s.repo.GetDatasetByID(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dataset.ProjectID != key.ProjectID {
+		return nil, ErrProjectMismatch
+	}
+
+	if !dataset.IsTrusted {
+		return nil, ErrDatasetNotTrusted
+	}
+
+	schema, err := s.repo.GetSchemaByDatasetID(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows are trusted to be valid, but values are still stored with the schema's types
+	coercedRows := make([]map[string]interface{}, len(rows))
+	var coercionErrors []models.DataValidationError
+	for i, row := range rows {
+		ApplyFieldTransforms(row, schema)
+		coerced, errs := CoerceRowToSchema(row, schema, i)
+		coercedRows[i] = coerced
+		for _, err := range errs {
+			// Coerced rows never hold unknown fields, so lenient datasets just drop them
+			if dataset.HeaderMode == models.HeaderModeLenient && err.ErrorType == "unexpected_field" {
+				continue
+			}
+			coercionErrors = append(coercionErrors, err)
+		}
+	}
+
+	if len(coercionErrors) > 0 {
+		return nil, &CoercionError{Errors: coercionErrors}
+	}
+
+	// Trust covers the values' format, not whether the contacts are already in the dataset
+	duplicates, err := CheckPIIGuardrails(s.repo, datasetID, dataset.PIIGuardrails, coercedRows)
+	if err != nil {
+		return nil, err
+	}
+	if len(duplicates) > 0 {
+		return nil, &PIIDuplicateError{Errors: piiValidationErrors(duplicates)}
+	}
+
+	startIndex, err := s.repo.AppendDatasetRows(datasetID, coercedRows, key.ActorID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to append rows: %w", err)
+	}
+
+	return &models.DirectAppendResult{
+		DatasetID:     datasetID,
+		RowsAppended:  len(coercedRows),
+		StartRowIndex: startIndex,
+	}, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDirectAppendRepository is an in-memory DirectAppendRepositoryInterface
+type fakeDirectAppendRepository struct {
+	dataset    *models.Dataset
+	schema     *models.DatasetSchema
+	appended   []map[string]interface{}
+	appendedBy uuid.UUID
+}
+
+// FindExistingPIIValues matches the normalized values against the appended rows
+func (f *fakeDirectAppendRepository) FindExistingPIIValues(datasetID uuid.UUID, fieldName, kind string, values []string) (map[string]int, error) {
+	existing := make(map[string]int)
+	for _, value := range values {
+		for i, row := range f.appended {
+			if stored, ok := row[fieldName].(string); ok && NormalizePII(kind, stored) == value {
+				existing[value] = i
+				break
+			}
+		}
+	}
+	return existing, nil
+}
+
+func (f *fakeDirectAppendRepository) GetDatasetByID(datasetID uuid.UUID) (*models.Dataset, error) {
+	return f.dataset, nil
+}
+
+func (f *fakeDirectAppendRepository) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error) {
+	return f.schema, nil
+}
+
+func (f *fakeDirectAppendRepository) AppendDatasetRows(datasetID uuid.UUID, rows []map[string]interface{}, userID uuid.UUID) (int, error) {
+	start := len(f.appended)
+	f.appended = append(f.appended, rows...)
+	f.appendedBy = userID
+	return start, nil
+}
+
+func newDirectAppendFixture(trusted bool) (*fakeDirectAppendRepository, *models.APIKey) {
+	projectID := uuid.New()
+	repo := &fakeDirectAppendRepository{
+		dataset: &models.Dataset{ID: uuid.New(), ProjectID: projectID, IsTrusted: trusted},
+		schema: &models.DatasetSchema{Fields: []models.SchemaField{
+			{Name: "name", DataType: "string"},
+			{Name: "amount", DataType: "number"},
+			{Name: "active", DataType: "boolean"},
+		}},
+	}
+	role := "collaborator"
+	principalID := uuid.New()
+	key := &models.APIKey{
+		ID:          uuid.New(),
+		ProjectID:   projectID,
+		Scopes:      []string{models.APIKeyScopeTrustedAppend},
+		Role:        &role,
+		PrincipalID: &principalID,
+		CreatedBy:   uuid.New(),
+	}
+	return repo, key
+}
+
+func TestDirectAppendService_AppendRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"name": "alpha", "amount": "12.5", "active": "true"},
+		{"name": "beta", "amount": 3.0, "active": false},
+	}
+
+	t.Run("trusted key appends coerced rows", func(t *testing.T) {
+		repo, key := newDirectAppendFixture(true)
+		svc := NewDirectAppendService(repo)
+
+		result, err := svc.AppendRows(key, repo.dataset.ID, rows)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.RowsAppended)
+		assert.Equal(t, 0, result.StartRowIndex)
+		require.Len(t, repo.appended, 2)
+		assert.Equal(t, 12.5, repo.appended[0]["amount"])
+		assert.Equal(t, true, repo.appended[0]["active"])
+		assert.Equal(t, *key.PrincipalID, repo.appendedBy, "rows are appended as the key's principal")
+	})
+
+	t.Run("rejected when dataset is not trusted", func(t *testing.T) {
+		repo, key := newDirectAppendFixture(false)
+		svc := NewDirectAppendService(repo)
+
+		_, err := svc.AppendRows(key, repo.dataset.ID, rows)
+		assert.True(t, errors.Is(err, ErrDatasetNotTrusted))
+		assert.Empty(t, repo.appended)
+	})
+
+	t.Run("rejected when key lacks scope", func(t *testing.T) {
+		repo, key := newDirectAppendFixture(true)
+		key.Scopes = []string{"data:read"}
+		svc := NewDirectAppendService(repo)
+
+		_, err := svc.AppendRows(key, repo.dataset.ID, rows)
+		assert.True(t, errors.Is(err, ErrMissingScope))
+		assert.Empty(t, repo.appended)
+	})
+
+	t.Run("rejected when key has the viewer role", func(t *testing.T) {
+		repo, key := newDirectAppendFixture(true)
+		role := models.RoleViewer
+		key.Role = &role
+		svc := NewDirectAppendService(repo)
+
+		_, err := svc.AppendRows(key, repo.dataset.ID, rows)
+		assert.True(t, errors.Is(err, ErrReadOnlyAPIKey))
+		assert.Empty(t, repo.appended)
+	})
+
+	t.Run("rejected when key belongs to another project", func(t *testing.T) {
+		repo, key := newDirectAppendFixture(true)
+		key.ProjectID = uuid.New()
+		svc := NewDirectAppendService(repo)
+
+		_, err := svc.AppendRows(key, repo.dataset.ID, rows)
+		assert.True(t, errors.Is(err, ErrProjectMismatch))
+		assert.Empty(t, repo.appended)
+	})
+
+	t.Run("rejected when values cannot be coerced", func(t *testing.T) {
+		repo, key := newDirectAppendFixture(true)
+		svc := NewDirectAppendService(repo)
+
+		_, err := svc.AppendRows(key, repo.dataset.ID, []map[string]interface{}{
+			{"name": "gamma", "amount": "lots", "active": "yes"},
+		})
+		var coercionErr *CoercionError
+		require.True(t, errors.As(err, &coercionErr))
+		assert.Len(t, coercionErr.Errors, 2)
+		assert.Empty(t, repo.appended)
+	})
+
+	t.Run("unknown fields depend on the header mode", func(t *testing.T) {
+		extra := []map[string]interface{}{{"name": "delta", "amount": "1", "active": "true", "note": "hi"}}
+
+		repo, key := newDirectAppendFixture(true)
+		_, err := NewDirectAppendService(repo).AppendRows(key, repo.dataset.ID, extra)
+		var coercionErr *CoercionError
+		require.True(t, errors.As(err, &coercionErr))
+		assert.Equal(t, "unexpected_field", coercionErr.Errors[0].ErrorType)
+
+		repo.dataset.HeaderMode = models.HeaderModeLenient
+		_, err = NewDirectAppendService(repo).AppendRows(key, repo.dataset.ID, extra)
+		require.NoError(t, err)
+		require.Len(t, repo.appended, 1)
+		assert.NotContains(t, repo.appended[0], "note")
+	})
+}
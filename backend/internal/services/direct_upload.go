@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+)
+
+// DefaultDirectUploadExpiry is how long a pre-signed upload URL stays valid
+const DefaultDirectUploadExpiry = 15 * time.Minute
+
+// DefaultDirectUploadMaxSizeMB is the largest direct upload, in megabytes, accepted when
+// DIRECT_UPLOAD_MAX_SIZE_MB is not set
+const DefaultDirectUploadMaxSizeMB = 500
+
+var (
+	// ErrDirectUploadUnsupported is returned when the configured storage cannot pre-sign uploads
+	ErrDirectUploadUnsupported = errors.New("file storage does not support direct uploads")
+	// ErrDirectUploadNotFound is returned when completing an upload whose file was never stored
+	ErrDirectUploadNotFound = errors.New("uploaded file not found")
+	// ErrInvalidUploadFileName is returned for empty file names or names containing a path
+	ErrInvalidUploadFileName = errors.New("invalid file name")
+)
+
+// DirectUploadTooLargeError reports a direct upload larger than the configured maximum
+type DirectUploadTooLargeError struct {
+	Limit int64 // bytes
+}
+
+func (e *DirectUploadTooLargeError) Error() string {
+	return fmt.Sprintf("uploaded file exceeds the limit of %d bytes", e.Limit)
+}
+
+// MaxDirectUploadSizeFromEnv reads DIRECT_UPLOAD_MAX_SIZE_MB and returns the limit in bytes; an
+// unset, invalid or non-positive value uses the default
+func MaxDirectUploadSizeFromEnv() int64 {
+	value := os.Getenv("DIRECT_UPLOAD_MAX_SIZE_MB")
+	if value == "" {
+		return DefaultDirectUploadMaxSizeMB << 20
+	}
+
+	megabytes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || megabytes <= 0 {
+		log.Printf("Warning: invalid DIRECT_UPLOAD_MAX_SIZE_MB %q, using default of %d", value, DefaultDirectUploadMaxSizeMB)
+		return DefaultDirectUploadMaxSizeMB << 20
+	}
+	return megabytes << 20
+}
+
+// DirectUpload is a pre-signed URL a client uploads a submission file to
+type DirectUpload struct {
+	UploadID  uuid.UUID `json:"upload_id"`
+	FileName  string    `json:"file_name"`
+	URL       string    `json:"url"`
+	Method    string    `json:"method"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CompletedUpload describes a file stored through a direct upload
+type CompletedUpload struct {
+	Key  string
+	Size int64
+	Hash string // hex SHA-256 of the content
+	Head []byte // leading bytes of the content, for content sniffing
+}
+
+// directUploadHeadLength is how many leading bytes CompletedUpload.Head keeps
+const directUploadHeadLength = 512
+
+// DirectUploadService lets clients upload submission files straight to blob storage with a
+// pre-signed URL, then picks the stored file up when the client reports the upload complete
+type DirectUploadService struct {
+	files   storage.Storage
+	expiry  time.Duration
+	maxSize int64
+	now     func() time.Time
+}
+
+// NewDirectUploadService creates a direct upload service accepting files of up to maxSize bytes;
+// expiry defaults to DefaultDirectUploadExpiry and maxSize to DefaultDirectUploadMaxSizeMB
+func NewDirectUploadService(files storage.Storage, expiry time.Duration, maxSize int64) *DirectUploadService {
+	if expiry <= 0 {
+		expiry = DefaultDirectUploadExpiry
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultDirectUploadMaxSizeMB << 20
+	}
+	return &DirectUploadService{files: files, expiry: expiry, maxSize: maxSize, now: time.Now}
+}
+
+// DirectUploadKey returns the storage key of a direct upload, named like form-uploaded submission
+// files. The key names the dataset and user the upload was pre-signed for, so only that user can
+// complete it, and only into that dataset.
+func DirectUploadKey(datasetID, userID, uploadID uuid.UUID, fileName string) string {
+	return storage.Key("submissions", fmt.Sprintf("%s_%s_%s_%s", datasetID, userID, uploadID, fileName))
+}
+
+// cleanUploadFileName rejects names that would escape the submissions prefix
+func cleanUploadFileName(fileName string) (string, error) {
+	name := strings.TrimSpace(fileName)
+	if name == "" || name != path.Base(name) || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", ErrInvalidUploadFileName
+	}
+	return name, nil
+}
+
+// Presign reserves an upload ID for a user's file to a dataset and returns a URL the client can
+// PUT the file to
+func (s *DirectUploadService) Presign(ctx context.Context, datasetID, userID uuid.UUID, fileName string) (*DirectUpload, error) {
+	presigner, ok := s.files.(storage.Presigner)
+	if !ok {
+		return nil, ErrDirectUploadUnsupported
+	}
+	name, err := cleanUploadFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadID := uuid.New()
+	url, err := presigner.PresignPut(ctx, DirectUploadKey(datasetID, userID, uploadID, name), s.expiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pre-sign upload: %w", err)
+	}
+
+	return &DirectUpload{
+		UploadID:  uploadID,
+		FileName:  name,
+		URL:       url,
+		Method:    http.MethodPut,
+		ExpiresAt: s.now().Add(s.expiry),
+	}, nil
+}
+
+// Complete reads the file stored for an upload the user pre-signed for the dataset, returning its
+// key, size and content hash. Uploads pre-signed for another user or dataset are not found. A file
+// over the size limit is deleted and reported as a *DirectUploadTooLargeError, without reading
+// more than the limit.
+func (s *DirectUploadService) Complete(ctx context.Context, datasetID, userID, uploadID uuid.UUID, fileName string) (*CompletedUpload, error) {
+	name, err := cleanUploadFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	key := DirectUploadKey(datasetID, userID, uploadID, name)
+	file, err := s.files.Open(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrDirectUploadNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	head := &limitedBuffer{limit: directUploadHeadLength}
+	size, err := io.Copy(io.MultiWriter(hasher, head), io.LimitReader(file, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+	if size > s.maxSize {
+		if err := s.files.Delete(ctx, key); err != nil {
+			log.Printf("Warning: failed to delete oversized upload %s: %v", key, err)
+		}
+		return nil, &DirectUploadTooLargeError{Limit: s.maxSize}
+	}
+
+	return &CompletedUpload{
+		Key:  key,
+		Size: size,
+		Hash: hex.EncodeToString(hasher.Sum(nil)),
+		Head: head.data,
+	}, nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	data  []byte
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - len(b.data); remaining > 0 {
+		b.data = append(b.data, p[:min(remaining, len(p))]...)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePresignStore keeps objects in memory and hands out fake pre-signed URLs that the test
+// "uploads" to with Upload
+type fakePresignStore struct {
+	objects map[string][]byte
+	signed  map[string]time.Duration
+}
+
+func newFakePresignStore() *fakePresignStore {
+	return &fakePresignStore{objects: map[string][]byte{}, signed: map[string]time.Duration{}}
+}
+
+func (f *fakePresignStore) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	f.objects[key] = data
+	return err
+}
+
+func (f *fakePresignStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := f.objects[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakePresignStore) Delete(ctx context.Context, key string) error {
+	delete(f.objects, key)
+	return nil
+}
+
+func (f *fakePresignStore) List(ctx context.Context, prefix string) ([]storage.Object, error) {
+	return nil, nil
+}
+
+func (f *fakePresignStore) PresignPut(ctx context.Context, key string, expires time.Duration) (string, error) {
+	f.signed[key] = expires
+	return "https://blob.example.com/" + url.PathEscape(key) + "?signature=fake", nil
+}
+
+// Upload plays the client's PUT to a URL returned by PresignPut
+func (f *fakePresignStore) Upload(t *testing.T, rawURL string, content string) {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	key := strings.TrimPrefix(u.Path, "/")
+	_, signed := f.signed[key]
+	require.True(t, signed, "upload to a URL that was not pre-signed")
+	f.objects[key] = []byte(content)
+}
+
+func TestDirectUploadService_PresignThenComplete(t *testing.T) {
+	ctx := context.Background()
+	store := newFakePresignStore()
+	svc := NewDirectUploadService(store, 0, 0)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+	datasetID, userID := uuid.New(), uuid.New()
+
+	upload, err := svc.Presign(ctx, datasetID, userID, "sales.csv")
+	require.NoError(t, err)
+	assert.Equal(t, "sales.csv", upload.FileName)
+	assert.Equal(t, "PUT", upload.Method)
+	assert.Equal(t, now.Add(DefaultDirectUploadExpiry), upload.ExpiresAt)
+	assert.Equal(t, DefaultDirectUploadExpiry, store.signed[DirectUploadKey(datasetID, userID, upload.UploadID, "sales.csv")])
+
+	t.Run("completing before the upload", func(t *testing.T) {
+		_, err := svc.Complete(ctx, datasetID, userID, upload.UploadID, "sales.csv")
+		assert.ErrorIs(t, err, ErrDirectUploadNotFound)
+	})
+
+	content := "id,amount\n" + strings.Repeat("1,9.99\n", 200)
+	store.Upload(t, upload.URL, content)
+
+	completed, err := svc.Complete(ctx, datasetID, userID, upload.UploadID, "sales.csv")
+	require.NoError(t, err)
+	sum := sha256.Sum256([]byte(content))
+	assert.Equal(t, "submissions/"+datasetID.String()+"_"+userID.String()+"_"+upload.UploadID.String()+"_sales.csv", completed.Key)
+	assert.Equal(t, int64(len(content)), completed.Size)
+	assert.Equal(t, hex.EncodeToString(sum[:]), completed.Hash)
+	assert.Equal(t, content[:directUploadHeadLength], string(completed.Head))
+
+	t.Run("another upload ID does not see the file", func(t *testing.T) {
+		_, err := svc.Complete(ctx, datasetID, userID, uuid.New(), "sales.csv")
+		assert.ErrorIs(t, err, ErrDirectUploadNotFound)
+	})
+
+	t.Run("another user or dataset can't complete the upload", func(t *testing.T) {
+		_, err := svc.Complete(ctx, datasetID, uuid.New(), upload.UploadID, "sales.csv")
+		assert.ErrorIs(t, err, ErrDirectUploadNotFound)
+		_, err = svc.Complete(ctx, uuid.New(), userID, upload.UploadID, "sales.csv")
+		assert.ErrorIs(t, err, ErrDirectUploadNotFound)
+	})
+}
+
+func TestDirectUploadService_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("storage without pre-signing", func(t *testing.T) {
+		svc := NewDirectUploadService(storage.NewLocalStorage(t.TempDir()), time.Minute, 0)
+		_, err := svc.Presign(ctx, uuid.New(), uuid.New(), "sales.csv")
+		assert.ErrorIs(t, err, ErrDirectUploadUnsupported)
+	})
+
+	t.Run("file names with paths", func(t *testing.T) {
+		svc := NewDirectUploadService(newFakePresignStore(), time.Minute, 0)
+		for _, name := range []string{"", "  ", "../sales.csv", "a/b.csv", `a\b.csv`, ".."} {
+			_, err := svc.Presign(ctx, uuid.New(), uuid.New(), name)
+			assert.ErrorIs(t, err, ErrInvalidUploadFileName, name)
+			_, err = svc.Complete(ctx, uuid.New(), uuid.New(), uuid.New(), name)
+			assert.ErrorIs(t, err, ErrInvalidUploadFileName, name)
+		}
+	})
+
+	t.Run("file over the size limit", func(t *testing.T) {
+		store := newFakePresignStore()
+		svc := NewDirectUploadService(store, time.Minute, 10)
+		datasetID, userID := uuid.New(), uuid.New()
+		upload, err := svc.Presign(ctx, datasetID, userID, "sales.csv")
+		require.NoError(t, err)
+		store.Upload(t, upload.URL, "id,amount\n1,2\n")
+
+		_, err = svc.Complete(ctx, datasetID, userID, upload.UploadID, "sales.csv")
+		var tooLargeErr *DirectUploadTooLargeError
+		require.ErrorAs(t, err, &tooLargeErr)
+		assert.Equal(t, int64(10), tooLargeErr.Limit)
+		assert.Empty(t, store.objects, "the oversized file is deleted")
+	})
+}
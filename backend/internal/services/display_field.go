@@ -0,0 +1,32 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ValidateDisplayField checks that a display field names a field of the dataset's schema
+func ValidateDisplayField(schema *models.DatasetSchema, displayField string) error {
+	for _, field := range schema.Fields {
+		if field.Name == displayField {
+			if field.DataType == string(models.FieldTypeObject) || field.DataType == string(models.FieldTypeArray) || field.DataType == string(models.FieldTypeList) {
+				return fmt.Errorf("field '%s' holds nested JSON and cannot label rows", displayField)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("field '%s' is not defined in the dataset schema", displayField)
+}
+
+// ApplyRowLabels sets each row's RowLabelKey to the value of its display field, or an empty
+// string when the row has no value for it
+func ApplyRowLabels(rows []map[string]interface{}, displayField string) {
+	for _, row := range rows {
+		label := ""
+		if value, exists := row[displayField]; exists && value != nil {
+			label = fmt.Sprintf("%v", value)
+		}
+		row[models.RowLabelKey] = label
+	}
+}
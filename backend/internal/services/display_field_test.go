@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDisplayField(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "name", DataType: "string"},
+		{Name: "tags", DataType: "array"},
+	}}
+
+	assert.NoError(t, ValidateDisplayField(schema, "name"))
+	assert.Error(t, ValidateDisplayField(schema, "missing"))
+	assert.Error(t, ValidateDisplayField(schema, "tags"))
+}
+
+func TestApplyRowLabels(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"_row_index": 0, "id": float64(1), "name": "Alice"},
+		{"_row_index": 1, "id": float64(2), "name": nil},
+		{"_row_index": 2, "id": float64(3)},
+	}
+
+	ApplyRowLabels(rows, "name")
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Alice", rows[0][models.RowLabelKey])
+	assert.Equal(t, "", rows[1][models.RowLabelKey])
+	assert.Equal(t, "", rows[2][models.RowLabelKey])
+
+	// Changing the display field changes the labels
+	ApplyRowLabels(rows, "id")
+	assert.Equal(t, "1", rows[0][models.RowLabelKey])
+	assert.Equal(t, "3", rows[2][models.RowLabelKey])
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+const (
+	// DefaultDisplayRows is how many rows of a dataset can be paged through when neither the
+	// project nor DISPLAY_ROWS_DEFAULT sets a cap
+	DefaultDisplayRows = 1000
+	// DefaultMaxDisplayRows is the server-wide ceiling on display caps when DISPLAY_ROWS_MAX is not set
+	DefaultMaxDisplayRows = 10000
+)
+
+// DisplayRowLimits bounds how many rows of a dataset can be paged through in the data viewer and
+// search. Default applies to projects without their own cap; Max bounds every project and request.
+type DisplayRowLimits struct {
+	Default int
+	Max     int
+}
+
+// DisplayRowLimitsFromEnv reads DISPLAY_ROWS_DEFAULT and DISPLAY_ROWS_MAX, falling back to the
+// package defaults when unset or invalid. The default never exceeds the max.
+func DisplayRowLimitsFromEnv() DisplayRowLimits {
+	limits := DisplayRowLimits{
+		Default: positiveIntFromEnv("DISPLAY_ROWS_DEFAULT", DefaultDisplayRows),
+		Max:     positiveIntFromEnv("DISPLAY_ROWS_MAX", DefaultMaxDisplayRows),
+	}
+	if limits.Default > limits.Max {
+		log.Printf("Warning: DISPLAY_ROWS_DEFAULT %d exceeds DISPLAY_ROWS_MAX %d, using %d", limits.Default, limits.Max, limits.Max)
+		limits.Default = limits.Max
+	}
+	return limits
+}
+
+func positiveIntFromEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		log.Printf("Warning: invalid %s %q, using default of %d", name, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+// ValidateProjectCap checks a project's display cap; zero clears the cap back to the default
+func (l DisplayRowLimits) ValidateProjectCap(projectCap int) error {
+	if projectCap < 0 || projectCap > l.Max {
+		return fmt.Errorf("max display rows must be between 1 and %d, or 0 to use the default", l.Max)
+	}
+	return nil
+}
+
+// Resolve returns the display cap for a request: the project's cap (or Default when the project
+// has none) bounded by Max, lowered to the max_rows query value when that is a smaller positive number
+func (l DisplayRowLimits) Resolve(projectCap *int, requested string) int {
+	limit := l.Default
+	if projectCap != nil && *projectCap > 0 {
+		limit = *projectCap
+	}
+	limit = min(limit, l.Max)
+
+	if requested != "" {
+		if n, err := strconv.Atoi(requested); err == nil && n > 0 && n < limit {
+			limit = n
+		}
+	}
+	return limit
+}
+
+// ClampDisplayPage keeps page within the pages needed to show the first maxRows rows
+func ClampDisplayPage(page, pageSize, maxRows int) int {
+	lastPage := max((maxRows+pageSize-1)/pageSize, 1)
+	return min(max(page, 1), lastPage)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayRowLimitsFromEnv(t *testing.T) {
+	t.Setenv("DISPLAY_ROWS_DEFAULT", "")
+	t.Setenv("DISPLAY_ROWS_MAX", "")
+	assert.Equal(t, DisplayRowLimits{Default: DefaultDisplayRows, Max: DefaultMaxDisplayRows}, DisplayRowLimitsFromEnv())
+
+	t.Setenv("DISPLAY_ROWS_DEFAULT", "2500")
+	t.Setenv("DISPLAY_ROWS_MAX", "50000")
+	assert.Equal(t, DisplayRowLimits{Default: 2500, Max: 50000}, DisplayRowLimitsFromEnv())
+
+	t.Setenv("DISPLAY_ROWS_DEFAULT", "-1")
+	t.Setenv("DISPLAY_ROWS_MAX", "lots")
+	assert.Equal(t, DisplayRowLimits{Default: DefaultDisplayRows, Max: DefaultMaxDisplayRows}, DisplayRowLimitsFromEnv())
+
+	t.Setenv("DISPLAY_ROWS_DEFAULT", "5000")
+	t.Setenv("DISPLAY_ROWS_MAX", "2000")
+	assert.Equal(t, DisplayRowLimits{Default: 2000, Max: 2000}, DisplayRowLimitsFromEnv(), "default is bounded by the max")
+}
+
+func TestDisplayRowLimits_Resolve(t *testing.T) {
+	limits := DisplayRowLimits{Default: 1000, Max: 10000}
+	projectCap := func(n int) *int { return &n }
+
+	tests := []struct {
+		name       string
+		projectCap *int
+		requested  string
+		expected   int
+	}{
+		{"server default", nil, "", 1000},
+		{"project cap", projectCap(5000), "", 5000},
+		{"project cap above the max", projectCap(50000), "", 10000},
+		{"cleared project cap", projectCap(0), "", 1000},
+		{"request lowers the cap", projectCap(5000), "200", 200},
+		{"request cannot raise the cap", nil, "5000", 1000},
+		{"invalid request is ignored", nil, "all", 1000},
+		{"non-positive request is ignored", nil, "0", 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, limits.Resolve(tt.projectCap, tt.requested))
+		})
+	}
+}
+
+func TestDisplayRowLimits_ValidateProjectCap(t *testing.T) {
+	limits := DisplayRowLimits{Default: 1000, Max: 10000}
+
+	assert.NoError(t, limits.ValidateProjectCap(0))
+	assert.NoError(t, limits.ValidateProjectCap(10000))
+	assert.Error(t, limits.ValidateProjectCap(10001))
+	assert.Error(t, limits.ValidateProjectCap(-5))
+}
+
+func TestClampDisplayPage(t *testing.T) {
+	assert.Equal(t, 1, ClampDisplayPage(0, 50, 1000))
+	assert.Equal(t, 20, ClampDisplayPage(20, 50, 1000))
+	assert.Equal(t, 20, ClampDisplayPage(99, 50, 1000))
+	assert.Equal(t, 34, ClampDisplayPage(99, 30, 1000), "a partial last page is reachable")
+	assert.Equal(t, 1, ClampDisplayPage(5, 100, 40), "caps smaller than a page keep page 1")
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// MaxDraftValidationRows caps how many rows one draft schema validation may check
+const MaxDraftValidationRows = 100
+
+// DraftSchema builds an unsaved schema from field definitions, filling in display names and
+// positions the way saved schemas get them. It rejects fields whose validation formats are invalid.
+func DraftSchema(fields []models.CreateFieldRequest, dateFormats []string) (*models.DatasetSchema, error) {
+	schema := &models.DatasetSchema{Name: "draft", DateFormats: dateFormats}
+	names := make(map[string]bool, len(fields))
+	for i, fieldReq := range fields {
+		if names[fieldReq.Name] {
+			return nil, fmt.Errorf("field '%s' is defined more than once", fieldReq.Name)
+		}
+		names[fieldReq.Name] = true
+
+		field := models.SchemaField{
+			Name:         fieldReq.Name,
+			DisplayName:  fieldReq.DisplayName,
+			DataType:     fieldReq.DataType,
+			IsRequired:   fieldReq.IsRequired,
+			IsUnique:     fieldReq.IsUnique,
+			DefaultValue: fieldReq.DefaultValue,
+			Position:     fieldReq.Position,
+			Validation:   fieldReq.Validation,
+		}
+		if field.DisplayName == "" {
+			field.DisplayName = field.Name
+		}
+		if field.Position == 0 {
+			field.Position = i + 1
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+
+	if err := ValidatePhoneFormats(schema.Fields); err != nil {
+		return nil, err
+	}
+	if err := ValidateEmailFormats(schema.Fields); err != nil {
+		return nil, err
+	}
+	if err := ValidateNumericPrecision(schema.Fields); err != nil {
+		return nil, err
+	}
+	if err := ValidateFieldTransforms(schema.Fields); err != nil {
+		return nil, err
+	}
+	if err := ValidateListFields(schema.Fields); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// ValidateDraftRows checks rows against a schema that need not be saved, the way submitted rows
+// are checked: transforms and defaults are applied, structured and list cells decoded and each row validated against the
+// schema's fields. Business rules, guardrails and uniqueness need a dataset and are not checked.
+// Rows are not modified.
+func (v *ValidationService) ValidateDraftRows(schema *models.DatasetSchema, rows []map[string]interface{}) *models.ValidationResult {
+	result := newValidationResult(schema)
+	result.TotalRows = len(rows)
+
+	allRowData := make([]map[string]interface{}, len(rows))
+	for rowIndex, row := range rows {
+		rowData := make(map[string]interface{}, len(row))
+		for name, value := range row {
+			rowData[name] = value
+		}
+		ApplyFieldTransforms(rowData, schema)
+		ApplyFieldDefaults(rowData, schema)
+		NullEmptyNumericFields(rowData, schema)
+		DecodeStructuredFields(rowData, schema)
+		DecodeListFields(rowData, schema)
+		allRowData[rowIndex] = rowData
+
+		rowValidation := v.validateRowAgainstSchema(rowData, schema, rowIndex)
+		result.SchemaErrors = append(result.SchemaErrors, rowValidation.Errors...)
+		v.updateFieldStats(rowData, schema, result.FieldStats)
+		countInvalidValues(rowValidation.Errors, result.FieldStats)
+
+		if len(rowValidation.Errors) > 0 {
+			result.InvalidRows++
+		} else {
+			result.ValidRows++
+		}
+	}
+
+	v.calculateUniqueValues(allRowData, result.FieldStats)
+	result.IsValid = result.InvalidRows == 0
+	return result
+}
+
+// ValidateRowEdit checks a directly edited row against a schema the way a submitted row is checked.
+// The row is prepared in place as an appended row would be stored: transforms and defaults are
+// applied, empty numbers made null and structured and list cells decoded. Business rules,
+// guardrails and uniqueness are not checked. It returns the row's field errors.
+func ValidateRowEdit(rowData map[string]interface{}, schema *models.DatasetSchema, rowIndex int) []models.DataValidationError {
+	ApplyFieldTransforms(rowData, schema)
+	ApplyFieldDefaults(rowData, schema)
+	NullEmptyNumericFields(rowData, schema)
+	DecodeStructuredFields(rowData, schema)
+	DecodeListFields(rowData, schema)
+
+	// Only the stateless checks are used, so the validator needs no repositories
+	v := &ValidationService{}
+	return v.validateRowAgainstSchema(rowData, schema, rowIndex).Errors
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDraftRows(t *testing.T) {
+	status := "open"
+	schema, err := DraftSchema([]models.CreateFieldRequest{
+		{Name: "id", DataType: "integer", IsRequired: true},
+		{Name: "email", DataType: "email"},
+		{Name: "status", DataType: "string", IsRequired: true, DefaultValue: &status},
+	}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "id", schema.Fields[0].DisplayName)
+	assert.Equal(t, 3, schema.Fields[2].Position)
+
+	rows := []map[string]interface{}{
+		{"id": 1.0, "email": "a@example.com"},
+		{"id": "x", "email": "not an email", "status": "closed"},
+		{"email": ""},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{}, &fakeSubmissionRepository{})
+	result := svc.ValidateDraftRows(schema, rows)
+
+	assert.False(t, result.IsValid)
+	assert.Equal(t, 3, result.TotalRows)
+	assert.Equal(t, 1, result.ValidRows)
+	assert.Equal(t, 2, result.InvalidRows)
+
+	fieldErrors := make(map[string]int)
+	for _, validationErr := range result.SchemaErrors {
+		fieldErrors[validationErr.FieldName]++
+		assert.NotZero(t, validationErr.RowIndex, "the first row is valid")
+	}
+	assert.Equal(t, map[string]int{"id": 2, "email": 1}, fieldErrors, "status takes its default when missing")
+	assert.NotContains(t, rows[0], "status", "the request rows are not modified")
+}
+
+func TestDraftSchema_RejectsInvalidDefinitions(t *testing.T) {
+	_, err := DraftSchema([]models.CreateFieldRequest{
+		{Name: "id", DataType: "string"},
+		{Name: "id", DataType: "integer"},
+	}, nil)
+	assert.EqualError(t, err, "field 'id' is defined more than once")
+
+	format := "nowhere"
+	_, err = DraftSchema([]models.CreateFieldRequest{
+		{Name: "phone", DataType: "phone", Validation: models.FieldValidation{Format: &format}},
+	}, nil)
+	assert.Error(t, err)
+}
+
+func TestValidateRowEdit(t *testing.T) {
+	status := "open"
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "id", DataType: "integer", IsRequired: true},
+		{Name: "status", DataType: "string", DefaultValue: &status, Validation: models.FieldValidation{Transforms: []string{models.FieldTransformLowercase}}},
+		{Name: "amount", DataType: "number"},
+	}}
+
+	row := map[string]interface{}{"id": "7", "status": "CLOSED", "amount": ""}
+	assert.Empty(t, ValidateRowEdit(row, schema, 3))
+	assert.Equal(t, map[string]interface{}{"id": "7", "status": "closed", "amount": nil}, row, "edits are stored like appended rows")
+
+	errs := ValidateRowEdit(map[string]interface{}{"id": "seven", "amount": "lots"}, schema, 3)
+	require.Len(t, errs, 2)
+	assert.Equal(t, "id", errs[0].FieldName)
+	assert.Equal(t, "amount", errs[1].FieldName)
+	assert.Equal(t, 3, errs[0].RowIndex)
+}
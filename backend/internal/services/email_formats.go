@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// Email strictness levels, set service-wide or per field through FieldValidation.Format
+const (
+	EmailStrictnessBasic  = "basic"  // common user@domain.tld shape
+	EmailStrictnessStrict = "strict" // RFC 5322 dot-atom local part and RFC 1035 domain labels
+)
+
+// basicEmailPattern is the historical email check
+var basicEmailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+
+var (
+	// atextPattern matches one dot-atom part of a local part (RFC 5322 atext)
+	atextPattern = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+/=?^_`{|}~-]+$")
+	// domainLabelPattern matches a hostname label that doesn't start or end with a hyphen
+	domainLabelPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+	// tldPattern matches alphabetic top-level domains and IDN (xn--) ones
+	tldPattern = regexp.MustCompile(`^([a-zA-Z]{2,63}|xn--[a-zA-Z0-9-]{1,59})$`)
+)
+
+// mxLookupTimeout bounds each MX lookup so slow DNS doesn't stall validation
+const mxLookupTimeout = 3 * time.Second
+
+// EmailValidationFromEnv reads EMAIL_VALIDATION_STRICTNESS (basic or strict, default basic) and
+// EMAIL_VALIDATION_MX_CHECK (true enables DNS MX lookups of email domains)
+func EmailValidationFromEnv() (string, bool) {
+	strictness := strings.ToLower(os.Getenv("EMAIL_VALIDATION_STRICTNESS"))
+	switch strictness {
+	case "":
+		strictness = EmailStrictnessBasic
+	case EmailStrictnessBasic, EmailStrictnessStrict:
+	default:
+		log.Printf("Warning: invalid EMAIL_VALIDATION_STRICTNESS %q, using %s", strictness, EmailStrictnessBasic)
+		strictness = EmailStrictnessBasic
+	}
+	return strictness, os.Getenv("EMAIL_VALIDATION_MX_CHECK") == "true"
+}
+
+// ValidateEmailFormats rejects email fields whose validation format names an unsupported strictness
+func ValidateEmailFormats(fields []models.SchemaField) error {
+	for _, field := range fields {
+		if field.DataType != string(models.FieldTypeEmail) || field.Validation.Format == nil || *field.Validation.Format == "" {
+			continue
+		}
+		switch strings.ToLower(*field.Validation.Format) {
+		case EmailStrictnessBasic, EmailStrictnessStrict:
+		default:
+			return fmt.Errorf("field '%s' has unsupported email format '%s' (supported: basic, strict)", field.Name, *field.Validation.Format)
+		}
+	}
+	return nil
+}
+
+// IsValidEmail checks an email address at the given strictness; anything but strict is basic
+func IsValidEmail(value, strictness string) bool {
+	if strings.ToLower(strictness) != EmailStrictnessStrict {
+		return basicEmailPattern.MatchString(value)
+	}
+
+	// RFC 5321 length limits
+	if len(value) > 254 {
+		return false
+	}
+	at := strings.LastIndex(value, "@")
+	if at < 1 || at == len(value)-1 {
+		return false
+	}
+	local, domain := value[:at], value[at+1:]
+	if len(local) > 64 {
+		return false
+	}
+
+	for _, atom := range strings.Split(local, ".") {
+		if !atextPattern.MatchString(atom) {
+			return false
+		}
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if !domainLabelPattern.MatchString(label) {
+			return false
+		}
+	}
+	return tldPattern.MatchString(labels[len(labels)-1])
+}
+
+// mxChecker looks up whether email domains accept mail, caching answers per domain
+type mxChecker struct {
+	lookup func(ctx context.Context, domain string) ([]*net.MX, error)
+	mu     sync.Mutex
+	known  map[string]bool
+}
+
+func newMXChecker() *mxChecker {
+	return &mxChecker{lookup: net.DefaultResolver.LookupMX, known: make(map[string]bool)}
+}
+
+// hasMailServer reports whether the address's domain has MX records. Lookups that fail for
+// reasons other than the domain not existing count as success, so DNS outages don't reject data.
+func (m *mxChecker) hasMailServer(email string) bool {
+	domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+
+	m.mu.Lock()
+	accepts, cached := m.known[domain]
+	m.mu.Unlock()
+	if cached {
+		return accepts
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mxLookupTimeout)
+	defer cancel()
+
+	records, err := m.lookup(ctx, domain)
+	var dnsErr *net.DNSError
+	switch {
+	case err == nil:
+		accepts = len(records) > 0
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		accepts = false
+	default:
+		log.Printf("Warning: MX lookup for %s failed, accepting address: %v", domain, err)
+		return true
+	}
+
+	m.mu.Lock()
+	m.known[domain] = accepts
+	m.mu.Unlock()
+	return accepts
+}
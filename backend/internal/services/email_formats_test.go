@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidEmail(t *testing.T) {
+	tests := []struct {
+		email  string
+		basic  bool
+		strict bool
+	}{
+		{"user@example.com", true, true},
+		{"first.last+tag@mail.example.co.uk", true, true},
+		{"o'brien@example.com", false, true},
+		{"user!team@example.org", false, true},
+		{"user@example.xn--p1ai", false, true},
+		{"user@example..com", true, false},
+		{"user@-example.com", true, false},
+		{"user@example-.com", true, false},
+		{".user@example.com", true, false},
+		{"us..er@example.com", true, false},
+		{"user@localhost", false, false},
+		{"user@example.c0m", false, false},
+		{"@example.com", false, false},
+		{"user@", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.basic, IsValidEmail(tt.email, EmailStrictnessBasic), "basic")
+			assert.Equal(t, tt.strict, IsValidEmail(tt.email, EmailStrictnessStrict), "strict")
+		})
+	}
+}
+
+func TestValidationService_EmailStrictness(t *testing.T) {
+	basicFormat := "basic"
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "email", DataType: "email"},
+			{Name: "legacy_email", DataType: "email", Validation: models.FieldValidation{Format: &basicFormat}},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+	row := map[string]interface{}{"email": "user@example..com", "legacy_email": "user@example..com"}
+
+	// Basic by default
+	result := svc.validateRowAgainstSchema(row, schema, 0)
+	assert.Empty(t, result.Errors)
+
+	// Strict service default; the field format keeps the legacy field basic
+	svc.SetEmailValidation(EmailStrictnessStrict, false)
+	result = svc.validateRowAgainstSchema(row, schema, 0)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "email", result.Errors[0].FieldName)
+	assert.Equal(t, "invalid_data_type", result.Errors[0].ErrorType)
+}
+
+func TestMXChecker(t *testing.T) {
+	lookups := 0
+	checker := &mxChecker{known: make(map[string]bool), lookup: func(ctx context.Context, domain string) ([]*net.MX, error) {
+		lookups++
+		switch domain {
+		case "example.com":
+			return []*net.MX{{Host: "mail.example.com.", Pref: 10}}, nil
+		case "flaky.example":
+			return nil, errors.New("i/o timeout")
+		default:
+			return nil, &net.DNSError{Err: "no such host", Name: domain, IsNotFound: true}
+		}
+	}}
+
+	assert.True(t, checker.hasMailServer("a@Example.com"))
+	assert.True(t, checker.hasMailServer("b@example.com"))
+	assert.Equal(t, 1, lookups, "answers are cached per domain")
+
+	assert.False(t, checker.hasMailServer("a@nomail.example"))
+	assert.True(t, checker.hasMailServer("a@flaky.example"), "lookup failures don't reject data")
+}
+
+func TestValidateEmailFormats(t *testing.T) {
+	strict, unsupported := "strict", "paranoid"
+	assert.NoError(t, ValidateEmailFormats([]models.SchemaField{
+		{Name: "a", DataType: "email", Validation: models.FieldValidation{Format: &strict}},
+		{Name: "b", DataType: "phone", Validation: models.FieldValidation{Format: &unsupported}},
+	}))
+	assert.ErrorContains(t, ValidateEmailFormats([]models.SchemaField{
+		{Name: "c", DataType: "email", Validation: models.FieldValidation{Format: &unsupported}},
+	}), "unsupported email format 'paranoid'")
+}
+
+func TestEmailValidationFromEnv(t *testing.T) {
+	t.Setenv("EMAIL_VALIDATION_STRICTNESS", "")
+	t.Setenv("EMAIL_VALIDATION_MX_CHECK", "")
+	strictness, checkMX := EmailValidationFromEnv()
+	assert.Equal(t, EmailStrictnessBasic, strictness)
+	assert.False(t, checkMX)
+
+	t.Setenv("EMAIL_VALIDATION_STRICTNESS", "Strict")
+	t.Setenv("EMAIL_VALIDATION_MX_CHECK", "true")
+	strictness, checkMX = EmailValidationFromEnv()
+	assert.Equal(t, EmailStrictnessStrict, strictness)
+	assert.True(t, checkMX)
+}
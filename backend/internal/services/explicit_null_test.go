@@ -0,0 +1,76 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestNullEmptyNumericFields(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "amount", DataType: "currency"},
+		{Name: "count", DataType: "integer"},
+		{Name: "note", DataType: "string"},
+		{Name: "score", DataType: "number"},
+	}}
+
+	row := map[string]interface{}{"amount": "", "count": "0", "note": ""}
+	assert.True(t, NullEmptyNumericFields(row, schema))
+	assert.Equal(t, map[string]interface{}{"amount": nil, "count": "0", "note": ""}, row, "zero is kept and text stays empty")
+	assert.False(t, NullEmptyNumericFields(row, schema))
+
+	t.Run("explicit nulls are not defaulted", func(t *testing.T) {
+		zero := "0"
+		withDefault := &models.DatasetSchema{Fields: []models.SchemaField{{Name: "count", DataType: "integer", DefaultValue: &zero}}}
+
+		row := map[string]interface{}{"count": nil}
+		assert.False(t, ApplyFieldDefaults(row, withDefault))
+		assert.Nil(t, row["count"])
+
+		row = map[string]interface{}{"count": ""}
+		assert.True(t, ApplyFieldDefaults(row, withDefault))
+		assert.Equal(t, "0", row["count"])
+	})
+
+	t.Run("coercion stores empty numbers as null", func(t *testing.T) {
+		coerced, errs := CoerceRowToSchema(map[string]interface{}{"amount": "", "count": 0.0, "note": nil}, schema, 0)
+		assert.Empty(t, errs)
+		assert.Equal(t, map[string]interface{}{"amount": nil, "count": 0.0, "note": nil, "score": nil}, coerced)
+	})
+}
+
+func TestValidationService_ExplicitNullsAreNotZero(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "id", DataType: "string", IsRequired: true},
+		{Name: "discount", DataType: "number"},
+		{Name: "quantity", DataType: "integer", IsRequired: true},
+	}}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "orders.csv")
+	require.NoError(t, os.WriteFile(path, []byte("id,discount,quantity\na,0,0\nb,,\n"), 0o644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	require.Len(t, staging, 2)
+	assert.JSONEq(t, `{"id": "a", "discount": "0", "quantity": "0"}`, string(staging[0].Data))
+	assert.JSONEq(t, `{"id": "b", "discount": null, "quantity": null}`, string(staging[1].Data), "empty numbers are stored as null")
+
+	assert.Equal(t, 1, result.FieldStats["discount"].NullValues, "zero is not counted as null")
+	assert.Equal(t, 1, result.FieldStats["discount"].UniqueValues)
+	require.Len(t, result.SchemaErrors, 1, "a null required field is still reported")
+	assert.Equal(t, "quantity", result.SchemaErrors[0].FieldName)
+	assert.Equal(t, "required_field", result.SchemaErrors[0].ErrorType)
+}
+
+func TestComputeFieldStats_ExplicitNulls(t *testing.T) {
+	rows := []map[string]interface{}{{"total": 0.0}, {"total": nil}, {}, {"total": 12.5}}
+	stats := ComputeFieldStats(rows, &models.DatasetSchema{Fields: []models.SchemaField{{Name: "total", DataType: "number"}}})
+	assert.Equal(t, models.FieldStats{TotalValues: 4, UniqueValues: 2, NullValues: 2, Values: []string{"0", "12.5"}}, stats["total"])
+}
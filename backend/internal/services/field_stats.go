@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+)
+
+// DefaultFieldStatsCacheTTL is how long field statistics are cached when no TTL is configured
+const DefaultFieldStatsCacheTTL = 10 * time.Minute
+
+// FieldStatsRepositoryInterface is the storage used to compute and persist dataset field statistics
+type FieldStatsRepositoryInterface interface {
+	GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error)
+	GetAllDatasetRows(datasetID uuid.UUID) ([]map[string]interface{}, error)
+	GetDatasetLastModified(datasetID uuid.UUID) (time.Time, error)
+	SaveFieldStats(stats *models.DatasetFieldStats) error
+	GetFieldStats(datasetID uuid.UUID) (*models.DatasetFieldStats, error)
+}
+
+// FieldStatsService computes, stores and caches per-field statistics over a dataset's stored rows.
+// Cache keys include the dataset's last modification time, so any data change invalidates them.
+type FieldStatsService struct {
+	repo  FieldStatsRepositoryInterface
+	cache cache.Cache
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// NewFieldStatsService creates a field statistics service
+func NewFieldStatsService(repo FieldStatsRepositoryInterface, c cache.Cache, ttl time.Duration) *FieldStatsService {
+	if ttl <= 0 {
+		ttl = DefaultFieldStatsCacheTTL
+	}
+	return &FieldStatsService{repo: repo, cache: c, ttl: ttl, now: time.Now}
+}
+
+func fieldStatsKey(datasetID uuid.UUID, lastModified time.Time) string {
+	return fmt.Sprintf("field_stats:%s:%d", datasetID, lastModified.UTC().UnixNano())
+}
+
+// ComputeFieldStats computes statistics for each schema field over the given rows. Without a schema
+// every key found in the rows is counted as a string field.
+func ComputeFieldStats(rows []map[string]interface{}, schema *models.DatasetSchema) map[string]models.FieldStats {
+	if schema == nil {
+		schema = &models.DatasetSchema{}
+		seen := make(map[string]bool)
+		for _, row := range rows {
+			for name := range row {
+				if !seen[name] {
+					seen[name] = true
+					schema.Fields = append(schema.Fields, models.SchemaField{Name: name, DataType: string(models.FieldTypeString)})
+				}
+			}
+		}
+		sort.Slice(schema.Fields, func(i, j int) bool { return schema.Fields[i].Name < schema.Fields[j].Name })
+	}
+
+	// Only the stateless type checks are used, so the validator needs no repositories
+	v := &ValidationService{}
+	fieldStats := make(map[string]models.FieldStats, len(schema.Fields))
+	for _, field := range schema.Fields {
+		fieldStats[field.Name] = models.FieldStats{}
+	}
+
+	for rowIndex, row := range rows {
+		v.updateFieldStats(row, schema, fieldStats)
+
+		for _, field := range schema.Fields {
+			value, exists := row[field.Name]
+			if isNullValue(value, exists) {
+				continue
+			}
+			if v.validateDataType(value, field, schema.DateFormats, rowIndex) != nil {
+				stats := fieldStats[field.Name]
+				stats.InvalidValues++
+				fieldStats[field.Name] = stats
+			}
+		}
+	}
+	v.calculateUniqueValues(rows, fieldStats)
+
+	return fieldStats
+}
+
+// Compute scans the dataset's stored rows, persists fresh statistics and caches them
+func (s *FieldStatsService) Compute(ctx context.Context, datasetID uuid.UUID) (*models.DatasetFieldStats, error) {
+	lastModified, err := s.repo.GetDatasetLastModified(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fall back to inferring fields from the data when the dataset has no schema yet
+	schema, err := s.repo.GetSchemaByDatasetID(datasetID)
+	if errors.Is(err, repository.ErrSchemaNotFound) {
+		log.Printf("No schema for dataset %s, computing stats from stored fields", datasetID)
+		schema = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.GetAllDatasetRows(datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.DatasetFieldStats{
+		DatasetID:  datasetID,
+		RowCount:   len(rows),
+		Fields:     ComputeFieldStats(rows, schema),
+		ComputedAt: s.now().UTC(),
+	}
+	if err := s.repo.SaveFieldStats(stats); err != nil {
+		return nil, err
+	}
+
+	s.store(ctx, datasetID, lastModified, stats)
+	return stats, nil
+}
+
+// Get returns the stored statistics of a dataset, served from cache while the data is unchanged.
+// Stats computed before the latest data change are returned flagged as stale.
+func (s *FieldStatsService) Get(ctx context.Context, datasetID uuid.UUID) (*models.DatasetFieldStats, bool, error) {
+	lastModified, err := s.repo.GetDatasetLastModified(datasetID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := fieldStatsKey(datasetID, lastModified)
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var stats models.DatasetFieldStats
+		if err := json.Unmarshal(data, &stats); err == nil {
+			return &stats, true, nil
+		}
+		log.Printf("Warning: discarding unreadable cached field stats for dataset %s", datasetID)
+	} else if !errors.Is(err, cache.ErrMiss) {
+		log.Printf("Warning: failed to read cached field stats for dataset %s: %v", datasetID, err)
+	}
+
+	stats, err := s.repo.GetFieldStats(datasetID)
+	if err != nil {
+		return nil, false, err
+	}
+	stats.Stale = stats.ComputedAt.Before(lastModified)
+
+	s.store(ctx, datasetID, lastModified, stats)
+	return stats, false, nil
+}
+
+func (s *FieldStatsService) store(ctx context.Context, datasetID uuid.UUID, lastModified time.Time, stats *models.DatasetFieldStats) {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		log.Printf("Warning: failed to encode field stats for dataset %s: %v", datasetID, err)
+		return
+	}
+	if err := s.cache.Set(ctx, fieldStatsKey(datasetID, lastModified), data, s.ttl); err != nil {
+		log.Printf("Warning: failed to cache field stats for dataset %s: %v", datasetID, err)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+)
+
+type fakeFieldStatsRepository struct {
+	schema       *models.DatasetSchema
+	schemaErr    error
+	rows         []map[string]interface{}
+	lastModified time.Time
+	saved        *models.DatasetFieldStats
+	loads        int
+}
+
+func (f *fakeFieldStatsRepository) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error) {
+	if f.schemaErr != nil {
+		return nil, f.schemaErr
+	}
+	if f.schema == nil {
+		return nil, repository.ErrSchemaNotFound
+	}
+	return f.schema, nil
+}
+
+func (f *fakeFieldStatsRepository) GetAllDatasetRows(datasetID uuid.UUID) ([]map[string]interface{}, error) {
+	return f.rows, nil
+}
+
+func (f *fakeFieldStatsRepository) GetDatasetLastModified(datasetID uuid.UUID) (time.Time, error) {
+	return f.lastModified, nil
+}
+
+func (f *fakeFieldStatsRepository) SaveFieldStats(stats *models.DatasetFieldStats) error {
+	copied := *stats
+	f.saved = &copied
+	return nil
+}
+
+func (f *fakeFieldStatsRepository) GetFieldStats(datasetID uuid.UUID) (*models.DatasetFieldStats, error) {
+	f.loads++
+	copied := *f.saved
+	return &copied, nil
+}
+
+func knownStatsDataset() ([]map[string]interface{}, *models.DatasetSchema) {
+	rows := []map[string]interface{}{
+		{"id": float64(1), "name": "Ann", "age": "31"},
+		{"id": float64(2), "name": "Bob", "age": "abc"},
+		{"id": float64(3), "name": "Ann", "age": ""},
+		{"id": float64(4), "age": "40"},
+	}
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "number"},
+			{Name: "name", DataType: "string"},
+			{Name: "age", DataType: "number"},
+		},
+	}
+	return rows, schema
+}
+
+func TestComputeFieldStats(t *testing.T) {
+	rows, schema := knownStatsDataset()
+
+	stats := ComputeFieldStats(rows, schema)
+	assert.Equal(t, models.FieldStats{TotalValues: 4, UniqueValues: 4, Values: []string{"1", "2", "3", "4"}}, stats["id"])
+	assert.Equal(t, models.FieldStats{TotalValues: 4, UniqueValues: 2, NullValues: 1, Values: []string{"Ann", "Bob"}}, stats["name"])
+	assert.Equal(t, models.FieldStats{TotalValues: 4, UniqueValues: 3, NullValues: 1, InvalidValues: 1, Values: []string{"31", "40", "abc"}}, stats["age"])
+
+	t.Run("without schema uses stored keys", func(t *testing.T) {
+		stats := ComputeFieldStats(rows, nil)
+		require.Len(t, stats, 3)
+		assert.Equal(t, 0, stats["age"].InvalidValues)
+		assert.Equal(t, 1, stats["name"].NullValues)
+	})
+
+	t.Run("high-cardinality fields list no values", func(t *testing.T) {
+		var many []map[string]interface{}
+		for i := 0; i <= MaxTrackedFieldValues; i++ {
+			many = append(many, map[string]interface{}{"id": float64(i)})
+		}
+		stats := ComputeFieldStats(many, &models.DatasetSchema{Fields: []models.SchemaField{{Name: "id", DataType: "number"}}})
+		assert.Equal(t, MaxTrackedFieldValues+1, stats["id"].UniqueValues)
+		assert.Nil(t, stats["id"].Values)
+	})
+}
+
+func TestFieldStatsService_ComputeAndGet(t *testing.T) {
+	rows, schema := knownStatsDataset()
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeFieldStatsRepository{schema: schema, rows: rows, lastModified: modified}
+	svc := NewFieldStatsService(repo, cache.NewMemoryCache(), time.Minute)
+	svc.now = func() time.Time { return modified.Add(time.Minute) }
+	ctx := context.Background()
+	datasetID := uuid.New()
+
+	computed, err := svc.Compute(ctx, datasetID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, computed.RowCount)
+	require.NotNil(t, repo.saved)
+	assert.Equal(t, computed.Fields, repo.saved.Fields)
+
+	got, cached, err := svc.Get(ctx, datasetID)
+	require.NoError(t, err)
+	assert.True(t, cached)
+	assert.Equal(t, computed.Fields, got.Fields)
+	assert.Equal(t, 0, repo.loads)
+
+	t.Run("data change invalidates the cache and marks stats stale", func(t *testing.T) {
+		repo.lastModified = modified.Add(time.Hour)
+
+		got, cached, err := svc.Get(ctx, datasetID)
+		require.NoError(t, err)
+		assert.False(t, cached)
+		assert.True(t, got.Stale)
+		assert.Equal(t, 1, repo.loads)
+
+		_, cached, err = svc.Get(ctx, datasetID)
+		require.NoError(t, err)
+		assert.True(t, cached)
+		assert.Equal(t, 1, repo.loads)
+	})
+}
+
+func TestFieldStatsService_ComputeSchemaLookup(t *testing.T) {
+	rows, _ := knownStatsDataset()
+	ctx := context.Background()
+
+	t.Run("without a schema stats come from stored fields", func(t *testing.T) {
+		repo := &fakeFieldStatsRepository{rows: rows}
+		stats, err := NewFieldStatsService(repo, cache.NewMemoryCache(), time.Minute).Compute(ctx, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.Fields["age"].InvalidValues)
+	})
+
+	t.Run("a failed schema lookup is an error", func(t *testing.T) {
+		repo := &fakeFieldStatsRepository{rows: rows, schemaErr: errors.New("connection reset")}
+		_, err := NewFieldStatsService(repo, cache.NewMemoryCache(), time.Minute).Compute(ctx, uuid.New())
+		require.Error(t, err)
+		assert.Nil(t, repo.saved)
+	})
+}
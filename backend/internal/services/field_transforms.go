@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// caseTransforms are the transforms that set a value's letter case; a field may use one of them
+var caseTransforms = map[string]bool{
+	models.FieldTransformLowercase: true,
+	models.FieldTransformUppercase: true,
+	models.FieldTransformTitlecase: true,
+}
+
+// ValidateFieldTransforms rejects fields with an unknown transform or more than one case transform
+func ValidateFieldTransforms(fields []models.SchemaField) error {
+	for _, field := range fields {
+		caseTransform := ""
+		for _, transform := range field.Validation.Transforms {
+			if transform != models.FieldTransformTrim && !caseTransforms[transform] {
+				return fmt.Errorf("field '%s' has unsupported transform '%s' (supported: trim, lowercase, uppercase, titlecase)", field.Name, transform)
+			}
+			if caseTransforms[transform] {
+				if caseTransform != "" && caseTransform != transform {
+					return fmt.Errorf("field '%s' has conflicting transforms '%s' and '%s'", field.Name, caseTransform, transform)
+				}
+				caseTransform = transform
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyFieldTransforms rewrites the text values of fields with transforms in place and reports
+// whether any value changed. Non-text values, like native numbers, are left as they are.
+func ApplyFieldTransforms(rowData map[string]interface{}, schema *models.DatasetSchema) bool {
+	changed := false
+	for _, field := range schema.Fields {
+		if len(field.Validation.Transforms) == 0 {
+			continue
+		}
+
+		value, ok := rowData[field.Name].(string)
+		if !ok || value == "" {
+			continue
+		}
+
+		transformed := TransformValue(value, field.Validation.Transforms)
+		if transformed != value {
+			rowData[field.Name] = transformed
+			changed = true
+		}
+	}
+	return changed
+}
+
+// TransformValue applies transforms to a value in order; unknown transforms are ignored
+func TransformValue(value string, transforms []string) string {
+	for _, transform := range transforms {
+		switch transform {
+		case models.FieldTransformTrim:
+			value = strings.TrimSpace(value)
+		case models.FieldTransformLowercase:
+			value = strings.ToLower(value)
+		case models.FieldTransformUppercase:
+			value = strings.ToUpper(value)
+		case models.FieldTransformTitlecase:
+			value = titleCase(value)
+		}
+	}
+	return value
+}
+
+// titleCase upper-cases the first letter of each word and lower-cases the rest. Words are split
+// on anything but letters, digits and apostrophes, so "o'neil-smith" becomes "O'neil-Smith".
+func titleCase(value string) string {
+	runes := []rune(value)
+	startOfWord := true
+	for i, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' {
+			if startOfWord {
+				runes[i] = unicode.ToUpper(r)
+			} else {
+				runes[i] = unicode.ToLower(r)
+			}
+			startOfWord = false
+		} else {
+			startOfWord = true
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestTransformValue(t *testing.T) {
+	tests := []struct {
+		value      string
+		transforms []string
+		want       string
+	}{
+		{"  Active ", []string{"trim"}, "Active"},
+		{"  Active ", []string{"trim", "lowercase"}, "active"},
+		{"us", []string{"uppercase"}, "US"},
+		{"jane DOE", []string{"titlecase"}, "Jane Doe"},
+		{"o'neil-smith jr.", []string{"titlecase"}, "O'neil-Smith Jr."},
+		{"ÉLODIE", []string{"lowercase"}, "élodie"},
+		{" kept ", nil, " kept "},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, TransformValue(tt.value, tt.transforms), tt.value)
+	}
+}
+
+func TestValidateFieldTransforms(t *testing.T) {
+	fields := func(transforms ...string) []models.SchemaField {
+		return []models.SchemaField{{Name: "status", DataType: "string", Validation: models.FieldValidation{Transforms: transforms}}}
+	}
+
+	assert.NoError(t, ValidateFieldTransforms(fields("trim", "lowercase")))
+	assert.NoError(t, ValidateFieldTransforms(fields()))
+	assert.ErrorContains(t, ValidateFieldTransforms(fields("reverse")), "unsupported transform 'reverse'")
+	assert.ErrorContains(t, ValidateFieldTransforms(fields("lowercase", "uppercase")), "conflicting transforms")
+}
+
+func TestValidationService_FieldTransforms(t *testing.T) {
+	defaultStatus := "active"
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "status", DataType: "string", DefaultValue: &defaultStatus, Validation: models.FieldValidation{
+			Options:    []string{"active", "inactive"},
+			Transforms: []string{"trim", "lowercase"},
+		}},
+		{Name: "name", DataType: "string", Validation: models.FieldValidation{Transforms: []string{"trim", "titlecase"}}},
+	}}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	require.NoError(t, os.WriteFile(path, []byte("status,name\n Active ,ada LOVELACE\nINACTIVE,grace\n   ,alan\n"), 0644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	assert.Empty(t, result.SchemaErrors, "transformed values match the options")
+	require.Len(t, staging, 3)
+	assert.JSONEq(t, `{"status": "active", "name": "Ada Lovelace"}`, string(staging[0].Data))
+	assert.JSONEq(t, `{"status": "inactive", "name": "Grace"}`, string(staging[1].Data))
+	assert.JSONEq(t, `{"status": "active", "name": "Alan"}`, string(staging[2].Data), "values trimmed to nothing take the default")
+
+	t.Run("normalization before storage", func(t *testing.T) {
+		row := map[string]interface{}{"status": "Inactive ", "name": "GRACE hopper"}
+		assert.True(t, NormalizeRowValues(row, schema))
+		assert.Equal(t, map[string]interface{}{"status": "inactive", "name": "Grace Hopper"}, row)
+		assert.False(t, NormalizeRowValues(row, schema))
+	})
+}
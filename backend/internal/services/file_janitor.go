@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+)
+
+// SubmissionFileLister lists the files referenced by data submissions
+type SubmissionFileLister interface {
+	ListSubmissionFiles() ([]models.SubmissionFile, error)
+}
+
+// DatasetFileLister lists the files referenced by datasets
+type DatasetFileLister interface {
+	ListFilePaths() ([]string, error)
+}
+
+// FileJanitorResult summarizes a janitor pass
+type FileJanitorResult struct {
+	RemovedSubmissionFiles int
+	RemovedUploadFiles     int
+	FreedBytes             int64
+}
+
+// FileJanitor removes uploaded files that are no longer needed: submission files whose submission
+// was rejected, applied or expired, and files left behind by uploads that never became a record
+type FileJanitor struct {
+	submissions      SubmissionFileLister
+	datasets         DatasetFileLister
+	files            storage.Storage
+	submissionPrefix string
+	uploadPrefix     string
+	retention        time.Duration
+	now              func() time.Time
+}
+
+// NewFileJanitor creates a janitor for the files stored under the given key prefixes. Files are only
+// removed once their submission was finished, or for unreferenced files since they were written,
+// longer than retention.
+func NewFileJanitor(submissions SubmissionFileLister, datasets DatasetFileLister, files storage.Storage, submissionPrefix, uploadPrefix string, retention time.Duration) *FileJanitor {
+	return &FileJanitor{
+		submissions:      submissions,
+		datasets:         datasets,
+		files:            files,
+		submissionPrefix: submissionPrefix,
+		uploadPrefix:     uploadPrefix,
+		retention:        retention,
+		now:              time.Now,
+	}
+}
+
+// isFinishedSubmissionStatus reports whether a submission no longer needs its uploaded file
+func isFinishedSubmissionStatus(status string) bool {
+	switch status {
+	case models.DataSubmissionStatusRejected, models.DataSubmissionStatusApplied, models.DataSubmissionStatusExpired:
+		return true
+	}
+	return false
+}
+
+// Cleanup removes every file past the retention window in one pass
+func (j *FileJanitor) Cleanup() (*FileJanitorResult, error) {
+	result := &FileJanitorResult{}
+	if j.retention <= 0 {
+		return result, nil
+	}
+	cutoff := j.now().Add(-j.retention)
+
+	submissionFiles, err := j.submissions.ListSubmissionFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submission files: %w", err)
+	}
+	submissionsByKey := make(map[string]models.SubmissionFile, len(submissionFiles))
+	for _, file := range submissionFiles {
+		submissionsByKey[storage.NormalizeKey(file.FilePath)] = file
+	}
+
+	removed, freed, err := j.sweep(j.submissionPrefix, func(object storage.Object) bool {
+		submission, exists := submissionsByKey[storage.NormalizeKey(object.Key)]
+		if !exists {
+			return object.ModTime.Before(cutoff)
+		}
+		return isFinishedSubmissionStatus(submission.Status) && submission.UpdatedAt.Before(cutoff)
+	})
+	result.RemovedSubmissionFiles, result.FreedBytes = removed, freed
+	if err != nil {
+		return result, err
+	}
+
+	datasetPaths, err := j.datasets.ListFilePaths()
+	if err != nil {
+		return result, fmt.Errorf("failed to list dataset files: %w", err)
+	}
+	referenced := make(map[string]bool, len(datasetPaths))
+	for _, path := range datasetPaths {
+		referenced[storage.NormalizeKey(path)] = true
+	}
+
+	removed, freed, err = j.sweep(j.uploadPrefix, func(object storage.Object) bool {
+		return !referenced[storage.NormalizeKey(object.Key)] && object.ModTime.Before(cutoff)
+	})
+	result.RemovedUploadFiles = removed
+	result.FreedBytes += freed
+	return result, err
+}
+
+// sweep removes the stored files under prefix for which shouldRemove returns true
+func (j *FileJanitor) sweep(prefix string, shouldRemove func(object storage.Object) bool) (int, int64, error) {
+	ctx := context.Background()
+	objects, err := j.files.List(ctx, strings.TrimSuffix(prefix, "/")+"/")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	removed := 0
+	var freed int64
+	for _, object := range objects {
+		if !shouldRemove(object) {
+			continue
+		}
+		if err := j.files.Delete(ctx, object.Key); err != nil {
+			log.Printf("Warning: failed to remove orphaned file %s: %v", object.Key, err)
+			continue
+		}
+		removed++
+		freed += object.Size
+	}
+	return removed, freed, nil
+}
+
+// Run performs a cleanup every interval until the context is cancelled
+func (j *FileJanitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := j.Cleanup()
+		if err != nil {
+			log.Printf("Error cleaning up uploaded files: %v", err)
+		} else if result.RemovedSubmissionFiles > 0 || result.RemovedUploadFiles > 0 {
+			log.Printf("Removed %d submission files and %d upload files, freeing %d bytes",
+				result.RemovedSubmissionFiles, result.RemovedUploadFiles, result.FreedBytes)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
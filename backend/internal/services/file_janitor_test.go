@@ -0,0 +1,104 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFileRecords serves the file paths stored on submissions and datasets
+type fakeFileRecords struct {
+	submissions []models.SubmissionFile
+	datasets    []string
+}
+
+func (f *fakeFileRecords) ListSubmissionFiles() ([]models.SubmissionFile, error) {
+	return f.submissions, nil
+}
+
+func (f *fakeFileRecords) ListFilePaths() ([]string, error) {
+	return f.datasets, nil
+}
+
+// writeAgedFile creates a file below root whose modification time lies age in the past and
+// returns its storage key
+func writeAgedFile(t *testing.T, root, key string, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(key))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("a,b\n1,2\n"), 0o644))
+	modTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return key
+}
+
+func TestFileJanitor_Cleanup(t *testing.T) {
+	now := time.Now()
+	retention := 24 * time.Hour
+	old := 48 * time.Hour
+
+	root := t.TempDir()
+	applied := writeAgedFile(t, root, "submissions/applied.csv", old)
+	rejected := writeAgedFile(t, root, "submissions/rejected.csv", old)
+	recentlyRejected := writeAgedFile(t, root, "submissions/recently-rejected.csv", old)
+	pending := writeAgedFile(t, root, "submissions/pending.csv", old)
+	orphanSubmission := writeAgedFile(t, root, "submissions/orphan.csv", old)
+	freshOrphanSubmission := writeAgedFile(t, root, "submissions/uploading.csv", time.Minute)
+
+	dataset := writeAgedFile(t, root, "uploads/dataset.csv", old)
+	orphanUpload := writeAgedFile(t, root, "uploads/failed.csv", old)
+	freshUpload := writeAgedFile(t, root, "uploads/processing.csv", time.Minute)
+
+	records := &fakeFileRecords{
+		submissions: []models.SubmissionFile{
+			{FilePath: applied, Status: models.DataSubmissionStatusApplied, UpdatedAt: now.Add(-old)},
+			{FilePath: rejected, Status: models.DataSubmissionStatusRejected, UpdatedAt: now.Add(-old)},
+			{FilePath: recentlyRejected, Status: models.DataSubmissionStatusRejected, UpdatedAt: now.Add(-time.Hour)},
+			{FilePath: pending, Status: models.DataSubmissionStatusPending, UpdatedAt: now.Add(-old)},
+		},
+		datasets: []string{dataset},
+	}
+
+	janitor := NewFileJanitor(records, records, storage.NewLocalStorage(root), "submissions", "uploads", retention)
+	janitor.now = func() time.Time { return now }
+
+	result, err := janitor.Cleanup()
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.RemovedSubmissionFiles)
+	assert.Equal(t, 1, result.RemovedUploadFiles)
+	assert.Positive(t, result.FreedBytes)
+
+	for _, key := range []string{applied, rejected, orphanSubmission, orphanUpload} {
+		assert.NoFileExists(t, filepath.Join(root, key))
+	}
+	for _, key := range []string{recentlyRejected, pending, freshOrphanSubmission, dataset, freshUpload} {
+		assert.FileExists(t, filepath.Join(root, key))
+	}
+}
+
+func TestFileJanitor_CleanupEdgeCases(t *testing.T) {
+	t.Run("disabled without retention", func(t *testing.T) {
+		root := t.TempDir()
+		key := writeAgedFile(t, root, "submissions/orphan.csv", 48*time.Hour)
+
+		janitor := NewFileJanitor(&fakeFileRecords{}, &fakeFileRecords{}, storage.NewLocalStorage(root), "submissions", "uploads", 0)
+		result, err := janitor.Cleanup()
+		require.NoError(t, err)
+		assert.Zero(t, result.RemovedSubmissionFiles)
+		assert.FileExists(t, filepath.Join(root, key))
+	})
+
+	t.Run("missing directories are skipped", func(t *testing.T) {
+		janitor := NewFileJanitor(&fakeFileRecords{}, &fakeFileRecords{}, storage.NewLocalStorage(t.TempDir()), "submissions", "uploads", time.Hour)
+
+		result, err := janitor.Cleanup()
+		require.NoError(t, err)
+		assert.Zero(t, result.RemovedSubmissionFiles+result.RemovedUploadFiles)
+	})
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestValidationService_HeaderMode(t *testing.T) {
+	repo := &fakeSchemaRepository{schema: &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "first_name", DataType: "string", IsRequired: true},
+	}}}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "people.csv")
+	require.NoError(t, os.WriteFile(path, []byte("first_name,notes\nAda,likes tea\n"), 0644))
+
+	t.Run("strict rejects unknown columns", func(t *testing.T) {
+		repo.headerMode = models.HeaderModeStrict
+		result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+		require.NoError(t, err)
+		assert.False(t, result.IsValid)
+		require.Len(t, result.SchemaErrors, 1)
+		assert.Equal(t, "unexpected_field", result.SchemaErrors[0].ErrorType)
+		assert.Empty(t, staging)
+	})
+
+	t.Run("lenient drops unknown columns", func(t *testing.T) {
+		repo.headerMode = models.HeaderModeLenient
+		result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+		require.NoError(t, err)
+		assert.True(t, result.IsValid)
+		assert.Empty(t, result.SchemaErrors)
+		assert.Equal(t, []string{"notes"}, result.IgnoredFields)
+		require.Len(t, staging, 1)
+		assert.JSONEq(t, `{"first_name": "Ada"}`, string(staging[0].Data), "ignored columns are never staged")
+
+		quick, err := svc.QuickValidate(strings.NewReader("first_name,notes\nAda,x\n"), uuid.New(), "", nil, SampleOptions{Size: 10, Mode: models.SampleModeFirst})
+		require.NoError(t, err)
+		assert.True(t, quick.Result.IsValid)
+		assert.Equal(t, []string{"notes"}, quick.Result.IgnoredFields)
+	})
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// DefaultListSeparator splits list values when a field sets no separator
+const DefaultListSeparator = "|"
+
+// listItemTypes are the data types list items may have; nested structures are not allowed
+var listItemTypes = map[models.SchemaFieldType]bool{
+	models.FieldTypeString:   true,
+	models.FieldTypeNumber:   true,
+	models.FieldTypeInteger:  true,
+	models.FieldTypeBoolean:  true,
+	models.FieldTypeDate:     true,
+	models.FieldTypeDateTime: true,
+	models.FieldTypeEmail:    true,
+	models.FieldTypeURL:      true,
+	models.FieldTypeUUID:     true,
+	models.FieldTypeCurrency: true,
+	models.FieldTypePercent:  true,
+	models.FieldTypePhone:    true,
+}
+
+// ValidateListFields rejects list fields with an unsupported item type, an empty separator or an
+// item count range that no list can satisfy
+func ValidateListFields(fields []models.SchemaField) error {
+	for _, field := range fields {
+		if field.DataType != string(models.FieldTypeList) {
+			continue
+		}
+		validation := field.Validation
+		if validation.Separator != nil && *validation.Separator == "" {
+			return fmt.Errorf("field '%s' has an empty list separator", field.Name)
+		}
+		if validation.ItemType != nil && !listItemTypes[models.SchemaFieldType(*validation.ItemType)] {
+			return fmt.Errorf("field '%s' has unsupported list item type '%s'", field.Name, *validation.ItemType)
+		}
+		if validation.MinItems != nil && *validation.MinItems < 0 {
+			return fmt.Errorf("field '%s' min_items must not be negative", field.Name)
+		}
+		if validation.MaxItems != nil && *validation.MaxItems < 0 {
+			return fmt.Errorf("field '%s' max_items must not be negative", field.Name)
+		}
+		if validation.MinItems != nil && validation.MaxItems != nil && *validation.MinItems > *validation.MaxItems {
+			return fmt.Errorf("field '%s' min_items %d exceeds its max_items %d", field.Name, *validation.MinItems, *validation.MaxItems)
+		}
+	}
+	return nil
+}
+
+// listItemField returns the field each item of a list field is validated against: the list's
+// rules with the item type as its data type. Unsupported item types are treated as strings.
+func listItemField(field models.SchemaField) models.SchemaField {
+	item := field
+	item.DataType = string(models.FieldTypeString)
+	if field.Validation.ItemType != nil && listItemTypes[models.SchemaFieldType(*field.Validation.ItemType)] {
+		item.DataType = *field.Validation.ItemType
+	}
+	return item
+}
+
+// ParseListValue returns the items of a list field value. Text is split on the field's separator
+// with each item trimmed and empty items dropped; native JSON arrays are accepted as-is.
+func ParseListValue(value interface{}, field models.SchemaField) ([]interface{}, bool) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case string:
+		separator := DefaultListSeparator
+		if field.Validation.Separator != nil && *field.Validation.Separator != "" {
+			separator = *field.Validation.Separator
+		}
+		items := []interface{}{}
+		for _, part := range strings.Split(v, separator) {
+			if part = strings.TrimSpace(part); part != "" {
+				items = append(items, part)
+			}
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+// coerceListValue splits a list value and converts each item to the Go type stored for the item
+// type. It fails when any item doesn't convert.
+func coerceListValue(value interface{}, field models.SchemaField) ([]interface{}, bool) {
+	items, ok := ParseListValue(value, field)
+	if !ok {
+		return nil, false
+	}
+	itemType := listItemField(field).DataType
+	coerced := make([]interface{}, len(items))
+	for i, item := range items {
+		if coerced[i], ok = coerceValue(item, itemType); !ok {
+			return nil, false
+		}
+	}
+	return coerced, true
+}
+
+// DecodeListFields replaces the text of list fields with a JSON array of their items, converted to
+// the item type. Values with items that don't convert are left untouched for validation to report.
+func DecodeListFields(rowData map[string]interface{}, schema *models.DatasetSchema) {
+	for _, field := range schema.Fields {
+		if field.DataType != string(models.FieldTypeList) {
+			continue
+		}
+
+		value, exists := rowData[field.Name]
+		if isNullValue(value, exists) {
+			continue
+		}
+
+		if items, ok := coerceListValue(value, field); ok {
+			rowData[field.Name] = items
+		}
+	}
+}
+
+// listItemKey returns the text an item is compared by for uniqueness, so "1" and 1 repeat each other
+func listItemKey(item interface{}) string {
+	return strings.TrimSpace(fmt.Sprintf("%v", item))
+}
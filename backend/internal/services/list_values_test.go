@@ -0,0 +1,97 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestValidateListFields(t *testing.T) {
+	one, two, empty, object := 1, 2, "", "object"
+
+	assert.NoError(t, ValidateListFields([]models.SchemaField{
+		{Name: "tags", DataType: "list", Validation: models.FieldValidation{MinItems: &one, MaxItems: &two}},
+		{Name: "note", DataType: "string", Validation: models.FieldValidation{Separator: &empty}},
+	}))
+	assert.EqualError(t, ValidateListFields([]models.SchemaField{{Name: "tags", DataType: "list", Validation: models.FieldValidation{Separator: &empty}}}),
+		"field 'tags' has an empty list separator")
+	assert.EqualError(t, ValidateListFields([]models.SchemaField{{Name: "tags", DataType: "list", Validation: models.FieldValidation{ItemType: &object}}}),
+		"field 'tags' has unsupported list item type 'object'")
+	assert.EqualError(t, ValidateListFields([]models.SchemaField{{Name: "tags", DataType: "list", Validation: models.FieldValidation{MinItems: &two, MaxItems: &one}}}),
+		"field 'tags' min_items 2 exceeds its max_items 1")
+}
+
+func TestParseListValue(t *testing.T) {
+	semicolon := ";"
+	field := models.SchemaField{Name: "tags", DataType: "list"}
+
+	items, ok := ParseListValue(" red | green||blue ", field)
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{"red", "green", "blue"}, items, "items are trimmed and empty items dropped")
+
+	field.Validation.Separator = &semicolon
+	items, ok = ParseListValue("a;b|c", field)
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{"a", "b|c"}, items)
+
+	items, ok = ParseListValue([]interface{}{"x", 1.0}, field)
+	require.True(t, ok)
+	assert.Equal(t, []interface{}{"x", 1.0}, items)
+
+	_, ok = ParseListValue(42.0, field)
+	assert.False(t, ok)
+}
+
+func TestValidationService_ListFields(t *testing.T) {
+	one, three, integer := 1, 3, "integer"
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "id", DataType: "string", IsRequired: true},
+		{Name: "tags", DataType: "list", Validation: models.FieldValidation{
+			MinItems: &one, MaxItems: &three, UniqueItems: true, Options: []string{"red", "green", "blue"},
+		}},
+		{Name: "sizes", DataType: "list", Validation: models.FieldValidation{ItemType: &integer}},
+	}}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "products.csv")
+	require.NoError(t, os.WriteFile(path, []byte("id,tags,sizes\n"+
+		"a,red|green,1|2\n"+
+		"b,red|red,\n"+
+		"c,red|green|blue|red,\n"+
+		"d,pink,1|x\n"), 0o644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	require.Len(t, staging, 4)
+	assert.JSONEq(t, `{"id": "a", "tags": ["red", "green"], "sizes": [1, 2]}`, string(staging[0].Data), "lists are stored as JSON arrays of typed items")
+	assert.Equal(t, 1, result.ValidRows)
+
+	errorTypes := map[int][]string{}
+	for _, e := range result.SchemaErrors {
+		errorTypes[e.RowIndex] = append(errorTypes[e.RowIndex], e.ErrorType)
+	}
+	assert.Equal(t, []string{"duplicate_items"}, errorTypes[1])
+	assert.Equal(t, []string{"max_items", "duplicate_items"}, errorTypes[2])
+	assert.Equal(t, []string{"invalid_option", "invalid_data_type"}, errorTypes[3])
+}
+
+func TestCoerceRowToSchema_List(t *testing.T) {
+	number := "number"
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "scores", DataType: "list", Validation: models.FieldValidation{ItemType: &number}},
+	}}
+
+	coerced, errs := CoerceRowToSchema(map[string]interface{}{"scores": "1.5|2"}, schema, 0)
+	assert.Empty(t, errs)
+	assert.Equal(t, []interface{}{1.5, 2.0}, coerced["scores"])
+
+	_, errs = CoerceRowToSchema(map[string]interface{}{"scores": "1|two"}, schema, 0)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "invalid_data_type", errs[0].ErrorType)
+}
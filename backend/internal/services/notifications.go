@@ -0,0 +1,72 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// NotificationStore is the subset of notification storage needed to notify users
+type NotificationStore interface {
+	CreateNotification(notification *models.Notification) error
+}
+
+// NotificationService records in-app notifications and, when a mailer is configured, emails them
+type NotificationService struct {
+	store  NotificationStore
+	mailer Mailer
+}
+
+// NewNotificationService creates a notification service; a nil mailer sends no email
+func NewNotificationService(store NotificationStore, mailer Mailer) *NotificationService {
+	return &NotificationService{store: store, mailer: mailer}
+}
+
+// submissionStatusTitles name each status a reviewer can give a submission
+var submissionStatusTitles = map[string]string{
+	models.DataSubmissionStatusUnderReview: "Submission under review",
+	models.DataSubmissionStatusApproved:    "Submission approved",
+	models.DataSubmissionStatusRejected:    "Submission rejected",
+}
+
+// NotifySubmissionReviewed tells the submitter that a reviewer set their submission to status,
+// including the reviewer's notes. The email is sent in the background and failures are only logged.
+func (s *NotificationService) NotifySubmissionReviewed(submission *models.DataSubmissionWithDetails, status string, adminNotes *string) error {
+	title, ok := submissionStatusTitles[status]
+	if !ok {
+		title = "Submission " + strings.ReplaceAll(status, "_", " ")
+	}
+
+	message := fmt.Sprintf("Your submission %s to dataset %s is now %s.",
+		submission.FileName, submission.DatasetName, strings.ReplaceAll(status, "_", " "))
+	if adminNotes != nil && strings.TrimSpace(*adminNotes) != "" {
+		message += "\n\nReviewer notes: " + strings.TrimSpace(*adminNotes)
+	}
+
+	resourceID := submission.ID
+	notification := &models.Notification{
+		ID:         uuid.New(),
+		UserID:     submission.SubmittedBy,
+		Type:       models.NotificationTypeSubmissionReviewed,
+		Title:      title,
+		Message:    message,
+		ResourceID: &resourceID,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.store.CreateNotification(notification); err != nil {
+		return err
+	}
+
+	if s.mailer != nil && submission.SubmitterEmail != "" {
+		go func() {
+			if err := s.mailer.Send(submission.SubmitterEmail, title, message); err != nil {
+				log.Printf("Warning: failed to email notification %s: %v", notification.ID, err)
+			}
+		}()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotificationStore keeps created notifications in memory
+type fakeNotificationStore struct {
+	notifications []*models.Notification
+}
+
+func (f *fakeNotificationStore) CreateNotification(notification *models.Notification) error {
+	f.notifications = append(f.notifications, notification)
+	return nil
+}
+
+// fakeMailer records sent emails on a channel, since they are sent in the background
+type fakeMailer struct {
+	sent chan string
+}
+
+func (f *fakeMailer) Send(to, subject, body string) error {
+	f.sent <- to + "|" + subject + "|" + body
+	return nil
+}
+
+func TestNotificationService_NotifySubmissionReviewed(t *testing.T) {
+	submission := &models.DataSubmissionWithDetails{
+		DataSubmission: models.DataSubmission{ID: uuid.New(), SubmittedBy: uuid.New(), FileName: "march.csv"},
+		DatasetName:    "Sales",
+		SubmitterEmail: "ada@example.com",
+	}
+	notes := "  Row 4 has a negative amount  "
+
+	store := &fakeNotificationStore{}
+	mailer := &fakeMailer{sent: make(chan string, 1)}
+	require.NoError(t, NewNotificationService(store, mailer).NotifySubmissionReviewed(submission, models.DataSubmissionStatusRejected, &notes))
+
+	require.Len(t, store.notifications, 1)
+	notification := store.notifications[0]
+	assert.Equal(t, submission.SubmittedBy, notification.UserID)
+	assert.Equal(t, models.NotificationTypeSubmissionReviewed, notification.Type)
+	assert.Equal(t, "Submission rejected", notification.Title)
+	assert.Equal(t, "Your submission march.csv to dataset Sales is now rejected.\n\nReviewer notes: Row 4 has a negative amount", notification.Message)
+	assert.Equal(t, &submission.ID, notification.ResourceID)
+
+	select {
+	case email := <-mailer.sent:
+		assert.Equal(t, "ada@example.com|Submission rejected|"+notification.Message, email)
+	case <-time.After(time.Second):
+		t.Fatal("no email was sent")
+	}
+
+	t.Run("no notes and no mailer", func(t *testing.T) {
+		store := &fakeNotificationStore{}
+		require.NoError(t, NewNotificationService(store, nil).NotifySubmissionReviewed(submission, models.DataSubmissionStatusUnderReview, nil))
+		require.Len(t, store.notifications, 1)
+		assert.Equal(t, "Submission under review", store.notifications[0].Title)
+		assert.Equal(t, "Your submission march.csv to dataset Sales is now under review.", store.notifications[0].Message)
+	})
+}
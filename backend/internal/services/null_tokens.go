@@ -0,0 +1,31 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// NormalizeNullTokens returns tokens trimmed of surrounding whitespace with repeats removed, since
+// cell values are trimmed before they are matched. Empty tokens are rejected: empty cells are
+// already empty.
+func NormalizeNullTokens(tokens models.NullTokens) (models.NullTokens, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	normalized := make(models.NullTokens, 0, len(tokens))
+	seen := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return nil, errors.New("null tokens must not be empty")
+		}
+		if !seen[token] {
+			seen[token] = true
+			normalized = append(normalized, token)
+		}
+	}
+	return normalized, nil
+}
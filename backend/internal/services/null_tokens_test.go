@@ -0,0 +1,58 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestNormalizeNullTokens(t *testing.T) {
+	tokens, err := NormalizeNullTokens(models.NullTokens{" N/A", "NA", "N/A ", "-"})
+	require.NoError(t, err)
+	assert.Equal(t, models.NullTokens{"N/A", "NA", "-"}, tokens)
+
+	tokens, err = NormalizeNullTokens(nil)
+	require.NoError(t, err)
+	assert.Nil(t, tokens)
+
+	_, err = NormalizeNullTokens(models.NullTokens{"NULL", "  "})
+	assert.EqualError(t, err, "null tokens must not be empty")
+}
+
+func TestValidationService_NullTokens(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "city", DataType: "string", IsRequired: true},
+		{Name: "population", DataType: "integer"},
+	}}
+	repo := &fakeSchemaRepository{schema: schema, nullTokens: models.NullTokens{"N/A", "-"}}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	require.NoError(t, os.WriteFile(path, []byte("city,population\nOslo,709000\nAtlantis, N/A \n-,-\n"), 0o644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	require.Len(t, staging, 3)
+	assert.JSONEq(t, `{"city": "Atlantis", "population": null}`, string(staging[1].Data), "null tokens are stored as null")
+
+	require.Len(t, result.SchemaErrors, 1, "a null token is not a type error")
+	assert.Equal(t, "required_field", result.SchemaErrors[0].ErrorType)
+	assert.Equal(t, 2, result.SchemaErrors[0].RowIndex)
+	assert.Equal(t, 2, result.FieldStats["population"].NullValues)
+
+	t.Run("quick validation reads tokens too", func(t *testing.T) {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		sampled, err := svc.QuickValidate(file, uuid.New(), "", nil, SampleOptions{Size: 10, Mode: models.SampleModeFirst})
+		require.NoError(t, err)
+		assert.Len(t, sampled.Result.SchemaErrors, 1)
+	})
+}
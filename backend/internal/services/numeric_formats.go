@@ -0,0 +1,190 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// currencySymbols are stripped from either end of currency values
+var currencySymbols = []string{"$", "€", "£", "¥", "₹"}
+
+// groupedNumberPattern matches unsigned numbers with optional, correctly placed thousands separators
+var groupedNumberPattern = regexp.MustCompile(`^(\d{1,3}(,\d{3})+|\d+)(\.\d+)?$|^\.\d+$`)
+
+// ParseCurrency parses values like "$1,234.56", "-€5", "(1,000)" or "12 £" to their numeric amount
+func ParseCurrency(value string) (float64, bool) {
+	s := strings.TrimSpace(value)
+
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative = true
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = strings.TrimSpace(s[1:])
+	}
+
+	for _, symbol := range currencySymbols {
+		if strings.HasPrefix(s, symbol) {
+			s = strings.TrimSpace(strings.TrimPrefix(s, symbol))
+			break
+		}
+		if strings.HasSuffix(s, symbol) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, symbol))
+			break
+		}
+	}
+	// Accept "$-5" as well as "-$5"
+	if strings.HasPrefix(s, "-") {
+		negative = !negative
+		s = s[1:]
+	}
+
+	amount, ok := parseGroupedNumber(s)
+	if !ok {
+		return 0, false
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, true
+}
+
+// ParsePercent parses values like "45%", "12.5 %" or "-3" to their number of percentage points,
+// so "45%" becomes 45
+func ParsePercent(value string) (float64, bool) {
+	s := strings.TrimSpace(value)
+	s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+
+	negative := false
+	if strings.HasPrefix(s, "-") {
+		negative = true
+		s = s[1:]
+	}
+
+	percent, ok := parseGroupedNumber(s)
+	if !ok {
+		return 0, false
+	}
+	if negative {
+		percent = -percent
+	}
+	return percent, true
+}
+
+func parseGroupedNumber(s string) (float64, bool) {
+	if !groupedNumberPattern.MatchString(s) {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
+
+// parseNumericValue parses a value of a numeric field type (number, currency or percent) to the
+// canonical number stored for it. Native numbers are already canonical.
+func parseNumericValue(value interface{}, dataType string) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+
+	valueStr := strings.TrimSpace(fmt.Sprintf("%v", value))
+	switch dataType {
+	case string(models.FieldTypeCurrency):
+		return ParseCurrency(valueStr)
+	case string(models.FieldTypePercent):
+		return ParsePercent(valueStr)
+	default:
+		number, err := strconv.ParseFloat(valueStr, 64)
+		return number, err == nil
+	}
+}
+
+// numericDigits counts the significant digits of a number and the digits after its decimal point,
+// in its shortest exact decimal form: 0.0250 has precision 3 and scale 4, 120 precision 3 and scale 0
+func numericDigits(number float64) (precision, scale int) {
+	if math.IsInf(number, 0) || math.IsNaN(number) {
+		return 0, 0
+	}
+
+	text := strconv.FormatFloat(math.Abs(number), 'f', -1, 64)
+	whole, fraction, _ := strings.Cut(text, ".")
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		// Leading zeros of a fraction below one aren't significant either
+		return len(strings.TrimLeft(fraction, "0")), len(fraction)
+	}
+	return len(whole) + len(fraction), len(fraction)
+}
+
+// ValidateNumericPrecision rejects fields whose precision or scale is negative, whose scale exceeds
+// their precision, or that set either on a non-numeric field
+func ValidateNumericPrecision(fields []models.SchemaField) error {
+	for _, field := range fields {
+		precision, scale := field.Validation.Precision, field.Validation.Scale
+		if precision == nil && scale == nil {
+			continue
+		}
+		if !isNumericType(field.DataType) {
+			return fmt.Errorf("field '%s' is %s; precision and scale only apply to numeric fields", field.Name, field.DataType)
+		}
+		if precision != nil && *precision < 1 {
+			return fmt.Errorf("field '%s' precision must be at least 1", field.Name)
+		}
+		if scale != nil && *scale < 0 {
+			return fmt.Errorf("field '%s' scale must not be negative", field.Name)
+		}
+		if precision != nil && scale != nil && *scale > *precision {
+			return fmt.Errorf("field '%s' scale %d exceeds its precision %d", field.Name, *scale, *precision)
+		}
+	}
+	return nil
+}
+
+// isNumericType reports whether values of the field type are stored as numbers
+func isNumericType(dataType string) bool {
+	switch dataType {
+	case string(models.FieldTypeNumber), string(models.FieldTypeInteger), string(models.FieldTypeCurrency), string(models.FieldTypePercent):
+		return true
+	}
+	return false
+}
+
+// NormalizeNumericFields rewrites number, integer, currency and percent field values in place to their
+// canonical numbers and reports whether any value changed
+func NormalizeNumericFields(rowData map[string]interface{}, schema *models.DatasetSchema) bool {
+	changed := false
+	for _, field := range schema.Fields {
+		if !isNumericType(field.DataType) {
+			continue
+		}
+
+		value, exists := rowData[field.Name]
+		if !exists || value == nil || value == "" {
+			continue
+		}
+		if _, native := value.(float64); native {
+			continue
+		}
+
+		// NaN and Inf parse as numbers but have no JSON form, so they are left as written
+		if number, ok := parseNumericValue(value, field.DataType); ok && !math.IsNaN(number) && !math.IsInf(number, 0) {
+			rowData[field.Name] = number
+			changed = true
+		}
+	}
+	return changed
+}
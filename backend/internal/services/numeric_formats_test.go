@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurrency(t *testing.T) {
+	valid := map[string]float64{
+		"$1,234.56":  1234.56,
+		"€5":         5,
+		"12 £":       12,
+		"-$1,000":    -1000,
+		"$-7.5":      -7.5,
+		"(1,000.00)": -1000,
+		"1234":       1234,
+		"$0.99":      0.99,
+	}
+	for input, expected := range valid {
+		amount, ok := ParseCurrency(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, expected, amount, input)
+	}
+
+	for _, input := range []string{"", "$", "abc", "$1,23.4", "1,2,3", "$12$"} {
+		_, ok := ParseCurrency(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestParsePercent(t *testing.T) {
+	valid := map[string]float64{"45%": 45, "12.5 %": 12.5, "-3%": -3, "1,200%": 1200, "7": 7}
+	for input, expected := range valid {
+		percent, ok := ParsePercent(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, expected, percent, input)
+	}
+
+	for _, input := range []string{"%", "abc%", "45%%"} {
+		_, ok := ParsePercent(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestValidationService_CurrencyAndPercentFields(t *testing.T) {
+	minValue := 0.0
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "price", DataType: "currency", Validation: models.FieldValidation{MinValue: &minValue}},
+			{Name: "share", DataType: "percent"},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	t.Run("symbols and separators accepted", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"price": "$1,234.56", "share": "45%"}, schema, 0)
+		assert.Empty(t, result.Errors)
+	})
+
+	t.Run("invalid values rejected", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"price": "lots", "share": "half"}, schema, 0)
+		require.Len(t, result.Errors, 2)
+		for _, validationErr := range result.Errors {
+			assert.Equal(t, "invalid_data_type", validationErr.ErrorType)
+		}
+	})
+
+	t.Run("range rules use the parsed amount", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"price": "-$5", "share": "1%"}, schema, 0)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "min_value", result.Errors[0].ErrorType)
+	})
+
+	t.Run("staged values stored as numbers", func(t *testing.T) {
+		staging := stagedRows(t,
+			map[string]interface{}{"price": "$1,234.56", "share": "45%"},
+			map[string]interface{}{"price": 10.0, "share": 5.0},
+		)
+
+		changed, err := svc.NormalizeStagingValues(uuid.New(), "", staging)
+		require.NoError(t, err)
+		require.Len(t, changed, 1)
+
+		var row map[string]interface{}
+		require.NoError(t, json.Unmarshal(changed[0].Data, &row))
+		assert.Equal(t, 1234.56, row["price"])
+		assert.Equal(t, 45.0, row["share"])
+	})
+
+	t.Run("direct append coerces to numbers", func(t *testing.T) {
+		coerced, errs := CoerceRowToSchema(map[string]interface{}{"price": "€2,000", "share": "12.5%"}, schema, 0)
+		assert.Empty(t, errs)
+		assert.Equal(t, 2000.0, coerced["price"])
+		assert.Equal(t, 12.5, coerced["share"])
+	})
+}
+
+func TestSchemaInferenceService_DetectsCurrencyAndPercent(t *testing.T) {
+	svc := NewSchemaInferenceService()
+	headers := []string{"revenue", "margin", "units"}
+	rows := [][]string{
+		{"$1,200.00", "45%", "3"},
+		{"$950.50", "12.5%", "7"},
+		{"$12,000", "3%", "1"},
+	}
+
+	schema, err := svc.InferSchemaFromData(headers, rows, "finance")
+	require.NoError(t, err)
+	assert.Equal(t, models.FieldTypeCurrency, schema.Fields[0].DataType)
+	assert.Equal(t, 12000.0, schema.Fields[0].Constraints["max"])
+	assert.Equal(t, models.FieldTypePercent, schema.Fields[1].DataType)
+	assert.Equal(t, models.FieldTypeNumber, schema.Fields[2].DataType)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// MaxSafeInteger is the largest magnitude up to which every integer has an exact float64 form.
+// Numeric values are stored as JSON numbers, so larger integers would silently change.
+const MaxSafeInteger = 1 << 53
+
+// unsafeIntegerReason describes an integer beyond MaxSafeInteger
+var unsafeIntegerReason = fmt.Sprintf("exceeds %d, the largest integer that can be stored exactly", int64(MaxSafeInteger))
+
+// NumericMaxMagnitudeFromEnv reads NUMERIC_MAX_MAGNITUDE, the largest absolute value accepted by
+// number and integer fields; zero, a negative value or an unset variable disables the limit
+func NumericMaxMagnitudeFromEnv() float64 {
+	value := os.Getenv("NUMERIC_MAX_MAGNITUDE")
+	if value == "" {
+		return 0
+	}
+
+	limit, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(limit) || math.IsInf(limit, 0) {
+		log.Printf("Warning: invalid NUMERIC_MAX_MAGNITUDE %q, numeric magnitude is not limited", value)
+		return 0
+	}
+	return limit
+}
+
+// checkNumericOverflow describes why a number or integer field value cannot be stored exactly:
+// it is out of range for the field type, beyond maxMagnitude when positive, or written with more
+// precision than a float64 keeps. It returns an empty string for values that fit and for values
+// that are not numbers at all, which the data type check reports instead.
+func checkNumericOverflow(value interface{}, dataType string, maxMagnitude float64) string {
+	var number float64
+	if native, ok := value.(float64); ok {
+		number = native
+	} else {
+		valueStr := strings.TrimSpace(fmt.Sprintf("%v", value))
+		parsed, err := strconv.ParseFloat(valueStr, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			return "exceeds the largest number that can be stored"
+		}
+		if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+			return ""
+		}
+		number = parsed
+
+		if dataType == string(models.FieldTypeInteger) {
+			n, err := strconv.ParseInt(valueStr, 10, 64)
+			if errors.Is(err, strconv.ErrRange) {
+				return "exceeds the 64-bit integer range"
+			}
+			// Compare exactly, since the float form of 2^53+1 rounds down to 2^53
+			if err == nil && (n > MaxSafeInteger || n < -MaxSafeInteger) {
+				return unsafeIntegerReason
+			}
+		} else if !isExactDecimal(valueStr, number) {
+			return "has more precision than can be stored"
+		}
+	}
+
+	if dataType == string(models.FieldTypeInteger) && math.Abs(number) > MaxSafeInteger {
+		return unsafeIntegerReason
+	}
+	if maxMagnitude > 0 && math.Abs(number) > maxMagnitude {
+		return fmt.Sprintf("exceeds the maximum magnitude of %s", strconv.FormatFloat(maxMagnitude, 'f', -1, 64))
+	}
+	return ""
+}
+
+// isExactDecimal reports whether the float64 parsed from a decimal string still represents the
+// written value, i.e. the shortest form of the float is numerically equal to the input
+func isExactDecimal(valueStr string, number float64) bool {
+	written, ok := new(big.Rat).SetString(valueStr)
+	if !ok {
+		// Forms big.Rat does not read (such as hex floats) are not decimal input
+		return true
+	}
+	stored, ok := new(big.Rat).SetString(strconv.FormatFloat(number, 'g', -1, 64))
+	if !ok {
+		return true
+	}
+	return written.Cmp(stored) == 0
+}
+
+// parseIntegerValue parses an integer field value, accepting whole native numbers
+func parseIntegerValue(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > MaxSafeInteger {
+			return 0, false
+		}
+		return int64(v), true
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(fmt.Sprintf("%v", value)), 10, 64)
+	return n, err == nil
+}
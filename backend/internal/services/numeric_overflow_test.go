@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckNumericOverflow(t *testing.T) {
+	tests := []struct {
+		name         string
+		value        interface{}
+		dataType     string
+		maxMagnitude float64
+		overflows    bool
+	}{
+		{"plain integer", "42", "integer", 0, false},
+		{"largest safe integer", "9007199254740992", "integer", 0, false},
+		{"beyond safe integer", "9007199254740993", "integer", 0, true},
+		{"beyond int64", "99999999999999999999", "integer", 0, true},
+		{"negative beyond int64", "-99999999999999999999", "integer", 0, true},
+		{"short decimal", "0.1", "number", 0, false},
+		{"exponent", "1.5e10", "number", 0, false},
+		{"too many digits", "3.14159265358979323846", "number", 0, true},
+		{"imprecise integer as number", "12345678901234567890", "number", 0, true},
+		{"beyond float64", "1e400", "number", 0, true},
+		{"below float64", "1e-400", "number", 0, true},
+		{"within magnitude", "1000", "number", 1000, false},
+		{"beyond magnitude", "-1000.5", "number", 1000, true},
+		{"native number beyond magnitude", 5000.0, "number", 1000, true},
+		{"not a number", "abc", "number", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := checkNumericOverflow(tt.value, tt.dataType, tt.maxMagnitude)
+			assert.Equal(t, tt.overflows, reason != "", reason)
+		})
+	}
+}
+
+func TestValidationService_NumericOverflow(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "integer"},
+			{Name: "amount", DataType: "number"},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	t.Run("values in range accepted", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"id": "123", "amount": "19.99"}, schema, 0)
+		assert.Empty(t, result.Errors)
+	})
+
+	t.Run("overflowing integer flagged", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"id": "92233720368547758070", "amount": "1"}, schema, 3)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "numeric_overflow", result.Errors[0].ErrorType)
+		assert.Equal(t, "id", result.Errors[0].FieldName)
+		assert.Equal(t, 3, result.Errors[0].RowIndex)
+	})
+
+	t.Run("fractional integer rejected as wrong type", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"id": "1.5", "amount": "1"}, schema, 0)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "invalid_data_type", result.Errors[0].ErrorType)
+	})
+
+	t.Run("configured magnitude applies to numbers", func(t *testing.T) {
+		svc.SetNumericMaxMagnitude(1e6)
+		defer svc.SetNumericMaxMagnitude(0)
+
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"id": "1", "amount": "2500000"}, schema, 0)
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "numeric_overflow", result.Errors[0].ErrorType)
+		assert.Equal(t, "amount", result.Errors[0].FieldName)
+	})
+}
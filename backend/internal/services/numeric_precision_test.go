@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumericDigits(t *testing.T) {
+	tests := []struct {
+		number    float64
+		precision int
+		scale     int
+	}{
+		{9.999999, 7, 6},
+		{1.50, 2, 1},
+		{-1234.5, 5, 1},
+		{120, 3, 0},
+		{0, 0, 0},
+		{0.025, 2, 3},
+	}
+	for _, tt := range tests {
+		precision, scale := numericDigits(tt.number)
+		assert.Equal(t, tt.precision, precision, "precision of %v", tt.number)
+		assert.Equal(t, tt.scale, scale, "scale of %v", tt.number)
+	}
+}
+
+func TestValidationService_PrecisionAndScale(t *testing.T) {
+	precision, scale := 6, 2
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "price", DataType: "currency", Validation: models.FieldValidation{Precision: &precision, Scale: &scale}},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	for _, value := range []string{"$1,234.50", "9.99", "9999.99", "5"} {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"price": value}, schema, 0)
+		assert.Empty(t, result.Errors, value)
+	}
+
+	result := svc.validateRowAgainstSchema(map[string]interface{}{"price": "9.999999"}, schema, 0)
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, "scale", result.Errors[0].ErrorType)
+	assert.Equal(t, "Field 'price' allows at most 2 decimal places", result.Errors[0].Message)
+	assert.Equal(t, "precision", result.Errors[1].ErrorType)
+
+	result = svc.validateRowAgainstSchema(map[string]interface{}{"price": "1234567"}, schema, 0)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "precision", result.Errors[0].ErrorType)
+	assert.Equal(t, "Field 'price' allows at most 6 digits", result.Errors[0].Message)
+}
+
+func TestValidateNumericPrecision(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	field := func(dataType string, precision, scale *int) []models.SchemaField {
+		return []models.SchemaField{{Name: "f", DataType: dataType, Validation: models.FieldValidation{Precision: precision, Scale: scale}}}
+	}
+
+	assert.NoError(t, ValidateNumericPrecision(field("number", intPtr(10), intPtr(2))))
+	assert.NoError(t, ValidateNumericPrecision(field("currency", nil, intPtr(0))))
+	assert.NoError(t, ValidateNumericPrecision(field("string", nil, nil)))
+	assert.ErrorContains(t, ValidateNumericPrecision(field("string", nil, intPtr(2))), "only apply to numeric fields")
+	assert.ErrorContains(t, ValidateNumericPrecision(field("number", intPtr(0), nil)), "precision must be at least 1")
+	assert.ErrorContains(t, ValidateNumericPrecision(field("number", nil, intPtr(-1))), "scale must not be negative")
+	assert.ErrorContains(t, ValidateNumericPrecision(field("number", intPtr(2), intPtr(3))), "scale 3 exceeds its precision 2")
+}
+
+func TestSchemaInferenceService_InfersScale(t *testing.T) {
+	svc := NewSchemaInferenceService()
+	headers := []string{"price", "ratio", "units", "margin"}
+	rows := [][]string{
+		{"$1,200.00", "0.125", "3", "45%"},
+		{"$950.5", "2.5", "7", "12.5%"},
+		{"$12,000", "1", "1", "3%"},
+	}
+
+	schema, err := svc.InferSchemaFromData(headers, rows, "finance")
+	require.NoError(t, err)
+	assert.Equal(t, 2, schema.Fields[0].Constraints["scale"], "written trailing zeros count")
+	assert.Equal(t, 3, schema.Fields[1].Constraints["scale"])
+	assert.Equal(t, 0, schema.Fields[2].Constraints["scale"])
+	assert.Equal(t, 1, schema.Fields[3].Constraints["scale"])
+}
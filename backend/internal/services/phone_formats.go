@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// phoneFormatPatterns are the stricter phone formats selectable through FieldValidation.Format
+var phoneFormatPatterns = map[string]*regexp.Regexp{
+	"e164": regexp.MustCompile(`^\+[1-9]\d{6,14}$`),                                         // +14155552671
+	"us":   regexp.MustCompile(`^(\+?1[\s.-]?)?(\(\d{3}\)|\d{3})[\s.-]?\d{3}[\s.-]?\d{4}$`), // (415) 555-2671
+	"uk":   regexp.MustCompile(`^(\+44\s?|0)\d{2,4}\s?\d{3,4}\s?\d{3,4}$`),                  // +44 20 7946 0958
+	"in":   regexp.MustCompile(`^(\+91[\s-]?|0)?[6-9]\d{4}[\s-]?\d{5}$`),                    // +91 98765 43210
+}
+
+// ValidatePhoneFormats rejects phone fields whose validation format names an unsupported region
+func ValidatePhoneFormats(fields []models.SchemaField) error {
+	for _, field := range fields {
+		if field.DataType != string(models.FieldTypePhone) || field.Validation.Format == nil || *field.Validation.Format == "" {
+			continue
+		}
+		if _, ok := phoneFormatPatterns[strings.ToLower(*field.Validation.Format)]; !ok {
+			return fmt.Errorf("field '%s' has unsupported phone format '%s' (supported: e164, in, uk, us)", field.Name, *field.Validation.Format)
+		}
+	}
+	return nil
+}
+
+// IsValidPhone checks a phone number against the named format, or against the lenient
+// international pattern when format is empty
+func IsValidPhone(value, format string) bool {
+	value = strings.TrimSpace(value)
+	if pattern, ok := phoneFormatPatterns[strings.ToLower(format)]; ok {
+		return pattern.MatchString(value)
+	}
+
+	if !phonePattern.MatchString(value) {
+		return false
+	}
+
+	// The lenient pattern also matches punctuation only, so count digits (E.164 allows at most 15)
+	digits := 0
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
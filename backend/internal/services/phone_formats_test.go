@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidPhone(t *testing.T) {
+	tests := []struct {
+		value  string
+		format string
+		valid  bool
+	}{
+		{"+1 (415) 555-2671", "", true},
+		{"020 7946 0958", "", true},
+		{"() - ()", "", false},
+		{"555-12", "", false},
+		{"call me", "", false},
+		{"+14155552671", "e164", true},
+		{"(415) 555-2671", "e164", false},
+		{"(415) 555-2671", "us", true},
+		{"+1 415.555.2671", "US", true},
+		{"415-555-267", "us", false},
+		{"+44 20 7946 0958", "uk", true},
+		{"+91 98765 43210", "in", true},
+		{"+91 18765 43210", "in", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.valid, IsValidPhone(tt.value, tt.format), "%q as %q", tt.value, tt.format)
+	}
+}
+
+func TestValidationService_PhoneFields(t *testing.T) {
+	usFormat := "us"
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "phone", DataType: "phone"},
+			{Name: "us_phone", DataType: "phone", Validation: models.FieldValidation{Format: &usFormat}},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	result := svc.validateRowAgainstSchema(map[string]interface{}{"phone": "+44 20 7946 0958", "us_phone": "(415) 555-2671"}, schema, 0)
+	assert.Empty(t, result.Errors)
+
+	result = svc.validateRowAgainstSchema(map[string]interface{}{"phone": "not a phone", "us_phone": "+44 20 7946 0958"}, schema, 0)
+	require.Len(t, result.Errors, 2)
+	assert.Equal(t, "invalid_data_type", result.Errors[0].ErrorType)
+	assert.Equal(t, "us phone number", result.Errors[1].ExpectedValue)
+}
+
+func TestValidatePhoneFormats(t *testing.T) {
+	supported, unsupported := "e164", "mars"
+	assert.NoError(t, ValidatePhoneFormats([]models.SchemaField{
+		{Name: "a", DataType: "phone", Validation: models.FieldValidation{Format: &supported}},
+		{Name: "b", DataType: "date", Validation: models.FieldValidation{Format: &unsupported}},
+	}))
+	assert.ErrorContains(t, ValidatePhoneFormats([]models.SchemaField{
+		{Name: "c", DataType: "phone", Validation: models.FieldValidation{Format: &unsupported}},
+	}), "unsupported phone format 'mars'")
+}
+
+func TestSchemaInferenceService_DetectsPhone(t *testing.T) {
+	svc := NewSchemaInferenceService()
+	rows := [][]string{
+		{"+1 (415) 555-2671", "5551234", "2024-01-05"},
+		{"020 7946 0958", "5559876", "2024-02-11"},
+		{"+91 98765 43210", "5550000", "2024-03-17"},
+	}
+
+	schema, err := svc.InferSchemaFromData([]string{"contact", "code", "joined"}, rows, "contacts")
+	require.NoError(t, err)
+	assert.Equal(t, models.FieldTypePhone, schema.Fields[0].DataType)
+	assert.Equal(t, models.FieldTypeNumber, schema.Fields[1].DataType)
+	assert.Equal(t, models.FieldTypeDate, schema.Fields[2].DataType)
+}
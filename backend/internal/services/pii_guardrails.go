@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// PIIValueFinder looks up stored rows by the normalized value of a guarded contact field
+type PIIValueFinder interface {
+	// FindExistingPIIValues returns the row index of stored rows whose field value, normalized
+	// for kind, matches one of the given normalized values
+	FindExistingPIIValues(datasetID uuid.UUID, fieldName, kind string, values []string) (map[string]int, error)
+}
+
+// PIIDuplicateError is returned when appended rows repeat contact data guarded by the dataset
+type PIIDuplicateError struct {
+	Errors []models.DataValidationError
+}
+
+func (e *PIIDuplicateError) Error() string {
+	return fmt.Sprintf("%d row(s) repeat contact details already in the dataset", len(e.Errors))
+}
+
+// PIIDuplicate is a checked row whose guarded contact value is already taken
+type PIIDuplicate struct {
+	Position         int // position of the row among the checked rows
+	FieldName        string
+	Kind             string
+	Value            string
+	ExistingRowIndex int // stored row holding the value, or -1 when an earlier checked row does
+}
+
+// piiKindLabels name each kind in messages
+var piiKindLabels = map[string]string{
+	models.PIIKindEmail: "Email address",
+	models.PIIKindPhone: "Phone number",
+}
+
+// Message explains the duplicate in terms of the contact data, without the generic unique-rule wording
+func (d PIIDuplicate) Message() string {
+	if d.ExistingRowIndex >= 0 {
+		return fmt.Sprintf("%s '%s' in field '%s' already belongs to a contact in row %d of this dataset", piiKindLabels[d.Kind], d.Value, d.FieldName, d.ExistingRowIndex)
+	}
+	return fmt.Sprintf("%s '%s' in field '%s' appears more than once in this upload", piiKindLabels[d.Kind], d.Value, d.FieldName)
+}
+
+// ValidationError reports the duplicate against the row's position
+func (d PIIDuplicate) ValidationError() models.DataValidationError {
+	return models.DataValidationError{
+		RowIndex:    d.Position,
+		FieldName:   d.FieldName,
+		ErrorType:   models.ConflictTypeDuplicatePII,
+		Message:     d.Message(),
+		ActualValue: d.Value,
+	}
+}
+
+// ValidatePIIGuardrails checks that every guardrail names a field once with a supported kind
+func ValidatePIIGuardrails(guardrails models.PIIGuardrails) error {
+	seen := make(map[string]bool)
+	for _, guardrail := range guardrails {
+		if strings.TrimSpace(guardrail.FieldName) == "" {
+			return errors.New("pii guardrail field_name is required")
+		}
+		if _, ok := piiKindLabels[guardrail.Kind]; !ok {
+			return fmt.Errorf("pii guardrail kind for field '%s' must be %s or %s", guardrail.FieldName, models.PIIKindEmail, models.PIIKindPhone)
+		}
+		if seen[guardrail.FieldName] {
+			return fmt.Errorf("field '%s' has more than one pii guardrail", guardrail.FieldName)
+		}
+		seen[guardrail.FieldName] = true
+	}
+	return nil
+}
+
+// NormalizePII returns the form of a contact value used to detect duplicates: emails are trimmed
+// and lowercased, phone numbers keep only their digits. Must match the repository's SQL normalization.
+func NormalizePII(kind, value string) string {
+	switch kind {
+	case models.PIIKindEmail:
+		return strings.ToLower(strings.TrimSpace(value))
+	case models.PIIKindPhone:
+		return strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, value)
+	default:
+		return strings.TrimSpace(value)
+	}
+}
+
+// CheckPIIGuardrails finds rows whose guarded contact values already exist in the dataset or
+// repeat an earlier row of the same batch. Empty values are never duplicates.
+func CheckPIIGuardrails(finder PIIValueFinder, datasetID uuid.UUID, guardrails models.PIIGuardrails, rows []map[string]interface{}) ([]PIIDuplicate, error) {
+	var duplicates []PIIDuplicate
+	for _, guardrail := range guardrails {
+		normalized := make([]string, len(rows))
+		var values []string
+		seen := make(map[string]bool)
+		for i, row := range rows {
+			value, exists := row[guardrail.FieldName]
+			if !exists || value == nil {
+				continue
+			}
+			normalized[i] = NormalizePII(guardrail.Kind, fmt.Sprintf("%v", value))
+			if normalized[i] != "" && !seen[normalized[i]] {
+				seen[normalized[i]] = true
+				values = append(values, normalized[i])
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		existing, err := finder.FindExistingPIIValues(datasetID, guardrail.FieldName, guardrail.Kind, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing values for field '%s': %w", guardrail.FieldName, err)
+		}
+
+		checked := make(map[string]bool)
+		for i, key := range normalized {
+			if key == "" {
+				continue
+			}
+			duplicate := PIIDuplicate{
+				Position:         i,
+				FieldName:        guardrail.FieldName,
+				Kind:             guardrail.Kind,
+				Value:            fmt.Sprintf("%v", rows[i][guardrail.FieldName]),
+				ExistingRowIndex: -1,
+			}
+			if rowIndex, found := existing[key]; found {
+				duplicate.ExistingRowIndex = rowIndex
+				duplicates = append(duplicates, duplicate)
+			} else if checked[key] {
+				duplicates = append(duplicates, duplicate)
+			}
+			checked[key] = true
+		}
+	}
+	return duplicates, nil
+}
+
+// piiValidationErrors converts duplicates to validation errors
+func piiValidationErrors(duplicates []PIIDuplicate) []models.DataValidationError {
+	errs := make([]models.DataValidationError, len(duplicates))
+	for i, duplicate := range duplicates {
+		errs[i] = duplicate.ValidationError()
+	}
+	return errs
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePII(t *testing.T) {
+	assert.Equal(t, "ada@example.com", NormalizePII(models.PIIKindEmail, "  Ada@Example.COM "))
+	assert.Equal(t, "15551234567", NormalizePII(models.PIIKindPhone, "+1 (555) 123-4567"))
+	assert.Equal(t, "", NormalizePII(models.PIIKindPhone, "n/a"))
+}
+
+func TestValidatePIIGuardrails(t *testing.T) {
+	assert.NoError(t, ValidatePIIGuardrails(nil))
+	assert.NoError(t, ValidatePIIGuardrails(models.PIIGuardrails{
+		{FieldName: "email", Kind: models.PIIKindEmail},
+		{FieldName: "phone", Kind: models.PIIKindPhone},
+	}))
+	assert.Error(t, ValidatePIIGuardrails(models.PIIGuardrails{{FieldName: " ", Kind: models.PIIKindEmail}}))
+	assert.Error(t, ValidatePIIGuardrails(models.PIIGuardrails{{FieldName: "ssn", Kind: "ssn"}}))
+	assert.Error(t, ValidatePIIGuardrails(models.PIIGuardrails{
+		{FieldName: "contact", Kind: models.PIIKindEmail},
+		{FieldName: "contact", Kind: models.PIIKindPhone},
+	}))
+}
+
+func TestCheckPIIGuardrails(t *testing.T) {
+	repo := &fakeSchemaRepository{stored: map[string]map[string]int{
+		"email": {"ada@example.com": 4},
+		"phone": {"5551234567": 9},
+	}}
+	guardrails := models.PIIGuardrails{
+		{FieldName: "email", Kind: models.PIIKindEmail},
+		{FieldName: "phone", Kind: models.PIIKindPhone},
+	}
+	rows := []map[string]interface{}{
+		{"email": "ADA@example.com ", "phone": ""},
+		{"email": "grace@example.com", "phone": "(555) 123-4567"},
+		{"email": "Grace@Example.com", "phone": nil},
+		{"email": "alan@example.com", "phone": "555-000-1111"},
+	}
+
+	duplicates, err := CheckPIIGuardrails(repo, uuid.New(), guardrails, rows)
+	require.NoError(t, err)
+	require.Len(t, duplicates, 3)
+
+	assert.Equal(t, PIIDuplicate{Position: 0, FieldName: "email", Kind: models.PIIKindEmail, Value: "ADA@example.com ", ExistingRowIndex: 4}, duplicates[0])
+	assert.Contains(t, duplicates[0].Message(), "Email address 'ADA@example.com ' in field 'email' already belongs to a contact in row 4")
+
+	assert.Equal(t, 2, duplicates[1].Position)
+	assert.Equal(t, -1, duplicates[1].ExistingRowIndex, "repeats an earlier row of the batch")
+	assert.Contains(t, duplicates[1].Message(), "appears more than once in this upload")
+
+	assert.Equal(t, PIIDuplicate{Position: 1, FieldName: "phone", Kind: models.PIIKindPhone, Value: "(555) 123-4567", ExistingRowIndex: 9}, duplicates[2])
+	assert.Contains(t, duplicates[2].Message(), "Phone number")
+}
+
+func TestValidationService_DuplicateEmailRejected(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "name", DataType: "string"},
+		{Name: "email", DataType: "email"},
+	}}
+	repo := &fakeSchemaRepository{
+		schema:     schema,
+		stored:     map[string]map[string]int{"email": {"ada@example.com": 0}},
+		guardrails: models.PIIGuardrails{{FieldName: "email", Kind: models.PIIKindEmail}},
+	}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "contacts.csv")
+	require.NoError(t, os.WriteFile(path, []byte("name,email\nAda,Ada@Example.com\nGrace,grace@example.com\n"), 0o644))
+
+	result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	require.NoError(t, err)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, 1, result.ValidRows)
+	assert.Equal(t, 1, result.InvalidRows)
+
+	require.Len(t, result.BusinessRuleErrors, 1)
+	assert.Equal(t, 0, result.BusinessRuleErrors[0].RowIndex)
+	assert.Equal(t, models.ConflictTypeDuplicatePII, result.BusinessRuleErrors[0].ErrorType)
+
+	require.Len(t, staging, 2)
+	assert.Equal(t, models.ValidationStatusInvalid, staging[0].ValidationStatus)
+	assert.Equal(t, models.ValidationStatusValid, staging[1].ValidationStatus)
+
+	t.Run("conflict report", func(t *testing.T) {
+		staged, _ := json.Marshal(map[string]string{"name": "Ada", "email": "ADA@example.com"})
+		report, err := svc.DetectConflicts(uuid.New(), "", []*models.DataSubmissionStaging{{RowIndex: 3, Data: staged}})
+		require.NoError(t, err)
+		require.Len(t, report.Conflicts, 1)
+		assert.Equal(t, models.ConflictTypeDuplicatePII, report.Conflicts[0].ConflictType)
+		assert.Equal(t, 3, report.Conflicts[0].RowIndex)
+		assert.Equal(t, 0, report.Conflicts[0].ExistingRowIndex)
+	})
+}
+
+func TestDirectAppendService_DuplicateEmailRejected(t *testing.T) {
+	repo, key := newDirectAppendFixture(true)
+	repo.schema.Fields = append(repo.schema.Fields, models.SchemaField{Name: "email", DataType: "email"})
+	repo.dataset.PIIGuardrails = models.PIIGuardrails{{FieldName: "email", Kind: models.PIIKindEmail}}
+	svc := NewDirectAppendService(repo)
+
+	_, err := svc.AppendRows(key, repo.dataset.ID, []map[string]interface{}{{"name": "alpha", "email": "ada@example.com"}})
+	require.NoError(t, err)
+
+	_, err = svc.AppendRows(key, repo.dataset.ID, []map[string]interface{}{
+		{"name": "beta", "email": "grace@example.com"},
+		{"name": "gamma", "email": " Ada@Example.com"},
+	})
+	var duplicateErr *PIIDuplicateError
+	require.True(t, errors.As(err, &duplicateErr))
+	require.Len(t, duplicateErr.Errors, 1)
+	assert.Equal(t, 1, duplicateErr.Errors[0].RowIndex)
+	assert.Equal(t, "email", duplicateErr.Errors[0].FieldName)
+	assert.Len(t, repo.appended, 1, "no rows of a rejected batch are appended")
+}
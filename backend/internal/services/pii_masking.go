@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// piiMask replaces the hidden part of a masked value
+const piiMask = "***"
+
+// phoneVisibleDigits is how many trailing digits of a phone number stay visible when masked
+const phoneVisibleDigits = 4
+
+// MaskPIIValue hides most of a PII field's value: emails keep the first letter and the domain
+// (j***@example.com), phone numbers keep their last four digits and anything else keeps its first
+// character. Null values stay null.
+func MaskPIIValue(value interface{}, field models.SchemaField) interface{} {
+	if value == nil {
+		return nil
+	}
+	if items, ok := value.([]interface{}); ok {
+		masked := make([]interface{}, len(items))
+		for i, item := range items {
+			masked[i] = MaskPIIValue(item, field)
+		}
+		return masked
+	}
+
+	text := strings.TrimSpace(fmt.Sprintf("%v", value))
+	if text == "" {
+		return value
+	}
+	if at := strings.LastIndex(text, "@"); at > 0 {
+		return text[:1] + piiMask + text[at:]
+	}
+	if field.DataType == string(models.FieldTypePhone) || listItemField(field).DataType == string(models.FieldTypePhone) {
+		return maskPhone(text)
+	}
+	return string([]rune(text)[:1]) + piiMask
+}
+
+// maskPhone replaces every digit of a phone number but the last few, keeping its formatting
+func maskPhone(phone string) string {
+	digits := 0
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+
+	var masked strings.Builder
+	seen := 0
+	for _, r := range phone {
+		if unicode.IsDigit(r) {
+			seen++
+			if seen <= digits-phoneVisibleDigits {
+				r = '*'
+			}
+		}
+		masked.WriteRune(r)
+	}
+	return masked.String()
+}
+
+// HasPIIFields reports whether any of the schema's fields is flagged as PII
+func HasPIIFields(schema *models.DatasetSchema) bool {
+	if schema == nil {
+		return false
+	}
+	for _, field := range schema.Fields {
+		if field.IsPII {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskPIIFields masks the values of the schema's PII fields in rows, in place
+func MaskPIIFields(rows []map[string]interface{}, schema *models.DatasetSchema) {
+	if !HasPIIFields(schema) {
+		return
+	}
+	for _, row := range rows {
+		for _, field := range schema.Fields {
+			if value, exists := row[field.Name]; field.IsPII && exists {
+				row[field.Name] = MaskPIIValue(value, field)
+			}
+		}
+	}
+}
+
+// PIIFieldNames returns the names of the schema's PII fields
+func PIIFieldNames(schema *models.DatasetSchema) []string {
+	var names []string
+	if schema == nil {
+		return names
+	}
+	for _, field := range schema.Fields {
+		if field.IsPII {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
+// HidePIIFieldValues drops the distinct values field stats list for the schema's PII fields, in place
+func HidePIIFieldValues(stats *models.DatasetFieldStats, schema *models.DatasetSchema) {
+	if stats == nil {
+		return
+	}
+	for _, name := range PIIFieldNames(schema) {
+		if fieldStats, exists := stats.Fields[name]; exists {
+			fieldStats.Values = nil
+			stats.Fields[name] = fieldStats
+		}
+	}
+}
+
+// HidePIIDriftValues drops the new values a drift report lists for the schema's PII fields, in place
+func HidePIIDriftValues(report *DriftReport, schema *models.DatasetSchema) {
+	if report == nil || !HasPIIFields(schema) {
+		return
+	}
+	pii := make(map[string]bool)
+	for _, name := range PIIFieldNames(schema) {
+		pii[name] = true
+	}
+	for i := range report.Submissions {
+		for j := range report.Submissions[i].Drift {
+			if drift := &report.Submissions[i].Drift[j]; pii[drift.FieldName] {
+				drift.NewValues = nil
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestMaskPIIValue(t *testing.T) {
+	email := models.SchemaField{Name: "email", DataType: "email", IsPII: true}
+	phone := models.SchemaField{Name: "phone", DataType: "phone", IsPII: true}
+	name := models.SchemaField{Name: "name", DataType: "string", IsPII: true}
+
+	assert.Equal(t, "j***@example.com", MaskPIIValue("john.doe@example.com", email))
+	assert.Equal(t, "+* (***) ***-4567", MaskPIIValue("+1 (555) 123-4567", phone))
+	assert.Equal(t, "A***", MaskPIIValue("Ada Lovelace", name))
+	assert.Equal(t, "j***@example.com", MaskPIIValue("jane@example.com", name), "emails in text fields keep their domain")
+	assert.Nil(t, MaskPIIValue(nil, name))
+	assert.Equal(t, "", MaskPIIValue("", name))
+
+	contacts := models.SchemaField{Name: "emails", DataType: "list", IsPII: true}
+	assert.Equal(t, []interface{}{"a***@x.io", "b***@y.io"}, MaskPIIValue([]interface{}{"ann@x.io", "bob@y.io"}, contacts))
+}
+
+func TestMaskPIIFields(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "name", DataType: "string"},
+		{Name: "email", DataType: "email", IsPII: true},
+	}}
+	rows := []map[string]interface{}{
+		{"name": "Ada", "email": "ada@example.com"},
+		{"name": "Bob", "email": nil},
+		{"name": "Cy"},
+	}
+
+	MaskPIIFields(rows, schema)
+	assert.Equal(t, []map[string]interface{}{
+		{"name": "Ada", "email": "a***@example.com"},
+		{"name": "Bob", "email": nil},
+		{"name": "Cy"},
+	}, rows)
+
+	assert.True(t, HasPIIFields(schema))
+	assert.False(t, HasPIIFields(nil))
+	assert.False(t, HasPIIFields(&models.DatasetSchema{Fields: []models.SchemaField{{Name: "name"}}}))
+}
+
+func TestHidePIIFieldValues(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{
+		{Name: "status", DataType: "string"},
+		{Name: "email", DataType: "email", IsPII: true},
+	}}
+	assert.Equal(t, []string{"email"}, PIIFieldNames(schema))
+	assert.Empty(t, PIIFieldNames(nil))
+
+	stats := &models.DatasetFieldStats{Fields: map[string]models.FieldStats{
+		"status": {TotalValues: 3, UniqueValues: 2, Values: []string{"active", "closed"}},
+		"email":  {TotalValues: 3, UniqueValues: 2, Values: []string{"ann@x.io", "bob@y.io"}},
+	}}
+	HidePIIFieldValues(stats, schema)
+	assert.Equal(t, []string{"active", "closed"}, stats.Fields["status"].Values)
+	assert.Nil(t, stats.Fields["email"].Values)
+	assert.Equal(t, 2, stats.Fields["email"].UniqueValues, "counts stay visible")
+
+	report := &DriftReport{Submissions: []SubmissionDrift{{Drift: []FieldDrift{
+		{FieldName: "status", Kind: DriftNewValues, NewValues: []string{"paused"}},
+		{FieldName: "email", Kind: DriftNewValues, NewValues: []string{"cy@z.io"}},
+	}}}}
+	HidePIIDriftValues(report, schema)
+	assert.Equal(t, []string{"paused"}, report.Submissions[0].Drift[0].NewValues)
+	assert.Nil(t, report.Submissions[0].Drift[1].NewValues)
+}
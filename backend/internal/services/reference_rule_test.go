@@ -0,0 +1,68 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func referenceRule(t *testing.T, targetDatasetID uuid.UUID) *models.DatasetBusinessRule {
+	config, err := json.Marshal(models.BusinessRuleConfig{
+		FieldName:       "country",
+		TargetDatasetID: targetDatasetID.String(),
+		TargetField:     "code",
+	})
+	require.NoError(t, err)
+	return &models.DatasetBusinessRule{
+		RuleName:     "known country",
+		RuleType:     models.RuleTypeReference,
+		RuleConfig:   config,
+		ErrorMessage: "Unknown country code",
+		IsActive:     true,
+	}
+}
+
+func TestValidateBusinessRules_Reference(t *testing.T) {
+	schemaRepo := &fakeSchemaRepository{stored: map[string]map[string]int{
+		"code": {"NZ": 0, "FR": 1},
+	}}
+	svc := NewValidationService(schemaRepo, &fakeSubmissionRepository{})
+	rows := []map[string]interface{}{
+		{"country": "NZ"},
+		{"country": "XX"},
+		{"country": ""},
+		{"name": "no country"},
+		{"country": "FR"},
+	}
+
+	violations, err := svc.validateBusinessRules(rows, []*models.DatasetBusinessRule{referenceRule(t, uuid.New())})
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, 1, violations[0].RowIndex)
+	assert.Equal(t, "country", violations[0].FieldName)
+	assert.Equal(t, "reference_violation", violations[0].ErrorType)
+	assert.Equal(t, "Unknown country code", violations[0].Message)
+	assert.Equal(t, "XX", violations[0].ActualValue)
+}
+
+// failingLookupRepository fails every stored value lookup
+type failingLookupRepository struct {
+	fakeSchemaRepository
+}
+
+func (f *failingLookupRepository) FindExistingFieldValues(datasetID uuid.UUID, fieldName string, values []string) (map[string]int, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestValidateBusinessRules_ReferenceLookupFails(t *testing.T) {
+	svc := NewValidationService(&failingLookupRepository{}, &fakeSubmissionRepository{})
+
+	_, err := svc.validateBusinessRules([]map[string]interface{}{{"country": "NZ"}}, []*models.DatasetBusinessRule{referenceRule(t, uuid.New())})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "known country")
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+// DefaultMaxUploadRows is the row cap applied to uploads when MAX_UPLOAD_ROWS is not set
+const DefaultMaxUploadRows = 100000
+
+// RowLimitError reports an upload whose data rows exceed the configured limit
+type RowLimitError struct {
+	Limit int
+	Rows  int
+}
+
+func (e *RowLimitError) Error() string {
+	return fmt.Sprintf("file contains %d data rows, exceeding the limit of %d rows per upload", e.Rows, e.Limit)
+}
+
+// MaxUploadRowsFromEnv reads MAX_UPLOAD_ROWS; zero or a negative value disables the limit
+func MaxUploadRowsFromEnv() int {
+	value := os.Getenv("MAX_UPLOAD_ROWS")
+	if value == "" {
+		return DefaultMaxUploadRows
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid MAX_UPLOAD_ROWS %q, using default of %d: %v", value, DefaultMaxUploadRows, err)
+		return DefaultMaxUploadRows
+	}
+	return limit
+}
+
+// CheckRowLimit returns a RowLimitError when rows exceeds a positive limit
+func CheckRowLimit(rows, limit int) error {
+	if limit > 0 && rows > limit {
+		return &RowLimitError{Limit: limit, Rows: rows}
+	}
+	return nil
+}
+
+// CountRemainingRecords consumes the rest of a CSV reader and returns how many records it held,
+// so a row limit error can report the full size of the offending file
+func CountRemainingRecords(reader CSVRecordReader) (int, error) {
+	if csvReader, ok := reader.(*csv.Reader); ok {
+		csvReader.ReuseRecord = true
+	}
+	count := 0
+	for {
+		_, err := reader.Read()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRowLimit(t *testing.T) {
+	assert.NoError(t, CheckRowLimit(10, 10))
+	assert.NoError(t, CheckRowLimit(1000, 0))
+
+	err := CheckRowLimit(11, 10)
+	var rowLimitErr *RowLimitError
+	require.True(t, errors.As(err, &rowLimitErr))
+	assert.Equal(t, 11, rowLimitErr.Rows)
+	assert.Equal(t, 10, rowLimitErr.Limit)
+}
+
+func TestMaxUploadRowsFromEnv(t *testing.T) {
+	t.Setenv("MAX_UPLOAD_ROWS", "")
+	assert.Equal(t, DefaultMaxUploadRows, MaxUploadRowsFromEnv())
+
+	t.Setenv("MAX_UPLOAD_ROWS", "500")
+	assert.Equal(t, 500, MaxUploadRowsFromEnv())
+
+	t.Setenv("MAX_UPLOAD_ROWS", "lots")
+	assert.Equal(t, DefaultMaxUploadRows, MaxUploadRowsFromEnv())
+}
+
+func TestValidationService_ValidateDataSubmission_RowLimit(t *testing.T) {
+	schemaRepo := &fakeSchemaRepository{
+		schema: &models.DatasetSchema{Fields: []models.SchemaField{{Name: "id", DataType: "string"}}},
+	}
+	svc := NewValidationService(schemaRepo, &fakeSubmissionRepository{})
+	svc.SetMaxRows(3)
+
+	path := filepath.Join(t.TempDir(), "rows.csv")
+	content := "id\n" + strings.Repeat("x\n", 7)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	_, _, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+	var rowLimitErr *RowLimitError
+	require.True(t, errors.As(err, &rowLimitErr))
+	assert.Equal(t, 7, rowLimitErr.Rows)
+	assert.Equal(t, 3, rowLimitErr.Limit)
+	assert.Contains(t, err.Error(), "7 data rows")
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// conditionOperators are the comparisons a rule condition may use, longest first so ">=" isn't read as ">"
+var conditionOperators = []string{"!=", ">=", "<=", "==", "=", ">", "<"}
+
+// ruleCondition is a parsed business rule condition such as "status = 'shipped'" or "end > start"
+type ruleCondition struct {
+	left, op, right string
+}
+
+// parseRuleCondition splits condition at its first comparison operator outside quotes
+func parseRuleCondition(condition string) (ruleCondition, error) {
+	var quote rune
+	for i, r := range condition {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			continue
+		case r == '\'' || r == '"':
+			quote = r
+			continue
+		}
+
+		for _, op := range conditionOperators {
+			if strings.HasPrefix(condition[i:], op) {
+				left := strings.TrimSpace(condition[:i])
+				right := strings.TrimSpace(condition[i+len(op):])
+				if left == "" || right == "" {
+					return ruleCondition{}, fmt.Errorf("condition %q needs a value on both sides of %s", condition, op)
+				}
+				return ruleCondition{left: left, op: op, right: right}, nil
+			}
+		}
+	}
+	return ruleCondition{}, fmt.Errorf("condition %q has no comparison operator", condition)
+}
+
+// operand resolves one side of a condition: a quoted literal, a field of the row, or else an unquoted literal
+func (c ruleCondition) operand(rowData map[string]interface{}, side string) string {
+	if len(side) >= 2 && (side[0] == '\'' || side[0] == '"') && side[len(side)-1] == side[0] {
+		return side[1 : len(side)-1]
+	}
+	if value, exists := rowData[side]; exists {
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprintf("%v", value)
+	}
+	return side
+}
+
+// holds reports whether the condition is true for the row. Equality compares numbers by value and
+// anything else as text; ordering compares numbers, reading values that aren't numbers as zero.
+func (c ruleCondition) holds(rowData map[string]interface{}) bool {
+	left, right := c.operand(rowData, c.left), c.operand(rowData, c.right)
+	leftNum, leftErr := strconv.ParseFloat(left, 64)
+	rightNum, rightErr := strconv.ParseFloat(right, 64)
+
+	switch c.op {
+	case "=", "==":
+		if leftErr == nil && rightErr == nil {
+			return leftNum == rightNum
+		}
+		return left == right
+	case "!=":
+		if leftErr == nil && rightErr == nil {
+			return leftNum != rightNum
+		}
+		return left != right
+	case ">":
+		return leftNum > rightNum
+	case ">=":
+		return leftNum >= rightNum
+	case "<":
+		return leftNum < rightNum
+	case "<=":
+		return leftNum <= rightNum
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRuleCondition(t *testing.T) {
+	condition, err := parseRuleCondition("status = 'a >= b'")
+	require.NoError(t, err)
+	assert.Equal(t, ruleCondition{left: "status", op: "=", right: "'a >= b'"}, condition)
+
+	condition, err = parseRuleCondition("end>=start")
+	require.NoError(t, err)
+	assert.Equal(t, ruleCondition{left: "end", op: ">=", right: "start"}, condition)
+
+	_, err = parseRuleCondition("status shipped")
+	assert.Error(t, err)
+
+	_, err = parseRuleCondition("status =")
+	assert.Error(t, err)
+}
+
+func TestRuleCondition_Holds(t *testing.T) {
+	row := map[string]interface{}{"status": "shipped", "qty": int64(3), "min": "2", "note": nil}
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{"status = 'shipped'", true},
+		{"status == shipped", true},
+		{`status != "shipped"`, false},
+		{"qty = 3.0", true},
+		{"qty > min", true},
+		{"qty <= 2", false},
+		{"note = ''", true},
+		{"missing = 'x'", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			condition, err := parseRuleCondition(tt.condition)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, condition.holds(row))
+		})
+	}
+}
+
+func TestValidateBusinessRules_ConditionalRequired(t *testing.T) {
+	svc := NewValidationService(&fakeSchemaRepository{}, &fakeSubmissionRepository{})
+	requiredRule := func(condition string) *models.DatasetBusinessRule {
+		config, err := json.Marshal(models.BusinessRuleConfig{FieldName: "tracking_number", Condition: condition})
+		require.NoError(t, err)
+		return &models.DatasetBusinessRule{
+			RuleType:     models.RuleTypeRequired,
+			RuleConfig:   config,
+			ErrorMessage: "Shipped orders need a tracking number",
+			IsActive:     true,
+		}
+	}
+	rows := []map[string]interface{}{
+		{"status": "shipped", "tracking_number": "1Z999"},
+		{"status": "shipped", "tracking_number": ""},
+		{"status": "pending", "tracking_number": ""},
+		{"status": "shipped"},
+	}
+
+	t.Run("only rows meeting the condition need the field", func(t *testing.T) {
+		violations, err := svc.validateBusinessRules(rows, []*models.DatasetBusinessRule{requiredRule("status = 'shipped'")})
+		require.NoError(t, err)
+		require.Len(t, violations, 2)
+		assert.Equal(t, 1, violations[0].RowIndex)
+		assert.Equal(t, 3, violations[1].RowIndex)
+		assert.Equal(t, "required_violation", violations[0].ErrorType)
+		assert.Equal(t, "tracking_number", violations[0].FieldName)
+	})
+
+	t.Run("without a condition every row needs the field", func(t *testing.T) {
+		violations, err := svc.validateBusinessRules(rows, []*models.DatasetBusinessRule{requiredRule("")})
+		require.NoError(t, err)
+		assert.Len(t, violations, 3)
+	})
+}
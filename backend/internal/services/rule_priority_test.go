@@ -0,0 +1,79 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rangeRule(name string, priority int, stopOnFail bool, min, max float64) *models.DatasetBusinessRule {
+	config, _ := json.Marshal(models.BusinessRuleConfig{FieldName: "amount", MinValue: min, MaxValue: max})
+	return &models.DatasetBusinessRule{
+		RuleName:     name,
+		RuleType:     models.RuleTypeRangeCheck,
+		RuleConfig:   config,
+		ErrorMessage: name,
+		IsActive:     true,
+		Priority:     priority,
+		StopOnFail:   stopOnFail,
+	}
+}
+
+func failedRules(errors []models.DataValidationError) map[int][]string {
+	failed := make(map[int][]string)
+	for _, e := range errors {
+		failed[e.RowIndex] = append(failed[e.RowIndex], e.Message)
+	}
+	return failed
+}
+
+func TestValidateBusinessRules_StopOnFail(t *testing.T) {
+	svc := NewValidationService(&fakeSchemaRepository{}, &fakeSubmissionRepository{})
+	rows := []map[string]interface{}{
+		{"amount": 5.0},
+		{"amount": -5.0},
+		{"amount": 500.0},
+	}
+
+	t.Run("a critical failure skips lower-priority rules for that row only", func(t *testing.T) {
+		rules := []*models.DatasetBusinessRule{
+			rangeRule("at most 100", 20, false, -1000, 100),
+			rangeRule("not negative", 10, true, 0, 1000),
+			rangeRule("at least 10", 20, false, 10, 1000),
+		}
+
+		errors, err := svc.validateBusinessRules(rows, rules)
+		require.NoError(t, err)
+		failed := failedRules(errors)
+		assert.Equal(t, []string{"at least 10"}, failed[0])
+		assert.Equal(t, []string{"not negative"}, failed[1])
+		assert.Equal(t, []string{"at most 100"}, failed[2])
+	})
+
+	t.Run("rules sharing the stopping rule's priority still run", func(t *testing.T) {
+		rules := []*models.DatasetBusinessRule{
+			rangeRule("not negative", 10, true, 0, 1000),
+			rangeRule("at least 10", 10, false, 10, 1000),
+		}
+
+		errors, err := svc.validateBusinessRules(rows, rules)
+		require.NoError(t, err)
+		failed := failedRules(errors)
+		assert.Equal(t, []string{"not negative", "at least 10"}, failed[1])
+	})
+
+	t.Run("without stop_on_fail every rule runs", func(t *testing.T) {
+		rules := []*models.DatasetBusinessRule{
+			rangeRule("not negative", 10, false, 0, 1000),
+			rangeRule("at least 10", 20, false, 10, 1000),
+		}
+
+		errors, err := svc.validateBusinessRules(rows, rules)
+		require.NoError(t, err)
+		failed := failedRules(errors)
+		assert.Equal(t, []string{"not negative", "at least 10"}, failed[1])
+	})
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ErrUnsupportedRuleType is returned when a rule type cannot be evaluated against sample rows
+var ErrUnsupportedRuleType = errors.New("rule type cannot be tested")
+
+// rowIndexKey is the key dataset previews use to carry each row's stored index
+const rowIndexKey = "_row_index"
+
+// TestBusinessRule runs an unsaved rule against sample rows and reports the rows it would flag.
+// Rows carrying a _row_index are reported by that index, others by their position in the sample.
+func (v *ValidationService) TestBusinessRule(req *models.TestBusinessRuleRequest, rows []map[string]interface{}, source string) (*models.BusinessRuleTestResult, error) {
+	switch req.RuleType {
+	case models.RuleTypeUnique, models.RuleTypeRangeCheck, models.RuleTypeCrossField, models.RuleTypeRequired:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedRuleType, req.RuleType)
+	}
+
+	config, err := json.Marshal(req.RuleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rule config: %w", err)
+	}
+
+	errorMessage := req.ErrorMessage
+	if errorMessage == "" {
+		errorMessage = fmt.Sprintf("Row violates %s rule", req.RuleType)
+	}
+
+	rule := &models.DatasetBusinessRule{
+		RuleType:     req.RuleType,
+		RuleConfig:   config,
+		ErrorMessage: errorMessage,
+		IsActive:     true,
+	}
+
+	violations, err := v.validateBusinessRules(rows, []*models.DatasetBusinessRule{rule})
+	if err != nil {
+		return nil, err
+	}
+	if violations == nil {
+		violations = []models.DataValidationError{}
+	}
+
+	flagged := make(map[int]bool)
+	for i := range violations {
+		violations[i].RowIndex = sampleRowIndex(rows[violations[i].RowIndex], violations[i].RowIndex)
+		flagged[violations[i].RowIndex] = true
+	}
+
+	flaggedRows := make([]int, 0, len(flagged))
+	for rowIndex := range flagged {
+		flaggedRows = append(flaggedRows, rowIndex)
+	}
+	sort.Ints(flaggedRows)
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].RowIndex < violations[j].RowIndex })
+
+	return &models.BusinessRuleTestResult{
+		RuleType:    req.RuleType,
+		Source:      source,
+		CheckedRows: len(rows),
+		FlaggedRows: flaggedRows,
+		Violations:  violations,
+	}, nil
+}
+
+// sampleRowIndex returns the stored row index carried by a preview row, or its position otherwise
+func sampleRowIndex(row map[string]interface{}, position int) int {
+	switch value := row[rowIndexKey].(type) {
+	case int:
+		return value
+	case float64:
+		return int(value)
+	case string:
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return position
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationService_TestBusinessRule(t *testing.T) {
+	svc := NewValidationService(&fakeSchemaRepository{}, &fakeSubmissionRepository{})
+
+	t.Run("unique rule flags repeated values", func(t *testing.T) {
+		req := &models.TestBusinessRuleRequest{
+			RuleType:     models.RuleTypeUnique,
+			RuleConfig:   models.BusinessRuleConfig{FieldName: "email"},
+			ErrorMessage: "Email must be unique",
+		}
+		rows := []map[string]interface{}{
+			{"email": "a@example.com"},
+			{"email": "b@example.com"},
+			{"email": "a@example.com"},
+		}
+
+		result, err := svc.TestBusinessRule(req, rows, "request")
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.CheckedRows)
+		assert.Equal(t, []int{2}, result.FlaggedRows)
+		require.Len(t, result.Violations, 1)
+		assert.Equal(t, "Email must be unique", result.Violations[0].Message)
+	})
+
+	t.Run("cross-field rule flags failing rows by stored index", func(t *testing.T) {
+		req := &models.TestBusinessRuleRequest{
+			RuleType: models.RuleTypeCrossField,
+			RuleConfig: models.BusinessRuleConfig{
+				Fields:    []string{"end", "start"},
+				Condition: "end > start",
+			},
+		}
+		rows := []map[string]interface{}{
+			{"start": "1", "end": "5", "_row_index": 10},
+			{"start": "7", "end": "3", "_row_index": 11},
+			{"start": "4", "end": "4", "_row_index": 12},
+		}
+
+		result, err := svc.TestBusinessRule(req, rows, "dataset")
+		require.NoError(t, err)
+		assert.Equal(t, "dataset", result.Source)
+		assert.Equal(t, []int{11, 12}, result.FlaggedRows)
+		assert.Equal(t, "cross_field_violation", result.Violations[0].ErrorType)
+		assert.NotEmpty(t, result.Violations[0].Message)
+	})
+
+	t.Run("unsupported rule type", func(t *testing.T) {
+		req := &models.TestBusinessRuleRequest{RuleType: models.RuleTypeCustomSQL}
+		_, err := svc.TestBusinessRule(req, nil, "request")
+		assert.ErrorIs(t, err, ErrUnsupportedRuleType)
+	})
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// Sample size limits for quick validation
+const (
+	DefaultQuickValidateSampleSize = 1000
+	MaxQuickValidateSampleSize     = 10000
+)
+
+// SampleOptions selects which rows quick validation checks
+type SampleOptions struct {
+	Size int
+	Mode string // models.SampleModeFirst or models.SampleModeRandom
+}
+
+// ParseSampleOptions builds sample options from optional form values, defaulting to the first
+// DefaultQuickValidateSampleSize rows
+func ParseSampleOptions(size, mode string) (SampleOptions, error) {
+	opts := SampleOptions{Size: DefaultQuickValidateSampleSize, Mode: models.SampleModeFirst}
+
+	if size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < 1 || n > MaxQuickValidateSampleSize {
+			return opts, fmt.Errorf("sample_size must be a number between 1 and %d", MaxQuickValidateSampleSize)
+		}
+		opts.Size = n
+	}
+
+	switch mode {
+	case "":
+	case models.SampleModeFirst, models.SampleModeRandom:
+		opts.Mode = mode
+	default:
+		return opts, fmt.Errorf("sample_mode must be '%s' or '%s'", models.SampleModeFirst, models.SampleModeRandom)
+	}
+
+	return opts, nil
+}
+
+// sampledRecord is a CSV record with its row index in the file
+type sampledRecord struct {
+	rowIndex int
+	record   []string
+}
+
+// QuickValidate validates a sample of a CSV upload against the schema and business rules for
+// fast feedback. Only the sampled rows are validated; the rest are counted so invalid rows can be
+// extrapolated. Rules spanning rows, like uniqueness, only see the sample.
+// An empty schemaName selects the dataset's default schema, and columnMapping renames file headers
+// as in ValidateDataSubmission.
+func (v *ValidationService) QuickValidate(r io.Reader, datasetID uuid.UUID, schemaName string, columnMapping models.ColumnMapping, opts SampleOptions) (*models.SampledValidationResult, error) {
+	release := v.validations.Acquire(datasetID)
+	defer release()
+
+	schema, err := v.loadSchema(datasetID, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", err)
+	}
+
+	businessRules, err := v.submissionRepo.GetBusinessRules(datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load business rules: %w", err)
+	}
+
+	dialect, err := v.schemaRepo.GetDatasetCSVDialect(datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CSV dialect: %w", err)
+	}
+
+	headerMode, err := v.schemaRepo.GetDatasetHeaderMode(datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load header mode: %w", err)
+	}
+
+	nullTokens, err := v.schemaRepo.GetDatasetNullTokens(datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load null tokens: %w", err)
+	}
+
+	reader := NewCSVReader(r, dialect)
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read headers: %w", err)
+	}
+	// Headers are matched by trimmed name, in any order
+	headers, err = ApplyColumnMapping(TrimHeaders(headers), columnMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	sampled := &models.SampledValidationResult{SampleMode: opts.Mode, SampledRowIndexes: []int{}}
+
+	headerValidation := v.validateHeaders(headers, schema, headerMode)
+	if !headerValidation.IsValid {
+		sampled.Result = headerValidation
+		return sampled, nil
+	}
+
+	sample, fileRows, err := sampleRecords(reader, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckRowLimit(fileRows, v.maxRows); err != nil {
+		return nil, &RowLimitError{Limit: v.maxRows, Rows: fileRows}
+	}
+
+	validationResult := newValidationResult(schema)
+	headers, validationResult.IgnoredFields = ignoreUnknownHeaders(headers, schema)
+	allRowData := make([]map[string]interface{}, 0, len(sample))
+	stagingData := make([]*models.DataSubmissionStaging, 0, len(sample))
+	for _, s := range sample {
+		validationResult.TotalRows++
+		rowData, stagingRow := v.validateRecord(headers, s.record, schema, nullTokens, s.rowIndex, validationResult)
+		allRowData = append(allRowData, rowData)
+		stagingData = append(stagingData, stagingRow)
+		sampled.SampledRowIndexes = append(sampled.SampledRowIndexes, s.rowIndex)
+	}
+
+	guardrailErrors, err := v.checkPIIGuardrails(datasetID, allRowData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.finishValidation(validationResult, allRowData, stagingData, businessRules, guardrailErrors); err != nil {
+		return nil, err
+	}
+
+	// Business rules report positions within the sample; point them back at file rows
+	for i := range validationResult.BusinessRuleErrors {
+		if pos := validationResult.BusinessRuleErrors[i].RowIndex; pos >= 0 && pos < len(sample) {
+			validationResult.BusinessRuleErrors[i].RowIndex = sample[pos].rowIndex
+		}
+	}
+
+	sampled.Result = validationResult
+	sampled.SampleSize = len(sample)
+	sampled.FileRows = fileRows
+	sampled.Sampled = len(sample) < fileRows
+	if len(sample) > 0 {
+		sampled.EstimatedInvalidRatio = float64(validationResult.InvalidRows) / float64(len(sample))
+	}
+	sampled.EstimatedInvalidRows = int(math.Round(sampled.EstimatedInvalidRatio * float64(fileRows)))
+	sampled.EstimatedValidRows = fileRows - sampled.EstimatedInvalidRows
+
+	return sampled, nil
+}
+
+// sampleRecords reads every remaining record and keeps opts.Size of them in file order: the first
+// ones, or a uniform random selection (reservoir sampling). It returns the sample and the row count.
+func sampleRecords(reader CSVRecordReader, opts SampleOptions) ([]sampledRecord, int, error) {
+	var sample []sampledRecord
+	rows := 0
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read row %d: %w", rows, err)
+		}
+
+		switch {
+		case len(sample) < opts.Size:
+			sample = append(sample, sampledRecord{rowIndex: rows, record: record})
+		case opts.Mode == models.SampleModeRandom:
+			if j := rand.IntN(rows + 1); j < opts.Size {
+				sample[j] = sampledRecord{rowIndex: rows, record: record}
+			}
+		}
+		rows++
+	}
+
+	sort.Slice(sample, func(i, j int) bool { return sample[i].rowIndex < sample[j].rowIndex })
+	return sample, rows, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleCSV builds a file with the given number of rows where every row from invalidFrom on
+// has a non-numeric amount
+func sampleCSV(rows, invalidFrom int) string {
+	var b strings.Builder
+	b.WriteString("id,amount\n")
+	for i := 0; i < rows; i++ {
+		amount := "10"
+		if i >= invalidFrom {
+			amount = "oops"
+		}
+		fmt.Fprintf(&b, "%d,%s\n", i, amount)
+	}
+	return b.String()
+}
+
+func TestParseSampleOptions(t *testing.T) {
+	opts, err := ParseSampleOptions("", "")
+	require.NoError(t, err)
+	assert.Equal(t, SampleOptions{Size: DefaultQuickValidateSampleSize, Mode: models.SampleModeFirst}, opts)
+
+	opts, err = ParseSampleOptions("50", "random")
+	require.NoError(t, err)
+	assert.Equal(t, SampleOptions{Size: 50, Mode: models.SampleModeRandom}, opts)
+
+	_, err = ParseSampleOptions("0", "")
+	assert.Error(t, err)
+	_, err = ParseSampleOptions("", "middle")
+	assert.Error(t, err)
+}
+
+func TestValidationService_QuickValidate(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "number", IsRequired: true},
+			{Name: "amount", DataType: "number"},
+		},
+	}
+	uniqueConfig, err := json.Marshal(models.BusinessRuleConfig{FieldName: "id"})
+	require.NoError(t, err)
+	rules := []*models.DatasetBusinessRule{{RuleType: models.RuleTypeUnique, RuleConfig: uniqueConfig}}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{rules: rules})
+
+	t.Run("first mode only validates the first N rows", func(t *testing.T) {
+		// Every row after the sample is invalid, so any row outside it would show up as an error
+		result, err := svc.QuickValidate(strings.NewReader(sampleCSV(100, 10)), uuid.New(), "", nil, SampleOptions{Size: 10, Mode: models.SampleModeFirst})
+		require.NoError(t, err)
+
+		assert.True(t, result.Sampled)
+		assert.Equal(t, 10, result.SampleSize)
+		assert.Equal(t, 100, result.FileRows)
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, result.SampledRowIndexes)
+		assert.Equal(t, 10, result.Result.TotalRows)
+		assert.Equal(t, 10, result.Result.ValidRows)
+		assert.Empty(t, result.Result.SchemaErrors)
+		assert.Equal(t, 0, result.EstimatedInvalidRows)
+	})
+
+	t.Run("invalid rows are extrapolated to the file", func(t *testing.T) {
+		result, err := svc.QuickValidate(strings.NewReader(sampleCSV(100, 5)), uuid.New(), "", nil, SampleOptions{Size: 10, Mode: models.SampleModeFirst})
+		require.NoError(t, err)
+
+		assert.Equal(t, 5, result.Result.InvalidRows)
+		assert.Len(t, result.Result.SchemaErrors, 5)
+		assert.Equal(t, 0.5, result.EstimatedInvalidRatio)
+		assert.Equal(t, 50, result.EstimatedInvalidRows)
+		assert.Equal(t, 50, result.EstimatedValidRows)
+	})
+
+	t.Run("random mode validates N rows spread over the file", func(t *testing.T) {
+		result, err := svc.QuickValidate(strings.NewReader(sampleCSV(500, 500)), uuid.New(), "", nil, SampleOptions{Size: 20, Mode: models.SampleModeRandom})
+		require.NoError(t, err)
+
+		assert.True(t, result.Sampled)
+		assert.Equal(t, 20, result.Result.TotalRows)
+		require.Len(t, result.SampledRowIndexes, 20)
+		assert.IsIncreasing(t, result.SampledRowIndexes)
+		for _, rowIndex := range result.SampledRowIndexes {
+			assert.Less(t, rowIndex, 500)
+		}
+	})
+
+	t.Run("business rule errors point at file rows", func(t *testing.T) {
+		// Every id repeats, so any sampled pair breaks the unique rule
+		file := "id,amount\n" + strings.Repeat("7,1\n", 50)
+		result, err := svc.QuickValidate(strings.NewReader(file), uuid.New(), "", nil, SampleOptions{Size: 2, Mode: models.SampleModeRandom})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, result.Result.BusinessRuleErrors)
+		for _, ruleErr := range result.Result.BusinessRuleErrors {
+			assert.Contains(t, result.SampledRowIndexes, ruleErr.RowIndex)
+		}
+	})
+}
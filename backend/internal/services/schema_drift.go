@@ -0,0 +1,203 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// MaxTrackedFieldValues is the most distinct values field stats list for a field; fields with more
+// are treated as free-form and are not checked for new values
+const MaxTrackedFieldValues = 20
+
+// Kinds of drift reported for a field
+const (
+	DriftNewValues       = "new_values"       // a categorical field received values absent from the baseline
+	DriftNullRate        = "null_rate"        // the share of empty values rose
+	DriftTypeConformance = "type_conformance" // the share of non-empty values passing the type check fell
+)
+
+// DriftThresholds sets how large a change must be to be reported as drift
+type DriftThresholds struct {
+	NullRateIncrease float64 // rise in null rate, as a fraction of all values
+	ConformanceDrop  float64 // drop in type conformance, as a fraction of non-empty values
+	MinValues        int     // rate checks are skipped for submissions with fewer values in a field
+}
+
+// DefaultDriftThresholds flags a 20 point null rate rise or a 10 point conformance drop over at least 5 values
+var DefaultDriftThresholds = DriftThresholds{NullRateIncrease: 0.2, ConformanceDrop: 0.1, MinValues: 5}
+
+// FieldDrift is one significant change of a field in a submission compared to the baseline
+type FieldDrift struct {
+	FieldName string   `json:"field_name"`
+	Kind      string   `json:"kind"`
+	Message   string   `json:"message"`
+	Baseline  float64  `json:"baseline,omitempty"` // baseline rate, for rate drifts
+	Observed  float64  `json:"observed,omitempty"` // submission rate, for rate drifts
+	NewValues []string `json:"new_values,omitempty"`
+}
+
+// SubmissionDrift lists the drift found in one submission
+type SubmissionDrift struct {
+	SubmissionID uuid.UUID    `json:"submission_id"`
+	FileName     string       `json:"file_name"`
+	SubmittedAt  time.Time    `json:"submitted_at"`
+	Drift        []FieldDrift `json:"drift"`
+}
+
+// DriftReport compares recent submissions' field stats against a dataset's stored field stats
+type DriftReport struct {
+	DatasetID           uuid.UUID         `json:"dataset_id"`
+	BaselineComputedAt  time.Time         `json:"baseline_computed_at"`
+	BaselineRowCount    int               `json:"baseline_row_count"`
+	SubmissionsCompared int               `json:"submissions_compared"`
+	Drifted             bool              `json:"drifted"`
+	Submissions         []SubmissionDrift `json:"submissions"`
+}
+
+// SubmissionFieldStats is a submission with the field stats recorded when it was validated
+type SubmissionFieldStats struct {
+	Submission *models.DataSubmission
+	FieldStats map[string]models.FieldStats
+}
+
+// SubmissionFieldStatsFromResults reads the field stats stored with each submission's validation
+// results, skipping submissions without any
+func SubmissionFieldStatsFromResults(submissions []*models.DataSubmission) []SubmissionFieldStats {
+	var stats []SubmissionFieldStats
+	for _, submission := range submissions {
+		if submission.ValidationResults == nil {
+			continue
+		}
+		var result models.ValidationResult
+		if err := json.Unmarshal(*submission.ValidationResults, &result); err != nil || len(result.FieldStats) == 0 {
+			continue
+		}
+		stats = append(stats, SubmissionFieldStats{Submission: submission, FieldStats: result.FieldStats})
+	}
+	return stats
+}
+
+// DetectDrift reports, for each submission, the fields whose stats changed significantly from the
+// baseline. Fields missing from the baseline are skipped; a field counts as categorical when the
+// baseline tracks its values and most of its non-empty values repeat.
+func DetectDrift(baseline *models.DatasetFieldStats, submissions []SubmissionFieldStats, thresholds DriftThresholds) *DriftReport {
+	report := &DriftReport{
+		DatasetID:           baseline.DatasetID,
+		BaselineComputedAt:  baseline.ComputedAt,
+		BaselineRowCount:    baseline.RowCount,
+		SubmissionsCompared: len(submissions),
+		Submissions:         []SubmissionDrift{},
+	}
+
+	for _, submission := range submissions {
+		names := make([]string, 0, len(submission.FieldStats))
+		for name := range submission.FieldStats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var drift []FieldDrift
+		for _, name := range names {
+			before, ok := baseline.Fields[name]
+			if !ok {
+				continue
+			}
+			drift = append(drift, fieldDrift(name, before, submission.FieldStats[name], thresholds)...)
+		}
+		if len(drift) == 0 {
+			continue
+		}
+
+		report.Drifted = true
+		report.Submissions = append(report.Submissions, SubmissionDrift{
+			SubmissionID: submission.Submission.ID,
+			FileName:     submission.Submission.FileName,
+			SubmittedAt:  submission.Submission.SubmittedAt,
+			Drift:        drift,
+		})
+	}
+
+	return report
+}
+
+// fieldDrift compares one field's submission stats to its baseline stats
+func fieldDrift(name string, before, after models.FieldStats, thresholds DriftThresholds) []FieldDrift {
+	var drift []FieldDrift
+
+	if isCategorical(before) {
+		if newValues := valuesMissingFrom(before.Values, after.Values); len(newValues) > 0 {
+			drift = append(drift, FieldDrift{
+				FieldName: name,
+				Kind:      DriftNewValues,
+				Message:   fmt.Sprintf("field '%s' has %d value(s) not seen in the dataset", name, len(newValues)),
+				NewValues: newValues,
+			})
+		} else if after.Values == nil && after.UniqueValues > len(before.Values) {
+			// Too many distinct values to list, so some of them must be new
+			drift = append(drift, FieldDrift{
+				FieldName: name,
+				Kind:      DriftNewValues,
+				Message:   fmt.Sprintf("field '%s' has %d distinct values, the dataset only %d", name, after.UniqueValues, len(before.Values)),
+			})
+		}
+	}
+
+	if after.TotalValues >= thresholds.MinValues && before.TotalValues > 0 && after.TotalValues > 0 {
+		beforeRate := float64(before.NullValues) / float64(before.TotalValues)
+		afterRate := float64(after.NullValues) / float64(after.TotalValues)
+		if afterRate-beforeRate > thresholds.NullRateIncrease {
+			drift = append(drift, FieldDrift{
+				FieldName: name,
+				Kind:      DriftNullRate,
+				Message:   fmt.Sprintf("field '%s' null rate rose from %.0f%% to %.0f%%", name, beforeRate*100, afterRate*100),
+				Baseline:  beforeRate,
+				Observed:  afterRate,
+			})
+		}
+	}
+
+	beforePresent := before.TotalValues - before.NullValues
+	afterPresent := after.TotalValues - after.NullValues
+	if afterPresent >= thresholds.MinValues && beforePresent > 0 {
+		beforeRate := float64(beforePresent-before.InvalidValues) / float64(beforePresent)
+		afterRate := float64(afterPresent-after.InvalidValues) / float64(afterPresent)
+		if beforeRate-afterRate > thresholds.ConformanceDrop {
+			drift = append(drift, FieldDrift{
+				FieldName: name,
+				Kind:      DriftTypeConformance,
+				Message:   fmt.Sprintf("field '%s' type conformance fell from %.0f%% to %.0f%%", name, beforeRate*100, afterRate*100),
+				Baseline:  beforeRate,
+				Observed:  afterRate,
+			})
+		}
+	}
+
+	return drift
+}
+
+// isCategorical reports whether baseline stats describe a field drawn from a small set of values
+func isCategorical(stats models.FieldStats) bool {
+	present := stats.TotalValues - stats.NullValues
+	return len(stats.Values) > 0 && stats.UniqueValues*2 <= present
+}
+
+// valuesMissingFrom returns the values not in known, sorted
+func valuesMissingFrom(known, values []string) []string {
+	seen := make(map[string]bool, len(known))
+	for _, value := range known {
+		seen[value] = true
+	}
+	var missing []string
+	for _, value := range values {
+		if !seen[value] {
+			missing = append(missing, value)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var driftSchema = &models.DatasetSchema{Fields: []models.SchemaField{
+	{Name: "status", DataType: "string"},
+	{Name: "amount", DataType: "number"},
+}}
+
+// driftRows returns one row per status, all with the same amount
+func driftRows(amount interface{}, statuses ...string) []map[string]interface{} {
+	rows := make([]map[string]interface{}, len(statuses))
+	for i, status := range statuses {
+		rows[i] = map[string]interface{}{"status": status, "amount": amount}
+	}
+	return rows
+}
+
+// driftBaseline has a categorical status and distinct amounts
+func driftBaseline() *models.DatasetFieldStats {
+	rows := driftRows(nil, "open", "closed", "open", "closed", "open", "open", "closed", "open")
+	for i, row := range rows {
+		row["amount"] = strconv.Itoa(10 + i)
+	}
+	return &models.DatasetFieldStats{DatasetID: uuid.New(), RowCount: len(rows), Fields: ComputeFieldStats(rows, driftSchema)}
+}
+
+// driftSubmission stores the stats of rows as a submission's validation results
+func driftSubmission(t *testing.T, rows []map[string]interface{}) *models.DataSubmission {
+	t.Helper()
+	result := models.ValidationResult{TotalRows: len(rows), FieldStats: ComputeFieldStats(rows, driftSchema)}
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	raw := json.RawMessage(data)
+	return &models.DataSubmission{ID: uuid.New(), FileName: "append.csv", ValidationResults: &raw}
+}
+
+func TestDetectDrift_NewCategoricalValues(t *testing.T) {
+	baseline := driftBaseline()
+	unchanged := driftSubmission(t, driftRows("12", "open", "closed"))
+	recent := driftSubmission(t, driftRows("15", "open", "pending", "archived"))
+
+	report := DetectDrift(baseline, SubmissionFieldStatsFromResults([]*models.DataSubmission{recent, unchanged}), DefaultDriftThresholds)
+	assert.True(t, report.Drifted)
+	assert.Equal(t, 2, report.SubmissionsCompared)
+	require.Len(t, report.Submissions, 1, "only the submission with new values drifted")
+
+	drift := report.Submissions[0]
+	assert.Equal(t, recent.ID, drift.SubmissionID)
+	require.Len(t, drift.Drift, 1)
+	assert.Equal(t, "status", drift.Drift[0].FieldName)
+	assert.Equal(t, DriftNewValues, drift.Drift[0].Kind)
+	assert.Equal(t, []string{"archived", "pending"}, drift.Drift[0].NewValues)
+}
+
+func TestDetectDrift_Rates(t *testing.T) {
+	baseline := driftBaseline()
+	rows := driftRows("abc", "open", "", "", "", "closed", "open")
+	rows = append(rows, driftRows("20", "open")...)
+
+	report := DetectDrift(baseline, SubmissionFieldStatsFromResults([]*models.DataSubmission{driftSubmission(t, rows)}), DefaultDriftThresholds)
+	require.Len(t, report.Submissions, 1)
+
+	kinds := make(map[string]FieldDrift)
+	for _, drift := range report.Submissions[0].Drift {
+		kinds[drift.FieldName+":"+drift.Kind] = drift
+	}
+	require.Contains(t, kinds, "status:"+DriftNullRate)
+	assert.InDelta(t, 0, kinds["status:"+DriftNullRate].Baseline, 0.001)
+	assert.InDelta(t, 3.0/7, kinds["status:"+DriftNullRate].Observed, 0.001)
+	require.Contains(t, kinds, "amount:"+DriftTypeConformance)
+	assert.InDelta(t, 1.0/7, kinds["amount:"+DriftTypeConformance].Observed, 0.001)
+	assert.NotContains(t, kinds, "amount:"+DriftNewValues, "numbers with mostly distinct values are not categorical")
+
+	t.Run("small submissions skip rate checks", func(t *testing.T) {
+		small := driftSubmission(t, driftRows("abc", "", ""))
+		report := DetectDrift(baseline, SubmissionFieldStatsFromResults([]*models.DataSubmission{small}), DefaultDriftThresholds)
+		assert.False(t, report.Drifted)
+	})
+}
+
+func TestSubmissionFieldStatsFromResults_SkipsMissingResults(t *testing.T) {
+	empty := json.RawMessage(`{"field_stats":{}}`)
+	submissions := []*models.DataSubmission{{ID: uuid.New()}, {ID: uuid.New(), ValidationResults: &empty}}
+	assert.Empty(t, SubmissionFieldStatsFromResults(submissions))
+}
+
+func TestCountInvalidValues(t *testing.T) {
+	fieldStats := map[string]models.FieldStats{"amount": {}, "status": {}}
+	countInvalidValues([]models.DataValidationError{
+		{FieldName: "amount", ErrorType: "invalid_data_type"},
+		{FieldName: "amount", ErrorType: "numeric_overflow"},
+		{FieldName: "status", ErrorType: "max_length"},
+	}, fieldStats)
+
+	assert.Equal(t, 1, fieldStats["amount"].InvalidValues, "a field counts once per row")
+	assert.Equal(t, 0, fieldStats["status"].InvalidValues)
+}
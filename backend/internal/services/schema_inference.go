@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,22 +21,49 @@ type InferredField struct {
 	IsRequired   bool                   `json:"is_required"`
 	Constraints  map[string]interface{} `json:"constraints,omitempty"`
 	Pattern      string                 `json:"pattern,omitempty"`
-	Confidence   float64                `json:"confidence"` // 0.0 to 1.0
+	Confidence   float64                `json:"confidence"`   // 0.0 to 1.0
+	NeedsReview  bool                   `json:"needs_review"` // Confidence is below the review threshold
 	SampleValues []string               `json:"sample_values,omitempty"`
+	// Options lists the distinct values of a categorical column, with OptionsConfidence (0.0 to 1.0)
+	// rising as values repeat more often
+	Options           []string `json:"options,omitempty"`
+	OptionsConfidence float64  `json:"options_confidence,omitempty"`
 }
 
 type InferredSchema struct {
-	Name        string          `json:"name"`
-	Description string          `json:"description"`
-	Fields      []InferredField `json:"fields"`
-	RowCount    int             `json:"row_count"`
-	Confidence  float64         `json:"overall_confidence"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Fields      []InferredField  `json:"fields"`
+	RowCount    int              `json:"row_count"`
+	Confidence  float64          `json:"overall_confidence"`
+	Thresholds  InferenceOptions `json:"thresholds"`
+}
+
+// InferenceOptions controls how strict schema inference is
+type InferenceOptions struct {
+	// TypeThreshold is the share of non-empty values that must match a type before a column
+	// is inferred as that type instead of string
+	TypeThreshold float64 `json:"type_threshold"`
+	// RequiredThreshold is the share of values that must be non-empty (strictly more than)
+	// before a column is inferred as required
+	RequiredThreshold float64 `json:"required_threshold"`
+	// ReviewThreshold is the type confidence below which a field is flagged for review
+	ReviewThreshold float64 `json:"review_threshold"`
+}
+
+// DefaultInferenceOptions returns the thresholds used when none are given
+func DefaultInferenceOptions() InferenceOptions {
+	return InferenceOptions{
+		TypeThreshold:     0.8,
+		RequiredThreshold: 0.9,
+		ReviewThreshold:   0.7,
+	}
 }
 
 // Common patterns for field detection
 var (
 	emailPattern    = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-	phonePattern    = regexp.MustCompile(`^\+?[\d\s\-\(\)]{7,15}$`)
+	phonePattern    = regexp.MustCompile(`^\+?[\d\s\-\(\)\.]{7,20}$`)
 	urlPattern      = regexp.MustCompile(`^https?://[^\s]+$`)
 	datePatterns    = []*regexp.Regexp{
 		regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`),         // YYYY-MM-DD
@@ -50,12 +78,76 @@ var (
 	uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 )
 
+// Limits for treating a string column as categorical
+const (
+	maxEnumOptions     = 20  // most distinct values an enum may have
+	maxEnumUniqueRatio = 0.2 // distinct values may be at most this share of non-empty values
+	minEnumValues      = 10  // fewer non-empty values are too few to tell an enum from free text
+)
+
 func NewSchemaInferenceService() *SchemaInferenceService {
 	return &SchemaInferenceService{}
 }
 
-// InferSchemaFromData analyzes data and infers schema with confidence scores
+// Describe explains the effect of each threshold for API responses
+func (o InferenceOptions) Describe() map[string]string {
+	return map[string]string{
+		"type_threshold":     fmt.Sprintf("A column is inferred as a non-string type when at least %.0f%% of its non-empty values match that type", o.TypeThreshold*100),
+		"required_threshold": fmt.Sprintf("A column is marked required when more than %.0f%% of its values are non-empty", o.RequiredThreshold*100),
+		"review_threshold":   fmt.Sprintf("A column is flagged for review when its type confidence is below %.0f%%", o.ReviewThreshold*100),
+	}
+}
+
+// ParseInferenceOptions builds options from optional threshold strings, keeping defaults for empty ones.
+// Thresholds must be numbers between 0 and 1.
+func ParseInferenceOptions(typeThreshold, requiredThreshold, reviewThreshold string) (InferenceOptions, error) {
+	opts := DefaultInferenceOptions()
+
+	if typeThreshold != "" {
+		value, err := parseThreshold(typeThreshold)
+		if err != nil {
+			return opts, fmt.Errorf("invalid type_threshold: %w", err)
+		}
+		opts.TypeThreshold = value
+	}
+
+	if requiredThreshold != "" {
+		value, err := parseThreshold(requiredThreshold)
+		if err != nil {
+			return opts, fmt.Errorf("invalid required_threshold: %w", err)
+		}
+		opts.RequiredThreshold = value
+	}
+
+	if reviewThreshold != "" {
+		value, err := parseThreshold(reviewThreshold)
+		if err != nil {
+			return opts, fmt.Errorf("invalid review_threshold: %w", err)
+		}
+		opts.ReviewThreshold = value
+	}
+
+	return opts, nil
+}
+
+func parseThreshold(value string) (float64, error) {
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if threshold < 0 || threshold > 1 {
+		return 0, fmt.Errorf("%v must be between 0 and 1", threshold)
+	}
+	return threshold, nil
+}
+
+// InferSchemaFromData analyzes data and infers schema with confidence scores using the default thresholds
 func (s *SchemaInferenceService) InferSchemaFromData(headers []string, rows [][]string, datasetName string) (*InferredSchema, error) {
+	return s.InferSchemaWithOptions(headers, rows, datasetName, DefaultInferenceOptions())
+}
+
+// InferSchemaWithOptions analyzes data and infers schema with confidence scores using the given thresholds
+func (s *SchemaInferenceService) InferSchemaWithOptions(headers []string, rows [][]string, datasetName string, opts InferenceOptions) (*InferredSchema, error) {
 	log.Printf("[DEBUG] InferSchemaFromData: Starting inference for dataset '%s' with %d columns and %d rows", datasetName, len(headers), len(rows))
 
 	fields := make([]InferredField, len(headers))
@@ -63,7 +155,8 @@ func (s *SchemaInferenceService) InferSchemaFromData(headers []string, rows [][]
 
 	// Analyze each column
 	for i, header := range headers {
-		field := s.analyzeColumn(header, s.extractColumn(rows, i))
+		field := s.analyzeColumn(header, s.extractColumn(rows, i), opts)
+		field.NeedsReview = field.Confidence < opts.ReviewThreshold
 		fields[i] = field
 		totalConfidence += field.Confidence
 	}
@@ -77,6 +170,7 @@ func (s *SchemaInferenceService) InferSchemaFromData(headers []string, rows [][]
 		Fields:      fields,
 		RowCount:    len(rows),
 		Confidence:  overallConfidence,
+		Thresholds:  opts,
 	}
 
 	log.Printf("[DEBUG] InferSchemaFromData: Completed inference with overall confidence %.2f", overallConfidence)
@@ -84,7 +178,7 @@ func (s *SchemaInferenceService) InferSchemaFromData(headers []string, rows [][]
 }
 
 // analyzeColumn performs deep analysis on a single column
-func (s *SchemaInferenceService) analyzeColumn(header string, values []string) InferredField {
+func (s *SchemaInferenceService) analyzeColumn(header string, values []string, opts InferenceOptions) InferredField {
 	log.Printf("[DEBUG] analyzeColumn: Analyzing column '%s' with %d values", header, len(values))
 
 	field := InferredField{
@@ -110,7 +204,7 @@ func (s *SchemaInferenceService) analyzeColumn(header string, values []string) I
 	// Calculate required field confidence
 	if len(values) > 0 {
 		requiredConfidence := float64(len(nonEmptyValues)) / float64(len(values))
-		field.IsRequired = requiredConfidence > 0.9 // Required if >90% of values are non-empty
+		field.IsRequired = requiredConfidence > opts.RequiredThreshold
 	}
 
 	// Store sample values (up to 5)
@@ -125,13 +219,16 @@ func (s *SchemaInferenceService) analyzeColumn(header string, values []string) I
 	}
 
 	// Analyze data types with confidence scoring
-	typeAnalysis := s.analyzeDataTypes(nonEmptyValues)
+	typeAnalysis := s.analyzeDataTypes(nonEmptyValues, opts.TypeThreshold)
 	field.DataType = typeAnalysis.PrimaryType
 	field.Confidence = typeAnalysis.Confidence
 	field.Pattern = typeAnalysis.Pattern
 
 	// Add constraints based on data type
 	s.addConstraints(&field, nonEmptyValues, typeAnalysis)
+	if field.DataType == models.FieldTypeString {
+		s.detectEnum(&field, nonEmptyValues)
+	}
 
 	log.Printf("[DEBUG] analyzeColumn: Column '%s' inferred as %s with confidence %.2f", header, field.DataType, field.Confidence)
 	return field
@@ -145,7 +242,7 @@ type TypeAnalysis struct {
 }
 
 // analyzeDataTypes performs statistical analysis of data types
-func (s *SchemaInferenceService) analyzeDataTypes(values []string) TypeAnalysis {
+func (s *SchemaInferenceService) analyzeDataTypes(values []string, typeThreshold float64) TypeAnalysis {
 	if len(values) == 0 {
 		return TypeAnalysis{
 			PrimaryType: models.FieldTypeString,
@@ -163,6 +260,9 @@ func (s *SchemaInferenceService) analyzeDataTypes(values []string) TypeAnalysis
 		models.FieldTypeEmail:    0,
 		models.FieldTypeURL:      0,
 		models.FieldTypeUUID:     0,
+		models.FieldTypeCurrency: 0,
+		models.FieldTypePercent:  0,
+		models.FieldTypePhone:    0,
 	}
 
 	patterns := make(map[string]int)
@@ -184,6 +284,15 @@ func (s *SchemaInferenceService) analyzeDataTypes(values []string) TypeAnalysis
 		if s.isUUID(value) {
 			typeScores[models.FieldTypeUUID]++
 		}
+		if s.isCurrency(value) {
+			typeScores[models.FieldTypeCurrency]++
+		}
+		if s.isPercent(value) {
+			typeScores[models.FieldTypePercent]++
+		}
+		if s.isPhone(value) {
+			typeScores[models.FieldTypePhone]++
+		}
 		
 		// Date/time analysis
 		if datePattern := s.isDate(value); datePattern != "" {
@@ -216,7 +325,7 @@ func (s *SchemaInferenceService) analyzeDataTypes(values []string) TypeAnalysis
 		confidence = float64(bestScore) / float64(len(values))
 		
 		// Require high confidence for non-string types
-		if confidence < 0.8 {
+		if confidence < typeThreshold {
 			bestType = models.FieldTypeString
 			confidence = 0.7 // Medium confidence for string fallback
 		}
@@ -263,6 +372,36 @@ func (s *SchemaInferenceService) isUUID(value string) bool {
 	return uuidPattern.MatchString(strings.ToLower(value))
 }
 
+// isCurrency requires a currency symbol so plain numbers stay numbers
+func (s *SchemaInferenceService) isCurrency(value string) bool {
+	hasSymbol := false
+	for _, symbol := range currencySymbols {
+		if strings.Contains(value, symbol) {
+			hasSymbol = true
+			break
+		}
+	}
+	if !hasSymbol {
+		return false
+	}
+	_, ok := ParseCurrency(value)
+	return ok
+}
+
+// isPhone leaves digit-only values and dashed dates to their own types
+func (s *SchemaInferenceService) isPhone(value string) bool {
+	return !s.isNumber(value) && s.isDate(value) == "" && IsValidPhone(value, "")
+}
+
+// isPercent requires a trailing percent sign so plain numbers stay numbers
+func (s *SchemaInferenceService) isPercent(value string) bool {
+	if !strings.HasSuffix(value, "%") {
+		return false
+	}
+	_, ok := ParsePercent(value)
+	return ok
+}
+
 func (s *SchemaInferenceService) isDate(value string) string {
 	for i, pattern := range datePatterns {
 		if pattern.MatchString(value) {
@@ -300,6 +439,10 @@ func (s *SchemaInferenceService) addConstraints(field *InferredField, values []s
 	switch field.DataType {
 	case models.FieldTypeNumber:
 		s.addNumberConstraints(field, values)
+		s.addScaleConstraint(field, values)
+	case models.FieldTypeCurrency, models.FieldTypePercent:
+		s.addNumberConstraints(field, canonicalNumbers(values, string(field.DataType)))
+		s.addScaleConstraint(field, values)
 	case models.FieldTypeString:
 		s.addStringConstraints(field, values)
 	case models.FieldTypeDate, models.FieldTypeDateTime:
@@ -343,6 +486,37 @@ func (s *SchemaInferenceService) addNumberConstraints(field *InferredField, valu
 	}
 }
 
+// addScaleConstraint suggests the most decimal places written in values of a numeric type, so
+// "$1.50" suggests a scale of 2 even though it is stored as 1.5
+func (s *SchemaInferenceService) addScaleConstraint(field *InferredField, values []string) {
+	scale, found := 0, false
+	for _, value := range values {
+		if _, ok := parseNumericValue(value, string(field.DataType)); !ok {
+			continue
+		}
+		found = true
+		if places := writtenDecimalPlaces(value); places > scale {
+			scale = places
+		}
+	}
+	if found {
+		field.Constraints["scale"] = scale
+	}
+}
+
+// writtenDecimalPlaces counts the digits written right after the decimal point of a number
+func writtenDecimalPlaces(value string) int {
+	_, fraction, found := strings.Cut(strings.TrimSpace(value), ".")
+	if !found {
+		return 0
+	}
+	places := 0
+	for places < len(fraction) && fraction[places] >= '0' && fraction[places] <= '9' {
+		places++
+	}
+	return places
+}
+
 func (s *SchemaInferenceService) addStringConstraints(field *InferredField, values []string) {
 	if len(values) > 0 {
 		minLen, maxLen := len(values[0]), len(values[0])
@@ -361,7 +535,45 @@ func (s *SchemaInferenceService) addStringConstraints(field *InferredField, valu
 	}
 }
 
+// detectEnum emits the distinct values of a column as enum options when there are few of them
+// relative to the number of values
+func (s *SchemaInferenceService) detectEnum(field *InferredField, values []string) {
+	if len(values) < minEnumValues {
+		return
+	}
+
+	distinct := make(map[string]bool)
+	for _, value := range values {
+		distinct[value] = true
+		if len(distinct) > maxEnumOptions {
+			return
+		}
+	}
+
+	uniqueRatio := float64(len(distinct)) / float64(len(values))
+	if uniqueRatio > maxEnumUniqueRatio {
+		return
+	}
+
+	field.Options = make([]string, 0, len(distinct))
+	for value := range distinct {
+		field.Options = append(field.Options, value)
+	}
+	sort.Strings(field.Options)
+	field.OptionsConfidence = 1 - uniqueRatio
+}
+
 // Utility functions
+func canonicalNumbers(values []string, dataType string) []string {
+	numbers := make([]string, 0, len(values))
+	for _, value := range values {
+		if number, ok := parseNumericValue(value, dataType); ok {
+			numbers = append(numbers, strconv.FormatFloat(number, 'f', -1, 64))
+		}
+	}
+	return numbers
+}
+
 func (s *SchemaInferenceService) extractColumn(rows [][]string, columnIndex int) []string {
 	column := make([]string, len(rows))
 	for i, row := range rows {
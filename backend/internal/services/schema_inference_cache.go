@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+)
+
+// DefaultSchemaInferenceCacheTTL is how long inferred schemas are cached when no TTL is configured
+const DefaultSchemaInferenceCacheTTL = 10 * time.Minute
+
+// SchemaInferenceCache caches inferred schemas per dataset version. Keys include the dataset's
+// updated_at, so new data invalidates the cached result and stale entries simply expire.
+type SchemaInferenceCache struct {
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+// NewSchemaInferenceCache creates an inferred schema cache
+func NewSchemaInferenceCache(c cache.Cache, ttl time.Duration) *SchemaInferenceCache {
+	if ttl <= 0 {
+		ttl = DefaultSchemaInferenceCacheTTL
+	}
+	return &SchemaInferenceCache{cache: c, ttl: ttl}
+}
+
+func schemaInferenceKey(datasetID uuid.UUID, updatedAt time.Time, opts InferenceOptions) string {
+	return fmt.Sprintf("schema_inference:%s:%d:%g:%g:%g", datasetID, updatedAt.UTC().UnixNano(), opts.TypeThreshold, opts.RequiredThreshold, opts.ReviewThreshold)
+}
+
+// Get returns the cached inference for the dataset version and thresholds, if any
+func (c *SchemaInferenceCache) Get(ctx context.Context, datasetID uuid.UUID, updatedAt time.Time, opts InferenceOptions) (*InferredSchema, bool) {
+	data, err := c.cache.Get(ctx, schemaInferenceKey(datasetID, updatedAt, opts))
+	if err != nil {
+		if !errors.Is(err, cache.ErrMiss) {
+			log.Printf("Warning: failed to read cached schema inference for dataset %s: %v", datasetID, err)
+		}
+		return nil, false
+	}
+
+	var schema InferredSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		log.Printf("Warning: discarding unreadable cached schema inference for dataset %s: %v", datasetID, err)
+		return nil, false
+	}
+	return &schema, true
+}
+
+// Set caches the inference for the dataset version and the thresholds it was computed with
+func (c *SchemaInferenceCache) Set(ctx context.Context, datasetID uuid.UUID, updatedAt time.Time, schema *InferredSchema) error {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("failed to encode inferred schema: %w", err)
+	}
+	return c.cache.Set(ctx, schemaInferenceKey(datasetID, updatedAt, schema.Thresholds), data, c.ttl)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/cache"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaInferenceCache(t *testing.T) {
+	ctx := context.Background()
+	inferenceCache := NewSchemaInferenceCache(cache.NewMemoryCache(), time.Minute)
+	datasetID := uuid.New()
+	updatedAt := time.Now()
+
+	schema := &InferredSchema{
+		Name:       "people",
+		Fields:     []InferredField{{Name: "age", DataType: models.FieldTypeNumber}},
+		RowCount:   3,
+		Confidence: 0.9,
+		Thresholds: DefaultInferenceOptions(),
+	}
+
+	_, ok := inferenceCache.Get(ctx, datasetID, updatedAt, DefaultInferenceOptions())
+	assert.False(t, ok)
+
+	require.NoError(t, inferenceCache.Set(ctx, datasetID, updatedAt, schema))
+
+	cached, ok := inferenceCache.Get(ctx, datasetID, updatedAt, DefaultInferenceOptions())
+	require.True(t, ok)
+	assert.Equal(t, schema.Name, cached.Name)
+	assert.Equal(t, models.FieldTypeNumber, cached.Fields[0].DataType)
+
+	// New data bumps updated_at, which must not hit the old entry
+	_, ok = inferenceCache.Get(ctx, datasetID, updatedAt.Add(time.Second), DefaultInferenceOptions())
+	assert.False(t, ok)
+
+	// Different thresholds produce a different inference
+	_, ok = inferenceCache.Get(ctx, datasetID, updatedAt, InferenceOptions{TypeThreshold: 0.5, RequiredThreshold: 0.9})
+	assert.False(t, ok)
+}
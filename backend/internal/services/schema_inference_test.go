@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInferenceOptions(t *testing.T) {
+	opts, err := ParseInferenceOptions("", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultInferenceOptions(), opts)
+
+	opts, err = ParseInferenceOptions("0.5", "0.75", "0.6")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, opts.TypeThreshold)
+	assert.Equal(t, 0.75, opts.RequiredThreshold)
+	assert.Equal(t, 0.6, opts.ReviewThreshold)
+
+	_, err = ParseInferenceOptions("high", "", "")
+	assert.ErrorContains(t, err, "type_threshold")
+
+	_, err = ParseInferenceOptions("", "1.5", "")
+	assert.ErrorContains(t, err, "required_threshold")
+
+	_, err = ParseInferenceOptions("", "", "-0.1")
+	assert.ErrorContains(t, err, "review_threshold")
+}
+
+func TestSchemaInferenceService_InferSchemaWithOptions(t *testing.T) {
+	svc := NewSchemaInferenceService()
+	headers := []string{"amount", "note"}
+	// 7 of 10 amounts are numbers and 8 of 10 notes are filled in
+	rows := [][]string{
+		{"1", "a"}, {"2", "b"}, {"3", "c"}, {"4", "d"}, {"5", "e"},
+		{"6", "f"}, {"7", "g"}, {"n/a", "h"}, {"unknown", ""}, {"-", ""},
+	}
+
+	t.Run("defaults keep mixed column as optional string", func(t *testing.T) {
+		schema, err := svc.InferSchemaFromData(headers, rows, "sales")
+		require.NoError(t, err)
+		assert.Equal(t, models.FieldTypeString, schema.Fields[0].DataType)
+		assert.False(t, schema.Fields[1].IsRequired)
+		assert.Equal(t, DefaultInferenceOptions(), schema.Thresholds)
+	})
+
+	t.Run("lower thresholds promote type and required", func(t *testing.T) {
+		opts := InferenceOptions{TypeThreshold: 0.6, RequiredThreshold: 0.7}
+		schema, err := svc.InferSchemaWithOptions(headers, rows, "sales", opts)
+		require.NoError(t, err)
+		assert.Equal(t, models.FieldTypeNumber, schema.Fields[0].DataType)
+		assert.True(t, schema.Fields[1].IsRequired)
+		assert.Equal(t, opts, schema.Thresholds)
+	})
+}
+
+func TestSchemaInferenceService_FlagsFieldsForReview(t *testing.T) {
+	svc := NewSchemaInferenceService()
+	headers := []string{"id", "amount", "note"}
+	// ids are all numbers, 7 of 10 amounts are numbers and notes are free text
+	rows := [][]string{
+		{"1", "1", "a"}, {"2", "2", "b"}, {"3", "3", "c"}, {"4", "4", "d"}, {"5", "5", "e"},
+		{"6", "6", "f"}, {"7", "7", "g"}, {"8", "n/a", "h"}, {"9", "unknown", "i"}, {"10", "-", "j"},
+	}
+
+	t.Run("default threshold", func(t *testing.T) {
+		schema, err := svc.InferSchemaFromData(headers, rows, "sales")
+		require.NoError(t, err)
+		assert.False(t, schema.Fields[0].NeedsReview, "high confidence field is not flagged")
+		assert.False(t, schema.Fields[1].NeedsReview, "confidence at the threshold is not flagged")
+		assert.True(t, schema.Fields[2].NeedsReview, "low confidence field is flagged")
+	})
+
+	t.Run("stricter threshold flags more fields", func(t *testing.T) {
+		opts := DefaultInferenceOptions()
+		opts.ReviewThreshold = 0.8
+		schema, err := svc.InferSchemaWithOptions(headers, rows, "sales", opts)
+		require.NoError(t, err)
+		assert.False(t, schema.Fields[0].NeedsReview)
+		assert.True(t, schema.Fields[1].NeedsReview)
+		assert.True(t, schema.Fields[2].NeedsReview)
+	})
+
+	t.Run("zero threshold flags nothing", func(t *testing.T) {
+		opts := DefaultInferenceOptions()
+		opts.ReviewThreshold = 0
+		schema, err := svc.InferSchemaWithOptions(headers, rows, "sales", opts)
+		require.NoError(t, err)
+		for _, field := range schema.Fields {
+			assert.False(t, field.NeedsReview, field.Name)
+		}
+	})
+}
+
+func TestSchemaInferenceService_DetectsEnums(t *testing.T) {
+	svc := NewSchemaInferenceService()
+	headers := []string{"status", "name"}
+	rows := make([][]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		status := "active"
+		if i%3 == 0 {
+			status = "inactive"
+		}
+		rows = append(rows, []string{status, string(rune('a'+i)) + "-user"})
+	}
+
+	schema, err := svc.InferSchemaFromData(headers, rows, "users")
+	require.NoError(t, err)
+
+	status := schema.Fields[0]
+	assert.Equal(t, []string{"active", "inactive"}, status.Options)
+	assert.InDelta(t, 1-2.0/12, status.OptionsConfidence, 1e-9)
+
+	name := schema.Fields[1]
+	assert.Empty(t, name.Options, "free text column is not an enum")
+
+	t.Run("too few values", func(t *testing.T) {
+		schema, err := svc.InferSchemaFromData(headers, rows[:5], "users")
+		require.NoError(t, err)
+		assert.Empty(t, schema.Fields[0].Options)
+	})
+}
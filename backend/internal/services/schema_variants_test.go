@@ -0,0 +1,58 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+func TestValidationService_SchemaVariants(t *testing.T) {
+	defaultSchema := &models.DatasetSchema{
+		Name:      "v1",
+		IsDefault: true,
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "integer", IsRequired: true},
+			{Name: "name", DataType: "string", IsRequired: true},
+		},
+	}
+	variant := &models.DatasetSchema{
+		Name: "v2",
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "integer", IsRequired: true},
+			{Name: "email", DataType: "email", IsRequired: true},
+		},
+	}
+	repo := &fakeSchemaRepository{
+		schema:   defaultSchema,
+		variants: map[string]*models.DatasetSchema{"v1": defaultSchema, "v2": variant},
+	}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+
+	path := filepath.Join(t.TempDir(), "rows.csv")
+	require.NoError(t, os.WriteFile(path, []byte("id,email\n1,a@example.com\n"), 0644))
+
+	t.Run("default schema rejects variant columns", func(t *testing.T) {
+		result, _, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.SchemaErrors)
+	})
+
+	t.Run("selected variant accepts its columns", func(t *testing.T) {
+		result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "v2", nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.SchemaErrors)
+		assert.Equal(t, 1, result.ValidRows)
+		assert.Len(t, staging, 1)
+	})
+
+	t.Run("unknown variant is an error", func(t *testing.T) {
+		_, _, err := svc.ValidateDataSubmission(path, uuid.New(), "v3", nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// SchemaVersionDiff is the machine-readable difference between two versions of a schema. Breaking
+// is set when data valid under the old version may be rejected by, or lose fields in, the new one.
+type SchemaVersionDiff struct {
+	SchemaID          uuid.UUID          `json:"schema_id"`
+	FromVersion       int                `json:"from_version"`
+	ToVersion         int                `json:"to_version"`
+	Added             []AddedField       `json:"added"`
+	Removed           []RemovedField     `json:"removed"`
+	Renamed           []RenamedField     `json:"renamed"`
+	TypeChanged       []FieldTypeChange  `json:"type_changed"`
+	ConstraintChanged []ConstraintChange `json:"constraint_changed"`
+	Breaking          bool               `json:"breaking"`
+	BreakingChanges   []string           `json:"breaking_changes"`
+}
+
+// AddedField is a field present only in the newer version
+type AddedField struct {
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	IsRequired bool   `json:"is_required"`
+}
+
+// RemovedField is a field present only in the older version
+type RemovedField struct {
+	Name     string `json:"name"`
+	DataType string `json:"data_type"`
+}
+
+// RenamedField is a field kept under a new name
+type RenamedField struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// FieldTypeChange is a field whose data type changed; Name is the field's name in the newer version
+type FieldTypeChange struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ConstraintChange is a change to one constraint of a field: is_required, is_unique, default_value
+// or a validation rule such as max_length. A nil From or To means the constraint was unset.
+type ConstraintChange struct {
+	Name       string      `json:"name"`
+	Constraint string      `json:"constraint"`
+	From       interface{} `json:"from"`
+	To         interface{} `json:"to"`
+}
+
+// DiffSchemaVersions compares two versions of a schema. Fields are matched by ID, so a field keeping
+// its ID under a new name is reported as renamed, then by name for fields whose IDs changed.
+func DiffSchemaVersions(from, to *models.SchemaVersion) SchemaVersionDiff {
+	diff := SchemaVersionDiff{
+		SchemaID:          to.SchemaID,
+		FromVersion:       from.Version,
+		ToVersion:         to.Version,
+		Added:             []AddedField{},
+		Removed:           []RemovedField{},
+		Renamed:           []RenamedField{},
+		TypeChanged:       []FieldTypeChange{},
+		ConstraintChanged: []ConstraintChange{},
+		BreakingChanges:   []string{},
+	}
+
+	before := sortedFields(from.Fields)
+	after := sortedFields(to.Fields)
+
+	// Pair fields by ID first, then by name among the fields left over
+	matched := make(map[int]int) // index in after -> index in before
+	usedBefore := make(map[int]bool)
+	beforeByID := make(map[uuid.UUID]int)
+	for i, field := range before {
+		if field.ID != uuid.Nil {
+			beforeByID[field.ID] = i
+		}
+	}
+	for j, field := range after {
+		if i, ok := beforeByID[field.ID]; ok && field.ID != uuid.Nil {
+			matched[j] = i
+			usedBefore[i] = true
+		}
+	}
+	beforeByName := make(map[string]int)
+	for i, field := range before {
+		if !usedBefore[i] {
+			beforeByName[field.Name] = i
+		}
+	}
+	for j, field := range after {
+		if _, ok := matched[j]; ok {
+			continue
+		}
+		if i, ok := beforeByName[field.Name]; ok {
+			matched[j] = i
+			usedBefore[i] = true
+		}
+	}
+
+	for j, field := range after {
+		i, ok := matched[j]
+		if !ok {
+			diff.Added = append(diff.Added, AddedField{Name: field.Name, DataType: field.DataType, IsRequired: field.IsRequired})
+			if field.IsRequired && field.DefaultValue == nil {
+				diff.addBreaking("field '%s' was added as required without a default", field.Name)
+			}
+			continue
+		}
+
+		old := before[i]
+		if old.Name != field.Name {
+			diff.Renamed = append(diff.Renamed, RenamedField{From: old.Name, To: field.Name})
+			diff.addBreaking("field '%s' was renamed to '%s'", old.Name, field.Name)
+		}
+		if old.DataType != field.DataType {
+			diff.TypeChanged = append(diff.TypeChanged, FieldTypeChange{Name: field.Name, From: old.DataType, To: field.DataType})
+			diff.addBreaking("field '%s' changed type from %s to %s", field.Name, old.DataType, field.DataType)
+		}
+		for _, change := range constraintChanges(old, field) {
+			diff.ConstraintChanged = append(diff.ConstraintChanged, change)
+			if change.To == true && (change.Constraint == "is_required" || change.Constraint == "is_unique") {
+				diff.addBreaking("field '%s' became %s", field.Name, change.Constraint)
+			}
+		}
+	}
+
+	for i, field := range before {
+		if !usedBefore[i] {
+			diff.Removed = append(diff.Removed, RemovedField{Name: field.Name, DataType: field.DataType})
+			diff.addBreaking("field '%s' was removed", field.Name)
+		}
+	}
+
+	return diff
+}
+
+func (d *SchemaVersionDiff) addBreaking(format string, args ...interface{}) {
+	d.Breaking = true
+	d.BreakingChanges = append(d.BreakingChanges, fmt.Sprintf(format, args...))
+}
+
+// sortedFields returns fields in position order
+func sortedFields(fields []models.SchemaField) []models.SchemaField {
+	sorted := append([]models.SchemaField(nil), fields...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+	return sorted
+}
+
+// constraintChanges lists the constraints that differ between two versions of a field, with
+// validation rules reported individually by their JSON name
+func constraintChanges(before, after models.SchemaField) []ConstraintChange {
+	var changes []ConstraintChange
+	if before.IsRequired != after.IsRequired {
+		changes = append(changes, ConstraintChange{Name: after.Name, Constraint: "is_required", From: before.IsRequired, To: after.IsRequired})
+	}
+	if before.IsUnique != after.IsUnique {
+		changes = append(changes, ConstraintChange{Name: after.Name, Constraint: "is_unique", From: before.IsUnique, To: after.IsUnique})
+	}
+	if !reflect.DeepEqual(before.DefaultValue, after.DefaultValue) {
+		changes = append(changes, ConstraintChange{Name: after.Name, Constraint: "default_value", From: stringOrNil(before.DefaultValue), To: stringOrNil(after.DefaultValue)})
+	}
+
+	oldRules, newRules := validationRules(before.Validation), validationRules(after.Validation)
+	names := make([]string, 0, len(oldRules)+len(newRules))
+	for name := range oldRules {
+		names = append(names, name)
+	}
+	for name := range newRules {
+		if _, ok := oldRules[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !reflect.DeepEqual(oldRules[name], newRules[name]) {
+			changes = append(changes, ConstraintChange{Name: after.Name, Constraint: name, From: oldRules[name], To: newRules[name]})
+		}
+	}
+	return changes
+}
+
+// validationRules returns the set validation rules of a field keyed by JSON name
+func validationRules(validation models.FieldValidation) map[string]interface{} {
+	rules := make(map[string]interface{})
+	encoded, err := json.Marshal(validation)
+	if err != nil {
+		return rules
+	}
+	_ = json.Unmarshal(encoded, &rules)
+	return rules
+}
+
+func stringOrNil(value *string) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
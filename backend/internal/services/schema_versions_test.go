@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func schemaVersion(number int, fields ...models.SchemaField) *models.SchemaVersion {
+	return &models.SchemaVersion{Version: number, Fields: fields}
+}
+
+func TestDiffSchemaVersions_TypeChange(t *testing.T) {
+	ageID := uuid.New()
+	from := schemaVersion(1,
+		models.SchemaField{ID: uuid.New(), Name: "name", DataType: "string", Position: 1},
+		models.SchemaField{ID: ageID, Name: "age", DataType: "string", Position: 2},
+	)
+	to := schemaVersion(2,
+		models.SchemaField{ID: uuid.New(), Name: "name", DataType: "string", Position: 1},
+		models.SchemaField{ID: ageID, Name: "age", DataType: "integer", Position: 2},
+	)
+
+	diff := DiffSchemaVersions(from, to)
+	assert.Equal(t, 1, diff.FromVersion)
+	assert.Equal(t, 2, diff.ToVersion)
+	assert.Equal(t, []FieldTypeChange{{Name: "age", From: "string", To: "integer"}}, diff.TypeChanged)
+	assert.Empty(t, diff.Added, "fields with new IDs are still matched by name")
+	assert.Empty(t, diff.Removed)
+	assert.Empty(t, diff.Renamed)
+	assert.True(t, diff.Breaking)
+	assert.Equal(t, []string{"field 'age' changed type from string to integer"}, diff.BreakingChanges)
+}
+
+func TestDiffSchemaVersions_FieldRemoval(t *testing.T) {
+	nameID := uuid.New()
+	from := schemaVersion(3,
+		models.SchemaField{ID: nameID, Name: "name", DataType: "string", Position: 1},
+		models.SchemaField{ID: uuid.New(), Name: "fax", DataType: "phone", Position: 2},
+	)
+	to := schemaVersion(4, models.SchemaField{ID: nameID, Name: "name", DataType: "string", Position: 1})
+
+	diff := DiffSchemaVersions(from, to)
+	assert.Equal(t, []RemovedField{{Name: "fax", DataType: "phone"}}, diff.Removed)
+	assert.Empty(t, diff.TypeChanged)
+	assert.True(t, diff.Breaking)
+	assert.Equal(t, []string{"field 'fax' was removed"}, diff.BreakingChanges)
+}
+
+func TestDiffSchemaVersions_RenamesAndConstraints(t *testing.T) {
+	emailID, notesID := uuid.New(), uuid.New()
+	maxLength, longer := 50, 200
+	defaultNote := "none"
+	from := schemaVersion(1,
+		models.SchemaField{ID: emailID, Name: "mail", DataType: "email", Position: 1},
+		models.SchemaField{ID: notesID, Name: "notes", DataType: "string", Position: 2, Validation: models.FieldValidation{MaxLength: &maxLength}},
+	)
+	to := schemaVersion(2,
+		models.SchemaField{ID: emailID, Name: "email", DataType: "email", IsUnique: true, Position: 1},
+		models.SchemaField{ID: notesID, Name: "notes", DataType: "string", Position: 2, DefaultValue: &defaultNote, Validation: models.FieldValidation{MaxLength: &longer}},
+		models.SchemaField{ID: uuid.New(), Name: "tags", DataType: "array", Position: 3},
+	)
+
+	diff := DiffSchemaVersions(from, to)
+	assert.Equal(t, []RenamedField{{From: "mail", To: "email"}}, diff.Renamed)
+	assert.Equal(t, []AddedField{{Name: "tags", DataType: "array"}}, diff.Added)
+	assert.Equal(t, []ConstraintChange{
+		{Name: "email", Constraint: "is_unique", From: false, To: true},
+		{Name: "notes", Constraint: "default_value", From: nil, To: "none"},
+		{Name: "notes", Constraint: "max_length", From: float64(50), To: float64(200)},
+	}, diff.ConstraintChanged)
+	assert.Equal(t, []string{"field 'mail' was renamed to 'email'", "field 'email' became is_unique"}, diff.BreakingChanges)
+
+	unchanged := DiffSchemaVersions(to, to)
+	assert.False(t, unchanged.Breaking)
+	assert.Empty(t, unchanged.ConstraintChanged)
+}
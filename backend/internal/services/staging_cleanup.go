@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+)
+
+// StagingCleanupRepositoryInterface is the submission storage used to purge abandoned staging data
+type StagingCleanupRepositoryInterface interface {
+	ListExpiredPendingSubmissions(before time.Time) ([]*models.DataSubmission, error)
+	ExpirePendingSubmission(id uuid.UUID, remove bool) (bool, int64, error)
+}
+
+// StagingCleanupResult summarizes a cleanup pass
+type StagingCleanupResult struct {
+	ExpiredSubmissions int
+	DeletedStagingRows int64
+}
+
+// StagingCleanupService purges staging data of submissions left pending beyond a TTL
+type StagingCleanupService struct {
+	repo              StagingCleanupRepositoryInterface
+	files             storage.Storage
+	ttl               time.Duration
+	deleteSubmissions bool
+	now               func() time.Time
+}
+
+// NewStagingCleanupService creates a cleanup service. Expired submissions are marked expired,
+// or deleted along with their uploaded file when deleteSubmissions is set.
+func NewStagingCleanupService(repo StagingCleanupRepositoryInterface, files storage.Storage, ttl time.Duration, deleteSubmissions bool) *StagingCleanupService {
+	return &StagingCleanupService{
+		repo:              repo,
+		files:             files,
+		ttl:               ttl,
+		deleteSubmissions: deleteSubmissions,
+		now:               time.Now,
+	}
+}
+
+// Cleanup purges staging rows for every pending submission older than the TTL
+func (s *StagingCleanupService) Cleanup() (*StagingCleanupResult, error) {
+	result := &StagingCleanupResult{}
+	if s.ttl <= 0 {
+		return result, nil
+	}
+
+	submissions, err := s.repo.ListExpiredPendingSubmissions(s.now().Add(-s.ttl))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired submissions: %w", err)
+	}
+
+	for _, submission := range submissions {
+		// Submissions reviewed since they were listed are skipped and keep their staged rows
+		expired, deleted, err := s.repo.ExpirePendingSubmission(submission.ID, s.deleteSubmissions)
+		if err != nil {
+			return result, fmt.Errorf("failed to expire submission %s: %w", submission.ID, err)
+		}
+		if !expired {
+			continue
+		}
+		result.DeletedStagingRows += deleted
+
+		if s.deleteSubmissions && submission.FilePath != "" {
+			if err := s.files.Delete(context.Background(), submission.FilePath); err != nil {
+				log.Printf("Warning: failed to remove file for expired submission %s: %v", submission.ID, err)
+			}
+		}
+
+		result.ExpiredSubmissions++
+	}
+
+	return result, nil
+}
+
+// Run performs a cleanup every interval until the context is cancelled
+func (s *StagingCleanupService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.Cleanup()
+		if err != nil {
+			log.Printf("Error cleaning up staging data: %v", err)
+		} else if result.ExpiredSubmissions > 0 {
+			log.Printf("Expired %d pending submissions, removed %d staging rows", result.ExpiredSubmissions, result.DeletedStagingRows)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
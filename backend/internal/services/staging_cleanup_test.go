@@ -0,0 +1,124 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStagingStore keeps submissions and their staged rows in memory
+type fakeStagingStore struct {
+	submissions map[uuid.UUID]*models.DataSubmission
+	staging     map[uuid.UUID]int
+	// beforeExpire runs at the start of each expiry, standing in for a concurrent review
+	beforeExpire func(id uuid.UUID)
+}
+
+func newFakeStagingStore(submissions ...*models.DataSubmission) *fakeStagingStore {
+	store := &fakeStagingStore{
+		submissions: make(map[uuid.UUID]*models.DataSubmission),
+		staging:     make(map[uuid.UUID]int),
+	}
+	for _, submission := range submissions {
+		store.submissions[submission.ID] = submission
+		store.staging[submission.ID] = 5
+	}
+	return store
+}
+
+func (f *fakeStagingStore) ListExpiredPendingSubmissions(before time.Time) ([]*models.DataSubmission, error) {
+	var expired []*models.DataSubmission
+	for _, submission := range f.submissions {
+		if submission.Status == models.DataSubmissionStatusPending && submission.SubmittedAt.Before(before) {
+			expired = append(expired, submission)
+		}
+	}
+	return expired, nil
+}
+
+func (f *fakeStagingStore) ExpirePendingSubmission(id uuid.UUID, remove bool) (bool, int64, error) {
+	if f.beforeExpire != nil {
+		f.beforeExpire(id)
+	}
+	submission := f.submissions[id]
+	if submission == nil || submission.Status != models.DataSubmissionStatusPending {
+		return false, 0, nil
+	}
+	submission.Status = models.DataSubmissionStatusExpired
+	deleted := f.staging[id]
+	delete(f.staging, id)
+	if remove {
+		delete(f.submissions, id)
+	}
+	return true, int64(deleted), nil
+}
+
+func TestStagingCleanupService_Cleanup(t *testing.T) {
+	now := time.Now()
+	expired := &models.DataSubmission{ID: uuid.New(), Status: models.DataSubmissionStatusPending, SubmittedAt: now.Add(-48 * time.Hour)}
+	recent := &models.DataSubmission{ID: uuid.New(), Status: models.DataSubmissionStatusPending, SubmittedAt: now.Add(-time.Hour)}
+	reviewed := &models.DataSubmission{ID: uuid.New(), Status: models.DataSubmissionStatusApproved, SubmittedAt: now.Add(-48 * time.Hour)}
+
+	t.Run("purges staging rows of expired pending submissions", func(t *testing.T) {
+		store := newFakeStagingStore(expired, recent, reviewed)
+		svc := NewStagingCleanupService(store, storage.NewLocalStorage(t.TempDir()), 24*time.Hour, false)
+		svc.now = func() time.Time { return now }
+
+		result, err := svc.Cleanup()
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.ExpiredSubmissions)
+		assert.Equal(t, int64(5), result.DeletedStagingRows)
+
+		assert.NotContains(t, store.staging, expired.ID)
+		assert.Equal(t, models.DataSubmissionStatusExpired, store.submissions[expired.ID].Status)
+		assert.Contains(t, store.staging, recent.ID)
+		assert.Contains(t, store.staging, reviewed.ID)
+	})
+
+	t.Run("optionally deletes the submission", func(t *testing.T) {
+		pending := *expired
+		pending.Status = models.DataSubmissionStatusPending
+		store := newFakeStagingStore(&pending)
+		svc := NewStagingCleanupService(store, storage.NewLocalStorage(t.TempDir()), 24*time.Hour, true)
+		svc.now = func() time.Time { return now }
+
+		_, err := svc.Cleanup()
+		require.NoError(t, err)
+		assert.Empty(t, store.submissions)
+		assert.Empty(t, store.staging)
+	})
+
+	t.Run("submission reviewed after listing keeps its staging rows", func(t *testing.T) {
+		pending := *expired
+		pending.Status = models.DataSubmissionStatusPending
+		store := newFakeStagingStore(&pending)
+		store.beforeExpire = func(id uuid.UUID) {
+			store.submissions[id].Status = models.DataSubmissionStatusApproved
+		}
+		svc := NewStagingCleanupService(store, storage.NewLocalStorage(t.TempDir()), 24*time.Hour, true)
+		svc.now = func() time.Time { return now }
+
+		result, err := svc.Cleanup()
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ExpiredSubmissions)
+		assert.Equal(t, int64(0), result.DeletedStagingRows)
+		assert.Contains(t, store.staging, pending.ID)
+		assert.Equal(t, models.DataSubmissionStatusApproved, store.submissions[pending.ID].Status)
+	})
+
+	t.Run("disabled without ttl", func(t *testing.T) {
+		pending := *expired
+		pending.Status = models.DataSubmissionStatusPending
+		store := newFakeStagingStore(&pending)
+
+		result, err := NewStagingCleanupService(store, storage.NewLocalStorage(t.TempDir()), 0, false).Cleanup()
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.ExpiredSubmissions)
+		assert.Contains(t, store.staging, pending.ID)
+	})
+}
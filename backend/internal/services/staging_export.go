@@ -0,0 +1,129 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// Columns added around the data fields of exported staging rows
+const (
+	StagingExportRowIndexColumn = "row_index"
+	StagingExportStatusColumn   = "validation_status"
+	StagingExportErrorsColumn   = "validation_errors"
+)
+
+// StagingCSVExporter writes staged submission rows as CSV, one batch at a time, with the
+// row index first and the validation status and error messages after the data columns
+type StagingCSVExporter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+// NewStagingCSVExporter writes the header for the given data columns and returns the exporter
+func NewStagingCSVExporter(w io.Writer, columns []string) (*StagingCSVExporter, error) {
+	e := &StagingCSVExporter{w: csv.NewWriter(w), columns: columns}
+
+	header := make([]string, 0, len(columns)+3)
+	header = append(header, StagingExportRowIndexColumn)
+	header = append(header, columns...)
+	header = append(header, StagingExportStatusColumn, StagingExportErrorsColumn)
+	if err := e.w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
+	}
+	return e, nil
+}
+
+// StagingExportColumns returns the data columns to export: the schema's fields when known,
+// otherwise the sorted keys found in the staged rows
+func StagingExportColumns(schema *models.DatasetSchema, rows []*models.DataSubmissionStaging) []string {
+	if schema != nil && len(schema.Fields) > 0 {
+		columns := make([]string, len(schema.Fields))
+		for i, field := range schema.Fields {
+			columns[i] = field.Name
+		}
+		return columns
+	}
+
+	seen := make(map[string]bool)
+	var columns []string
+	for _, row := range rows {
+		var rowData map[string]interface{}
+		if err := json.Unmarshal(row.Data, &rowData); err != nil {
+			continue
+		}
+		for name := range rowData {
+			if !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// WriteRows writes a batch of staged rows and flushes them to the underlying writer
+func (e *StagingCSVExporter) WriteRows(rows []*models.DataSubmissionStaging) error {
+	for _, row := range rows {
+		var rowData map[string]interface{}
+		if err := json.Unmarshal(row.Data, &rowData); err != nil {
+			return fmt.Errorf("failed to decode staged row %d: %w", row.RowIndex, err)
+		}
+
+		record := make([]string, 0, len(e.columns)+3)
+		record = append(record, fmt.Sprintf("%d", row.RowIndex))
+		for _, column := range e.columns {
+			record = append(record, exportCellValue(rowData[column]))
+		}
+		record = append(record, row.ValidationStatus, stagingErrorMessages(row.ValidationErrors))
+
+		if err := e.w.Write(record); err != nil {
+			return fmt.Errorf("failed to write staged row %d: %w", row.RowIndex, err)
+		}
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// exportCellValue renders a stored value as a CSV cell, keeping nested values as JSON
+func exportCellValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// stagingErrorMessages joins the messages of a staged row's validation errors
+func stagingErrorMessages(raw *json.RawMessage) string {
+	if raw == nil {
+		return ""
+	}
+
+	var validationErrors []models.DataValidationError
+	if err := json.Unmarshal(*raw, &validationErrors); err != nil {
+		return string(*raw)
+	}
+
+	messages := make([]string, len(validationErrors))
+	for i, validationErr := range validationErrors {
+		messages[i] = validationErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStagingCSVExporter(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "name", DataType: "string"},
+			{Name: "age", DataType: "number"},
+			{Name: "tags", DataType: "array"},
+		},
+	}
+	staging := stagedRows(t,
+		map[string]interface{}{"name": "Ann", "age": 31, "tags": []string{"a", "b"}},
+		map[string]interface{}{"name": "Bob", "age": "old"},
+	)
+	errorsJSON, err := json.Marshal([]models.DataValidationError{
+		{RowIndex: 1, FieldName: "age", Message: "Field 'age' must be a number"},
+		{RowIndex: 1, FieldName: "tags", Message: "Field 'tags' is required"},
+	})
+	require.NoError(t, err)
+	raw := json.RawMessage(errorsJSON)
+	staging[1].ValidationStatus = models.ValidationStatusInvalid
+	staging[1].ValidationErrors = &raw
+
+	var buf bytes.Buffer
+	exporter, err := NewStagingCSVExporter(&buf, StagingExportColumns(schema, staging))
+	require.NoError(t, err)
+	// Export in two batches as the handler does
+	require.NoError(t, exporter.WriteRows(staging[:1]))
+	require.NoError(t, exporter.WriteRows(staging[1:]))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"row_index", "name", "age", "tags", "validation_status", "validation_errors"}, records[0])
+	assert.Equal(t, []string{"0", "Ann", "31", `["a","b"]`, models.ValidationStatusValid, ""}, records[1])
+	assert.Equal(t, []string{"1", "Bob", "old", "", models.ValidationStatusInvalid,
+		"Field 'age' must be a number; Field 'tags' is required"}, records[2])
+}
+
+func TestStagingExportColumns_WithoutSchema(t *testing.T) {
+	staging := stagedRows(t,
+		map[string]interface{}{"b": 1, "a": 2},
+		map[string]interface{}{"c": 3},
+	)
+	assert.Equal(t, []string{"a", "b", "c"}, StagingExportColumns(nil, staging))
+}
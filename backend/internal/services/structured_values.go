@@ -0,0 +1,48 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ParseStructuredValue returns value as a native JSON object or array matching dataType.
+// Native maps and slices are accepted as-is and strings are decoded as JSON.
+func ParseStructuredValue(value interface{}, dataType string) (interface{}, bool) {
+	decoded := value
+	if str, ok := value.(string); ok {
+		if err := json.Unmarshal([]byte(strings.TrimSpace(str)), &decoded); err != nil {
+			return nil, false
+		}
+	}
+
+	switch dataType {
+	case string(models.FieldTypeObject):
+		obj, ok := decoded.(map[string]interface{})
+		return obj, ok
+	case string(models.FieldTypeArray):
+		arr, ok := decoded.([]interface{})
+		return arr, ok
+	}
+	return nil, false
+}
+
+// DecodeStructuredFields replaces JSON text in object and array fields with the decoded value.
+// Values that are not valid JSON of the declared shape are left untouched for validation to report.
+func DecodeStructuredFields(rowData map[string]interface{}, schema *models.DatasetSchema) {
+	for _, field := range schema.Fields {
+		if field.DataType != string(models.FieldTypeObject) && field.DataType != string(models.FieldTypeArray) {
+			continue
+		}
+
+		value, exists := rowData[field.Name]
+		if !exists || value == nil || value == "" {
+			continue
+		}
+
+		if decoded, ok := ParseStructuredValue(value, field.DataType); ok {
+			rowData[field.Name] = decoded
+		}
+	}
+}
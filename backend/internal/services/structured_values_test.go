@@ -0,0 +1,86 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructuredValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		dataType string
+		wantOK   bool
+	}{
+		{name: "object text", value: `{"city":"Pune"}`, dataType: "object", wantOK: true},
+		{name: "native object", value: map[string]interface{}{"city": "Pune"}, dataType: "object", wantOK: true},
+		{name: "array text", value: `[1, 2, 3]`, dataType: "array", wantOK: true},
+		{name: "native array", value: []interface{}{"a", "b"}, dataType: "array", wantOK: true},
+		{name: "array is not object", value: `[1]`, dataType: "object", wantOK: false},
+		{name: "object is not array", value: `{"a":1}`, dataType: "array", wantOK: false},
+		{name: "invalid json", value: `{city:`, dataType: "object", wantOK: false},
+		{name: "scalar", value: "42", dataType: "array", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := ParseStructuredValue(tt.value, tt.dataType)
+			assert.Equal(t, tt.wantOK, ok)
+		})
+	}
+}
+
+func TestValidationService_StructuredFields(t *testing.T) {
+	schema := &models.DatasetSchema{
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "string"},
+			{Name: "address", DataType: "object"},
+			{Name: "tags", DataType: "array"},
+		},
+	}
+	svc := NewValidationService(&fakeSchemaRepository{schema: schema}, &fakeSubmissionRepository{})
+
+	t.Run("staging preserves nested values", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "rows.csv")
+		content := "id,address,tags\n" +
+			`1,"{""city"":""Pune"",""zip"":411001}","[""a"",""b""]"` + "\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		result, staging, err := svc.ValidateDataSubmission(path, uuid.New(), "", nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.SchemaErrors)
+		require.Len(t, staging, 1)
+
+		var row map[string]interface{}
+		require.NoError(t, json.Unmarshal(staging[0].Data, &row))
+		assert.Equal(t, map[string]interface{}{"city": "Pune", "zip": float64(411001)}, row["address"])
+		assert.Equal(t, []interface{}{"a", "b"}, row["tags"])
+	})
+
+	t.Run("wrong shape is rejected", func(t *testing.T) {
+		result := svc.validateRowAgainstSchema(map[string]interface{}{"address": `["not","an","object"]`, "tags": `oops`}, schema, 0)
+		require.Len(t, result.Errors, 2)
+		for _, validationErr := range result.Errors {
+			assert.Equal(t, "invalid_data_type", validationErr.ErrorType)
+		}
+	})
+
+	t.Run("direct append keeps native values", func(t *testing.T) {
+		row := map[string]interface{}{
+			"id":      "1",
+			"address": map[string]interface{}{"city": "Pune"},
+			"tags":    []interface{}{"x"},
+		}
+		coerced, errs := CoerceRowToSchema(row, schema, 0)
+		assert.Empty(t, errs)
+		assert.Equal(t, map[string]interface{}{"city": "Pune"}, coerced["address"])
+		assert.Equal(t, []interface{}{"x"}, coerced["tags"])
+	})
+}
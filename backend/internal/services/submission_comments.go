@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+var (
+	// ErrSubmissionCommentDenied is returned when a user is neither the submitter nor a reviewer
+	ErrSubmissionCommentDenied = errors.New("not allowed to access submission comments")
+	// ErrEmptySubmissionComment is returned when a comment has no text
+	ErrEmptySubmissionComment = errors.New("comment must not be empty")
+)
+
+// SubmissionCommentRepositoryInterface is the subset of submission storage needed for review threads
+type SubmissionCommentRepositoryInterface interface {
+	GetSubmission(id uuid.UUID) (*models.DataSubmission, error)
+	IsUserAdmin(userID uuid.UUID) (bool, error)
+	CreateSubmissionComment(comment *models.SubmissionComment) error
+	ListSubmissionComments(submissionID uuid.UUID) ([]models.SubmissionCommentWithAuthor, error)
+}
+
+// SubmissionCommentService keeps the review thread of a submission, letting a reviewer ask for a
+// fix and the submitter reply
+type SubmissionCommentService struct {
+	repo SubmissionCommentRepositoryInterface
+}
+
+// NewSubmissionCommentService creates a new submission comment service
+func NewSubmissionCommentService(repo SubmissionCommentRepositoryInterface) *SubmissionCommentService {
+	return &SubmissionCommentService{repo: repo}
+}
+
+// authorize loads the submission and checks the user is its submitter or a reviewer (an admin)
+func (s *SubmissionCommentService) authorize(submissionID, userID uuid.UUID) error {
+	submission, err := s.repo.GetSubmission(submissionID)
+	if err != nil {
+		return err
+	}
+
+	if submission.SubmittedBy == userID {
+		return nil
+	}
+	isAdmin, err := s.repo.IsUserAdmin(userID)
+	if err != nil {
+		return fmt.Errorf("failed to verify admin status: %w", err)
+	}
+	if !isAdmin {
+		return ErrSubmissionCommentDenied
+	}
+	return nil
+}
+
+// AddComment posts a comment on a submission as the user
+func (s *SubmissionCommentService) AddComment(submissionID, userID uuid.UUID, body string) (*models.SubmissionComment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrEmptySubmissionComment
+	}
+
+	if err := s.authorize(submissionID, userID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.SubmissionComment{
+		ID:           uuid.New(),
+		SubmissionID: submissionID,
+		UserID:       userID,
+		Body:         body,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.CreateSubmissionComment(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// ListComments returns a submission's review thread, oldest first
+func (s *SubmissionCommentService) ListComments(submissionID, userID uuid.UUID) ([]models.SubmissionCommentWithAuthor, error) {
+	if err := s.authorize(submissionID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSubmissionComments(submissionID)
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCommentStore keeps submissions and their comments in memory
+type fakeCommentStore struct {
+	submissions map[uuid.UUID]*models.DataSubmission
+	admins      map[uuid.UUID]bool
+	comments    []models.SubmissionComment
+}
+
+func (f *fakeCommentStore) GetSubmission(id uuid.UUID) (*models.DataSubmission, error) {
+	submission, ok := f.submissions[id]
+	if !ok {
+		return nil, repository.ErrSubmissionNotFound
+	}
+	return submission, nil
+}
+
+func (f *fakeCommentStore) IsUserAdmin(userID uuid.UUID) (bool, error) {
+	return f.admins[userID], nil
+}
+
+func (f *fakeCommentStore) CreateSubmissionComment(comment *models.SubmissionComment) error {
+	f.comments = append(f.comments, *comment)
+	return nil
+}
+
+func (f *fakeCommentStore) ListSubmissionComments(submissionID uuid.UUID) ([]models.SubmissionCommentWithAuthor, error) {
+	thread := []models.SubmissionCommentWithAuthor{}
+	for _, comment := range f.comments {
+		if comment.SubmissionID == submissionID {
+			thread = append(thread, models.SubmissionCommentWithAuthor{SubmissionComment: comment})
+		}
+	}
+	return thread, nil
+}
+
+func TestSubmissionCommentService(t *testing.T) {
+	submitter := uuid.New()
+	reviewer := uuid.New()
+	stranger := uuid.New()
+	submission := &models.DataSubmission{ID: uuid.New(), SubmittedBy: submitter, Status: models.DataSubmissionStatusUnderReview}
+
+	store := &fakeCommentStore{
+		submissions: map[uuid.UUID]*models.DataSubmission{submission.ID: submission},
+		admins:      map[uuid.UUID]bool{reviewer: true},
+	}
+	svc := NewSubmissionCommentService(store)
+
+	_, err := svc.AddComment(submission.ID, reviewer, "Row 12 has a bad date, please fix it")
+	require.NoError(t, err)
+	reply, err := svc.AddComment(submission.ID, submitter, "  Fixed, thanks!  ")
+	require.NoError(t, err)
+	assert.Equal(t, "Fixed, thanks!", reply.Body, "comments are trimmed")
+
+	thread, err := svc.ListComments(submission.ID, submitter)
+	require.NoError(t, err)
+	require.Len(t, thread, 2)
+	assert.Equal(t, reviewer, thread[0].UserID)
+	assert.Equal(t, submitter, thread[1].UserID)
+
+	t.Run("others can't read or post", func(t *testing.T) {
+		_, err := svc.ListComments(submission.ID, stranger)
+		assert.ErrorIs(t, err, ErrSubmissionCommentDenied)
+		_, err = svc.AddComment(submission.ID, stranger, "hello")
+		assert.ErrorIs(t, err, ErrSubmissionCommentDenied)
+	})
+
+	t.Run("empty comments are rejected", func(t *testing.T) {
+		_, err := svc.AddComment(submission.ID, submitter, "   ")
+		assert.ErrorIs(t, err, ErrEmptySubmissionComment)
+	})
+
+	t.Run("unknown submission", func(t *testing.T) {
+		_, err := svc.ListComments(uuid.New(), reviewer)
+		assert.ErrorIs(t, err, repository.ErrSubmissionNotFound)
+	})
+
+	assert.Len(t, store.comments, 2)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// SubmissionDedupStore looks up recent submissions of an identical file and creates submissions,
+// atomically skipping a duplicate
+type SubmissionDedupStore interface {
+	FindRecentSubmission(datasetID, userID uuid.UUID, fileHash string, since time.Time) (*models.DataSubmission, error)
+	CreateSubmission(submission *models.DataSubmission) error
+	CreateSubmissionUnlessRecent(submission *models.DataSubmission, since time.Time, isDuplicate func(*models.DataSubmission) bool) (*models.DataSubmission, error)
+}
+
+// SubmissionDeduplicator detects repeated submissions of the same file by the same user within a window,
+// such as those caused by double-clicking submit
+type SubmissionDeduplicator struct {
+	store  SubmissionDedupStore
+	window time.Duration
+	now    func() time.Time
+}
+
+// NewSubmissionDeduplicator creates a new deduplicator; a non-positive window disables deduplication
+func NewSubmissionDeduplicator(store SubmissionDedupStore, window time.Duration) *SubmissionDeduplicator {
+	return &SubmissionDeduplicator{
+		store:  store,
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// Enabled reports whether deduplication is active
+func (d *SubmissionDeduplicator) Enabled() bool {
+	return d != nil && d.window > 0
+}
+
+// isDuplicate reports whether an earlier submission of the same file also used the same mode and
+// column mapping
+func isDuplicate(existing, submission *models.DataSubmission) bool {
+	return existing.Mode == submission.Mode && existing.ColumnMapping.Equal(submission.ColumnMapping)
+}
+
+// FindDuplicate returns the existing submission identical to the given one within the window, or
+// nil if there is none. It lets a duplicate be answered before the file is validated; Create makes
+// the final, atomic check.
+func (d *SubmissionDeduplicator) FindDuplicate(submission *models.DataSubmission) (*models.DataSubmission, error) {
+	if !d.Enabled() || submission.FileHash == nil || *submission.FileHash == "" {
+		return nil, nil
+	}
+
+	existing, err := d.store.FindRecentSubmission(submission.DatasetID, submission.SubmittedBy, *submission.FileHash, d.now().Add(-d.window))
+	if err != nil || existing == nil || !isDuplicate(existing, submission) {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// Create records the submission, unless an identical one was made within the window, in which
+// case that submission is returned and nothing is recorded
+func (d *SubmissionDeduplicator) Create(submission *models.DataSubmission) (*models.DataSubmission, error) {
+	if !d.Enabled() || submission.FileHash == nil || *submission.FileHash == "" {
+		return nil, d.store.CreateSubmission(submission)
+	}
+
+	return d.store.CreateSubmissionUnlessRecent(submission, d.now().Add(-d.window), func(existing *models.DataSubmission) bool {
+		return isDuplicate(existing, submission)
+	})
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSubmissionStore records created submissions and finds recent ones by hash. Its conditional
+// create holds a lock across the lookup and insert, like the repository's advisory lock.
+type fakeSubmissionStore struct {
+	mu          sync.Mutex
+	submissions []*models.DataSubmission
+}
+
+func (f *fakeSubmissionStore) recent(datasetID, userID uuid.UUID, fileHash string, since time.Time) *models.DataSubmission {
+	for i := len(f.submissions) - 1; i >= 0; i-- {
+		s := f.submissions[i]
+		if s.DatasetID == datasetID && s.SubmittedBy == userID && s.FileHash != nil &&
+			*s.FileHash == fileHash && !s.SubmittedAt.Before(since) {
+			return s
+		}
+	}
+	return nil
+}
+
+func (f *fakeSubmissionStore) FindRecentSubmission(datasetID, userID uuid.UUID, fileHash string, since time.Time) (*models.DataSubmission, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.recent(datasetID, userID, fileHash, since), nil
+}
+
+func (f *fakeSubmissionStore) CreateSubmission(submission *models.DataSubmission) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.submissions = append(f.submissions, submission)
+	return nil
+}
+
+func (f *fakeSubmissionStore) CreateSubmissionUnlessRecent(submission *models.DataSubmission, since time.Time, isDuplicate func(*models.DataSubmission) bool) (*models.DataSubmission, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if existing := f.recent(submission.DatasetID, submission.SubmittedBy, *submission.FileHash, since); existing != nil && isDuplicate(existing) {
+		return existing, nil
+	}
+	f.submissions = append(f.submissions, submission)
+	return nil, nil
+}
+
+func newSubmission(datasetID, userID uuid.UUID, fileHash string, at time.Time) *models.DataSubmission {
+	return &models.DataSubmission{
+		ID:          uuid.New(),
+		DatasetID:   datasetID,
+		SubmittedBy: userID,
+		FileHash:    &fileHash,
+		Mode:        models.SubmissionModeAppend,
+		SubmittedAt: at,
+	}
+}
+
+// submit follows the append handler: answer a duplicate found up front, otherwise create
+func submit(t *testing.T, d *SubmissionDeduplicator, submission *models.DataSubmission) *models.DataSubmission {
+	t.Helper()
+	existing, err := d.FindDuplicate(submission)
+	require.NoError(t, err)
+	if existing != nil {
+		return existing
+	}
+	existing, err = d.Create(submission)
+	require.NoError(t, err)
+	if existing != nil {
+		return existing
+	}
+	return submission
+}
+
+func TestSubmissionDeduplicator(t *testing.T) {
+	datasetID := uuid.New()
+	userID := uuid.New()
+	start := time.Now()
+
+	t.Run("rapid double submit yields one submission", func(t *testing.T) {
+		store := &fakeSubmissionStore{}
+		dedup := NewSubmissionDeduplicator(store, 30*time.Second)
+		dedup.now = func() time.Time { return start.Add(time.Second) }
+
+		first := submit(t, dedup, newSubmission(datasetID, userID, "abc123", start))
+		second := submit(t, dedup, newSubmission(datasetID, userID, "abc123", start.Add(time.Second)))
+
+		assert.Equal(t, first.ID, second.ID)
+		assert.Len(t, store.submissions, 1)
+	})
+
+	t.Run("concurrent double submits that both miss the early check yield one submission", func(t *testing.T) {
+		store := &fakeSubmissionStore{}
+		dedup := NewSubmissionDeduplicator(store, 30*time.Second)
+		dedup.now = func() time.Time { return start }
+
+		// Both requests have already passed FindDuplicate and are validated when they create
+		var wg sync.WaitGroup
+		created := make([]*models.DataSubmission, 8)
+		for i := range created {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				submission := newSubmission(datasetID, userID, "abc123", start)
+				existing, err := dedup.Create(submission)
+				assert.NoError(t, err)
+				if existing == nil {
+					existing = submission
+				}
+				created[i] = existing
+			}(i)
+		}
+		wg.Wait()
+
+		require.Len(t, store.submissions, 1)
+		for _, submission := range created {
+			assert.Equal(t, store.submissions[0].ID, submission.ID)
+		}
+	})
+
+	t.Run("different mode or column mapping is not a duplicate", func(t *testing.T) {
+		store := &fakeSubmissionStore{}
+		dedup := NewSubmissionDeduplicator(store, 30*time.Second)
+		dedup.now = func() time.Time { return start }
+
+		submit(t, dedup, newSubmission(datasetID, userID, "abc123", start))
+		replace := newSubmission(datasetID, userID, "abc123", start)
+		replace.Mode = models.SubmissionModeReplace
+		submit(t, dedup, replace)
+		mapped := newSubmission(datasetID, userID, "abc123", start)
+		mapped.ColumnMapping = models.ColumnMapping{"Name": "name"}
+		submit(t, dedup, mapped)
+
+		assert.Len(t, store.submissions, 3)
+	})
+
+	t.Run("submission outside window is not deduplicated", func(t *testing.T) {
+		store := &fakeSubmissionStore{}
+		dedup := NewSubmissionDeduplicator(store, 30*time.Second)
+
+		dedup.now = func() time.Time { return start }
+		submit(t, dedup, newSubmission(datasetID, userID, "abc123", start))
+		dedup.now = func() time.Time { return start.Add(time.Minute) }
+		submit(t, dedup, newSubmission(datasetID, userID, "abc123", start.Add(time.Minute)))
+
+		assert.Len(t, store.submissions, 2)
+	})
+
+	t.Run("different file or user is not deduplicated", func(t *testing.T) {
+		store := &fakeSubmissionStore{}
+		dedup := NewSubmissionDeduplicator(store, 30*time.Second)
+		dedup.now = func() time.Time { return start }
+
+		submit(t, dedup, newSubmission(datasetID, userID, "abc123", start))
+		submit(t, dedup, newSubmission(datasetID, userID, "def456", start))
+		submit(t, dedup, newSubmission(datasetID, uuid.New(), "abc123", start))
+
+		assert.Len(t, store.submissions, 3)
+	})
+
+	t.Run("disabled without window", func(t *testing.T) {
+		store := &fakeSubmissionStore{}
+		dedup := NewSubmissionDeduplicator(store, 0)
+
+		submit(t, dedup, newSubmission(datasetID, userID, "abc123", start))
+		submit(t, dedup, newSubmission(datasetID, userID, "abc123", start))
+
+		assert.False(t, dedup.Enabled())
+		assert.Len(t, store.submissions, 2)
+	})
+}
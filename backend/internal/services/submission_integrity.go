@@ -0,0 +1,121 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+)
+
+// ErrSubmissionFileMissing is returned when a submission's file is no longer stored
+var ErrSubmissionFileMissing = errors.New("submission file is no longer stored")
+
+// ExpectedRowsWarning describes a file that parsed into a different number of data rows than the
+// client expected, or returns "" when they agree
+func ExpectedRowsWarning(expected, parsed int) string {
+	if expected == parsed {
+		return ""
+	}
+	return fmt.Sprintf("File parsed as %d data rows but %d were expected; it may be malformed, for example with line breaks inside quoted values", parsed, expected)
+}
+
+// lineCounter counts the lines of the bytes written to it
+type lineCounter struct {
+	lines    int
+	lastByte byte
+}
+
+func (l *lineCounter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		l.lines += bytes.Count(p, []byte{'\n'})
+		l.lastByte = p[len(p)-1]
+	}
+	return len(p), nil
+}
+
+// total returns the line count, including a last line without a trailing line break
+func (l *lineCounter) total() int {
+	if l.lastByte != 0 && l.lastByte != '\n' {
+		return l.lines + 1
+	}
+	return l.lines
+}
+
+// VerifySubmissionFile re-reads a submission's stored file, hashing it and re-parsing it with the
+// dataset's CSV dialect, and reports whether it still matches what was recorded at submission.
+// expectedRows, when set, is the data row count the client expects the file to hold.
+func (v *ValidationService) VerifySubmissionFile(submission *models.DataSubmission, expectedRows *int) (*models.SubmissionIntegrityReport, error) {
+	dialect, err := v.schemaRepo.GetDatasetCSVDialect(submission.DatasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CSV dialect: %w", err)
+	}
+
+	file, err := v.files.Open(context.Background(), submission.FilePath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrSubmissionFileMissing
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	lines := &lineCounter{}
+	content := io.TeeReader(file, io.MultiWriter(hasher, lines))
+
+	parsedRows := 0
+	reader := NewCSVReader(content, dialect)
+	if _, err := reader.Read(); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read headers: %w", err)
+	} else if err == nil {
+		if parsedRows, err = CountRemainingRecords(reader); err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %w", parsedRows, err)
+		}
+	}
+	// The CSV reader may stop short of the end; hash the whole file
+	if _, err := io.Copy(io.Discard, content); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	fileLines := lines.total()
+	if fileLines > 0 {
+		fileLines-- // the header
+	}
+
+	report := &models.SubmissionIntegrityReport{
+		SubmissionID: submission.ID,
+		FileHash:     hex.EncodeToString(hasher.Sum(nil)),
+		RecordedHash: submission.FileHash,
+		RowCount:     submission.RowCount,
+		ParsedRows:   parsedRows,
+		FileLines:    fileLines,
+		ExpectedRows: expectedRows,
+		Warnings:     []string{},
+	}
+	report.HashMatches = submission.FileHash != nil && *submission.FileHash == report.FileHash
+
+	if !report.HashMatches {
+		report.Warnings = append(report.Warnings, "Stored file does not match the SHA-256 recorded at submission")
+	}
+	if parsedRows != submission.RowCount {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("Stored file parses as %d data rows but %d were recorded at submission", parsedRows, submission.RowCount))
+	}
+	if expectedRows != nil {
+		if warning := ExpectedRowsWarning(*expectedRows, parsedRows); warning != "" {
+			// Counting lines instead of records gives the expected count when quoted values span lines
+			if fileLines == *expectedRows {
+				warning += fmt.Sprintf("; the file has %d lines after the header, so some values span several lines", fileLines)
+			}
+			report.Warnings = append(report.Warnings, warning)
+		}
+	}
+	report.Verified = len(report.Warnings) == 0
+
+	return report, nil
+}
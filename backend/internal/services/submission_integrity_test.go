@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func storedSubmission(t *testing.T, files storage.Storage, content string, rowCount int) *models.DataSubmission {
+	key := storage.Key("submissions", uuid.NewString()+".csv")
+	require.NoError(t, files.Put(context.Background(), key, strings.NewReader(content)))
+
+	sum := sha256.Sum256([]byte(content))
+	hash := hex.EncodeToString(sum[:])
+	return &models.DataSubmission{ID: uuid.New(), DatasetID: uuid.New(), FilePath: key, FileHash: &hash, RowCount: rowCount}
+}
+
+func TestVerifySubmissionFile(t *testing.T) {
+	files := storage.NewLocalStorage(t.TempDir())
+	svc := NewValidationService(&fakeSchemaRepository{}, &fakeSubmissionRepository{})
+	svc.SetStorage(files)
+
+	content := "name,notes\nAnn,\"line one\nline two\"\nBob,ok\n"
+
+	t.Run("unchanged file verifies", func(t *testing.T) {
+		submission := storedSubmission(t, files, content, 2)
+
+		report, err := svc.VerifySubmissionFile(submission, nil)
+		require.NoError(t, err)
+		assert.True(t, report.Verified)
+		assert.True(t, report.HashMatches)
+		assert.Equal(t, *submission.FileHash, report.FileHash)
+		assert.Equal(t, 2, report.ParsedRows)
+		assert.Equal(t, 3, report.FileLines)
+		assert.Empty(t, report.Warnings)
+	})
+
+	t.Run("expected count from line breaks is warned about", func(t *testing.T) {
+		submission := storedSubmission(t, files, content, 2)
+		expected := 3
+
+		report, err := svc.VerifySubmissionFile(submission, &expected)
+		require.NoError(t, err)
+		assert.False(t, report.Verified)
+		require.Len(t, report.Warnings, 1)
+		assert.Contains(t, report.Warnings[0], "parsed as 2 data rows but 3 were expected")
+		assert.Contains(t, report.Warnings[0], "some values span several lines")
+	})
+
+	t.Run("changed file fails verification", func(t *testing.T) {
+		submission := storedSubmission(t, files, content, 2)
+		other := "something else"
+		submission.FileHash = &other
+
+		report, err := svc.VerifySubmissionFile(submission, nil)
+		require.NoError(t, err)
+		assert.False(t, report.HashMatches)
+		assert.False(t, report.Verified)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := svc.VerifySubmissionFile(&models.DataSubmission{FilePath: "submissions/gone.csv"}, nil)
+		assert.ErrorIs(t, err, ErrSubmissionFileMissing)
+	})
+}
+
+func TestExpectedRowsWarning(t *testing.T) {
+	assert.Empty(t, ExpectedRowsWarning(5, 5))
+	assert.Contains(t, ExpectedRowsWarning(5, 4), "File parsed as 4 data rows but 5 were expected")
+}
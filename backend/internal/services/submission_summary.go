@@ -0,0 +1,56 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+// ErrSubmissionAccessDenied is returned when a user cannot view a submission's dataset
+var ErrSubmissionAccessDenied = errors.New("no access to submission")
+
+// SubmissionSummaryRepositoryInterface is the subset of submission storage needed for summaries
+type SubmissionSummaryRepositoryInterface interface {
+	GetSubmission(id uuid.UUID) (*models.DataSubmission, error)
+	CheckDatasetAccess(datasetID uuid.UUID, userID uuid.UUID) (bool, error)
+}
+
+// SubmissionSummaryService builds lightweight submission summaries
+type SubmissionSummaryService struct {
+	repo SubmissionSummaryRepositoryInterface
+}
+
+// NewSubmissionSummaryService creates a new submission summary service
+func NewSubmissionSummaryService(repo SubmissionSummaryRepositoryInterface) *SubmissionSummaryService {
+	return &SubmissionSummaryService{repo: repo}
+}
+
+// GetSummary returns a submission and its stored validation result without loading staging rows
+func (s *SubmissionSummaryService) GetSummary(submissionID, userID uuid.UUID) (*models.SubmissionSummary, error) {
+	submission, err := s.repo.GetSubmission(submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	hasAccess, err := s.repo.CheckDatasetAccess(submission.DatasetID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify dataset access: %w", err)
+	}
+	if !hasAccess {
+		return nil, ErrSubmissionAccessDenied
+	}
+
+	summary := &models.SubmissionSummary{Submission: submission}
+	if submission.ValidationResults != nil {
+		var result models.ValidationResult
+		if err := json.Unmarshal(*submission.ValidationResults, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode validation results: %w", err)
+		}
+		summary.ValidationResult = &result
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSummaryRepository serves a single submission and counts staging lookups
+type fakeSummaryRepository struct {
+	submission   *models.DataSubmission
+	hasAccess    bool
+	stagingCalls int
+}
+
+func (f *fakeSummaryRepository) GetSubmission(id uuid.UUID) (*models.DataSubmission, error) {
+	if f.submission == nil || f.submission.ID != id {
+		return nil, errors.New("submission not found")
+	}
+	return f.submission, nil
+}
+
+func (f *fakeSummaryRepository) CheckDatasetAccess(datasetID uuid.UUID, userID uuid.UUID) (bool, error) {
+	return f.hasAccess, nil
+}
+
+func (f *fakeSummaryRepository) GetStagingData(submissionID uuid.UUID, limit, offset int) ([]*models.DataSubmissionStaging, int, error) {
+	f.stagingCalls++
+	return nil, 0, nil
+}
+
+func TestSubmissionSummaryService_GetSummary(t *testing.T) {
+	result := models.ValidationResult{IsValid: false, TotalRows: 10, ValidRows: 8, InvalidRows: 2}
+	raw, err := json.Marshal(result)
+	require.NoError(t, err)
+	validationResults := json.RawMessage(raw)
+
+	submission := &models.DataSubmission{
+		ID:                uuid.New(),
+		DatasetID:         uuid.New(),
+		RowCount:          10,
+		ValidationResults: &validationResults,
+	}
+
+	t.Run("returns metadata and validation result without staging rows", func(t *testing.T) {
+		repo := &fakeSummaryRepository{submission: submission, hasAccess: true}
+		svc := NewSubmissionSummaryService(repo)
+
+		summary, err := svc.GetSummary(submission.ID, uuid.New())
+		require.NoError(t, err)
+		assert.Equal(t, submission, summary.Submission)
+		require.NotNil(t, summary.ValidationResult)
+		assert.Equal(t, 10, summary.ValidationResult.TotalRows)
+		assert.Equal(t, 2, summary.ValidationResult.InvalidRows)
+		assert.Equal(t, 0, repo.stagingCalls)
+	})
+
+	t.Run("submission without validation results", func(t *testing.T) {
+		bare := &models.DataSubmission{ID: uuid.New(), DatasetID: uuid.New()}
+		repo := &fakeSummaryRepository{submission: bare, hasAccess: true}
+
+		summary, err := NewSubmissionSummaryService(repo).GetSummary(bare.ID, uuid.New())
+		require.NoError(t, err)
+		assert.Nil(t, summary.ValidationResult)
+	})
+
+	t.Run("denies users without dataset access", func(t *testing.T) {
+		repo := &fakeSummaryRepository{submission: submission}
+
+		_, err := NewSubmissionSummaryService(repo).GetSummary(submission.ID, uuid.New())
+		assert.ErrorIs(t, err, ErrSubmissionAccessDenied)
+	})
+}
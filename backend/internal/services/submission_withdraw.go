@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+)
+
+var (
+	// ErrSubmissionWithdrawDenied is returned when a user is neither the submitter nor an admin
+	ErrSubmissionWithdrawDenied = errors.New("not allowed to withdraw submission")
+	// ErrSubmissionAlreadyApplied is returned when withdrawing a submission whose rows were applied
+	ErrSubmissionAlreadyApplied = errors.New("submission has already been applied")
+)
+
+// SubmissionWithdrawRepositoryInterface is the subset of submission storage needed to withdraw submissions
+type SubmissionWithdrawRepositoryInterface interface {
+	GetSubmission(id uuid.UUID) (*models.DataSubmission, error)
+	IsUserAdmin(userID uuid.UUID) (bool, error)
+	DeleteSubmission(id uuid.UUID) error
+}
+
+// SubmissionWithdrawService lets submitters cancel submissions that have not been applied
+type SubmissionWithdrawService struct {
+	repo  SubmissionWithdrawRepositoryInterface
+	files storage.Storage
+}
+
+// NewSubmissionWithdrawService creates a new submission withdraw service
+func NewSubmissionWithdrawService(repo SubmissionWithdrawRepositoryInterface, files storage.Storage) *SubmissionWithdrawService {
+	return &SubmissionWithdrawService{repo: repo, files: files}
+}
+
+// Withdraw deletes a submission, its staging rows and its uploaded file. Only the submitter
+// or an admin may withdraw, and applied submissions are kept.
+func (s *SubmissionWithdrawService) Withdraw(submissionID, userID uuid.UUID) (*models.DataSubmission, error) {
+	submission, err := s.repo.GetSubmission(submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission.SubmittedBy != userID {
+		isAdmin, err := s.repo.IsUserAdmin(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify admin status: %w", err)
+		}
+		if !isAdmin {
+			return nil, ErrSubmissionWithdrawDenied
+		}
+	}
+
+	if submission.Status == models.DataSubmissionStatusApplied {
+		return nil, ErrSubmissionAlreadyApplied
+	}
+
+	if err := s.repo.DeleteSubmission(submission.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete submission: %w", err)
+	}
+
+	if submission.FilePath != "" {
+		if err := s.files.Delete(context.Background(), submission.FilePath); err != nil {
+			log.Printf("Warning: failed to remove file for withdrawn submission %s: %v", submission.ID, err)
+		}
+	}
+
+	return submission, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/repository"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeWithdrawStore keeps submissions in memory and records deletions
+type fakeWithdrawStore struct {
+	submissions map[uuid.UUID]*models.DataSubmission
+	admins      map[uuid.UUID]bool
+	deleted     []uuid.UUID
+}
+
+func (f *fakeWithdrawStore) GetSubmission(id uuid.UUID) (*models.DataSubmission, error) {
+	submission, ok := f.submissions[id]
+	if !ok {
+		return nil, repository.ErrSubmissionNotFound
+	}
+	return submission, nil
+}
+
+func (f *fakeWithdrawStore) IsUserAdmin(userID uuid.UUID) (bool, error) {
+	return f.admins[userID], nil
+}
+
+func (f *fakeWithdrawStore) DeleteSubmission(id uuid.UUID) error {
+	delete(f.submissions, id)
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestSubmissionWithdrawService_Withdraw(t *testing.T) {
+	submitter := uuid.New()
+	admin := uuid.New()
+	stranger := uuid.New()
+
+	newStore := func(status string, filePath string) (*fakeWithdrawStore, *models.DataSubmission) {
+		submission := &models.DataSubmission{ID: uuid.New(), SubmittedBy: submitter, Status: status, FilePath: filePath}
+		return &fakeWithdrawStore{
+			submissions: map[uuid.UUID]*models.DataSubmission{submission.ID: submission},
+			admins:      map[uuid.UUID]bool{admin: true},
+		}, submission
+	}
+
+	t.Run("submitter withdraws pending submission and its file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "upload.csv")
+		require.NoError(t, os.WriteFile(path, []byte("a,b\n1,2\n"), 0o644))
+		store, submission := newStore(models.DataSubmissionStatusPending, path)
+
+		_, err := NewSubmissionWithdrawService(store, storage.NewLocalStorage("")).Withdraw(submission.ID, submitter)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{submission.ID}, store.deleted)
+		_, statErr := os.Stat(path)
+		assert.True(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("admin withdraws someone else's submission", func(t *testing.T) {
+		store, submission := newStore(models.DataSubmissionStatusUnderReview, "")
+
+		_, err := NewSubmissionWithdrawService(store, storage.NewLocalStorage("")).Withdraw(submission.ID, admin)
+		require.NoError(t, err)
+		assert.Len(t, store.deleted, 1)
+	})
+
+	t.Run("missing file is not an error", func(t *testing.T) {
+		store, submission := newStore(models.DataSubmissionStatusPending, filepath.Join(t.TempDir(), "gone.csv"))
+
+		_, err := NewSubmissionWithdrawService(store, storage.NewLocalStorage("")).Withdraw(submission.ID, submitter)
+		require.NoError(t, err)
+	})
+
+	t.Run("other users are denied", func(t *testing.T) {
+		store, submission := newStore(models.DataSubmissionStatusPending, "")
+
+		_, err := NewSubmissionWithdrawService(store, storage.NewLocalStorage("")).Withdraw(submission.ID, stranger)
+		assert.ErrorIs(t, err, ErrSubmissionWithdrawDenied)
+		assert.Empty(t, store.deleted)
+	})
+
+	t.Run("applied submissions are kept", func(t *testing.T) {
+		store, submission := newStore(models.DataSubmissionStatusApplied, "")
+
+		_, err := NewSubmissionWithdrawService(store, storage.NewLocalStorage("")).Withdraw(submission.ID, submitter)
+		assert.ErrorIs(t, err, ErrSubmissionAlreadyApplied)
+		assert.Empty(t, store.deleted)
+	})
+
+	t.Run("unknown submission", func(t *testing.T) {
+		store, _ := newStore(models.DataSubmissionStatusPending, "")
+
+		_, err := NewSubmissionWithdrawService(store, storage.NewLocalStorage("")).Withdraw(uuid.New(), submitter)
+		assert.ErrorIs(t, err, repository.ErrSubmissionNotFound)
+	})
+}
@@ -1,52 +1,114 @@
 package services
 
 import (
-	"encoding/csv"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/saurabh22suman/oreo.io/internal/storage"
 )
 
 type ValidationService struct {
 	schemaRepo         SchemaRepositoryInterface
 	submissionRepo     DataSubmissionRepositoryInterface
+	maxRows            int
+	emailStrictness    string
+	emailMX            *mxChecker
+	maxMagnitude       float64
+	files              storage.Storage
+	validations        *DatasetLimiter
 }
 
 func NewValidationService(schemaRepo SchemaRepositoryInterface, submissionRepo DataSubmissionRepositoryInterface) *ValidationService {
 	return &ValidationService{
 		schemaRepo:     schemaRepo,
 		submissionRepo: submissionRepo,
+		files:          storage.NewLocalStorage(""),
 	}
 }
 
+// SetStorage sets where submitted files are read from; files are read from local disk by default
+func (v *ValidationService) SetStorage(files storage.Storage) {
+	v.files = files
+}
+
+// SetMaxRows caps the number of data rows accepted per submission; zero or less disables the cap
+func (v *ValidationService) SetMaxRows(maxRows int) {
+	v.maxRows = maxRows
+}
+
+// SetEmailValidation sets the default email strictness, which email fields can override through
+// their validation format, and whether email domains must have MX records (requires network access)
+func (v *ValidationService) SetEmailValidation(strictness string, checkMX bool) {
+	v.emailStrictness = strictness
+	v.emailMX = nil
+	if checkMX {
+		v.emailMX = newMXChecker()
+	}
+}
+
+// SetNumericMaxMagnitude caps the absolute value of number and integer field values; zero or less
+// leaves only the limits of the field type
+func (v *ValidationService) SetNumericMaxMagnitude(maxMagnitude float64) {
+	v.maxMagnitude = maxMagnitude
+}
+
+// SetMaxConcurrentValidations limits how many validations of the same dataset run at once, queueing
+// the rest so large submissions don't thrash the database; zero or less disables the limit
+func (v *ValidationService) SetMaxConcurrentValidations(limit int) {
+	v.validations = NewDatasetLimiter(limit)
+}
+
 // hasValidationRules checks if a FieldValidation struct has any validation rules set
 func (v *ValidationService) hasValidationRules(validation models.FieldValidation) bool {
 	return validation.MinLength != nil || validation.MaxLength != nil ||
 		validation.MinValue != nil || validation.MaxValue != nil ||
+		validation.Precision != nil || validation.Scale != nil ||
 		validation.Pattern != nil || len(validation.Options) > 0 ||
 		validation.Format != nil
 }
 
 type SchemaRepositoryInterface interface {
 	GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error)
+	GetSchemaByName(datasetID uuid.UUID, name string) (*models.DatasetSchema, error)
+	FindExistingFieldValues(datasetID uuid.UUID, fieldName string, values []string) (map[string]int, error)
+	GetDatasetCSVDialect(datasetID uuid.UUID) (*models.CSVDialect, error)
+	GetDatasetHeaderMode(datasetID uuid.UUID) (string, error)
+	GetDatasetNullTokens(datasetID uuid.UUID) (models.NullTokens, error)
+	GetDatasetPIIGuardrails(datasetID uuid.UUID) (models.PIIGuardrails, error)
+	PIIValueFinder
 }
 
 type DataSubmissionRepositoryInterface interface {
 	GetBusinessRules(datasetID uuid.UUID) ([]*models.DatasetBusinessRule, error)
 }
 
-// ValidateDataSubmission validates an uploaded file against dataset schema and business rules
-func (v *ValidationService) ValidateDataSubmission(filePath string, datasetID uuid.UUID) (*models.ValidationResult, []*models.DataSubmissionStaging, error) {
+// loadSchema returns the named schema variant of a dataset, or its default schema when name is empty
+func (v *ValidationService) loadSchema(datasetID uuid.UUID, schemaName string) (*models.DatasetSchema, error) {
+	if schemaName == "" {
+		return v.schemaRepo.GetSchemaByDatasetID(datasetID)
+	}
+	return v.schemaRepo.GetSchemaByName(datasetID, schemaName)
+}
+
+// ValidateDataSubmission validates a stored file against a dataset schema and business rules.
+// filePath is the file's storage key. An empty schemaName selects the dataset's default schema.
+// columnMapping renames file headers to schema fields before the headers are checked; a mapping
+// that doesn't fit the file returns a *ColumnMappingError.
+func (v *ValidationService) ValidateDataSubmission(filePath string, datasetID uuid.UUID, schemaName string, columnMapping models.ColumnMapping) (*models.ValidationResult, []*models.DataSubmissionStaging, error) {
+	release := v.validations.Acquire(datasetID)
+	defer release()
+
 	// Load dataset schema
-	schema, err := v.schemaRepo.GetSchemaByDatasetID(datasetID)
+	schema, err := v.loadSchema(datasetID, schemaName)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to load schema: %w", err)
 	}
@@ -57,14 +119,29 @@ func (v *ValidationService) ValidateDataSubmission(filePath string, datasetID uu
 		return nil, nil, fmt.Errorf("failed to load business rules: %w", err)
 	}
 
+	dialect, err := v.schemaRepo.GetDatasetCSVDialect(datasetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load CSV dialect: %w", err)
+	}
+
+	headerMode, err := v.schemaRepo.GetDatasetHeaderMode(datasetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load header mode: %w", err)
+	}
+
+	nullTokens, err := v.schemaRepo.GetDatasetNullTokens(datasetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load null tokens: %w", err)
+	}
+
 	// Parse CSV file
-	file, err := os.Open(filePath)
+	file, err := v.files.Open(context.Background(), filePath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
+	reader := NewCSVReader(file, dialect)
 	
 	// Read header
 	headers, err := reader.Read()
@@ -72,13 +149,63 @@ func (v *ValidationService) ValidateDataSubmission(filePath string, datasetID uu
 		return nil, nil, fmt.Errorf("failed to read headers: %w", err)
 	}
 
+	// Headers are matched by trimmed name, in any order
+	headers, err = ApplyColumnMapping(TrimHeaders(headers), columnMapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Validate headers against schema
-	headerValidation := v.validateHeaders(headers, schema)
+	headerValidation := v.validateHeaders(headers, schema, headerMode)
 	if !headerValidation.IsValid {
 		return headerValidation, nil, nil
 	}
 
 	// Read and validate data rows
+	validationResult := newValidationResult(schema)
+	headers, validationResult.IgnoredFields = ignoreUnknownHeaders(headers, schema)
+
+	var stagingData []*models.DataSubmissionStaging
+	var allRowData []map[string]interface{}
+
+	rowIndex := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read row %d: %w", rowIndex, err)
+		}
+
+		validationResult.TotalRows++
+		if err := CheckRowLimit(validationResult.TotalRows, v.maxRows); err != nil {
+			remaining, _ := CountRemainingRecords(reader)
+			return nil, nil, &RowLimitError{Limit: v.maxRows, Rows: validationResult.TotalRows + remaining}
+		}
+
+		rowData, stagingRow := v.validateRecord(headers, record, schema, nullTokens, rowIndex, validationResult)
+
+		// Store row data for business rule validation
+		allRowData = append(allRowData, rowData)
+		stagingData = append(stagingData, stagingRow)
+		rowIndex++
+	}
+
+	guardrailErrors, err := v.checkPIIGuardrails(datasetID, allRowData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := v.finishValidation(validationResult, allRowData, stagingData, businessRules, guardrailErrors); err != nil {
+		return nil, nil, err
+	}
+
+	return validationResult, stagingData, nil
+}
+
+// newValidationResult creates an empty result with zeroed stats for every schema field
+func newValidationResult(schema *models.DatasetSchema) *models.ValidationResult {
 	validationResult := &models.ValidationResult{
 		IsValid:            true,
 		TotalRows:          0,
@@ -90,9 +217,6 @@ func (v *ValidationService) ValidateDataSubmission(filePath string, datasetID uu
 		FieldStats:         make(map[string]models.FieldStats),
 	}
 
-	var stagingData []*models.DataSubmissionStaging
-	var allRowData []map[string]interface{}
-
 	// Initialize field stats
 	for _, field := range schema.Fields {
 		validationResult.FieldStats[field.Name] = models.FieldStats{
@@ -103,66 +227,86 @@ func (v *ValidationService) ValidateDataSubmission(filePath string, datasetID uu
 		}
 	}
 
-	rowIndex := 0
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
+	return validationResult
+}
+
+// validateRecord validates one CSV record against the schema, records its errors and stats in
+// validationResult and returns the row data with its staging row. Cells matching one of
+// nullTokens are explicit nulls.
+func (v *ValidationService) validateRecord(headers, record []string, schema *models.DatasetSchema, nullTokens models.NullTokens, rowIndex int, validationResult *models.ValidationResult) (map[string]interface{}, *models.DataSubmissionStaging) {
+	// Convert row to map
+	rowData := make(map[string]interface{})
+	for i, header := range headers {
+		if header == "" {
+			continue // column ignored by the lenient header mode
 		}
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to read row %d: %w", rowIndex, err)
+		if i < len(record) && nullTokens.Matches(record[i]) {
+			rowData[header] = nil
+		} else if i < len(record) {
+			rowData[header] = record[i]
+		} else {
+			rowData[header] = ""
 		}
+	}
 
-		validationResult.TotalRows++
+	// Canonicalize text first so options match and cells trimmed to nothing take the default
+	ApplyFieldTransforms(rowData, schema)
 
-		// Convert row to map
-		rowData := make(map[string]interface{})
-		for i, header := range headers {
-			if i < len(record) {
-				rowData[header] = record[i]
-			} else {
-				rowData[header] = ""
-			}
-		}
+	// Empty cells take their field's default, so appended rows store it rather than a blank
+	ApplyFieldDefaults(rowData, schema)
 
-		// Validate row against schema
-		rowValidation := v.validateRowAgainstSchema(rowData, schema, rowIndex)
-		validationResult.SchemaErrors = append(validationResult.SchemaErrors, rowValidation.Errors...)
+	// Numeric cells still empty are stored as null, distinct from zero
+	NullEmptyNumericFields(rowData, schema)
 
-		// Update field statistics
-		v.updateFieldStats(rowData, schema, validationResult.FieldStats)
+	// Keep object and array cells as native JSON so staging preserves their structure
+	DecodeStructuredFields(rowData, schema)
 
-		// Store row data for business rule validation
-		allRowData = append(allRowData, rowData)
+	// Split list cells into JSON arrays of their items
+	DecodeListFields(rowData, schema)
 
-		// Create staging data
-		dataJSON, _ := json.Marshal(rowData)
-		validationErrors, _ := json.Marshal(rowValidation.Errors)
-		
-		validationStatus := models.ValidationStatusValid
-		if len(rowValidation.Errors) > 0 {
-			validationStatus = models.ValidationStatusInvalid
-			validationResult.InvalidRows++
-		} else {
-			validationResult.ValidRows++
-		}
+	// Validate row against schema
+	rowValidation := v.validateRowAgainstSchema(rowData, schema, rowIndex)
+	validationResult.SchemaErrors = append(validationResult.SchemaErrors, rowValidation.Errors...)
 
-		validationErrorsJSON := json.RawMessage(validationErrors)
-		stagingRow := &models.DataSubmissionStaging{
-			ID:               uuid.New(),
-			RowIndex:         rowIndex,
-			Data:             dataJSON,
-			ValidationStatus: validationStatus,
-			ValidationErrors: &validationErrorsJSON,
-			CreatedAt:        time.Now(),
-		}
+	// Update field statistics
+	v.updateFieldStats(rowData, schema, validationResult.FieldStats)
+	countInvalidValues(rowValidation.Errors, validationResult.FieldStats)
 
-		stagingData = append(stagingData, stagingRow)
-		rowIndex++
+	// Create staging data
+	dataJSON, _ := json.Marshal(rowData)
+	validationErrors, _ := json.Marshal(rowValidation.Errors)
+	
+	validationStatus := models.ValidationStatusValid
+	if len(rowValidation.Errors) > 0 {
+		validationStatus = models.ValidationStatusInvalid
+		validationResult.InvalidRows++
+	} else {
+		validationResult.ValidRows++
+	}
+
+	validationErrorsJSON := json.RawMessage(validationErrors)
+	stagingRow := &models.DataSubmissionStaging{
+		ID:               uuid.New(),
+		RowIndex:         rowIndex,
+		Data:             dataJSON,
+		ValidationStatus: validationStatus,
+		ValidationErrors: &validationErrorsJSON,
+		CreatedAt:        time.Now(),
 	}
 
+	return rowData, stagingRow
+}
+
+// finishValidation applies business rules across all rows, marking offending staging rows invalid
+// along with the rows in guardrailErrors, and completes the field stats and overall status.
+// Business rule and guardrail errors index into allRowData. It fails only when a rule's lookup does.
+func (v *ValidationService) finishValidation(validationResult *models.ValidationResult, allRowData []map[string]interface{}, stagingData []*models.DataSubmissionStaging, businessRules []*models.DatasetBusinessRule, guardrailErrors []models.DataValidationError) error {
 	// Validate business rules across all data
-	businessRuleErrors := v.validateBusinessRules(allRowData, businessRules)
+	businessRuleErrors, err := v.validateBusinessRules(allRowData, businessRules)
+	if err != nil {
+		return err
+	}
+	businessRuleErrors = append(businessRuleErrors, guardrailErrors...)
 	validationResult.BusinessRuleErrors = businessRuleErrors
 
 	// Update validation status based on business rule errors
@@ -191,12 +335,26 @@ func (v *ValidationService) ValidateDataSubmission(filePath string, datasetID uu
 
 	// Overall validation status
 	validationResult.IsValid = validationResult.InvalidRows == 0
+	return nil
+}
 
-	return validationResult, stagingData, nil
+// checkPIIGuardrails reports rows repeating contact data guarded by the dataset
+func (v *ValidationService) checkPIIGuardrails(datasetID uuid.UUID, allRowData []map[string]interface{}) ([]models.DataValidationError, error) {
+	guardrails, err := v.schemaRepo.GetDatasetPIIGuardrails(datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PII guardrails: %w", err)
+	}
+
+	duplicates, err := CheckPIIGuardrails(v.schemaRepo, datasetID, guardrails, allRowData)
+	if err != nil {
+		return nil, err
+	}
+	return piiValidationErrors(duplicates), nil
 }
 
-// validateHeaders checks if uploaded headers match schema fields
-func (v *ValidationService) validateHeaders(headers []string, schema *models.DatasetSchema) *models.ValidationResult {
+// validateHeaders checks if uploaded headers match schema fields, in any order. Headers that
+// aren't schema fields make the upload invalid unless headerMode is lenient.
+func (v *ValidationService) validateHeaders(headers []string, schema *models.DatasetSchema, headerMode string) *models.ValidationResult {
 	result := &models.ValidationResult{
 		IsValid:            true,
 		SchemaErrors:       []models.DataValidationError{},
@@ -208,8 +366,11 @@ func (v *ValidationService) validateHeaders(headers []string, schema *models.Dat
 		schemaFields[field.Name] = true
 	}
 
-	// Check for missing required fields
+	// Check for missing fields; fields with a default are filled in on every row
 	for _, field := range schema.Fields {
+		if field.DefaultValue != nil && *field.DefaultValue != "" {
+			continue
+		}
 		found := false
 		for _, header := range headers {
 			if header == field.Name {
@@ -228,7 +389,10 @@ func (v *ValidationService) validateHeaders(headers []string, schema *models.Dat
 		}
 	}
 
-	// Check for unexpected fields
+	// Check for unexpected fields; lenient datasets ignore them
+	if headerMode == models.HeaderModeLenient {
+		return result
+	}
 	for _, header := range headers {
 		if !schemaFields[header] {
 			result.SchemaErrors = append(result.SchemaErrors, models.DataValidationError{
@@ -237,12 +401,60 @@ func (v *ValidationService) validateHeaders(headers []string, schema *models.Dat
 				ErrorType:   "unexpected_field",
 				Message:     fmt.Sprintf("Field '%s' is not defined in the dataset schema", header),
 			})
+			result.IsValid = false
 		}
 	}
 
 	return result
 }
 
+// ignoreUnknownHeaders blanks the headers that aren't schema fields, so validateRecord skips their
+// columns and they are never staged, and returns the names it blanked
+func ignoreUnknownHeaders(headers []string, schema *models.DatasetSchema) ([]string, []string) {
+	schemaFields := make(map[string]bool, len(schema.Fields))
+	for _, field := range schema.Fields {
+		schemaFields[field.Name] = true
+	}
+
+	kept := make([]string, len(headers))
+	var ignored []string
+	for i, header := range headers {
+		if schemaFields[header] {
+			kept[i] = header
+		} else if header != "" {
+			ignored = append(ignored, header)
+		}
+	}
+	return kept, ignored
+}
+
+// isNullValue reports whether a row value counts as having no value: the field is missing, empty
+// or an explicit null. Zero and false are values.
+func isNullValue(value interface{}, exists bool) bool {
+	return !exists || value == nil || value == ""
+}
+
+// fieldValueText formats a decoded JSON value the way stored values are compared by
+// FindExistingFieldValues: numbers in plain decimal without trailing zeros, so 1e+06 matches a
+// stored 1000000
+func fieldValueText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 // validateRowAgainstSchema validates a single row against the schema
 func (v *ValidationService) validateRowAgainstSchema(rowData map[string]interface{}, schema *models.DatasetSchema, rowIndex int) *rowValidationResult {
 	result := &rowValidationResult{
@@ -253,7 +465,7 @@ func (v *ValidationService) validateRowAgainstSchema(rowData map[string]interfac
 		value, exists := rowData[field.Name]
 		
 		// Check required fields
-		if field.IsRequired && (!exists || value == "" || value == nil) {
+		if field.IsRequired && isNullValue(value, exists) {
 			result.Errors = append(result.Errors, models.DataValidationError{
 				RowIndex:    rowIndex,
 				FieldName:   field.Name,
@@ -265,12 +477,12 @@ func (v *ValidationService) validateRowAgainstSchema(rowData map[string]interfac
 		}
 
 		// Skip validation for empty optional fields
-		if !exists || value == "" || value == nil {
+		if isNullValue(value, exists) {
 			continue
 		}
 
 		// Validate data type
-		if err := v.validateDataType(value, field, rowIndex); err != nil {
+		if err := v.validateDataType(value, field, schema.DateFormats, rowIndex); err != nil {
 			result.Errors = append(result.Errors, *err)
 		}
 
@@ -290,11 +502,14 @@ type rowValidationResult struct {
 }
 
 // validateDataType validates the data type of a field value
-func (v *ValidationService) validateDataType(value interface{}, field models.SchemaField, rowIndex int) *models.DataValidationError {
+func (v *ValidationService) validateDataType(value interface{}, field models.SchemaField, dateFormats []string, rowIndex int) *models.DataValidationError {
 	valueStr := fmt.Sprintf("%v", value)
 	
 	switch field.DataType {
 	case "number":
+		if reason := checkNumericOverflow(value, field.DataType, v.maxMagnitude); reason != "" {
+			return numericOverflowError(field, valueStr, reason, rowIndex)
+		}
 		if _, err := strconv.ParseFloat(valueStr, 64); err != nil {
 			return &models.DataValidationError{
 				RowIndex:      rowIndex,
@@ -305,6 +520,20 @@ func (v *ValidationService) validateDataType(value interface{}, field models.Sch
 				ExpectedValue: "number",
 			}
 		}
+	case string(models.FieldTypeInteger):
+		if reason := checkNumericOverflow(value, field.DataType, v.maxMagnitude); reason != "" {
+			return numericOverflowError(field, valueStr, reason, rowIndex)
+		}
+		if _, ok := parseIntegerValue(value); !ok {
+			return &models.DataValidationError{
+				RowIndex:      rowIndex,
+				FieldName:     field.Name,
+				ErrorType:     "invalid_data_type",
+				Message:       fmt.Sprintf("Field '%s' must be a whole number", field.Name),
+				ActualValue:   valueStr,
+				ExpectedValue: "integer",
+			}
+		}
 	case "boolean":
 		lowerValue := strings.ToLower(valueStr)
 		if lowerValue != "true" && lowerValue != "false" && lowerValue != "1" && lowerValue != "0" {
@@ -318,35 +547,90 @@ func (v *ValidationService) validateDataType(value interface{}, field models.Sch
 			}
 		}
 	case "date":
-		// Try common date formats
-		formats := []string{
-			"2006-01-02",
-			"2006-01-02 15:04:05",
-			"01/02/2006",
-			"02-01-2006",
+		if _, ok := parseDate(valueStr, dateFormats); !ok {
+			expected := "YYYY-MM-DD or MM/DD/YYYY"
+			if len(dateFormats) > 0 {
+				expected = strings.Join(dateFormats, " or ")
+			}
+			return &models.DataValidationError{
+				RowIndex:      rowIndex,
+				FieldName:     field.Name,
+				ErrorType:     "invalid_data_type",
+				Message:       fmt.Sprintf("Field '%s' must be a valid date", field.Name),
+				ActualValue:   valueStr,
+				ExpectedValue: expected,
+			}
 		}
-		
-		valid := false
-		for _, format := range formats {
-			if _, err := time.Parse(format, valueStr); err == nil {
-				valid = true
-				break
+	case string(models.FieldTypeCurrency), string(models.FieldTypePercent):
+		if _, ok := parseNumericValue(value, field.DataType); !ok {
+			expected := "amount such as $1,234.56"
+			if field.DataType == string(models.FieldTypePercent) {
+				expected = "percentage such as 45%"
+			}
+			return &models.DataValidationError{
+				RowIndex:      rowIndex,
+				FieldName:     field.Name,
+				ErrorType:     "invalid_data_type",
+				Message:       fmt.Sprintf("Field '%s' must be a valid %s", field.Name, field.DataType),
+				ActualValue:   valueStr,
+				ExpectedValue: expected,
 			}
 		}
-		
-		if !valid {
+	case string(models.FieldTypeObject), string(models.FieldTypeArray):
+		if _, ok := ParseStructuredValue(value, field.DataType); !ok {
 			return &models.DataValidationError{
 				RowIndex:      rowIndex,
 				FieldName:     field.Name,
 				ErrorType:     "invalid_data_type",
-				Message:       fmt.Sprintf("Field '%s' must be a valid date", field.Name),
+				Message:       fmt.Sprintf("Field '%s' must be a valid JSON %s", field.Name, field.DataType),
+				ActualValue:   valueStr,
+				ExpectedValue: "JSON " + field.DataType,
+			}
+		}
+	case string(models.FieldTypeList):
+		items, ok := ParseListValue(value, field)
+		if !ok {
+			return &models.DataValidationError{
+				RowIndex:      rowIndex,
+				FieldName:     field.Name,
+				ErrorType:     "invalid_data_type",
+				Message:       fmt.Sprintf("Field '%s' must be a list", field.Name),
+				ActualValue:   valueStr,
+				ExpectedValue: "list",
+			}
+		}
+		itemField := listItemField(field)
+		for i, item := range items {
+			if err := v.validateDataType(item, itemField, dateFormats, rowIndex); err != nil {
+				err.Message = fmt.Sprintf("Item %d of field '%s' must be a valid %s", i+1, field.Name, itemField.DataType)
+				return err
+			}
+		}
+	case string(models.FieldTypePhone):
+		format := ""
+		if field.Validation.Format != nil {
+			format = *field.Validation.Format
+		}
+		if !IsValidPhone(valueStr, format) {
+			expected := "phone number"
+			if format != "" {
+				expected = format + " phone number"
+			}
+			return &models.DataValidationError{
+				RowIndex:      rowIndex,
+				FieldName:     field.Name,
+				ErrorType:     "invalid_data_type",
+				Message:       fmt.Sprintf("Field '%s' must be a valid phone number", field.Name),
 				ActualValue:   valueStr,
-				ExpectedValue: "YYYY-MM-DD or MM/DD/YYYY",
+				ExpectedValue: expected,
 			}
 		}
 	case "email":
-		emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-		if !emailRegex.MatchString(valueStr) {
+		strictness := v.emailStrictness
+		if field.Validation.Format != nil && *field.Validation.Format != "" {
+			strictness = *field.Validation.Format
+		}
+		if !IsValidEmail(valueStr, strictness) {
 			return &models.DataValidationError{
 				RowIndex:      rowIndex,
 				FieldName:     field.Name,
@@ -356,11 +640,33 @@ func (v *ValidationService) validateDataType(value interface{}, field models.Sch
 				ExpectedValue: "valid email format",
 			}
 		}
+		if v.emailMX != nil && !v.emailMX.hasMailServer(valueStr) {
+			return &models.DataValidationError{
+				RowIndex:      rowIndex,
+				FieldName:     field.Name,
+				ErrorType:     "invalid_data_type",
+				Message:       fmt.Sprintf("Field '%s' must be an email address whose domain accepts mail", field.Name),
+				ActualValue:   valueStr,
+				ExpectedValue: "email domain with MX records",
+			}
+		}
 	}
 
 	return nil
 }
 
+// numericOverflowError reports a numeric value that cannot be stored without corruption
+func numericOverflowError(field models.SchemaField, valueStr, reason string, rowIndex int) *models.DataValidationError {
+	return &models.DataValidationError{
+		RowIndex:      rowIndex,
+		FieldName:     field.Name,
+		ErrorType:     "numeric_overflow",
+		Message:       fmt.Sprintf("Field '%s' %s", field.Name, reason),
+		ActualValue:   valueStr,
+		ExpectedValue: field.DataType + " within storable range and precision",
+	}
+}
+
 // validateFieldRules validates field-specific validation rules
 func (v *ValidationService) validateFieldRules(value interface{}, field models.SchemaField, rowIndex int) []models.DataValidationError {
 	var errors []models.DataValidationError
@@ -368,6 +674,11 @@ func (v *ValidationService) validateFieldRules(value interface{}, field models.S
 	
 	validation := field.Validation
 
+	// List fields check their item count and uniqueness, then each item against the other rules
+	if field.DataType == string(models.FieldTypeList) {
+		return v.validateListRules(value, field, rowIndex)
+	}
+
 	// String length validation
 	if field.DataType == "string" {
 		if validation.MinLength != nil && len(valueStr) < *validation.MinLength {
@@ -393,8 +704,8 @@ func (v *ValidationService) validateFieldRules(value interface{}, field models.S
 	}
 
 	// Numeric range validation
-	if field.DataType == "number" {
-		if floatVal, err := strconv.ParseFloat(valueStr, 64); err == nil {
+	if isNumericType(field.DataType) {
+		if floatVal, ok := parseNumericValue(value, field.DataType); ok {
 			if validation.MinValue != nil && floatVal < *validation.MinValue {
 				errors = append(errors, models.DataValidationError{
 					RowIndex:      rowIndex,
@@ -415,6 +726,29 @@ func (v *ValidationService) validateFieldRules(value interface{}, field models.S
 					ExpectedValue: fmt.Sprintf("max %f", *validation.MaxValue),
 				})
 			}
+
+			// Digits are counted on the stored number, so trailing zeros don't count
+			precision, scale := numericDigits(floatVal)
+			if validation.Scale != nil && scale > *validation.Scale {
+				errors = append(errors, models.DataValidationError{
+					RowIndex:      rowIndex,
+					FieldName:     field.Name,
+					ErrorType:     "scale",
+					Message:       fmt.Sprintf("Field '%s' allows at most %d decimal places", field.Name, *validation.Scale),
+					ActualValue:   valueStr,
+					ExpectedValue: fmt.Sprintf("max %d decimal places", *validation.Scale),
+				})
+			}
+			if validation.Precision != nil && precision > *validation.Precision {
+				errors = append(errors, models.DataValidationError{
+					RowIndex:      rowIndex,
+					FieldName:     field.Name,
+					ErrorType:     "precision",
+					Message:       fmt.Sprintf("Field '%s' allows at most %d digits", field.Name, *validation.Precision),
+					ActualValue:   valueStr,
+					ExpectedValue: fmt.Sprintf("max %d digits", *validation.Precision),
+				})
+			}
 		}
 	}
 
@@ -456,26 +790,114 @@ func (v *ValidationService) validateFieldRules(value interface{}, field models.S
 	return errors
 }
 
-// validateBusinessRules validates data against business rules
-func (v *ValidationService) validateBusinessRules(allRowData []map[string]interface{}, rules []*models.DatasetBusinessRule) []models.DataValidationError {
+// validateListRules validates the item count and uniqueness of a list field value and each item
+// against the field's rules for its item type
+func (v *ValidationService) validateListRules(value interface{}, field models.SchemaField, rowIndex int) []models.DataValidationError {
+	var errors []models.DataValidationError
+	items, ok := ParseListValue(value, field)
+	if !ok {
+		return nil // reported by the data type check
+	}
+	valueStr := exportCellValue(value)
+	validation := field.Validation
+
+	if validation.MinItems != nil && len(items) < *validation.MinItems {
+		errors = append(errors, models.DataValidationError{
+			RowIndex:      rowIndex,
+			FieldName:     field.Name,
+			ErrorType:     "min_items",
+			Message:       fmt.Sprintf("Field '%s' must have at least %d items", field.Name, *validation.MinItems),
+			ActualValue:   valueStr,
+			ExpectedValue: fmt.Sprintf("min %d items", *validation.MinItems),
+		})
+	}
+	if validation.MaxItems != nil && len(items) > *validation.MaxItems {
+		errors = append(errors, models.DataValidationError{
+			RowIndex:      rowIndex,
+			FieldName:     field.Name,
+			ErrorType:     "max_items",
+			Message:       fmt.Sprintf("Field '%s' must have at most %d items", field.Name, *validation.MaxItems),
+			ActualValue:   valueStr,
+			ExpectedValue: fmt.Sprintf("max %d items", *validation.MaxItems),
+		})
+	}
+	if validation.UniqueItems {
+		seen := make(map[string]bool, len(items))
+		for _, item := range items {
+			key := listItemKey(item)
+			if seen[key] {
+				errors = append(errors, models.DataValidationError{
+					RowIndex:      rowIndex,
+					FieldName:     field.Name,
+					ErrorType:     "duplicate_items",
+					Message:       fmt.Sprintf("Field '%s' lists '%s' more than once", field.Name, key),
+					ActualValue:   valueStr,
+					ExpectedValue: "unique items",
+				})
+				break
+			}
+			seen[key] = true
+		}
+	}
+
+	itemField := listItemField(field)
+	for _, item := range items {
+		errors = append(errors, v.validateFieldRules(item, itemField, rowIndex)...)
+	}
+	return errors
+}
+
+// validateBusinessRules validates data against business rules in priority order. Once a row fails
+// a stop_on_fail rule, rules of lower priority (a higher number) are not evaluated for that row.
+// It returns an error only when a reference rule can't look up its target dataset.
+func (v *ValidationService) validateBusinessRules(allRowData []map[string]interface{}, rules []*models.DatasetBusinessRule) ([]models.DataValidationError, error) {
 	var errors []models.DataValidationError
 
-	for _, rule := range rules {
+	ordered := make([]*models.DatasetBusinessRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Priority < ordered[j].Priority })
+
+	// stoppedAt maps each row that failed a stop_on_fail rule to that rule's priority
+	stoppedAt := make(map[int]int)
+
+	for _, rule := range ordered {
+		skip := func(rowIndex int) bool {
+			priority, stopped := stoppedAt[rowIndex]
+			return stopped && rule.Priority > priority
+		}
+
+		var ruleErrors []models.DataValidationError
 		switch rule.RuleType {
 		case models.RuleTypeUnique:
-			errors = append(errors, v.validateUniqueRule(allRowData, rule)...)
+			ruleErrors = v.validateUniqueRule(allRowData, rule, skip)
 		case models.RuleTypeRangeCheck:
-			errors = append(errors, v.validateRangeRule(allRowData, rule)...)
+			ruleErrors = v.validateRangeRule(allRowData, rule, skip)
 		case models.RuleTypeCrossField:
-			errors = append(errors, v.validateCrossFieldRule(allRowData, rule)...)
+			ruleErrors = v.validateCrossFieldRule(allRowData, rule, skip)
+		case models.RuleTypeRequired:
+			ruleErrors = v.validateRequiredRule(allRowData, rule, skip)
+		case models.RuleTypeReference:
+			var err error
+			if ruleErrors, err = v.validateReferenceRule(allRowData, rule, skip); err != nil {
+				return nil, err
+			}
+		}
+		errors = append(errors, ruleErrors...)
+
+		if rule.StopOnFail {
+			for _, ruleError := range ruleErrors {
+				if _, stopped := stoppedAt[ruleError.RowIndex]; !stopped {
+					stoppedAt[ruleError.RowIndex] = rule.Priority
+				}
+			}
 		}
 	}
 
-	return errors
+	return errors, nil
 }
 
 // validateUniqueRule validates uniqueness constraints
-func (v *ValidationService) validateUniqueRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule) []models.DataValidationError {
+func (v *ValidationService) validateUniqueRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule, skip func(rowIndex int) bool) []models.DataValidationError {
 	var errors []models.DataValidationError
 	
 	var config models.BusinessRuleConfig
@@ -486,6 +908,9 @@ func (v *ValidationService) validateUniqueRule(allRowData []map[string]interface
 	seen := make(map[string][]int)
 	
 	for rowIndex, rowData := range allRowData {
+		if skip(rowIndex) {
+			continue
+		}
 		if value, exists := rowData[config.FieldName]; exists && value != "" {
 			valueStr := fmt.Sprintf("%v", value)
 			seen[valueStr] = append(seen[valueStr], rowIndex)
@@ -511,7 +936,7 @@ func (v *ValidationService) validateUniqueRule(allRowData []map[string]interface
 }
 
 // validateRangeRule validates range constraints
-func (v *ValidationService) validateRangeRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule) []models.DataValidationError {
+func (v *ValidationService) validateRangeRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule, skip func(rowIndex int) bool) []models.DataValidationError {
 	var errors []models.DataValidationError
 	
 	var config models.BusinessRuleConfig
@@ -520,6 +945,9 @@ func (v *ValidationService) validateRangeRule(allRowData []map[string]interface{
 	}
 
 	for rowIndex, rowData := range allRowData {
+		if skip(rowIndex) {
+			continue
+		}
 		if value, exists := rowData[config.FieldName]; exists && value != "" {
 			if numValue, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64); err == nil {
 				valid := true
@@ -553,7 +981,7 @@ func (v *ValidationService) validateRangeRule(allRowData []map[string]interface{
 }
 
 // validateCrossFieldRule validates relationships between fields
-func (v *ValidationService) validateCrossFieldRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule) []models.DataValidationError {
+func (v *ValidationService) validateCrossFieldRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule, skip func(rowIndex int) bool) []models.DataValidationError {
 	var errors []models.DataValidationError
 	
 	var config models.BusinessRuleConfig
@@ -563,6 +991,9 @@ func (v *ValidationService) validateCrossFieldRule(allRowData []map[string]inter
 
 	// This is a simplified implementation - in practice, you'd parse and evaluate the condition
 	for rowIndex, rowData := range allRowData {
+		if skip(rowIndex) {
+			continue
+		}
 		if !v.evaluateCrossFieldCondition(rowData, config) {
 			errors = append(errors, models.DataValidationError{
 				RowIndex:    rowIndex,
@@ -577,30 +1008,109 @@ func (v *ValidationService) validateCrossFieldRule(allRowData []map[string]inter
 	return errors
 }
 
-// evaluateCrossFieldCondition evaluates cross-field conditions (simplified)
+// validateRequiredRule flags rows without a field_name value. With a condition, such as
+// "status = 'shipped'", only rows where the condition holds need the value; unlike a schema
+// field's is_required this lets a field be required depending on the rest of the row.
+func (v *ValidationService) validateRequiredRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule, skip func(rowIndex int) bool) []models.DataValidationError {
+	var errors []models.DataValidationError
+
+	var config models.BusinessRuleConfig
+	if err := json.Unmarshal(rule.RuleConfig, &config); err != nil || config.FieldName == "" {
+		return errors
+	}
+
+	var condition *ruleCondition
+	if config.Condition != "" {
+		parsed, err := parseRuleCondition(config.Condition)
+		if err != nil {
+			return errors
+		}
+		condition = &parsed
+	}
+
+	for rowIndex, rowData := range allRowData {
+		if skip(rowIndex) || (condition != nil && !condition.holds(rowData)) {
+			continue
+		}
+		if value, exists := rowData[config.FieldName]; isNullValue(value, exists) {
+			errors = append(errors, models.DataValidationError{
+				RowIndex:  rowIndex,
+				FieldName: config.FieldName,
+				ErrorType: "required_violation",
+				Message:   rule.ErrorMessage,
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateReferenceRule checks that each row's field_name value is stored in the target dataset's
+// field, like a foreign key into a lookup table. Rows without a value are not checked.
+func (v *ValidationService) validateReferenceRule(allRowData []map[string]interface{}, rule *models.DatasetBusinessRule, skip func(rowIndex int) bool) ([]models.DataValidationError, error) {
+	var errors []models.DataValidationError
+
+	var config models.BusinessRuleConfig
+	if err := json.Unmarshal(rule.RuleConfig, &config); err != nil {
+		return errors, nil
+	}
+	targetDatasetID, err := uuid.Parse(config.TargetDatasetID)
+	if err != nil || config.FieldName == "" || config.TargetField == "" {
+		return errors, nil
+	}
+
+	var values []string
+	seen := make(map[string]bool)
+	for rowIndex, rowData := range allRowData {
+		if value, exists := rowData[config.FieldName]; !skip(rowIndex) && !isNullValue(value, exists) {
+			valueStr := fieldValueText(value)
+			if !seen[valueStr] {
+				seen[valueStr] = true
+				values = append(values, valueStr)
+			}
+		}
+	}
+	if len(values) == 0 {
+		return errors, nil
+	}
+
+	existing, err := v.schemaRepo.FindExistingFieldValues(targetDatasetID, config.TargetField, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up references for rule '%s': %w", rule.RuleName, err)
+	}
+
+	for rowIndex, rowData := range allRowData {
+		value, exists := rowData[config.FieldName]
+		if skip(rowIndex) || isNullValue(value, exists) {
+			continue
+		}
+		valueStr := fieldValueText(value)
+		if _, found := existing[valueStr]; !found {
+			errors = append(errors, models.DataValidationError{
+				RowIndex:    rowIndex,
+				FieldName:   config.FieldName,
+				ErrorType:   "reference_violation",
+				Message:     rule.ErrorMessage,
+				ActualValue: valueStr,
+			})
+		}
+	}
+
+	return errors, nil
+}
+
+// evaluateCrossFieldCondition evaluates a cross-field condition such as "end > start" for a row.
+// Conditions that can't be parsed are treated as valid.
 func (v *ValidationService) evaluateCrossFieldCondition(rowData map[string]interface{}, config models.BusinessRuleConfig) bool {
-	// This is a very basic implementation
-	// In a production system, you'd want a proper expression parser
-	
 	if len(config.Fields) < 2 {
 		return true
 	}
 
-	// Example: "field1 > field2"
-	if strings.Contains(config.Condition, ">") {
-		parts := strings.Split(config.Condition, ">")
-		if len(parts) == 2 {
-			field1 := strings.TrimSpace(parts[0])
-			field2 := strings.TrimSpace(parts[1])
-			
-			val1, _ := strconv.ParseFloat(fmt.Sprintf("%v", rowData[field1]), 64)
-			val2, _ := strconv.ParseFloat(fmt.Sprintf("%v", rowData[field2]), 64)
-			
-			return val1 > val2
-		}
+	condition, err := parseRuleCondition(config.Condition)
+	if err != nil {
+		return true
 	}
-
-	return true // Default to valid if condition can't be evaluated
+	return condition.holds(rowData)
 }
 
 // updateFieldStats updates field statistics during validation
@@ -609,8 +1119,7 @@ func (v *ValidationService) updateFieldStats(rowData map[string]interface{}, sch
 		stats := fieldStats[field.Name]
 		stats.TotalValues++
 
-		value, exists := rowData[field.Name]
-		if !exists || value == "" || value == nil {
+		if value, exists := rowData[field.Name]; isNullValue(value, exists) {
 			stats.NullValues++
 		}
 
@@ -630,15 +1139,191 @@ func (v *ValidationService) calculateUniqueValues(allRowData []map[string]interf
 	// Count unique values
 	for _, rowData := range allRowData {
 		for fieldName := range fieldStats {
-			if value, exists := rowData[fieldName]; exists && value != "" && value != nil {
+			if value, exists := rowData[fieldName]; !isNullValue(value, exists) {
 				uniqueValues[fieldName][fmt.Sprintf("%v", value)] = true
 			}
 		}
 	}
 
-	// Update stats
+	// Update stats, listing the values of fields with few enough to track
 	for fieldName, stats := range fieldStats {
 		stats.UniqueValues = len(uniqueValues[fieldName])
+		stats.Values = nil
+		if stats.UniqueValues > 0 && stats.UniqueValues <= MaxTrackedFieldValues {
+			stats.Values = make([]string, 0, stats.UniqueValues)
+			for value := range uniqueValues[fieldName] {
+				stats.Values = append(stats.Values, value)
+			}
+			sort.Strings(stats.Values)
+		}
 		fieldStats[fieldName] = stats
 	}
 }
+
+// countInvalidValues counts the fields of one row whose value failed its type check
+func countInvalidValues(rowErrors []models.DataValidationError, fieldStats map[string]models.FieldStats) {
+	counted := make(map[string]bool)
+	for _, err := range rowErrors {
+		if err.ErrorType != "invalid_data_type" && err.ErrorType != "numeric_overflow" {
+			continue
+		}
+		stats, ok := fieldStats[err.FieldName]
+		if !ok || counted[err.FieldName] {
+			continue
+		}
+		counted[err.FieldName] = true
+		stats.InvalidValues++
+		fieldStats[err.FieldName] = stats
+	}
+}
+
+// DetectConflicts checks staged rows against data already stored in the dataset and reports values
+// on unique fields (schema fields marked unique and unique business rules) and PII-guarded contact
+// fields that collide with existing rows. An empty schemaName selects the dataset's default schema.
+func (v *ValidationService) DetectConflicts(datasetID uuid.UUID, schemaName string, stagingData []*models.DataSubmissionStaging) (*models.ConflictReport, error) {
+	schema, err := v.loadSchema(datasetID, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema: %w", err)
+	}
+
+	businessRules, err := v.submissionRepo.GetBusinessRules(datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load business rules: %w", err)
+	}
+
+	report := &models.ConflictReport{
+		DatasetID:   datasetID,
+		CheckedRows: len(stagingData),
+		Conflicts:   []models.DataConflict{},
+	}
+
+	// Collect key fields, schema-level unique fields take precedence over unique rules
+	var keyFields []string
+	conflictTypes := make(map[string]string)
+	ruleMessages := make(map[string]string)
+	for _, field := range schema.Fields {
+		if field.IsUnique {
+			keyFields = append(keyFields, field.Name)
+			conflictTypes[field.Name] = models.ConflictTypeDuplicateKey
+		}
+	}
+	for _, rule := range businessRules {
+		if rule.RuleType != models.RuleTypeUnique {
+			continue
+		}
+		var config models.BusinessRuleConfig
+		if err := json.Unmarshal(rule.RuleConfig, &config); err != nil || config.FieldName == "" {
+			continue
+		}
+		if _, exists := conflictTypes[config.FieldName]; exists {
+			continue
+		}
+		keyFields = append(keyFields, config.FieldName)
+		conflictTypes[config.FieldName] = models.ConflictTypeUniqueViolation
+		ruleMessages[config.FieldName] = rule.ErrorMessage
+	}
+
+	guardrails, err := v.schemaRepo.GetDatasetPIIGuardrails(datasetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PII guardrails: %w", err)
+	}
+
+	if (len(keyFields) == 0 && len(guardrails) == 0) || len(stagingData) == 0 {
+		return report, nil
+	}
+
+	// Decode staged rows once. Staged file values are still text, so numeric fields are parsed the
+	// way they will be stored: a staged "1.50" must match a stored 1.5.
+	stagedRows := make([]map[string]interface{}, len(stagingData))
+	for i, staged := range stagingData {
+		if err := json.Unmarshal(staged.Data, &stagedRows[i]); err != nil {
+			return nil, fmt.Errorf("failed to decode staged row %d: %w", staged.RowIndex, err)
+		}
+		NormalizeNumericFields(stagedRows[i], schema)
+	}
+
+	conflictedRows := make(map[int]bool)
+	for _, fieldName := range keyFields {
+		var values []string
+		seen := make(map[string]bool)
+		for _, rowData := range stagedRows {
+			if value, exists := rowData[fieldName]; !isNullValue(value, exists) {
+				valueStr := fieldValueText(value)
+				if !seen[valueStr] {
+					seen[valueStr] = true
+					values = append(values, valueStr)
+				}
+			}
+		}
+
+		existing, err := v.schemaRepo.FindExistingFieldValues(datasetID, fieldName, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing values for field '%s': %w", fieldName, err)
+		}
+
+		for i, rowData := range stagedRows {
+			value, exists := rowData[fieldName]
+			if !exists || value == "" || value == nil {
+				continue
+			}
+			valueStr := fieldValueText(value)
+			existingRow, found := existing[valueStr]
+			if !found {
+				continue
+			}
+
+			message := ruleMessages[fieldName]
+			if message == "" {
+				message = fmt.Sprintf("Value '%s' for unique field '%s' already exists in row %d", valueStr, fieldName, existingRow)
+			}
+
+			report.Conflicts = append(report.Conflicts, models.DataConflict{
+				RowIndex:         stagingData[i].RowIndex,
+				FieldName:        fieldName,
+				ConflictType:     conflictTypes[fieldName],
+				Value:            valueStr,
+				ExistingRowIndex: existingRow,
+				Message:          message,
+			})
+			conflictedRows[stagingData[i].RowIndex] = true
+		}
+	}
+
+	// Guarded contact fields also match stored values that differ only in case or punctuation
+	duplicates, err := CheckPIIGuardrails(v.schemaRepo, datasetID, guardrails, stagedRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, duplicate := range duplicates {
+		rowIndex := stagingData[duplicate.Position].RowIndex
+		if duplicate.ExistingRowIndex < 0 || hasConflict(report.Conflicts, rowIndex, duplicate.FieldName) {
+			continue
+		}
+		report.Conflicts = append(report.Conflicts, models.DataConflict{
+			RowIndex:         rowIndex,
+			FieldName:        duplicate.FieldName,
+			ConflictType:     models.ConflictTypeDuplicatePII,
+			Value:            duplicate.Value,
+			ExistingRowIndex: duplicate.ExistingRowIndex,
+			Message:          duplicate.Message(),
+		})
+		conflictedRows[rowIndex] = true
+	}
+
+	sort.SliceStable(report.Conflicts, func(i, j int) bool {
+		return report.Conflicts[i].RowIndex < report.Conflicts[j].RowIndex
+	})
+	report.ConflictedRows = len(conflictedRows)
+
+	return report, nil
+}
+
+// hasConflict reports whether a conflict was already recorded for the row's field
+func hasConflict(conflicts []models.DataConflict, rowIndex int, fieldName string) bool {
+	for _, conflict := range conflicts {
+		if conflict.RowIndex == rowIndex && conflict.FieldName == fieldName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxConcurrentValidations is how many validations of one dataset run at once when
+// MAX_CONCURRENT_VALIDATIONS_PER_DATASET is not set
+const DefaultMaxConcurrentValidations = 2
+
+// MaxConcurrentValidationsFromEnv reads MAX_CONCURRENT_VALIDATIONS_PER_DATASET; zero or a negative
+// value disables the limit
+func MaxConcurrentValidationsFromEnv() int {
+	value := os.Getenv("MAX_CONCURRENT_VALIDATIONS_PER_DATASET")
+	if value == "" {
+		return DefaultMaxConcurrentValidations
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Warning: invalid MAX_CONCURRENT_VALIDATIONS_PER_DATASET %q, using default of %d: %v", value, DefaultMaxConcurrentValidations, err)
+		return DefaultMaxConcurrentValidations
+	}
+	return limit
+}
+
+// DatasetLimiter bounds how many callers work on the same dataset at once. Callers beyond the
+// limit wait their turn; different datasets never wait on each other.
+type DatasetLimiter struct {
+	limit int
+	mu    sync.Mutex
+	slots map[uuid.UUID]*datasetSlots
+}
+
+// datasetSlots is the semaphore of one dataset; waiters counts holders and queued callers so the
+// entry can be dropped once the dataset is idle
+type datasetSlots struct {
+	sem     chan struct{}
+	waiters int
+}
+
+// NewDatasetLimiter creates a limiter allowing limit concurrent callers per dataset; zero or less
+// disables the limit
+func NewDatasetLimiter(limit int) *DatasetLimiter {
+	return &DatasetLimiter{limit: limit, slots: make(map[uuid.UUID]*datasetSlots)}
+}
+
+// Acquire blocks until datasetID has a free slot and returns the function that frees it
+func (l *DatasetLimiter) Acquire(datasetID uuid.UUID) (release func()) {
+	if l == nil || l.limit <= 0 {
+		return func() {}
+	}
+
+	l.mu.Lock()
+	slots, ok := l.slots[datasetID]
+	if !ok {
+		slots = &datasetSlots{sem: make(chan struct{}, l.limit)}
+		l.slots[datasetID] = slots
+	}
+	slots.waiters++
+	l.mu.Unlock()
+
+	slots.sem <- struct{}{}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-slots.sem
+			l.mu.Lock()
+			slots.waiters--
+			if slots.waiters == 0 {
+				delete(l.slots, datasetID)
+			}
+			l.mu.Unlock()
+		})
+	}
+}
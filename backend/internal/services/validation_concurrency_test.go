@@ -0,0 +1,118 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyTrackingRepository records how many schema loads, which start every validation, overlap
+type concurrencyTrackingRepository struct {
+	*fakeSchemaRepository
+	mu     sync.Mutex
+	active int
+	peak   int
+}
+
+func (r *concurrencyTrackingRepository) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error) {
+	r.mu.Lock()
+	r.active++
+	r.peak = max(r.peak, r.active)
+	r.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	r.mu.Lock()
+	r.active--
+	r.mu.Unlock()
+	return r.fakeSchemaRepository.GetSchemaByDatasetID(datasetID)
+}
+
+func TestMaxConcurrentValidationsFromEnv(t *testing.T) {
+	t.Setenv("MAX_CONCURRENT_VALIDATIONS_PER_DATASET", "")
+	assert.Equal(t, DefaultMaxConcurrentValidations, MaxConcurrentValidationsFromEnv())
+
+	t.Setenv("MAX_CONCURRENT_VALIDATIONS_PER_DATASET", "4")
+	assert.Equal(t, 4, MaxConcurrentValidationsFromEnv())
+
+	t.Setenv("MAX_CONCURRENT_VALIDATIONS_PER_DATASET", "0")
+	assert.Equal(t, 0, MaxConcurrentValidationsFromEnv())
+
+	t.Setenv("MAX_CONCURRENT_VALIDATIONS_PER_DATASET", "many")
+	assert.Equal(t, DefaultMaxConcurrentValidations, MaxConcurrentValidationsFromEnv())
+}
+
+func TestValidationService_ConcurrentValidationsPerDataset(t *testing.T) {
+	schema := &models.DatasetSchema{Fields: []models.SchemaField{{Name: "name", DataType: "string"}}}
+	repo := &concurrencyTrackingRepository{fakeSchemaRepository: &fakeSchemaRepository{schema: schema}}
+	svc := NewValidationService(repo, &fakeSubmissionRepository{})
+	svc.SetMaxConcurrentValidations(2)
+
+	path := filepath.Join(t.TempDir(), "names.csv")
+	require.NoError(t, os.WriteFile(path, []byte("name\nalpha\nbeta\n"), 0o644))
+
+	datasetID := uuid.New()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, _, err := svc.ValidateDataSubmission(path, datasetID, "", nil)
+			assert.NoError(t, err)
+			assert.True(t, result.IsValid)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, repo.peak, 2, "validations of one dataset never exceed the limit")
+	assert.Equal(t, 0, repo.active)
+	assert.Empty(t, svc.validations.slots, "idle datasets are forgotten")
+}
+
+func TestDatasetLimiter(t *testing.T) {
+	limiter := NewDatasetLimiter(1)
+	busy, idle := uuid.New(), uuid.New()
+
+	release := limiter.Acquire(busy)
+
+	// Another dataset is never held up by a busy one
+	done := make(chan struct{})
+	go func() {
+		limiter.Acquire(idle)()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a different dataset waited for the busy one")
+	}
+
+	// A second caller for the busy dataset queues until the slot is released
+	queued := make(chan struct{})
+	go func() {
+		limiter.Acquire(busy)()
+		close(queued)
+	}()
+	select {
+	case <-queued:
+		t.Fatal("the limit was exceeded")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	release() // releasing twice frees only one slot
+	select {
+	case <-queued:
+	case <-time.After(time.Second):
+		t.Fatal("the queued caller never ran")
+	}
+
+	assert.NotPanics(t, func() { NewDatasetLimiter(0).Acquire(busy)() })
+}
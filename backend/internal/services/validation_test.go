@@ -0,0 +1,224 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSchemaRepository is an in-memory SchemaRepositoryInterface for validation tests
+type fakeSchemaRepository struct {
+	schema *models.DatasetSchema
+	// variants maps schema name -> named schema variant
+	variants map[string]*models.DatasetSchema
+	// stored maps field name -> value -> row index of rows already in the dataset
+	stored map[string]map[string]int
+	// dialect is the CSV dialect configured on the dataset
+	dialect *models.CSVDialect
+	// guardrails are the dataset's PII guardrails; stored PII values are looked up in stored
+	guardrails models.PIIGuardrails
+	// headerMode is the dataset's header mode; empty behaves as strict
+	headerMode string
+	// nullTokens are the cell values the dataset reads as null
+	nullTokens models.NullTokens
+}
+
+func (f *fakeSchemaRepository) GetDatasetHeaderMode(datasetID uuid.UUID) (string, error) {
+	return f.headerMode, nil
+}
+
+func (f *fakeSchemaRepository) GetDatasetNullTokens(datasetID uuid.UUID) (models.NullTokens, error) {
+	return f.nullTokens, nil
+}
+
+func (f *fakeSchemaRepository) GetDatasetPIIGuardrails(datasetID uuid.UUID) (models.PIIGuardrails, error) {
+	return f.guardrails, nil
+}
+
+func (f *fakeSchemaRepository) FindExistingPIIValues(datasetID uuid.UUID, fieldName, kind string, values []string) (map[string]int, error) {
+	return f.FindExistingFieldValues(datasetID, fieldName, values)
+}
+
+func (f *fakeSchemaRepository) GetDatasetCSVDialect(datasetID uuid.UUID) (*models.CSVDialect, error) {
+	return f.dialect, nil
+}
+
+func (f *fakeSchemaRepository) GetSchemaByDatasetID(datasetID uuid.UUID) (*models.DatasetSchema, error) {
+	return f.schema, nil
+}
+
+func (f *fakeSchemaRepository) GetSchemaByName(datasetID uuid.UUID, name string) (*models.DatasetSchema, error) {
+	if schema, ok := f.variants[name]; ok {
+		return schema, nil
+	}
+	return nil, errors.New("schema not found")
+}
+
+func (f *fakeSchemaRepository) FindExistingFieldValues(datasetID uuid.UUID, fieldName string, values []string) (map[string]int, error) {
+	existing := make(map[string]int)
+	for _, value := range values {
+		if rowIndex, ok := f.stored[fieldName][value]; ok {
+			existing[value] = rowIndex
+		}
+	}
+	return existing, nil
+}
+
+// fakeSubmissionRepository is an in-memory DataSubmissionRepositoryInterface for validation tests
+type fakeSubmissionRepository struct {
+	rules []*models.DatasetBusinessRule
+}
+
+func (f *fakeSubmissionRepository) GetBusinessRules(datasetID uuid.UUID) ([]*models.DatasetBusinessRule, error) {
+	return f.rules, nil
+}
+
+func stagedRows(t *testing.T, rows ...map[string]interface{}) []*models.DataSubmissionStaging {
+	t.Helper()
+	staging := make([]*models.DataSubmissionStaging, len(rows))
+	for i, row := range rows {
+		data, err := json.Marshal(row)
+		require.NoError(t, err)
+		staging[i] = &models.DataSubmissionStaging{
+			ID:               uuid.New(),
+			RowIndex:         i,
+			Data:             data,
+			ValidationStatus: models.ValidationStatusValid,
+		}
+	}
+	return staging
+}
+
+func TestValidationService_DetectConflicts(t *testing.T) {
+	datasetID := uuid.New()
+	schema := &models.DatasetSchema{
+		DatasetID: datasetID,
+		Fields: []models.SchemaField{
+			{Name: "id", DataType: "string", IsUnique: true},
+			{Name: "email", DataType: "email"},
+		},
+	}
+
+	t.Run("reports key collision with stored row", func(t *testing.T) {
+		schemaRepo := &fakeSchemaRepository{
+			schema: schema,
+			stored: map[string]map[string]int{"id": {"A-1": 4}},
+		}
+		svc := NewValidationService(schemaRepo, &fakeSubmissionRepository{})
+
+		staging := stagedRows(t,
+			map[string]interface{}{"id": "A-2", "email": "new@example.com"},
+			map[string]interface{}{"id": "A-1", "email": "dup@example.com"},
+		)
+
+		report, err := svc.DetectConflicts(datasetID, "", staging)
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.CheckedRows)
+		assert.Equal(t, 1, report.ConflictedRows)
+		require.Len(t, report.Conflicts, 1)
+
+		conflict := report.Conflicts[0]
+		assert.Equal(t, 1, conflict.RowIndex)
+		assert.Equal(t, "id", conflict.FieldName)
+		assert.Equal(t, models.ConflictTypeDuplicateKey, conflict.ConflictType)
+		assert.Equal(t, "A-1", conflict.Value)
+		assert.Equal(t, 4, conflict.ExistingRowIndex)
+	})
+
+	t.Run("reports unique business rule violations", func(t *testing.T) {
+		config, _ := json.Marshal(models.BusinessRuleConfig{FieldName: "email"})
+		submissionRepo := &fakeSubmissionRepository{
+			rules: []*models.DatasetBusinessRule{{
+				RuleType:     models.RuleTypeUnique,
+				RuleConfig:   config,
+				ErrorMessage: "Email already registered",
+				IsActive:     true,
+			}},
+		}
+		schemaRepo := &fakeSchemaRepository{
+			schema: schema,
+			stored: map[string]map[string]int{"email": {"taken@example.com": 0}},
+		}
+		svc := NewValidationService(schemaRepo, submissionRepo)
+
+		staging := stagedRows(t, map[string]interface{}{"id": "B-1", "email": "taken@example.com"})
+
+		report, err := svc.DetectConflicts(datasetID, "", staging)
+		require.NoError(t, err)
+		require.Len(t, report.Conflicts, 1)
+		assert.Equal(t, models.ConflictTypeUniqueViolation, report.Conflicts[0].ConflictType)
+		assert.Equal(t, "Email already registered", report.Conflicts[0].Message)
+	})
+
+	t.Run("numeric keys match stored values written differently", func(t *testing.T) {
+		numeric := &models.DatasetSchema{
+			DatasetID: datasetID,
+			Fields:    []models.SchemaField{{Name: "code", DataType: "number", IsUnique: true}},
+		}
+		schemaRepo := &fakeSchemaRepository{
+			schema: numeric,
+			stored: map[string]map[string]int{"code": {"1000000": 2, "2.5": 7}},
+		}
+		svc := NewValidationService(schemaRepo, &fakeSubmissionRepository{})
+
+		staging := stagedRows(t,
+			map[string]interface{}{"code": 1e6},
+			map[string]interface{}{"code": 2.50},
+			map[string]interface{}{"code": 3},
+		)
+
+		report, err := svc.DetectConflicts(datasetID, "", staging)
+		require.NoError(t, err)
+		require.Len(t, report.Conflicts, 2)
+		assert.Equal(t, "1000000", report.Conflicts[0].Value)
+		assert.Equal(t, 2, report.Conflicts[0].ExistingRowIndex)
+		assert.Equal(t, "2.5", report.Conflicts[1].Value)
+		assert.Equal(t, 7, report.Conflicts[1].ExistingRowIndex)
+	})
+
+	t.Run("numeric keys staged as text match stored numbers", func(t *testing.T) {
+		typed := &models.DatasetSchema{
+			DatasetID: datasetID,
+			Fields: []models.SchemaField{
+				{Name: "code", DataType: "number", IsUnique: true},
+				{Name: "price", DataType: "currency", IsUnique: true},
+			},
+		}
+		schemaRepo := &fakeSchemaRepository{
+			schema: typed,
+			stored: map[string]map[string]int{"code": {"1.5": 3}, "price": {"1200": 5}},
+		}
+		svc := NewValidationService(schemaRepo, &fakeSubmissionRepository{})
+
+		staging := stagedRows(t,
+			map[string]interface{}{"code": "1.50", "price": "9"},
+			map[string]interface{}{"code": "8", "price": "$1,200.00"},
+		)
+
+		report, err := svc.DetectConflicts(datasetID, "", staging)
+		require.NoError(t, err)
+		require.Len(t, report.Conflicts, 2)
+		assert.Equal(t, "code", report.Conflicts[0].FieldName)
+		assert.Equal(t, "1.5", report.Conflicts[0].Value)
+		assert.Equal(t, 3, report.Conflicts[0].ExistingRowIndex)
+		assert.Equal(t, "price", report.Conflicts[1].FieldName)
+		assert.Equal(t, 5, report.Conflicts[1].ExistingRowIndex)
+	})
+
+	t.Run("no conflicts without key fields", func(t *testing.T) {
+		schemaRepo := &fakeSchemaRepository{
+			schema: &models.DatasetSchema{Fields: []models.SchemaField{{Name: "note", DataType: "string"}}},
+		}
+		svc := NewValidationService(schemaRepo, &fakeSubmissionRepository{})
+
+		report, err := svc.DetectConflicts(datasetID, "", stagedRows(t, map[string]interface{}{"note": "x"}))
+		require.NoError(t, err)
+		assert.Empty(t, report.Conflicts)
+		assert.Equal(t, 0, report.ConflictedRows)
+	})
+}
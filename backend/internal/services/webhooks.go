@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+)
+
+const (
+	// EventSchemaUpdated fires when a dataset schema is created, updated or deleted
+	EventSchemaUpdated = "schema.updated"
+
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body, keyed by WEBHOOK_SECRET
+	WebhookSignatureHeader = "X-Oreo-Signature"
+	// WebhookEventHeader carries the event type so receivers can route without parsing the body
+	WebhookEventHeader = "X-Oreo-Event"
+
+	// webhookTimeout bounds each delivery so a slow receiver can't pile up goroutines
+	webhookTimeout = 10 * time.Second
+)
+
+// Schema change actions reported by schema.updated events
+const (
+	SchemaActionCreated = "created"
+	SchemaActionUpdated = "updated"
+	SchemaActionDeleted = "deleted"
+)
+
+// WebhookEvent is the JSON body posted to webhook receivers
+type WebhookEvent struct {
+	ID         uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	DatasetID  uuid.UUID   `json:"dataset_id"`
+	Data       interface{} `json:"data"`
+}
+
+// SchemaUpdatedData is the data of a schema.updated event
+type SchemaUpdatedData struct {
+	Action     string     `json:"action"` // created, updated or deleted
+	SchemaID   uuid.UUID  `json:"schema_id"`
+	SchemaName string     `json:"schema_name"`
+	Diff       SchemaDiff `json:"diff"`
+}
+
+// SchemaDiff summarizes how the fields of a schema changed, by field name
+type SchemaDiff struct {
+	AddedFields   []string            `json:"added_fields"`
+	RemovedFields []string            `json:"removed_fields"`
+	ChangedFields []SchemaFieldChange `json:"changed_fields"`
+}
+
+// SchemaFieldChange lists the attributes of a field that changed
+type SchemaFieldChange struct {
+	Name       string   `json:"name"`
+	Attributes []string `json:"attributes"`
+}
+
+// DiffSchemaFields compares two versions of a schema's fields, matching fields by name. Either
+// side may be empty, so creating and deleting a schema report every field as added or removed.
+func DiffSchemaFields(before, after []models.SchemaField) SchemaDiff {
+	diff := SchemaDiff{AddedFields: []string{}, RemovedFields: []string{}, ChangedFields: []SchemaFieldChange{}}
+
+	previous := make(map[string]models.SchemaField, len(before))
+	for _, field := range before {
+		previous[field.Name] = field
+	}
+
+	current := make(map[string]bool, len(after))
+	for _, field := range after {
+		current[field.Name] = true
+		old, existed := previous[field.Name]
+		if !existed {
+			diff.AddedFields = append(diff.AddedFields, field.Name)
+			continue
+		}
+		if attributes := changedFieldAttributes(old, field); len(attributes) > 0 {
+			diff.ChangedFields = append(diff.ChangedFields, SchemaFieldChange{Name: field.Name, Attributes: attributes})
+		}
+	}
+
+	for _, field := range before {
+		if !current[field.Name] {
+			diff.RemovedFields = append(diff.RemovedFields, field.Name)
+		}
+	}
+	return diff
+}
+
+// changedFieldAttributes names the attributes, by JSON name, that differ between two versions of a field
+func changedFieldAttributes(before, after models.SchemaField) []string {
+	var attributes []string
+	if before.DisplayName != after.DisplayName {
+		attributes = append(attributes, "display_name")
+	}
+	if before.DataType != after.DataType {
+		attributes = append(attributes, "data_type")
+	}
+	if before.IsRequired != after.IsRequired {
+		attributes = append(attributes, "is_required")
+	}
+	if before.IsUnique != after.IsUnique {
+		attributes = append(attributes, "is_unique")
+	}
+	if !reflect.DeepEqual(before.DefaultValue, after.DefaultValue) {
+		attributes = append(attributes, "default_value")
+	}
+	if before.Position != after.Position {
+		attributes = append(attributes, "position")
+	}
+	if !reflect.DeepEqual(before.Validation, after.Validation) {
+		attributes = append(attributes, "validation")
+	}
+	return attributes
+}
+
+// NewSchemaUpdatedEvent builds the schema.updated event for a change to schema; before holds the
+// fields prior to the change (nil for a new schema) and after the fields following it (nil once deleted)
+func NewSchemaUpdatedEvent(action string, schema *models.DatasetSchema, before, after []models.SchemaField) WebhookEvent {
+	return WebhookEvent{
+		ID:         uuid.New(),
+		Type:       EventSchemaUpdated,
+		OccurredAt: time.Now().UTC(),
+		DatasetID:  schema.DatasetID,
+		Data: SchemaUpdatedData{
+			Action:     action,
+			SchemaID:   schema.ID,
+			SchemaName: schema.Name,
+			Diff:       DiffSchemaFields(before, after),
+		},
+	}
+}
+
+// WebhookDispatcher posts events to the configured webhook URLs. A nil dispatcher or one without
+// URLs drops events, so callers can publish unconditionally.
+type WebhookDispatcher struct {
+	urls   []string
+	secret string
+	client *http.Client
+}
+
+// NewWebhookDispatcher creates a dispatcher for urls; when secret is set each request is signed
+func NewWebhookDispatcher(urls []string, secret string) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		urls:   urls,
+		secret: secret,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// WebhookDispatcherFromEnv reads WEBHOOK_URLS (comma separated receivers, empty disables webhooks)
+// and WEBHOOK_SECRET (key for the X-Oreo-Signature header)
+func WebhookDispatcherFromEnv() *WebhookDispatcher {
+	var urls []string
+	for _, url := range strings.Split(os.Getenv("WEBHOOK_URLS"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return NewWebhookDispatcher(urls, os.Getenv("WEBHOOK_SECRET"))
+}
+
+// Publish delivers event to every receiver in the background. Failures are logged, never returned:
+// a receiver being down must not fail the change that raised the event.
+func (d *WebhookDispatcher) Publish(event WebhookEvent) {
+	if d == nil || len(d.urls) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Warning: failed to encode %s webhook event: %v", event.Type, err)
+		return
+	}
+
+	for _, url := range d.urls {
+		go func(url string) {
+			ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+			defer cancel()
+			if err := d.deliver(ctx, url, event.Type, body); err != nil {
+				log.Printf("Warning: failed to deliver %s webhook event %s to %s: %v", event.Type, event.ID, url, err)
+			}
+		}(url)
+	}
+}
+
+// deliver posts one encoded event to url
+func (d *WebhookDispatcher) deliver(ctx context.Context, url, eventType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, eventType)
+	if d.secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookBody(d.secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookBody returns the hex HMAC-SHA256 of body keyed by secret, as sent in X-Oreo-Signature
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
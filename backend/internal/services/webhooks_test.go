@@ -0,0 +1,97 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/saurabh22suman/oreo.io/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSchemaFields(t *testing.T) {
+	maxLength := 50
+	before := []models.SchemaField{
+		{Name: "name", DataType: "string", IsRequired: true, Position: 1},
+		{Name: "age", DataType: "string", Position: 2},
+		{Name: "fax", DataType: "string", Position: 3},
+	}
+	after := []models.SchemaField{
+		{Name: "name", DataType: "string", IsRequired: true, Position: 1, Validation: models.FieldValidation{MaxLength: &maxLength}},
+		{Name: "age", DataType: "integer", IsRequired: true, Position: 2},
+		{Name: "email", DataType: "email", Position: 3},
+	}
+
+	diff := DiffSchemaFields(before, after)
+	assert.Equal(t, []string{"email"}, diff.AddedFields)
+	assert.Equal(t, []string{"fax"}, diff.RemovedFields)
+	assert.Equal(t, []SchemaFieldChange{
+		{Name: "name", Attributes: []string{"validation"}},
+		{Name: "age", Attributes: []string{"data_type", "is_required"}},
+	}, diff.ChangedFields)
+
+	unchanged := DiffSchemaFields(before, before)
+	assert.Empty(t, unchanged.AddedFields)
+	assert.Empty(t, unchanged.RemovedFields)
+	assert.Empty(t, unchanged.ChangedFields)
+}
+
+func TestWebhookDispatcher_SchemaUpdated(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	received := make(chan delivery, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- delivery{header: r.Header, body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	schema := &models.DatasetSchema{ID: uuid.New(), DatasetID: uuid.New(), Name: "default"}
+	before := []models.SchemaField{{Name: "name", DataType: "string"}, {Name: "fax", DataType: "string"}}
+	after := []models.SchemaField{{Name: "name", DataType: "text"}, {Name: "email", DataType: "email"}}
+
+	dispatcher := NewWebhookDispatcher([]string{receiver.URL}, "s3cret")
+	dispatcher.Publish(NewSchemaUpdatedEvent(SchemaActionUpdated, schema, before, after))
+
+	var got delivery
+	select {
+	case got = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook receiver was never called")
+	}
+
+	assert.Equal(t, EventSchemaUpdated, got.header.Get(WebhookEventHeader))
+	assert.Equal(t, "sha256="+SignWebhookBody("s3cret", got.body), got.header.Get(WebhookSignatureHeader))
+
+	var event struct {
+		Type      string            `json:"type"`
+		DatasetID uuid.UUID         `json:"dataset_id"`
+		Data      SchemaUpdatedData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(got.body, &event))
+	assert.Equal(t, EventSchemaUpdated, event.Type)
+	assert.Equal(t, schema.DatasetID, event.DatasetID)
+	assert.Equal(t, SchemaActionUpdated, event.Data.Action)
+	assert.Equal(t, schema.ID, event.Data.SchemaID)
+	assert.Equal(t, []string{"email"}, event.Data.Diff.AddedFields)
+	assert.Equal(t, []string{"fax"}, event.Data.Diff.RemovedFields)
+	assert.Equal(t, []SchemaFieldChange{{Name: "name", Attributes: []string{"data_type"}}}, event.Data.Diff.ChangedFields)
+}
+
+func TestWebhookDispatcher_Disabled(t *testing.T) {
+	t.Setenv("WEBHOOK_URLS", "")
+	event := NewSchemaUpdatedEvent(SchemaActionCreated, &models.DatasetSchema{}, nil, nil)
+
+	assert.NotPanics(t, func() { WebhookDispatcherFromEnv().Publish(event) })
+
+	var dispatcher *WebhookDispatcher
+	assert.NotPanics(t, func() { dispatcher.Publish(event) })
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Object describes a stored file
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage keeps uploaded files by key. Keys are slash-separated paths such as
+// "uploads/<id>_data.csv", as recorded in the file_path columns.
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes an object; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// List returns every object whose key starts with prefix
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// Presigner is implemented by storages that let clients upload an object directly, without
+// sending its content through the server
+type Presigner interface {
+	// PresignPut returns a URL accepting a PUT of the object's content until expires has passed
+	PresignPut(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// Storage backends selectable through STORAGE_BACKEND
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// FromEnv builds the storage selected by STORAGE_BACKEND. The local backend (default) stores files
+// under STORAGE_LOCAL_ROOT, or the working directory; the s3 backend is configured by S3_ENDPOINT,
+// S3_BUCKET, S3_REGION, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY.
+func FromEnv() (Storage, error) {
+	switch backend := strings.ToLower(os.Getenv("STORAGE_BACKEND")); backend {
+	case "", BackendLocal:
+		return NewLocalStorage(os.Getenv("STORAGE_LOCAL_ROOT")), nil
+	case BackendS3:
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return NewS3Storage(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			Region:          region,
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported STORAGE_BACKEND %q (supported: local, s3)", backend)
+	}
+}
+
+// Key joins path elements into a storage key
+func Key(elem ...string) string {
+	return path.Join(elem...)
+}
+
+// NormalizeKey returns the canonical form of a key or of a file path recorded before storage keys
+// were introduced, so the two can be compared
+func NormalizeKey(key string) string {
+	return path.Clean(filepath.ToSlash(key))
+}
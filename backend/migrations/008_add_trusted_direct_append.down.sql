@@ -0,0 +1,6 @@
+-- Drop API keys and trusted dataset flag
+DROP INDEX IF EXISTS idx_api_keys_key_hash;
+DROP INDEX IF EXISTS idx_api_keys_project_id;
+DROP TABLE IF EXISTS api_keys;
+
+ALTER TABLE datasets DROP COLUMN IF EXISTS is_trusted;
@@ -0,0 +1,4 @@
+-- Drop submission file hash
+DROP INDEX IF EXISTS idx_data_submissions_dedup;
+
+ALTER TABLE data_submissions DROP COLUMN IF EXISTS file_hash;
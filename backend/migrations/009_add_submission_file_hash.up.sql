@@ -0,0 +1,4 @@
+-- Store a content hash of submitted files so repeated submissions can be detected
+ALTER TABLE data_submissions ADD COLUMN IF NOT EXISTS file_hash VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS idx_data_submissions_dedup ON data_submissions(dataset_id, submitted_by, file_hash);
@@ -0,0 +1 @@
+ALTER TABLE dataset_schemas DROP COLUMN IF EXISTS date_formats;
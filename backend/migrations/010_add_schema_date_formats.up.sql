@@ -0,0 +1,2 @@
+-- Let each dataset define the date formats accepted for its date fields
+ALTER TABLE dataset_schemas ADD COLUMN IF NOT EXISTS date_formats TEXT[] NOT NULL DEFAULT '{}';
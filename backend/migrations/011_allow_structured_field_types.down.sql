@@ -0,0 +1,3 @@
+ALTER TABLE schema_fields DROP CONSTRAINT IF EXISTS schema_fields_data_type_check;
+ALTER TABLE schema_fields ADD CONSTRAINT schema_fields_data_type_check
+    CHECK (data_type IN ('string', 'number', 'date', 'boolean', 'email', 'url'));
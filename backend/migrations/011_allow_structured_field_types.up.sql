@@ -0,0 +1,4 @@
+-- Allow nested JSON object and array fields alongside the existing scalar types
+ALTER TABLE schema_fields DROP CONSTRAINT IF EXISTS schema_fields_data_type_check;
+ALTER TABLE schema_fields ADD CONSTRAINT schema_fields_data_type_check
+    CHECK (data_type IN ('string', 'number', 'date', 'boolean', 'email', 'url', 'object', 'array'));
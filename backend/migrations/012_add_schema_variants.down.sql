@@ -0,0 +1,6 @@
+ALTER TABLE data_submissions DROP COLUMN IF EXISTS schema_name;
+
+DROP INDEX IF EXISTS idx_dataset_schemas_default;
+DROP INDEX IF EXISTS idx_dataset_schemas_dataset_name;
+
+ALTER TABLE dataset_schemas DROP COLUMN IF EXISTS is_default;
@@ -0,0 +1,11 @@
+-- Allow several named schema variants per dataset, one of which is the default
+ALTER TABLE dataset_schemas ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Existing datasets have a single schema, which becomes their default
+UPDATE dataset_schemas SET is_default = TRUE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dataset_schemas_dataset_name ON dataset_schemas(dataset_id, name);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dataset_schemas_default ON dataset_schemas(dataset_id) WHERE is_default;
+
+-- Remember which variant a submission was validated against
+ALTER TABLE data_submissions ADD COLUMN IF NOT EXISTS schema_name VARCHAR(255);
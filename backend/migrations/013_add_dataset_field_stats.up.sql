@@ -0,0 +1,11 @@
+-- Persisted per-field statistics computed over a dataset's stored rows
+CREATE TABLE IF NOT EXISTS dataset_field_stats (
+    dataset_id UUID NOT NULL REFERENCES datasets(id) ON DELETE CASCADE,
+    field_name VARCHAR(255) NOT NULL,
+    total_values INTEGER NOT NULL DEFAULT 0,
+    unique_values INTEGER NOT NULL DEFAULT 0,
+    null_values INTEGER NOT NULL DEFAULT 0,
+    invalid_values INTEGER NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (dataset_id, field_name)
+);
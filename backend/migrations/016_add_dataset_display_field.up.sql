@@ -0,0 +1,2 @@
+-- Field whose value labels dataset rows in list and reference UIs
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS display_field VARCHAR(255);
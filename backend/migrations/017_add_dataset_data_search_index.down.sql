@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_dataset_data_search;
@@ -0,0 +1,4 @@
+-- Full-text index over the string and numeric values of dataset rows. The search query must use
+-- the same expression to be served by this index.
+CREATE INDEX IF NOT EXISTS idx_dataset_data_search
+    ON dataset_data USING GIN (jsonb_to_tsvector('simple', data, '["string", "numeric"]'));
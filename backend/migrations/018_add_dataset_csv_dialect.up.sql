@@ -0,0 +1,2 @@
+-- CSV dialect (delimiter, quote, escape, null marker) used to parse the dataset's uploads and appends
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS csv_dialect JSONB;
@@ -0,0 +1,2 @@
+-- Header order of the uploaded file, used to order columns of datasets without a schema
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS column_order JSONB;
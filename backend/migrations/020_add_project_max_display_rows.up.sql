@@ -0,0 +1,2 @@
+-- Per-project cap on how many dataset rows can be paged through; NULL uses the server default
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS max_display_rows INTEGER CHECK (max_display_rows > 0);
@@ -0,0 +1 @@
+ALTER TABLE datasets DROP COLUMN IF EXISTS pii_guardrails;
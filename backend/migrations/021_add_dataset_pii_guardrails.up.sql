@@ -0,0 +1,2 @@
+-- Contact fields (email, phone) whose values must not repeat across the dataset's rows
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS pii_guardrails JSONB;
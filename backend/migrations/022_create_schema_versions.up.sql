@@ -0,0 +1,17 @@
+-- Snapshots of a schema's fields after each change, numbered from 1 per schema
+CREATE TABLE IF NOT EXISTS schema_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    schema_id UUID NOT NULL REFERENCES dataset_schemas(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    fields JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(schema_id, version)
+);
+
+-- Existing schemas start at version 1 with their current fields
+INSERT INTO schema_versions (schema_id, version, fields)
+SELECT s.id, 1, COALESCE(jsonb_agg(to_jsonb(f) ORDER BY f.position) FILTER (WHERE f.id IS NOT NULL), '[]')
+FROM dataset_schemas s
+LEFT JOIN schema_fields f ON f.schema_id = s.id
+GROUP BY s.id
+ON CONFLICT (schema_id, version) DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE dataset_field_stats DROP COLUMN IF EXISTS distinct_values;
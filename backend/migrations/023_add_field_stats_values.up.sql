@@ -0,0 +1,2 @@
+-- Distinct values of low-cardinality fields, used as the baseline for drift detection
+ALTER TABLE dataset_field_stats ADD COLUMN IF NOT EXISTS distinct_values JSONB;
@@ -0,0 +1,2 @@
+-- Whether an approved submission appends to the dataset or replaces its rows
+ALTER TABLE data_submissions ADD COLUMN IF NOT EXISTS mode VARCHAR(20) NOT NULL DEFAULT 'append';
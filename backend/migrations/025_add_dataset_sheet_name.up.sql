@@ -0,0 +1,2 @@
+-- Sheet of an uploaded Excel workbook the dataset was read from; NULL for CSV files
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS sheet_name VARCHAR(255);
@@ -0,0 +1 @@
+ALTER TABLE data_submissions DROP COLUMN IF EXISTS column_mapping;
@@ -0,0 +1,2 @@
+-- File headers renamed to schema field names before a submission was validated
+ALTER TABLE data_submissions ADD COLUMN IF NOT EXISTS column_mapping JSONB;
@@ -0,0 +1,2 @@
+-- Whether submitted columns that aren't schema fields are rejected or ignored
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS header_mode VARCHAR(20) NOT NULL DEFAULT 'strict';
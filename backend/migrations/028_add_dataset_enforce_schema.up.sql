@@ -0,0 +1,2 @@
+-- Whether direct edits to a dataset's rows are validated against its schema
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS enforce_schema BOOLEAN NOT NULL DEFAULT FALSE;
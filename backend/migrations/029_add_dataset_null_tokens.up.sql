@@ -0,0 +1,2 @@
+-- Cell values, such as 'N/A' or '-', read as null in the dataset's uploaded and appended files
+ALTER TABLE datasets ADD COLUMN IF NOT EXISTS null_tokens JSONB;
@@ -0,0 +1 @@
+ALTER TABLE schema_fields DROP COLUMN IF EXISTS is_pii;
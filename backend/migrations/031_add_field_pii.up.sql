@@ -0,0 +1,2 @@
+-- Whether a field's values are masked for users who can't edit the dataset
+ALTER TABLE schema_fields ADD COLUMN IF NOT EXISTS is_pii BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS chk_api_keys_role;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS principal_id;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS role;
@@ -0,0 +1,6 @@
+-- Project API keys act as a synthetic user that is a member of the project with the key's role
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS role VARCHAR(20);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS principal_id UUID REFERENCES users(id);
+
+ALTER TABLE api_keys ADD CONSTRAINT chk_api_keys_role
+    CHECK (role IS NULL OR role IN ('collaborator', 'viewer'));
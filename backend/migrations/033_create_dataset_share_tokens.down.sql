@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_dataset_share_tokens_dataset_id;
+DROP TABLE IF EXISTS dataset_share_tokens;
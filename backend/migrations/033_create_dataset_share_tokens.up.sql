@@ -0,0 +1,14 @@
+-- Signed, expiring tokens that let someone without an account read one dataset
+CREATE TABLE IF NOT EXISTS dataset_share_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    dataset_id UUID NOT NULL REFERENCES datasets(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    permission VARCHAR(20) NOT NULL DEFAULT 'view' CHECK (permission IN ('view')),
+    created_by UUID NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dataset_share_tokens_dataset_id ON dataset_share_tokens(dataset_id);
@@ -0,0 +1,10 @@
+-- Review thread between a submission's submitter and its reviewers
+CREATE TABLE IF NOT EXISTS submission_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    submission_id UUID NOT NULL REFERENCES data_submissions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    body TEXT NOT NULL CHECK (length(trim(body)) > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_submission_comments_submission_id ON submission_comments(submission_id, created_at);
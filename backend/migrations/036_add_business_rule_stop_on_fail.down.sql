@@ -0,0 +1 @@
+ALTER TABLE dataset_business_rules DROP COLUMN IF EXISTS stop_on_fail;
@@ -0,0 +1,2 @@
+-- Whether a row failing the rule skips the dataset's lower-priority rules
+ALTER TABLE dataset_business_rules ADD COLUMN IF NOT EXISTS stop_on_fail BOOLEAN NOT NULL DEFAULT FALSE;
@@ -415,7 +415,7 @@ const AdminDataSubmissionReview: React.FC = () => {
                             </td>
                             {Object.values(row.data).map((value, index) => (
                               <td key={index} className="px-6 py-4 whitespace-nowrap text-sm text-gray-900">
-                                {String(value)}
+                                {String(value ?? '')}
                               </td>
                             ))}
                           </tr>
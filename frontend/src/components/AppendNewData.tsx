@@ -471,7 +471,7 @@ const AppendNewData: React.FC<AppendNewDataProps> = ({ datasetId, datasetName, o
                                   onClick={() => handleEditRow(row.row_index, row.data)}
                                   className="cursor-pointer hover:bg-gray-100 px-2 py-1 rounded"
                                 >
-                                  {String(value)}
+                                  {String(value ?? '')}
                                 </span>
                               )}
                             </td>
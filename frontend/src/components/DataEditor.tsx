@@ -8,7 +8,7 @@ interface SchemaField {
   id: string;
   name: string;
   display_name: string;
-  data_type: 'string' | 'number' | 'boolean' | 'date' | 'email';
+  data_type: 'string' | 'number' | 'integer' | 'currency' | 'percent' | 'boolean' | 'date' | 'email' | 'phone' | 'list';
   is_required: boolean;
   is_unique: boolean;
   default_value?: string;
@@ -30,6 +30,7 @@ interface DataRow {
 
 interface DataPageResult {
   data: DataRow[];
+  columns?: string[]; // column names in display order
   total: number;
   page: number;
   page_size: number;
@@ -253,6 +254,7 @@ const DataEditor: React.FC<DataEditorProps> = ({ onOpenSchemaEditor, schema: pro
         body: JSON.stringify({
           row_index: editingRow,
           data: editingData,
+          version: editingData._version,
         }),
       });
 
@@ -260,6 +262,12 @@ const DataEditor: React.FC<DataEditorProps> = ({ onOpenSchemaEditor, schema: pro
         setEditingRow(null);
         setEditingData({});
         loadData(); // Reload data
+      } else if (response.status === 409) {
+        // Someone else saved the row first; show their version before editing again
+        setEditingRow(null);
+        setEditingData({});
+        setError('This row was changed by someone else. The latest version has been loaded.');
+        loadData();
       } else {
         throw new Error('Failed to save data');
       }
@@ -315,10 +323,14 @@ const DataEditor: React.FC<DataEditorProps> = ({ onOpenSchemaEditor, schema: pro
     switch (field.data_type) {
       case 'number':
         return <input type="number" {...commonProps} />;
+      case 'integer':
+        return <input type="number" step={1} {...commonProps} />;
       case 'date':
         return <input type="date" {...commonProps} />;
       case 'email':
         return <input type="email" {...commonProps} />;
+      case 'phone':
+        return <input type="tel" {...commonProps} />;
       case 'boolean':
         return (
           <select
@@ -343,6 +355,8 @@ const DataEditor: React.FC<DataEditorProps> = ({ onOpenSchemaEditor, schema: pro
         return value ? 'Yes' : 'No';
       case 'date':
         return new Date(value).toLocaleDateString();
+      case 'list':
+        return Array.isArray(value) ? value.join(', ') : String(value);
       default:
         return String(value);
     }
@@ -367,6 +381,12 @@ const DataEditor: React.FC<DataEditorProps> = ({ onOpenSchemaEditor, schema: pro
   // Remove the early return for missing schema - we can show data without schema
   const displayResult = isQueryMode ? queryResult : dataResult;
   const { data, total, total_pages } = displayResult || { data: [], total: 0, total_pages: 1 };
+  // Prefer the server's column order; JSON object keys carry no reliable order
+  const columnNames = displayResult?.columns?.length
+    ? displayResult.columns
+    : data.length > 0
+      ? Object.keys(data[0]).filter(key => key !== '_row_index')
+      : [];
 
   return (
     <div className="space-y-6">
@@ -473,9 +493,8 @@ const DataEditor: React.FC<DataEditorProps> = ({ onOpenSchemaEditor, schema: pro
                       </th>
                     ))
                 ) : (
-                  // When no schema, use column names from data
-                  data.length > 0 && Object.keys(data[0])
-                    .filter(key => key !== '_row_index') // Exclude internal row index
+                  // When no schema, use the column order of the data
+                  columnNames
                     .map((columnName) => (
                       <th
                         key={columnName}
@@ -508,8 +527,7 @@ const DataEditor: React.FC<DataEditorProps> = ({ onOpenSchemaEditor, schema: pro
                       ))
                   ) : (
                     // When no schema, display all columns
-                    Object.keys(row)
-                      .filter(key => key !== '_row_index') // Exclude internal row index
+                    columnNames
                       .map((columnName) => (
                         <td key={columnName} className="px-6 py-4 whitespace-nowrap text-sm text-gray-900">
                           {row[columnName]?.toString() || ''}
@@ -5,9 +5,10 @@ interface SchemaField {
   id?: string;
   name: string;
   display_name: string;
-  data_type: 'string' | 'number' | 'boolean' | 'date' | 'email';
+  data_type: 'string' | 'number' | 'integer' | 'currency' | 'percent' | 'boolean' | 'date' | 'email' | 'phone' | 'list';
   is_required: boolean;
   is_unique: boolean;
+  is_pii?: boolean;
   default_value?: string;
   position: number;
   validation?: any;
@@ -31,9 +32,14 @@ interface SchemaEditorProps {
 const dataTypes = [
   { value: 'string', label: 'Text' },
   { value: 'number', label: 'Number' },
+  { value: 'integer', label: 'Whole Number' },
+  { value: 'currency', label: 'Currency' },
+  { value: 'percent', label: 'Percentage' },
   { value: 'boolean', label: 'Yes/No' },
   { value: 'date', label: 'Date' },
   { value: 'email', label: 'Email' },
+  { value: 'phone', label: 'Phone' },
+  { value: 'list', label: 'List' },
 ];
 
 const SchemaEditor: React.FC<SchemaEditorProps> = ({
@@ -64,6 +70,7 @@ const SchemaEditor: React.FC<SchemaEditorProps> = ({
       data_type: 'string',
       is_required: false,
       is_unique: false,
+      is_pii: false,
       position: schema.fields.length + 1,
     };
     setSchema(prev => ({
@@ -318,6 +325,15 @@ const SchemaEditor: React.FC<SchemaEditorProps> = ({
                           />
                           <span className="ml-2 text-sm text-gray-700">Unique</span>
                         </label>
+                        <label className="flex items-center" title="Masked for viewers who can't edit the dataset">
+                          <input
+                            type="checkbox"
+                            checked={field.is_pii ?? false}
+                            onChange={(e) => updateField(index, { is_pii: e.target.checked })}
+                            className="rounded border-gray-300 text-blue-600 focus:ring-blue-500"
+                          />
+                          <span className="ml-2 text-sm text-gray-700">PII</span>
+                        </label>
                       </div>
                       <div className="flex items-center space-x-2">
                         <button
@@ -162,8 +162,10 @@ const DataViewPage: React.FC = () => {
               max_length: field.constraints?.max_length,
               min_value: field.constraints?.min,
               max_value: field.constraints?.max,
+              scale: field.constraints?.scale,
               pattern: field.pattern,
               format: field.constraints?.format,
+              options: field.options,
             },
           })),
         };
@@ -16,6 +16,7 @@ const ProjectManagementPage: React.FC = () => {
   const [datasets, setDatasets] = useState<Dataset[]>([]);
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
+  const [uploadWarning, setUploadWarning] = useState<string | null>(null);
   const [activeTab, setActiveTab] = useState<'overview' | 'datasets' | 'members' | 'settings'>('overview');
   const [showUploadModal, setShowUploadModal] = useState(false);
   const [inviteEmail, setInviteEmail] = useState('');
@@ -65,7 +66,8 @@ const ProjectManagementPage: React.FC = () => {
 
   const handleUploadDataset = async (uploadData: any) => {
     try {
-      await datasetService.uploadDataset(uploadData);
+      const result = await datasetService.uploadDataset(uploadData);
+      setUploadWarning(result.warning || null);
       setShowUploadModal(false);
       await loadProjectData(); // Refresh datasets
     } catch (err) {
@@ -240,6 +242,18 @@ const ProjectManagementPage: React.FC = () => {
           </div>
         )}
 
+        {uploadWarning && (
+          <div className="bg-yellow-50 border border-yellow-200 rounded-md p-4 mb-6">
+            <p className="text-sm text-yellow-800">{uploadWarning}</p>
+            <button 
+              onClick={() => setUploadWarning(null)}
+              className="text-yellow-700 hover:text-yellow-600 text-sm underline mt-1"
+            >
+              Dismiss
+            </button>
+          </div>
+        )}
+
         {/* Overview Tab */}
         {activeTab === 'overview' && (
           <div className="grid grid-cols-1 lg:grid-cols-3 gap-6">
@@ -329,8 +343,8 @@ const ProjectManagementPage: React.FC = () => {
                           <td className="px-6 py-4 whitespace-nowrap">
                             <span className={`inline-flex px-2 py-1 text-xs font-semibold rounded-full ${
                               dataset.status === 'ready' ? 'bg-green-100 text-green-800' :
-                              dataset.status === 'processing' ? 'bg-yellow-100 text-yellow-800' :
-                              dataset.status === 'error' ? 'bg-red-100 text-red-800' :
+                              dataset.status === 'processing' || dataset.status === 'partial' ? 'bg-yellow-100 text-yellow-800' :
+                              dataset.status === 'error' || dataset.status === 'ingestion_failed' ? 'bg-red-100 text-red-800' :
                               'bg-gray-100 text-gray-800'
                             }`}>
                               {dataset.status}
@@ -17,7 +17,7 @@ const createAuthHeaders = () => {
 
 export const datasetService = {
   // Upload a dataset file
-  async uploadDataset(data: UploadDatasetRequest): Promise<{ dataset: Dataset; message: string }> {
+  async uploadDataset(data: UploadDatasetRequest): Promise<{ dataset: Dataset; message: string; warning?: string }> {
     const formData = new FormData();
     formData.append('file', data.file);
     formData.append('project_id', data.project_id);
@@ -9,7 +9,7 @@ export interface Dataset {
   mime_type: string;
   row_count: number;
   column_count: number;
-  status: 'processing' | 'ready' | 'error';
+  status: 'processing' | 'ready' | 'error' | 'partial' | 'ingestion_failed';
   uploaded_by: string;
   created_at: string;
   updated_at: string;
@@ -5,6 +5,7 @@ export interface Project {
   owner_id: string;
   created_at: string;
   updated_at: string;
+  max_display_rows?: number;
 }
 
 export interface CreateProjectRequest {
@@ -15,4 +16,5 @@ export interface CreateProjectRequest {
 export interface UpdateProjectRequest {
   name?: string;
   description?: string;
+  max_display_rows?: number;
 }